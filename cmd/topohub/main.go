@@ -18,6 +18,10 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/infrastructure-io/topohub/pkg/biosconfig"
+	"github.com/infrastructure-io/topohub/pkg/bmcaccount"
+	"github.com/infrastructure-io/topohub/pkg/bmccertificate"
+	"github.com/infrastructure-io/topohub/pkg/bmcprofile"
 	"github.com/infrastructure-io/topohub/pkg/bindingip"
 	"github.com/infrastructure-io/topohub/pkg/config"
 	"github.com/infrastructure-io/topohub/pkg/hostendpoint"
@@ -27,7 +31,9 @@ import (
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
 	crdclientset "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
 	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/powercapconfig"
 	"github.com/infrastructure-io/topohub/pkg/secret"
+	"github.com/infrastructure-io/topohub/pkg/storageconfig"
 	"github.com/infrastructure-io/topohub/pkg/subnet"
 	bindingipwebhook "github.com/infrastructure-io/topohub/pkg/webhook/bindingip"
 	hostendpointwebhook "github.com/infrastructure-io/topohub/pkg/webhook/hostendpoint"
@@ -113,7 +119,7 @@ func main() {
 	}
 
 	// Setup HostOperation webhook
-	if err = (&hostoperationwebhook.HostOperationWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&hostoperationwebhook.HostOperationWebhook{}).SetupWebhookWithManager(mgr, *agentConfig); err != nil {
 		log.Logger.Errorf("unable to create webhook %s: %v", "HostOperation", err)
 		os.Exit(1)
 	}
@@ -186,6 +192,78 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Initialize biosconfig controller
+	biosConfigCtrl, err := biosconfig.NewBiosConfigController(mgr, agentConfig)
+	if err != nil {
+		log.Logger.Errorf("Failed to create biosconfig controller: %v", err)
+		os.Exit(1)
+	}
+
+	if err = biosConfigCtrl.SetupWithManager(mgr); err != nil {
+		log.Logger.Errorf("Unable to create biosconfig controller: %v", err)
+		os.Exit(1)
+	}
+
+	// Initialize storageconfig controller
+	storageConfigCtrl, err := storageconfig.NewStorageConfigController(mgr, agentConfig)
+	if err != nil {
+		log.Logger.Errorf("Failed to create storageconfig controller: %v", err)
+		os.Exit(1)
+	}
+
+	if err = storageConfigCtrl.SetupWithManager(mgr); err != nil {
+		log.Logger.Errorf("Unable to create storageconfig controller: %v", err)
+		os.Exit(1)
+	}
+
+	// Initialize powercapconfig controller
+	powerCapConfigCtrl, err := powercapconfig.NewPowerCapConfigController(mgr, agentConfig)
+	if err != nil {
+		log.Logger.Errorf("Failed to create powercapconfig controller: %v", err)
+		os.Exit(1)
+	}
+
+	if err = powerCapConfigCtrl.SetupWithManager(mgr); err != nil {
+		log.Logger.Errorf("Unable to create powercapconfig controller: %v", err)
+		os.Exit(1)
+	}
+
+	// Initialize bmccertificate controller
+	bmcCertificateCtrl, err := bmccertificate.NewBmcCertificateController(mgr, agentConfig)
+	if err != nil {
+		log.Logger.Errorf("Failed to create bmccertificate controller: %v", err)
+		os.Exit(1)
+	}
+
+	if err = bmcCertificateCtrl.SetupWithManager(mgr); err != nil {
+		log.Logger.Errorf("Unable to create bmccertificate controller: %v", err)
+		os.Exit(1)
+	}
+
+	// Initialize bmcprofile controller
+	bmcProfileCtrl, err := bmcprofile.NewBmcProfileController(mgr, agentConfig)
+	if err != nil {
+		log.Logger.Errorf("Failed to create bmcprofile controller: %v", err)
+		os.Exit(1)
+	}
+
+	if err = bmcProfileCtrl.SetupWithManager(mgr); err != nil {
+		log.Logger.Errorf("Unable to create bmcprofile controller: %v", err)
+		os.Exit(1)
+	}
+
+	// Initialize bmcaccount controller
+	bmcAccountCtrl, err := bmcaccount.NewBmcAccountController(mgr, agentConfig)
+	if err != nil {
+		log.Logger.Errorf("Failed to create bmcaccount controller: %v", err)
+		os.Exit(1)
+	}
+
+	if err = bmcAccountCtrl.SetupWithManager(mgr); err != nil {
+		log.Logger.Errorf("Unable to create bmcaccount controller: %v", err)
+		os.Exit(1)
+	}
+
 	// Initialize bindingIP controller
 	bindingIPCtrl := bindingip.NewBindingIPController(mgr, agentConfig, addBindingIpChan, deleteBindingIpChan)
 	if err != nil {
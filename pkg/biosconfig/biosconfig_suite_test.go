@@ -0,0 +1,13 @@
+package biosconfig
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBiosConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BiosConfig Suite")
+}
@@ -0,0 +1,279 @@
+package biosconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// BiosConfigController reconciles the bios attributes of the hosts to the desired ones in the BiosConfig
+type BiosConfigController struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	agentConfig *config.AgentConfig
+	log         *zap.SugaredLogger
+}
+
+func NewBiosConfigController(mgr ctrl.Manager, agentConfig *config.AgentConfig) (*BiosConfigController, error) {
+	return &BiosConfigController{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		agentConfig: agentConfig,
+		log:         log.Logger.Named("BiosConfigController"),
+	}, nil
+}
+
+// 只有 leader 才会执行 Reconcile
+// Reconcile checks the bios attributes of every selected host, and it is requeued at the interval of updating the HostStatus
+func (r *BiosConfigController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.With("biosconfig", req.Name)
+	logger.Debugf("Starting reconcile for BiosConfig %s", req.Name)
+
+	biosConfig := &topohubv1beta1.BiosConfig{}
+	if err := r.Get(ctx, req.NamespacedName, biosConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := time.Duration(r.agentConfig.RedfishHostStatusUpdateInterval) * time.Second
+
+	// 记录上一次下发配置的时间
+	lastApplyTime := map[string]string{}
+	for _, item := range biosConfig.Status.Hosts {
+		lastApplyTime[item.HostStatusName+"/"+item.SystemId] = item.LastApplyTime
+	}
+
+	hosts, missing, err := r.selectHosts(ctx, biosConfig)
+	if err != nil {
+		logger.Errorf("Failed to select hosts: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	status := topohubv1beta1.BiosConfigStatus{}
+	for _, name := range missing {
+		status.Hosts = append(status.Hosts, topohubv1beta1.BiosHostStatus{
+			HostStatusName: name,
+			State:          topohubv1beta1.BiosStateFailed,
+			Message:        fmt.Sprintf("hostStatus %s is not found", name),
+		})
+	}
+	for i := range hosts {
+		// the bios belongs to the ComputerSystem, so check every system of a multi-node host
+		systemIds := []string{""}
+		if len(hosts[i].Status.Systems) > 0 {
+			systemIds = []string{}
+			for _, s := range hosts[i].Status.Systems {
+				systemIds = append(systemIds, s.Id)
+			}
+		}
+		for _, systemId := range systemIds {
+			item := r.syncHost(logger, biosConfig, &hosts[i], systemId)
+			if len(item.LastApplyTime) == 0 {
+				item.LastApplyTime = lastApplyTime[item.HostStatusName+"/"+item.SystemId]
+			}
+			status.Hosts = append(status.Hosts, item)
+		}
+	}
+
+	for _, item := range status.Hosts {
+		status.TotalHosts++
+		switch item.State {
+		case topohubv1beta1.BiosStateInSync:
+			status.InSyncHosts++
+		case topohubv1beta1.BiosStatePendingReboot:
+			status.PendingRebootHosts++
+		case topohubv1beta1.BiosStateDrifted:
+			status.DriftedHosts++
+		default:
+			status.FailedHosts++
+		}
+	}
+
+	// ignore the update time when comparing
+	status.LastUpdateTime = biosConfig.Status.LastUpdateTime
+	if reflect.DeepEqual(status, biosConfig.Status) {
+		logger.Debugf("no need to update BiosConfig %s", biosConfig.Name)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+	status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	biosConfig.Status = status
+	if err := r.Status().Update(ctx, biosConfig); err != nil {
+		logger.Errorf("Failed to update BiosConfig status: %v", err)
+		return ctrl.Result{}, err
+	}
+	logger.Infof("Successfully updated BiosConfig %s status, total %d, inSync %d, pendingReboot %d, drifted %d, failed %d",
+		biosConfig.Name, status.TotalHosts, status.InSyncHosts, status.PendingRebootHosts, status.DriftedHosts, status.FailedHosts)
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// selectHosts returns the HostStatus selected by the names and the label selector, and the names which are not found
+func (r *BiosConfigController) selectHosts(ctx context.Context, biosConfig *topohubv1beta1.BiosConfig) ([]topohubv1beta1.HostStatus, []string, error) {
+	selected := map[string]topohubv1beta1.HostStatus{}
+	missing := []string{}
+
+	for _, name := range biosConfig.Spec.HostStatusNames {
+		hostStatus := topohubv1beta1.HostStatus{}
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &hostStatus); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return nil, nil, err
+		}
+		selected[name] = hostStatus
+	}
+
+	if biosConfig.Spec.HostSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(biosConfig.Spec.HostSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid hostSelector: %v", err)
+		}
+		hostStatusList := &topohubv1beta1.HostStatusList{}
+		if err := r.List(ctx, hostStatusList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range hostStatusList.Items {
+			selected[item.Name] = item
+		}
+	}
+
+	result := []topohubv1beta1.HostStatus{}
+	for _, item := range selected {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	sort.Strings(missing)
+	return result, missing, nil
+}
+
+// syncHost compares the bios attributes of the system with the desired ones, and applies the differences in the Enforce mode
+func (r *BiosConfigController) syncHost(logger *zap.SugaredLogger, biosConfig *topohubv1beta1.BiosConfig, hostStatus *topohubv1beta1.HostStatus, systemId string) topohubv1beta1.BiosHostStatus {
+	result := topohubv1beta1.BiosHostStatus{
+		HostStatusName: hostStatus.Name,
+		SystemId:       systemId,
+		State:          topohubv1beta1.BiosStateFailed,
+	}
+
+	if !hostStatus.Status.Healthy {
+		result.Message = fmt.Sprintf("hostStatus %s is not healthy", hostStatus.Name)
+		return result
+	}
+	d := hoststatusData.HostCacheDatabase.Get(hostStatus.Name)
+	if d == nil {
+		result.Message = fmt.Sprintf("failed to get connect config %s from cache", hostStatus.Name)
+		return result
+	}
+	c, err := redfish.NewClient(*d, logger)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	attrs, err := c.GetBiosAttributes(systemId)
+	if err != nil {
+		logger.Warnf("Failed to get bios attributes of %s: %v", hostStatus.Name, err)
+		result.Message = err.Error()
+		return result
+	}
+
+	drift, toApply, missing := diffAttributes(biosConfig.Spec.Attributes, attrs)
+	result.Drift = drift
+	switch {
+	case len(missing) > 0:
+		result.Message = fmt.Sprintf("the bios does not have the attributes: %s", strings.Join(missing, ","))
+		return result
+	case len(drift) == 0:
+		result.State = topohubv1beta1.BiosStateInSync
+		return result
+	case len(toApply) == 0:
+		result.State = topohubv1beta1.BiosStatePendingReboot
+		return result
+	case biosConfig.Spec.Mode == topohubv1beta1.BiosConfigModeMonitor:
+		result.State = topohubv1beta1.BiosStateDrifted
+		return result
+	}
+
+	// apply the differences
+	if err := c.SetBiosAttributes(systemId, toApply, biosConfig.Spec.ApplyTime); err != nil {
+		logger.Errorf("Failed to set bios attributes of %s: %v", hostStatus.Name, err)
+		result.Message = err.Error()
+		return result
+	}
+	result.LastApplyTime = time.Now().UTC().Format(time.RFC3339)
+	logger.Infof("Applied bios attributes to %s, system %s: %v", hostStatus.Name, systemId, toApply)
+
+	// read again to report the result
+	result.State = topohubv1beta1.BiosStatePendingReboot
+	if attrs, err := c.GetBiosAttributes(systemId); err == nil {
+		drift, _, _ = diffAttributes(biosConfig.Spec.Attributes, attrs)
+		result.Drift = drift
+		if len(drift) == 0 {
+			result.State = topohubv1beta1.BiosStateInSync
+		}
+	}
+	return result
+}
+
+// diffAttributes returns the drifted attributes, the attributes which need to be applied, and the attributes which the bios does not have
+func diffAttributes(desired map[string]string, attrs *redfish.BiosAttributes) ([]topohubv1beta1.BiosAttributeDrift, map[string]string, []string) {
+	drift := []topohubv1beta1.BiosAttributeDrift{}
+	toApply := map[string]string{}
+	missing := []string{}
+
+	names := []string{}
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := desired[name]
+		current, ok := attrs.Current[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		pending, hasPending := attrs.Pending[name]
+		if strings.EqualFold(current, value) && !hasPending {
+			continue
+		}
+		drift = append(drift, topohubv1beta1.BiosAttributeDrift{
+			Name:    name,
+			Desired: value,
+			Current: current,
+			Pending: pending,
+		})
+		// the desired value which is pending takes effect after the reboot
+		if !hasPending || !strings.EqualFold(pending, value) {
+			toApply[name] = value
+		}
+	}
+	return drift, toApply, missing
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *BiosConfigController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topohubv1beta1.BiosConfig{}).
+		// the status is updated by itself, and the hosts are checked periodically
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
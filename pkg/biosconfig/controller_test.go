@@ -0,0 +1,208 @@
+package biosconfig
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const (
+	testBiosConfigName = "baseline"
+	biosSettingsPath   = "/redfish/v1/Systems/1/Bios/Settings"
+)
+
+var _ = Describe("diffAttributes", Label("unitest"), func() {
+	DescribeTable("compares the desired attributes with the current and pending ones",
+		func(current, pending map[string]string, drift []topohubv1beta1.BiosAttributeDrift, toApply map[string]string, missing []string) {
+			desired := map[string]string{"BootMode": "Uefi", "HyperThreading": "Enabled"}
+			d, a, m := diffAttributes(desired, &redfish.BiosAttributes{Current: current, Pending: pending})
+			Expect(d).To(Equal(drift))
+			Expect(a).To(Equal(toApply))
+			Expect(m).To(Equal(missing))
+		},
+		Entry("in sync",
+			map[string]string{"BootMode": "Uefi", "HyperThreading": "Enabled", "SriovEnable": "true"}, map[string]string{},
+			[]topohubv1beta1.BiosAttributeDrift{}, map[string]string{}, []string{}),
+		Entry("the values differ in the case only",
+			map[string]string{"BootMode": "UEFI", "HyperThreading": "enabled"}, map[string]string{},
+			[]topohubv1beta1.BiosAttributeDrift{}, map[string]string{}, []string{}),
+		Entry("drifted",
+			map[string]string{"BootMode": "Legacy", "HyperThreading": "Enabled"}, map[string]string{},
+			[]topohubv1beta1.BiosAttributeDrift{{Name: "BootMode", Desired: "Uefi", Current: "Legacy"}},
+			map[string]string{"BootMode": "Uefi"}, []string{}),
+		Entry("the desired value is pending",
+			map[string]string{"BootMode": "Legacy", "HyperThreading": "Enabled"}, map[string]string{"BootMode": "UEFI"},
+			[]topohubv1beta1.BiosAttributeDrift{{Name: "BootMode", Desired: "Uefi", Current: "Legacy", Pending: "UEFI"}},
+			map[string]string{}, []string{}),
+		Entry("another value is pending",
+			map[string]string{"BootMode": "Legacy", "HyperThreading": "Enabled"}, map[string]string{"BootMode": "Auto"},
+			[]topohubv1beta1.BiosAttributeDrift{{Name: "BootMode", Desired: "Uefi", Current: "Legacy", Pending: "Auto"}},
+			map[string]string{"BootMode": "Uefi"}, []string{}),
+		Entry("the current value is desired but another value is pending",
+			map[string]string{"BootMode": "Uefi", "HyperThreading": "Enabled"}, map[string]string{"HyperThreading": "Disabled"},
+			[]topohubv1beta1.BiosAttributeDrift{{Name: "HyperThreading", Desired: "Enabled", Current: "Enabled", Pending: "Disabled"}},
+			map[string]string{"HyperThreading": "Enabled"}, []string{}),
+		Entry("the bios does not have the attributes",
+			map[string]string{"HyperThreading": "Disabled"}, map[string]string{},
+			[]topohubv1beta1.BiosAttributeDrift{{Name: "HyperThreading", Desired: "Enabled", Current: "Disabled"}},
+			map[string]string{"HyperThreading": "Enabled"}, []string{"BootMode"}),
+	)
+})
+
+var _ = Describe("BiosConfigController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *BiosConfigController
+
+	// newController creates the controller with the HostStatus of the emulator, and the BiosConfig which also names a
+	// missing HostStatus
+	newController := func(spec topohubv1beta1.BiosConfigSpec) {
+		spec.HostStatusNames = []string{"missing", testhost.HostStatusName}
+		c := bmc.Build(bmc.HostStatus(), &topohubv1beta1.BiosConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: testBiosConfigName},
+			Spec:       spec,
+		})
+		r = &BiosConfigController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{RedfishHostStatusUpdateInterval: 60},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	// reconcile returns the status of the emulator host, the first host is the missing one
+	reconcile := func() topohubv1beta1.BiosHostStatus {
+		testhost.Reconcile(r, testBiosConfigName)
+		biosConfig := testhost.Get(r, testBiosConfigName, &topohubv1beta1.BiosConfig{})
+		Expect(biosConfig.Status.TotalHosts).To(Equal(int32(2)))
+		Expect(biosConfig.Status.Hosts).To(HaveLen(2))
+		Expect(biosConfig.Status.Hosts[0].HostStatusName).To(Equal("missing"))
+		Expect(biosConfig.Status.Hosts[0].State).To(Equal(topohubv1beta1.BiosStateFailed))
+		Expect(biosConfig.Status.Hosts[1].HostStatusName).To(Equal(testhost.HostStatusName))
+		return biosConfig.Status.Hosts[1]
+	}
+
+	desired := map[string]string{"BootMode": "uefi", "HyperThreading": "Disabled", "SriovEnable": "true"}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(false)
+		bmc.SetBios("1", emulator.Bios{
+			Attributes: map[string]interface{}{"BootMode": "Uefi", "HyperThreading": "Enabled", "SriovEnable": false, "NumaNodes": 2.0},
+		})
+	})
+
+	It("reports the drift in the Monitor mode", func() {
+		newController(topohubv1beta1.BiosConfigSpec{
+			Attributes: desired,
+			Mode:       topohubv1beta1.BiosConfigModeMonitor,
+		})
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStateDrifted))
+		Expect(host.LastApplyTime).To(BeEmpty())
+		Expect(host.Drift).To(Equal([]topohubv1beta1.BiosAttributeDrift{
+			{Name: "HyperThreading", Desired: "Disabled", Current: "Enabled"},
+			{Name: "SriovEnable", Desired: "true", Current: "false"},
+		}))
+		Expect(bmc.CountRequests(http.MethodPatch, biosSettingsPath)).To(BeZero())
+	})
+
+	It("applies the drift in the Enforce mode and waits for the reboot", func() {
+		newController(topohubv1beta1.BiosConfigSpec{
+			Attributes: desired,
+			Mode:       topohubv1beta1.BiosConfigModeEnforce,
+			ApplyTime:  "OnReset",
+		})
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStatePendingReboot))
+		Expect(host.LastApplyTime).NotTo(BeEmpty())
+		Expect(host.Drift).To(Equal([]topohubv1beta1.BiosAttributeDrift{
+			{Name: "HyperThreading", Desired: "Disabled", Current: "Enabled", Pending: "Disabled"},
+			{Name: "SriovEnable", Desired: "true", Current: "false", Pending: "true"},
+		}))
+		bios, ok := bmc.Bios("1")
+		Expect(ok).To(BeTrue())
+		Expect(bios.ApplyTime).To(Equal("OnReset"))
+		// the value is converted to the type of the current value
+		Expect(bios.Pending).To(Equal(map[string]interface{}{"HyperThreading": "Disabled", "SriovEnable": true}))
+		Expect(bmc.CountRequests(http.MethodPatch, biosSettingsPath)).To(Equal(1))
+
+		// the pending attributes are not applied again
+		lastApplyTime := host.LastApplyTime
+		host = reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStatePendingReboot))
+		Expect(host.LastApplyTime).To(Equal(lastApplyTime))
+		Expect(bmc.CountRequests(http.MethodPatch, biosSettingsPath)).To(Equal(1))
+
+		// the attributes take effect after the reboot
+		bmc.SetBios("1", emulator.Bios{
+			Attributes: map[string]interface{}{"BootMode": "Uefi", "HyperThreading": "Disabled", "SriovEnable": true, "NumaNodes": 2.0},
+		})
+		host = reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStateInSync))
+		Expect(host.Drift).To(BeEmpty())
+		Expect(host.LastApplyTime).To(Equal(lastApplyTime))
+	})
+
+	It("is in sync after the attributes are applied immediately", func() {
+		bmc.SetBios("1", emulator.Bios{
+			Attributes:          map[string]interface{}{"BootMode": "Uefi", "HyperThreading": "Enabled", "SriovEnable": false},
+			SupportedApplyTimes: []string{"Immediate", "OnReset"},
+		})
+		newController(topohubv1beta1.BiosConfigSpec{
+			Attributes: desired,
+			Mode:       topohubv1beta1.BiosConfigModeEnforce,
+			ApplyTime:  "Immediate",
+		})
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStateInSync))
+		Expect(host.LastApplyTime).NotTo(BeEmpty())
+		Expect(host.Drift).To(BeEmpty())
+		bios, _ := bmc.Bios("1")
+		Expect(bios.Attributes).To(HaveKeyWithValue("SriovEnable", true))
+		Expect(bios.Pending).To(BeEmpty())
+	})
+
+	It("fails when the bios does not support the apply time", func() {
+		bmc.SetBios("1", emulator.Bios{
+			Attributes:          map[string]interface{}{"BootMode": "Uefi", "HyperThreading": "Enabled", "SriovEnable": false},
+			SupportedApplyTimes: []string{"OnReset"},
+		})
+		newController(topohubv1beta1.BiosConfigSpec{
+			Attributes: desired,
+			Mode:       topohubv1beta1.BiosConfigModeEnforce,
+			ApplyTime:  "Immediate",
+		})
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStateFailed))
+		Expect(host.Message).To(ContainSubstring("does not support the apply time Immediate"))
+		Expect(host.LastApplyTime).To(BeEmpty())
+		Expect(bmc.CountRequests(http.MethodPatch, biosSettingsPath)).To(BeZero())
+	})
+
+	It("fails when the bios does not have the attributes", func() {
+		newController(topohubv1beta1.BiosConfigSpec{
+			Attributes: map[string]string{"HyperThreading": "Disabled", "PowerProfile": "Performance", "C1E": "Disabled"},
+			Mode:       topohubv1beta1.BiosConfigModeEnforce,
+		})
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BiosStateFailed))
+		Expect(host.Message).To(Equal("the bios does not have the attributes: C1E,PowerProfile"))
+		// nothing is applied when any attribute is missing
+		Expect(bmc.CountRequests(http.MethodPatch, biosSettingsPath)).To(BeZero())
+		biosConfig := testhost.Get(r, testBiosConfigName, &topohubv1beta1.BiosConfig{})
+		Expect(biosConfig.Status.FailedHosts).To(Equal(int32(2)))
+	})
+})
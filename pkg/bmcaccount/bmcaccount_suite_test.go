@@ -0,0 +1,13 @@
+package bmcaccount
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBmcAccount(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BmcAccount Suite")
+}
@@ -0,0 +1,475 @@
+package bmcaccount
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// BmcAccountController creates the service account on the bmc of the hosts which use the secret,
+// rotates its password, and writes the credential back to the secret
+type BmcAccountController struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	agentConfig *config.AgentConfig
+	log         *zap.SugaredLogger
+}
+
+func NewBmcAccountController(mgr ctrl.Manager, agentConfig *config.AgentConfig) (*BmcAccountController, error) {
+	return &BmcAccountController{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		agentConfig: agentConfig,
+		log:         log.Logger.Named("BmcAccountController"),
+	}, nil
+}
+
+// the failed rotation, and the host failing to be set with the service account after the rotation, are retried after
+// the interval
+const rotationRetryInterval = 30 * time.Minute
+
+type credential struct {
+	username string
+	password string
+}
+
+// hostAccount records the change on a host during the rotation, which is used to roll back
+type hostAccount struct {
+	hostStatus *topohubv1beta1.HostStatus
+	con        hoststatusData.HostConnectCon
+	uri        string
+	created    bool
+	changed    bool
+	// rollbackErr is the failure to roll back the host, whose password of the service account is unknown then
+	rollbackErr error
+}
+
+// 只有 leader 才会执行 Reconcile
+// Reconcile rotates the password when it is due, and it is requeued at the interval of updating the HostStatus
+func (r *BmcAccountController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.With("bmcaccount", req.Name)
+	logger.Debugf("Starting reconcile for BmcAccount %s", req.Name)
+
+	account := &topohubv1beta1.BmcAccount{}
+	if err := r.Get(ctx, req.NamespacedName, account); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := time.Duration(r.agentConfig.RedfishHostStatusUpdateInterval) * time.Second
+
+	status := topohubv1beta1.BmcAccountStatus{
+		LastRotationTime: account.Status.LastRotationTime,
+	}
+	if err := r.sync(ctx, logger, account, &status); err != nil {
+		logger.Errorf("Failed to sync BmcAccount %s: %v", account.Name, err)
+		status.State = topohubv1beta1.BmcAccountStateFailed
+		status.Message = err.Error()
+	}
+	status.TotalHosts = int32(len(status.Hosts))
+
+	// ignore the update time when comparing
+	status.LastUpdateTime = account.Status.LastUpdateTime
+	if reflect.DeepEqual(status, account.Status) {
+		logger.Debugf("no need to update BmcAccount %s", account.Name)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+	status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	account.Status = status
+	if err := r.Status().Update(ctx, account); err != nil {
+		logger.Errorf("Failed to update BmcAccount status: %v", err)
+		return ctrl.Result{}, err
+	}
+	logger.Infof("Successfully updated BmcAccount %s status, state %s", account.Name, status.State)
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// sync rotates the password when it is due, and reports the state of the service account on each host
+func (r *BmcAccountController) sync(ctx context.Context, logger *zap.SugaredLogger, account *topohubv1beta1.BmcAccount, status *topohubv1beta1.BmcAccountStatus) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Name: account.Spec.SecretName, Namespace: account.Spec.SecretNamespace}, secret); err != nil {
+		return fmt.Errorf("failed to get secret %s/%s: %v", account.Spec.SecretNamespace, account.Spec.SecretName, err)
+	}
+	current, err := secretCredential(secret)
+	if err != nil {
+		return err
+	}
+	admin := current
+	if account.Spec.AdminSecretName != nil {
+		namespace := account.Spec.SecretNamespace
+		if account.Spec.AdminSecretNamespace != nil {
+			namespace = *account.Spec.AdminSecretNamespace
+		}
+		adminSecret := &corev1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Name: *account.Spec.AdminSecretName, Namespace: namespace}, adminSecret); err != nil {
+			return fmt.Errorf("failed to get admin secret %s/%s: %v", namespace, *account.Spec.AdminSecretName, err)
+		}
+		if admin, err = secretCredential(adminSecret); err != nil {
+			return err
+		}
+	}
+
+	hosts, err := r.selectHosts(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// 记录每个主机上账户的 uri
+	accountUri := map[string]string{}
+	previous := map[string]topohubv1beta1.BmcAccountHostStatus{}
+	for _, item := range account.Status.Hosts {
+		accountUri[item.HostStatusName] = item.AccountUri
+		previous[item.HostStatusName] = item
+	}
+
+	// the password of the service account on some bmc is unknown after the failed rollback, and neither rotating again
+	// nor setting the account with the stale credential would fix it, so wait for the spec to be updated after the
+	// account is repaired on the bmc
+	if cond := meta.FindStatusCondition(account.Status.Conditions, topohubv1beta1.ConditionRollbackFailed); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == account.Generation {
+		logger.Debugf("the rotation stops after the failed rollback: %s", cond.Message)
+		status.State = topohubv1beta1.BmcAccountStateFailed
+		status.Message = account.Status.Message
+		status.LastFailureTime = account.Status.LastFailureTime
+		status.Hosts = account.Status.Hosts
+		status.Conditions = account.Status.Conditions
+		return nil
+	}
+
+	if len(hosts) == 0 {
+		status.State = topohubv1beta1.BmcAccountStatePending
+		status.Message = "no host uses the secret"
+		return nil
+	}
+
+	due, reason := rotationDue(account, current)
+	if !due {
+		status.State = topohubv1beta1.BmcAccountStateReady
+		for i := range hosts {
+			item := topohubv1beta1.BmcAccountHostStatus{
+				HostStatusName: hosts[i].Name,
+				AccountUri:     accountUri[hosts[i].Name],
+				State:          topohubv1beta1.BmcAccountStateReady,
+			}
+			if !hosts[i].Status.Healthy {
+				// avoid locking the admin account on the bmc by logging in too often
+				if retryLater(previous[hosts[i].Name].LastFailureTime) {
+					item = previous[hosts[i].Name]
+					status.State = topohubv1beta1.BmcAccountStateFailed
+					status.Message = "failed to set the service account on some hosts"
+					status.Hosts = append(status.Hosts, item)
+					continue
+				}
+				// maybe the host is added after the rotation, and it does not have the service account
+				uri, err := r.addHost(logger, account, &hosts[i], current, admin)
+				if err != nil {
+					item.State = topohubv1beta1.BmcAccountStateFailed
+					item.Message = err.Error()
+					item.LastFailureTime = time.Now().UTC().Format(time.RFC3339)
+					status.State = topohubv1beta1.BmcAccountStateFailed
+					status.Message = "failed to set the service account on some hosts"
+				} else {
+					item.AccountUri = uri
+				}
+			}
+			status.Hosts = append(status.Hosts, item)
+		}
+		return nil
+	}
+
+	// avoid locking the account on the bmc by retrying too often
+	if retryLater(account.Status.LastFailureTime) {
+		status.State = topohubv1beta1.BmcAccountStateFailed
+		status.Message = account.Status.Message
+		status.LastFailureTime = account.Status.LastFailureTime
+		status.Hosts = account.Status.Hosts
+		return nil
+	}
+
+	// the password could only be rotated when all hosts are accessible, or else the unreachable hosts would keep the old password
+	unhealthy := []string{}
+	for i := range hosts {
+		item := topohubv1beta1.BmcAccountHostStatus{
+			HostStatusName: hosts[i].Name,
+			AccountUri:     accountUri[hosts[i].Name],
+			State:          topohubv1beta1.BmcAccountStatePending,
+		}
+		if !hosts[i].Status.Healthy {
+			item.Message = fmt.Sprintf("hostStatus %s is not healthy", hosts[i].Name)
+			unhealthy = append(unhealthy, hosts[i].Name)
+		}
+		status.Hosts = append(status.Hosts, item)
+	}
+	if len(unhealthy) > 0 {
+		status.State = topohubv1beta1.BmcAccountStatePending
+		status.Message = fmt.Sprintf("waiting for the unhealthy hosts to rotate the password: %s", strings.Join(unhealthy, ","))
+		return nil
+	}
+
+	logger.Infof("rotate the password of the service account %s for %d hosts, reason: %s", account.Spec.Username, len(hosts), reason)
+	changes, err := r.rotate(ctx, logger, account, secret, current, admin, hosts)
+	status.Hosts = nil
+	failed := []string{}
+	for _, change := range changes {
+		item := topohubv1beta1.BmcAccountHostStatus{
+			HostStatusName: change.hostStatus.Name,
+			AccountUri:     change.uri,
+			State:          topohubv1beta1.BmcAccountStateReady,
+		}
+		if err != nil {
+			item.State = topohubv1beta1.BmcAccountStateFailed
+			if change.rollbackErr != nil {
+				item.Message = fmt.Sprintf("failed to roll back the service account, its password on the bmc is unknown: %v", change.rollbackErr)
+				failed = append(failed, change.hostStatus.Name)
+			} else {
+				item.AccountUri = accountUri[change.hostStatus.Name]
+			}
+		}
+		status.Hosts = append(status.Hosts, item)
+	}
+	if err != nil {
+		status.LastFailureTime = time.Now().UTC().Format(time.RFC3339)
+		if len(failed) > 0 {
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:               topohubv1beta1.ConditionRollbackFailed,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: account.Generation,
+				Reason:             "RollbackFailed",
+				Message: fmt.Sprintf("the password of the service account %s is unknown on %s, reset it on the bmc and update the BmcAccount to resume the rotation",
+					account.Spec.Username, strings.Join(failed, ",")),
+			})
+			return fmt.Errorf("failed to rotate the password and failed to roll back %s: %v", strings.Join(failed, ","), err)
+		}
+		return fmt.Errorf("failed to rotate the password and rolled back: %v", err)
+	}
+	status.State = topohubv1beta1.BmcAccountStateReady
+	status.LastRotationTime = time.Now().UTC().Format(time.RFC3339)
+	logger.Infof("rotated the password of the service account %s", account.Spec.Username)
+	return nil
+}
+
+// rotate sets the new password of the service account on all hosts, checks the new credential on each host, and then
+// writes it to the secret. The hosts are rolled back when any step fails
+func (r *BmcAccountController) rotate(ctx context.Context, logger *zap.SugaredLogger, account *topohubv1beta1.BmcAccount, secret *corev1.Secret,
+	current, admin credential, hosts []topohubv1beta1.HostStatus) ([]*hostAccount, error) {
+
+	length := int(account.Spec.PasswordLength)
+	if length == 0 {
+		length = 16
+	}
+	password, err := generatePassword(length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %v", err)
+	}
+	updated := credential{username: account.Spec.Username, password: password}
+
+	changes := []*hostAccount{}
+	for i := range hosts {
+		change := &hostAccount{hostStatus: &hosts[i]}
+		changes = append(changes, change)
+	}
+
+	for _, change := range changes {
+		name := change.hostStatus.Name
+		d := hoststatusData.HostCacheDatabase.Get(name)
+		if d == nil {
+			err = fmt.Errorf("failed to get connect config %s from cache", name)
+			break
+		}
+		change.con = *d
+
+		err = redfish.ConnectOnce(withCredential(change.con, admin), logger, func(c redfish.RefishClient) error {
+			var e error
+			change.uri, change.created, e = c.EnsureAccount(updated.username, updated.password, account.Spec.RoleId)
+			return e
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to set the service account on %s: %v", name, err)
+			break
+		}
+		change.changed = true
+
+		// log in with the new credential
+		err = redfish.ConnectOnce(withCredential(change.con, updated), logger, func(c redfish.RefishClient) error {
+			_, e := c.GetSystems()
+			return e
+		})
+		if err != nil {
+			err = fmt.Errorf("the new credential fails to authenticate on %s: %v", name, err)
+			break
+		}
+		logger.Infof("the new credential of the service account works on %s", name)
+	}
+
+	if err == nil {
+		// the update fails when the secret is changed by others after it is read, so the secret is written at once
+		newSecret := secret.DeepCopy()
+		if newSecret.Data == nil {
+			newSecret.Data = map[string][]byte{}
+		}
+		newSecret.Data["username"] = []byte(updated.username)
+		newSecret.Data["password"] = []byte(updated.password)
+		if newSecret.Annotations == nil {
+			newSecret.Annotations = map[string]string{}
+		}
+		newSecret.Annotations[topohubv1beta1.AnnotationBmcAccountRotateTime] = time.Now().UTC().Format(time.RFC3339)
+		if err = r.Update(ctx, newSecret); err == nil {
+			logger.Infof("updated secret %s/%s with the new credential", secret.Namespace, secret.Name)
+			return changes, nil
+		}
+		err = fmt.Errorf("failed to update secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+
+	logger.Errorf("roll back the service account on the hosts: %v", err)
+	for _, change := range changes {
+		if change.changed {
+			change.rollbackErr = r.rollback(logger, account, change, current, admin, updated)
+		}
+	}
+	return changes, err
+}
+
+// rollback deletes the created account, or restores the old password of the service account
+func (r *BmcAccountController) rollback(logger *zap.SugaredLogger, account *topohubv1beta1.BmcAccount, change *hostAccount, current, admin, updated credential) error {
+	name := change.hostStatus.Name
+	// the service account manages itself when there is no admin secret, and only the new password works now
+	operator := admin
+	if admin.username == account.Spec.Username {
+		operator = updated
+	}
+
+	err := redfish.ConnectOnce(withCredential(change.con, operator), logger, func(c redfish.RefishClient) error {
+		if change.created {
+			return c.DeleteAccount(change.uri)
+		}
+		if current.username != account.Spec.Username {
+			logger.Warnf("the old password of the existing account %s on %s is unknown, it could not be restored", account.Spec.Username, name)
+			return nil
+		}
+		_, _, e := c.EnsureAccount(current.username, current.password, account.Spec.RoleId)
+		return e
+	})
+	if err != nil {
+		logger.Errorf("Failed to roll back the service account on %s: %v", name, err)
+		return err
+	}
+	logger.Infof("rolled back the service account on %s", name)
+	return nil
+}
+
+// addHost creates the service account with the current password on the host, which is added after the rotation
+func (r *BmcAccountController) addHost(logger *zap.SugaredLogger, account *topohubv1beta1.BmcAccount, hostStatus *topohubv1beta1.HostStatus, current, admin credential) (string, error) {
+	if admin.username == current.username {
+		return "", fmt.Errorf("hostStatus %s is not healthy with the credential of the service account, and there is no admin secret to create the account", hostStatus.Name)
+	}
+	d := hoststatusData.HostCacheDatabase.Get(hostStatus.Name)
+	if d == nil {
+		return "", fmt.Errorf("failed to get connect config %s from cache", hostStatus.Name)
+	}
+
+	uri := ""
+	err := redfish.ConnectOnce(withCredential(*d, admin), logger, func(c redfish.RefishClient) error {
+		var e error
+		uri, _, e = c.EnsureAccount(current.username, current.password, account.Spec.RoleId)
+		return e
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to set the service account on %s: %v", hostStatus.Name, err)
+	}
+	logger.Infof("set the service account on the new host %s", hostStatus.Name)
+	return uri, nil
+}
+
+// retryLater returns true when the last failure happens within rotationRetryInterval
+func retryLater(lastFailureTime string) bool {
+	if len(lastFailureTime) == 0 {
+		return false
+	}
+	last, err := time.Parse(time.RFC3339, lastFailureTime)
+	return err == nil && time.Since(last) < rotationRetryInterval
+}
+
+// rotationDue returns whether to rotate the password and the reason
+func rotationDue(account *topohubv1beta1.BmcAccount, current credential) (bool, string) {
+	if current.username != account.Spec.Username {
+		return true, fmt.Sprintf("the secret holds the credential of %s", current.username)
+	}
+	if len(account.Status.LastRotationTime) == 0 {
+		return true, "the password has never been rotated"
+	}
+	if account.Spec.RotationInterval == nil || account.Spec.RotationInterval.Duration == 0 {
+		return false, ""
+	}
+	last, err := time.Parse(time.RFC3339, account.Status.LastRotationTime)
+	if err != nil {
+		return true, fmt.Sprintf("invalid last rotation time %s", account.Status.LastRotationTime)
+	}
+	if time.Since(last) < account.Spec.RotationInterval.Duration {
+		return false, ""
+	}
+	return true, fmt.Sprintf("the password is rotated at %s", account.Status.LastRotationTime)
+}
+
+// selectHosts returns the HostStatus which use the secret
+func (r *BmcAccountController) selectHosts(ctx context.Context, account *topohubv1beta1.BmcAccount) ([]topohubv1beta1.HostStatus, error) {
+	hostStatusList := &topohubv1beta1.HostStatusList{}
+	if err := r.List(ctx, hostStatusList); err != nil {
+		return nil, err
+	}
+	result := []topohubv1beta1.HostStatus{}
+	for _, item := range hostStatusList.Items {
+		if item.Status.Basic.SecretName == account.Spec.SecretName && item.Status.Basic.SecretNamespace == account.Spec.SecretNamespace {
+			result = append(result, item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func secretCredential(secret *corev1.Secret) (credential, error) {
+	username, ok := secret.Data["username"]
+	if !ok || len(username) == 0 {
+		return credential{}, fmt.Errorf("secret %s/%s does not have the username", secret.Namespace, secret.Name)
+	}
+	password, ok := secret.Data["password"]
+	if !ok || len(password) == 0 {
+		return credential{}, fmt.Errorf("secret %s/%s does not have the password", secret.Namespace, secret.Name)
+	}
+	return credential{username: string(username), password: string(password)}, nil
+}
+
+func withCredential(con hoststatusData.HostConnectCon, c credential) hoststatusData.HostConnectCon {
+	con.Username = c.username
+	con.Password = c.password
+	return con
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *BmcAccountController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topohubv1beta1.BmcAccount{}).
+		// the status is updated by itself, and the rotation is checked periodically
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
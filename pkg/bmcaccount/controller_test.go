@@ -0,0 +1,291 @@
+package bmcaccount
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const (
+	testBmcAccountName  = "default"
+	testSecretName      = "topohub-auth"
+	testAdminSecretName = "admin-auth"
+	testNamespace       = "topohub"
+	testServiceAccount  = "topohub"
+	accountsPath        = "/redfish/v1/AccountService/Accounts"
+	sessionsPath        = "/redfish/v1/SessionService/Sessions"
+)
+
+// failingSecretWriter fails to update the secrets, like the secret is changed by others after it is read
+type failingSecretWriter struct {
+	client.Client
+}
+
+func (c failingSecretWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		return fmt.Errorf("the object has been modified")
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func secret(name, username, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data: map[string][]byte{
+			"username": []byte(username),
+			"password": []byte(password),
+		},
+	}
+}
+
+var _ = Describe("BmcAccountController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *BmcAccountController
+
+	// newController creates the controller with the HostStatus of the emulators using the secret, and the BmcAccount
+	// with the admin secret
+	newController := func(serviceSecret *corev1.Secret, status topohubv1beta1.BmcAccountStatus, hosts ...*topohubv1beta1.HostStatus) {
+		adminSecretName := testAdminSecretName
+		objects := []client.Object{
+			serviceSecret,
+			secret(testAdminSecretName, testhost.Username, testhost.Password),
+			&topohubv1beta1.BmcAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: testBmcAccountName},
+				Spec: topohubv1beta1.BmcAccountSpec{
+					SecretName:      testSecretName,
+					SecretNamespace: testNamespace,
+					Username:        testServiceAccount,
+					RoleId:          "Administrator",
+					AdminSecretName: &adminSecretName,
+				},
+				Status: status,
+			},
+		}
+		for _, host := range hosts {
+			objects = append(objects, host)
+		}
+		c := bmc.Build(objects...)
+		r = &BmcAccountController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{RedfishHostStatusUpdateInterval: 60},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	// addEmulator starts another emulator as the HostStatus with the name, which uses the secret
+	addEmulator := func(name string) (*emulator.Server, *topohubv1beta1.HostStatus) {
+		other := emulator.NewHTTP()
+		other.SetCredential(testhost.Username, testhost.Password)
+		basic := bmc.Basic
+		basic.IpAddr = other.Host()
+		basic.Port = other.Port()
+		hoststatusData.HostCacheDatabase.Add(name, hoststatusData.HostConnectCon{
+			Info:     &basic,
+			Username: testhost.Username,
+			Password: testhost.Password,
+		})
+		DeferCleanup(func() {
+			redfish.CacheClient.Delete(other.Host())
+			hoststatusData.HostCacheDatabase.Delete(name)
+			other.Close()
+		})
+		hostStatus := bmc.HostStatus()
+		hostStatus.Name = name
+		hostStatus.Status.Basic = basic
+		return other, hostStatus
+	}
+
+	reconcile := func() topohubv1beta1.BmcAccountStatus {
+		testhost.Reconcile(r, testBmcAccountName)
+		return testhost.Get(r, testBmcAccountName, &topohubv1beta1.BmcAccount{}).Status
+	}
+
+	getSecret := func() *corev1.Secret {
+		s := &corev1.Secret{}
+		Expect(r.Get(context.Background(), client.ObjectKey{Name: testSecretName, Namespace: testNamespace}, s)).To(Succeed())
+		return s
+	}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(false)
+		bmc.Basic.SecretName = testSecretName
+		bmc.Basic.SecretNamespace = testNamespace
+	})
+
+	It("creates the service account and writes its credential to the secret", func() {
+		newController(secret(testSecretName, testhost.Username, testhost.Password), topohubv1beta1.BmcAccountStatus{}, bmc.HostStatus())
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateReady))
+		Expect(status.LastRotationTime).NotTo(BeEmpty())
+		Expect(status.Hosts).To(ConsistOf(topohubv1beta1.BmcAccountHostStatus{
+			HostStatusName: testhost.HostStatusName,
+			AccountUri:     accountsPath + "/1",
+			State:          topohubv1beta1.BmcAccountStateReady,
+		}))
+
+		accounts := bmc.Accounts()
+		Expect(accounts).To(HaveLen(1))
+		Expect(accounts[0].UserName).To(Equal(testServiceAccount))
+		s := getSecret()
+		Expect(string(s.Data["username"])).To(Equal(testServiceAccount))
+		Expect(string(s.Data["password"])).To(Equal(accounts[0].Password))
+		Expect(s.Annotations).To(HaveKey(topohubv1beta1.AnnotationBmcAccountRotateTime))
+	})
+
+	It("rolls back the hosts when the service account fails to be set on a host", func() {
+		other, otherHostStatus := addEmulator("emulator2")
+		other.Fail(http.MethodPost, accountsPath, http.StatusInternalServerError, 0)
+		newController(secret(testSecretName, testhost.Username, testhost.Password), topohubv1beta1.BmcAccountStatus{}, bmc.HostStatus(), otherHostStatus)
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateFailed))
+		Expect(status.Message).To(ContainSubstring("emulator2"))
+		Expect(status.LastFailureTime).NotTo(BeEmpty())
+		Expect(status.LastRotationTime).To(BeEmpty())
+		Expect(status.Hosts).To(HaveLen(2))
+		Expect(status.Hosts).To(HaveEach(HaveField("State", topohubv1beta1.BmcAccountStateFailed)))
+
+		// the account created on the first host is deleted, and the secret is not changed
+		Expect(bmc.Accounts()).To(BeEmpty())
+		Expect(string(getSecret().Data["username"])).To(Equal(testhost.Username))
+
+		// the rotation is not retried within the interval
+		logins := bmc.CountRequests(http.MethodPost, sessionsPath)
+		Expect(reconcile().State).To(Equal(topohubv1beta1.BmcAccountStateFailed))
+		Expect(bmc.CountRequests(http.MethodPost, sessionsPath)).To(Equal(logins))
+	})
+
+	It("records the host failing to be rolled back, and stops the rotation until the spec is updated", func() {
+		other, otherHostStatus := addEmulator("emulator2")
+		other.Fail(http.MethodPost, accountsPath, http.StatusInternalServerError, 0)
+		// the account created on the first host could neither be deleted nor emptied
+		bmc.Fail(http.MethodDelete, accountsPath, http.StatusInternalServerError, 0)
+		bmc.Fail(http.MethodPatch, accountsPath, http.StatusInternalServerError, 0)
+		newController(secret(testSecretName, testhost.Username, testhost.Password), topohubv1beta1.BmcAccountStatus{}, bmc.HostStatus(), otherHostStatus)
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateFailed))
+		Expect(status.Message).To(ContainSubstring("failed to roll back " + testhost.HostStatusName))
+		cond := meta.FindStatusCondition(status.Conditions, topohubv1beta1.ConditionRollbackFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Message).To(ContainSubstring(testhost.HostStatusName))
+		Expect(cond.Message).NotTo(ContainSubstring("emulator2"))
+		Expect(status.Hosts).To(HaveLen(2))
+		Expect(status.Hosts).To(HaveEach(HaveField("State", topohubv1beta1.BmcAccountStateFailed)))
+		// the account left on the first host is recorded
+		Expect(status.Hosts[0].HostStatusName).To(Equal(testhost.HostStatusName))
+		Expect(status.Hosts[0].AccountUri).To(Equal(accountsPath + "/1"))
+		Expect(status.Hosts[0].Message).To(ContainSubstring("its password on the bmc is unknown"))
+		Expect(status.Hosts[1].Message).To(BeEmpty())
+		Expect(bmc.Accounts()).To(HaveLen(1))
+		Expect(string(getSecret().Data["username"])).To(Equal(testhost.Username))
+
+		// the rotation is not retried with the stale credential even after the retry interval
+		account := testhost.Get(r, testBmcAccountName, &topohubv1beta1.BmcAccount{})
+		account.Status.LastFailureTime = time.Now().Add(-rotationRetryInterval).UTC().Format(time.RFC3339)
+		Expect(r.Status().Update(context.Background(), account)).To(Succeed())
+		logins := bmc.CountRequests(http.MethodPost, sessionsPath)
+		Expect(reconcile().Conditions).To(Equal(status.Conditions))
+		Expect(bmc.CountRequests(http.MethodPost, sessionsPath)).To(Equal(logins))
+
+		// the bmc is repaired and the spec is updated, the fake client does not bump the generation itself
+		bmc.Reset()
+		other.Reset()
+		account = testhost.Get(r, testBmcAccountName, &topohubv1beta1.BmcAccount{})
+		account.Generation++
+		Expect(r.Update(context.Background(), account)).To(Succeed())
+		status = reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateReady))
+		Expect(status.Conditions).To(BeEmpty())
+	})
+
+	It("restores the old password when the secret fails to be written", func() {
+		bmc.AddAccount(emulator.Account{UserName: testServiceAccount, Password: "old-password", RoleId: "Administrator", Enabled: true})
+		newController(secret(testSecretName, testServiceAccount, "old-password"), topohubv1beta1.BmcAccountStatus{}, bmc.HostStatus())
+		r.Client = failingSecretWriter{Client: r.Client}
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateFailed))
+		Expect(status.Message).To(ContainSubstring("failed to update secret"))
+		Expect(bmc.Accounts()).To(ConsistOf(HaveField("Password", "old-password")))
+		Expect(string(getSecret().Data["password"])).To(Equal("old-password"))
+	})
+
+	It("backs off the host which fails to be set with the service account after the rotation", func() {
+		hostStatus := bmc.HostStatus()
+		hostStatus.Status.Healthy = false
+		rotated := topohubv1beta1.BmcAccountStatus{
+			State:            topohubv1beta1.BmcAccountStateReady,
+			LastRotationTime: time.Now().UTC().Format(time.RFC3339),
+		}
+		newController(secret(testSecretName, testServiceAccount, "current-password"), rotated, hostStatus)
+		bmc.Fail(http.MethodPost, accountsPath, http.StatusInternalServerError, 1)
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateFailed))
+		Expect(status.Hosts).To(HaveLen(1))
+		Expect(status.Hosts[0].State).To(Equal(topohubv1beta1.BmcAccountStateFailed))
+		Expect(status.Hosts[0].LastFailureTime).NotTo(BeEmpty())
+
+		// the admin credential is not used again within the interval
+		logins := bmc.CountRequests(http.MethodPost, sessionsPath)
+		Expect(reconcile()).To(Equal(status))
+		Expect(bmc.CountRequests(http.MethodPost, sessionsPath)).To(Equal(logins))
+
+		// retry after the interval
+		account := testhost.Get(r, testBmcAccountName, &topohubv1beta1.BmcAccount{})
+		account.Status.Hosts[0].LastFailureTime = time.Now().Add(-rotationRetryInterval).UTC().Format(time.RFC3339)
+		Expect(r.Status().Update(context.Background(), account)).To(Succeed())
+		status = reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.BmcAccountStateReady))
+		Expect(status.Hosts[0].AccountUri).To(Equal(accountsPath + "/1"))
+		Expect(bmc.Accounts()).To(ConsistOf(HaveField("Password", "current-password")))
+	})
+})
+
+var _ = Describe("rotationDue", Label("unitest"), func() {
+	hour := &metav1.Duration{Duration: time.Hour}
+	ago := func(d time.Duration) string {
+		return time.Now().Add(-d).UTC().Format(time.RFC3339)
+	}
+
+	DescribeTable("decides whether to rotate the password",
+		func(username, lastRotationTime string, interval *metav1.Duration, expected bool) {
+			account := &topohubv1beta1.BmcAccount{
+				Spec:   topohubv1beta1.BmcAccountSpec{Username: testServiceAccount, RotationInterval: interval},
+				Status: topohubv1beta1.BmcAccountStatus{LastRotationTime: lastRotationTime},
+			}
+			due, reason := rotationDue(account, credential{username: username, password: "password"})
+			Expect(due).To(Equal(expected))
+			if due {
+				Expect(reason).NotTo(BeEmpty())
+			}
+		},
+		Entry("the secret holds the admin credential", "admin", ago(time.Minute), hour, true),
+		Entry("never rotated", testServiceAccount, "", nil, true),
+		Entry("no rotation interval", testServiceAccount, ago(1000*time.Hour), nil, false),
+		Entry("zero rotation interval", testServiceAccount, ago(1000*time.Hour), &metav1.Duration{}, false),
+		Entry("within the rotation interval", testServiceAccount, ago(time.Minute), hour, false),
+		Entry("after the rotation interval", testServiceAccount, ago(2*time.Hour), hour, true),
+		Entry("invalid last rotation time", testServiceAccount, "yesterday", hour, true),
+	)
+})
@@ -0,0 +1,53 @@
+package bmcaccount
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// the special characters are limited, since some bmc rejects the quotes and the backslash
+const (
+	lowerChars   = "abcdefghijkmnopqrstuvwxyz"
+	upperChars   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digitChars   = "23456789"
+	specialChars = "!@#%^*-_+="
+)
+
+// generatePassword returns a random password, which contains the lower and upper letters, digits and special characters
+// to meet the password policy of the bmc
+func generatePassword(length int) (string, error) {
+	classes := []string{lowerChars, upperChars, digitChars, specialChars}
+	all := lowerChars + upperChars + digitChars + specialChars
+
+	result := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		chars := all
+		if i < len(classes) {
+			chars = classes[i]
+		}
+		c, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		result = append(result, c)
+	}
+
+	// shuffle, so the characters of each class are not at the fixed position
+	for i := len(result) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		result[i], result[j] = result[j], result[i]
+	}
+	return string(result), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
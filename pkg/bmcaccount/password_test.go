@@ -0,0 +1,35 @@
+package bmcaccount
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("generatePassword", Label("unitest"), func() {
+	It("contains the characters of every class", func() {
+		all := lowerChars + upperChars + digitChars + specialChars
+		for _, length := range []int{8, 16, 32} {
+			password, err := generatePassword(length)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(password).To(HaveLen(length))
+			for _, chars := range []string{lowerChars, upperChars, digitChars, specialChars} {
+				Expect(strings.ContainsAny(password, chars)).To(BeTrue(), "%s has no character of %s", password, chars)
+			}
+			for _, c := range password {
+				Expect(all).To(ContainSubstring(string(c)))
+			}
+		}
+	})
+
+	It("generates the different passwords", func() {
+		passwords := map[string]bool{}
+		for i := 0; i < 100; i++ {
+			password, err := generatePassword(16)
+			Expect(err).NotTo(HaveOccurred())
+			passwords[password] = true
+		}
+		Expect(passwords).To(HaveLen(100))
+	})
+})
@@ -0,0 +1,13 @@
+package bmccertificate
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBmcCertificate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BmcCertificate Suite")
+}
@@ -0,0 +1,322 @@
+package bmccertificate
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	"github.com/infrastructure-io/topohub/pkg/hoststatus"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+const (
+	defaultDuration    = 365 * 24 * time.Hour
+	defaultRenewBefore = 30 * 24 * time.Hour
+)
+
+// BmcCertificateController replaces the https certificate of the bmc with the certificate issued by the CA, and renews it
+type BmcCertificateController struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	agentConfig *config.AgentConfig
+	log         *zap.SugaredLogger
+}
+
+func NewBmcCertificateController(mgr ctrl.Manager, agentConfig *config.AgentConfig) (*BmcCertificateController, error) {
+	return &BmcCertificateController{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		agentConfig: agentConfig,
+		log:         log.Logger.Named("BmcCertificateController"),
+	}, nil
+}
+
+// 只有 leader 才会执行 Reconcile
+// Reconcile checks the certificate of every selected host, and it is requeued at the interval of updating the HostStatus
+// to renew the expiring certificate
+func (r *BmcCertificateController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.With("bmccertificate", req.Name)
+	logger.Debugf("Starting reconcile for BmcCertificate %s", req.Name)
+
+	bmcCertificate := &topohubv1beta1.BmcCertificate{}
+	if err := r.Get(ctx, req.NamespacedName, bmcCertificate); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := time.Duration(r.agentConfig.RedfishHostStatusUpdateInterval) * time.Second
+
+	hosts, missing, err := r.selectHosts(ctx, bmcCertificate)
+	if err != nil {
+		logger.Errorf("Failed to select hosts: %v", err)
+		return ctrl.Result{}, err
+	}
+	// the hosts are reported as failed when the CA is not available, or the certificate would be renewed once it is issued
+	ca, configErr := r.loadIssuer(ctx, bmcCertificate)
+	if configErr == nil && duration(&bmcCertificate.Spec) <= renewBefore(&bmcCertificate.Spec) {
+		configErr = fmt.Errorf("the duration %s is not longer than the renewBefore %s", duration(&bmcCertificate.Spec), renewBefore(&bmcCertificate.Spec))
+	}
+	if configErr != nil {
+		logger.Errorf("Invalid BmcCertificate %s: %v", bmcCertificate.Name, configErr)
+	}
+
+	previous := map[string]topohubv1beta1.BmcCertificateHostStatus{}
+	for _, item := range bmcCertificate.Status.Hosts {
+		previous[item.HostStatusName] = item
+	}
+
+	status := topohubv1beta1.BmcCertificateStatus{}
+	for _, name := range missing {
+		status.Hosts = append(status.Hosts, topohubv1beta1.BmcCertificateHostStatus{
+			HostStatusName: name,
+			State:          topohubv1beta1.BmcCertificateStateFailed,
+			Message:        fmt.Sprintf("hostStatus %s is not found", name),
+		})
+	}
+	for i := range hosts {
+		if configErr != nil {
+			status.Hosts = append(status.Hosts, topohubv1beta1.BmcCertificateHostStatus{
+				HostStatusName: hosts[i].Name,
+				State:          topohubv1beta1.BmcCertificateStateFailed,
+				Message:        configErr.Error(),
+				LastRenewTime:  previous[hosts[i].Name].LastRenewTime,
+			})
+			continue
+		}
+		status.Hosts = append(status.Hosts, r.syncHost(ctx, logger, bmcCertificate, ca, &hosts[i], previous[hosts[i].Name]))
+	}
+
+	for _, item := range status.Hosts {
+		status.TotalHosts++
+		switch item.State {
+		case topohubv1beta1.BmcCertificateStateReady:
+			status.ReadyHosts++
+		case topohubv1beta1.BmcCertificateStatePending:
+			status.PendingHosts++
+		default:
+			status.FailedHosts++
+		}
+	}
+
+	// ignore the update time when comparing
+	status.LastUpdateTime = bmcCertificate.Status.LastUpdateTime
+	if reflect.DeepEqual(status, bmcCertificate.Status) {
+		logger.Debugf("no need to update BmcCertificate %s", bmcCertificate.Name)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+	status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	bmcCertificate.Status = status
+	if err := r.Status().Update(ctx, bmcCertificate); err != nil {
+		logger.Errorf("Failed to update BmcCertificate status: %v", err)
+		return ctrl.Result{}, err
+	}
+	logger.Debugf("Successfully updated BmcCertificate %s status, total %d, ready %d, pending %d, failed %d",
+		bmcCertificate.Name, status.TotalHosts, status.ReadyHosts, status.PendingHosts, status.FailedHosts)
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// loadIssuer reads the CA from the issuer secret
+func (r *BmcCertificateController) loadIssuer(ctx context.Context, bmcCertificate *topohubv1beta1.BmcCertificate) (*issuer, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: bmcCertificate.Spec.IssuerSecretName, Namespace: bmcCertificate.Spec.IssuerSecretNamespace}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get issuer secret %s: %v", key, err)
+	}
+	return newIssuer(secret)
+}
+
+// selectHosts returns the HostStatus selected by the names and the label selector, and the names which are not found
+func (r *BmcCertificateController) selectHosts(ctx context.Context, bmcCertificate *topohubv1beta1.BmcCertificate) ([]topohubv1beta1.HostStatus, []string, error) {
+	selected := map[string]topohubv1beta1.HostStatus{}
+	missing := []string{}
+
+	for _, name := range bmcCertificate.Spec.HostStatusNames {
+		hostStatus := topohubv1beta1.HostStatus{}
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &hostStatus); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return nil, nil, err
+		}
+		selected[name] = hostStatus
+	}
+
+	if bmcCertificate.Spec.HostSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(bmcCertificate.Spec.HostSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid hostSelector: %v", err)
+		}
+		hostStatusList := &topohubv1beta1.HostStatusList{}
+		if err := r.List(ctx, hostStatusList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range hostStatusList.Items {
+			selected[item.Name] = item
+		}
+	}
+
+	result := []topohubv1beta1.HostStatus{}
+	for _, item := range selected {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	sort.Strings(missing)
+	return result, missing, nil
+}
+
+// syncHost reads the https certificate of the host, and replaces it with the certificate issued by the CA in the Enforce mode
+func (r *BmcCertificateController) syncHost(ctx context.Context, logger *zap.SugaredLogger, bmcCertificate *topohubv1beta1.BmcCertificate, ca *issuer,
+	hostStatus *topohubv1beta1.HostStatus, previous topohubv1beta1.BmcCertificateHostStatus) topohubv1beta1.BmcCertificateHostStatus {
+	item := topohubv1beta1.BmcCertificateHostStatus{
+		HostStatusName: hostStatus.Name,
+		State:          topohubv1beta1.BmcCertificateStateFailed,
+		LastRenewTime:  previous.LastRenewTime,
+	}
+	failed := func(message string) topohubv1beta1.BmcCertificateHostStatus {
+		item.State = topohubv1beta1.BmcCertificateStateFailed
+		item.Message = message
+		return item
+	}
+
+	if !hostStatus.Status.Healthy {
+		return failed(fmt.Sprintf("hostStatus %s is not healthy", hostStatus.Name))
+	}
+	d := hoststatusData.HostCacheDatabase.Get(hostStatus.Name)
+	if d == nil {
+		return failed(fmt.Sprintf("failed to get connect config %s from cache", hostStatus.Name))
+	}
+	c, err := redfish.NewClient(*d, logger)
+	if err != nil {
+		return failed(err.Error())
+	}
+	collectionUri, certificates, err := c.GetHttpsCertificates()
+	if err != nil {
+		logger.Warnf("Failed to get https certificates of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	if len(certificates) == 0 {
+		return failed("the bmc does not report the https certificate")
+	}
+
+	current := certificates[0]
+	item.CertificateUri = current.Uri
+	ipAddr := hostStatus.Status.Basic.IpAddr
+	spec := &bmcCertificate.Spec
+	cert, err := parseCertificate(current.CertificateString)
+	if err == nil {
+		item.Fingerprint = redfish.Fingerprint(cert)
+		item.NotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+
+	reason := renewReason(ca, cert, err, ipAddr, renewBefore(spec))
+	switch {
+	case len(reason) == 0:
+		item.State = topohubv1beta1.BmcCertificateStateReady
+		return item
+	case spec.Mode == topohubv1beta1.BmcCertificateModeMonitor:
+		item.State = topohubv1beta1.BmcCertificateStatePending
+		item.Message = reason
+		return item
+	}
+
+	request := redfish.CertificateSigningRequest{
+		CommonName:         ipAddr,
+		AlternativeNames:   append([]string{ipAddr}, spec.AlternativeNames...),
+		Organization:       spec.Organization,
+		OrganizationalUnit: spec.OrganizationalUnit,
+		City:               spec.City,
+		State:              spec.State,
+		Country:            spec.Country,
+		KeyPairAlgorithm:   spec.KeyPairAlgorithm,
+	}
+	if spec.KeyBitLength != nil {
+		request.KeyBitLength = *spec.KeyBitLength
+	}
+	csr, err := c.GenerateCSR(collectionUri, request)
+	if err != nil {
+		logger.Errorf("Failed to generate the certificate signing request of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	signedPem, signed, err := ca.sign(csr, ipAddr, duration(spec))
+	if err != nil {
+		logger.Errorf("Failed to sign the certificate of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	if err := c.ReplaceCertificate(current.Uri, signedPem); err != nil {
+		logger.Errorf("Failed to replace the certificate of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	logger.Infof("Replaced the certificate %s of %s, %s, the new certificate expires at %s",
+		current.Uri, hostStatus.Name, reason, signed.NotAfter.UTC().Format(time.RFC3339))
+
+	item.Fingerprint = redfish.Fingerprint(signed)
+	item.NotAfter = signed.NotAfter.UTC().Format(time.RFC3339)
+	item.LastRenewTime = time.Now().UTC().Format(time.RFC3339)
+	// or else the host which trusts the certificate on first use refuses the new certificate
+	if err := hoststatus.RecordReplacedCertificate(ctx, r.Client, hostStatus.Name, redfish.NewCertificateInfo(signed)); err != nil {
+		logger.Errorf("Failed to trust the new certificate of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	item.State = topohubv1beta1.BmcCertificateStateReady
+	return item
+}
+
+// renewReason describes why the certificate of the bmc needs to be replaced, it is empty when the certificate is issued
+// by the CA for the ip address of the bmc and it is not expiring
+func renewReason(ca *issuer, cert *x509.Certificate, parseErr error, ipAddr string, renewBefore time.Duration) string {
+	if parseErr != nil {
+		return fmt.Sprintf("failed to parse the certificate: %v", parseErr)
+	}
+	if time.Until(cert.NotAfter) < renewBefore {
+		return fmt.Sprintf("the certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if err := ca.verify(cert, ipAddr); err != nil {
+		if _, ok := err.(x509.HostnameError); ok {
+			return fmt.Sprintf("the certificate is not issued for %s", ipAddr)
+		}
+		return fmt.Sprintf("the certificate is not issued by the CA %s", ca.certificate.Subject.CommonName)
+	}
+	return ""
+}
+
+func duration(spec *topohubv1beta1.BmcCertificateSpec) time.Duration {
+	if spec.Duration == nil || spec.Duration.Duration <= 0 {
+		return defaultDuration
+	}
+	return spec.Duration.Duration
+}
+
+func renewBefore(spec *topohubv1beta1.BmcCertificateSpec) time.Duration {
+	if spec.RenewBefore == nil {
+		return defaultRenewBefore
+	}
+	return spec.RenewBefore.Duration
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *BmcCertificateController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topohubv1beta1.BmcCertificate{}).
+		// the status is updated by itself, and the hosts are checked periodically
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
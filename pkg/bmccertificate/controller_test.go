@@ -0,0 +1,227 @@
+package bmccertificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const (
+	testBmcCertificateName = "rack1"
+	testIssuerSecretName   = "bmc-ca"
+	testNamespace          = "topohub"
+	generateCSRPath        = "/redfish/v1/CertificateService/Actions/CertificateService.GenerateCSR"
+)
+
+// newCA creates the self-signed CA, and returns the issuer secret holding it
+func newCA() (*x509.Certificate, *ecdsa.PrivateKey, *corev1.Secret) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "topohub-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testIssuerSecretName, Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}),
+		},
+	}
+	return cert, key, secret
+}
+
+var _ = Describe("BmcCertificateController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *BmcCertificateController
+	var caCert *x509.Certificate
+	var caKey *ecdsa.PrivateKey
+	var caSecret *corev1.Secret
+
+	// newController creates the controller with the HostStatus of the emulator, which is selected by the cluster label,
+	// and the BmcCertificate which also names a missing HostStatus
+	newController := func(spec topohubv1beta1.BmcCertificateSpec, withCA bool) {
+		hostStatus := bmc.HostStatus()
+		hostStatus.Labels = map[string]string{topohubv1beta1.LabelClusterName: "cluster1"}
+		spec.HostStatusNames = []string{"missing"}
+		spec.HostSelector = &metav1.LabelSelector{MatchLabels: map[string]string{topohubv1beta1.LabelClusterName: "cluster1"}}
+		spec.IssuerSecretName = testIssuerSecretName
+		spec.IssuerSecretNamespace = testNamespace
+		objects := []client.Object{hostStatus, &topohubv1beta1.BmcCertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: testBmcCertificateName},
+			Spec:       spec,
+		}}
+		if withCA {
+			objects = append(objects, caSecret)
+		}
+		c := bmc.Build(objects...)
+		r = &BmcCertificateController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{RedfishHostStatusUpdateInterval: 60},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	// reconcile returns the status of the emulator host, the first host is the missing one
+	reconcile := func() topohubv1beta1.BmcCertificateHostStatus {
+		testhost.Reconcile(r, testBmcCertificateName)
+		bmcCertificate := testhost.Get(r, testBmcCertificateName, &topohubv1beta1.BmcCertificate{})
+		Expect(bmcCertificate.Status.TotalHosts).To(Equal(int32(2)))
+		Expect(bmcCertificate.Status.Hosts).To(HaveLen(2))
+		Expect(bmcCertificate.Status.Hosts[0].HostStatusName).To(Equal("missing"))
+		Expect(bmcCertificate.Status.Hosts[0].State).To(Equal(topohubv1beta1.BmcCertificateStateFailed))
+		Expect(bmcCertificate.Status.Hosts[1].HostStatusName).To(Equal(testhost.HostStatusName))
+		return bmcCertificate.Status.Hosts[1]
+	}
+
+	// verify checks the certificate installed on the emulator is issued by the CA for its ip address
+	verify := func() *x509.Certificate {
+		block, _ := pem.Decode([]byte(bmc.HttpsCertificate()))
+		Expect(block).NotTo(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		roots := x509.NewCertPool()
+		roots.AddCert(caCert)
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: bmc.Host(), Roots: roots})
+		Expect(err).NotTo(HaveOccurred())
+		return cert
+	}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(true)
+		caCert, caKey, caSecret = newCA()
+	})
+
+	It("replaces the self-signed certificate in the Enforce mode", func() {
+		newController(topohubv1beta1.BmcCertificateSpec{
+			Organization:     "topohub",
+			AlternativeNames: []string{"bmc.example.com"},
+			Mode:             topohubv1beta1.BmcCertificateModeEnforce,
+		}, true)
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcCertificateStateReady))
+		Expect(host.Message).To(BeEmpty())
+		Expect(host.CertificateUri).To(Equal("/redfish/v1/Managers/bmc/NetworkProtocol/HTTPS/Certificates/1"))
+		Expect(host.LastRenewTime).NotTo(BeEmpty())
+		cert := verify()
+		Expect(cert.Subject.CommonName).To(Equal(bmc.Host()))
+		Expect(cert.Subject.Organization).To(ConsistOf("topohub"))
+		Expect(cert.DNSNames).To(ConsistOf("bmc.example.com"))
+		Expect(host.Fingerprint).To(Equal(redfish.Fingerprint(cert)))
+		Expect(host.NotAfter).To(Equal(cert.NotAfter.UTC().Format(time.RFC3339)))
+		Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(defaultDuration), time.Minute))
+
+		// the certificate issued by the CA is kept
+		Expect(reconcile()).To(Equal(host))
+		Expect(bmc.CountRequests(http.MethodPost, generateCSRPath)).To(Equal(1))
+	})
+
+	It("trusts the new certificate for the host which trusts the certificate on first use", func() {
+		known := redfish.NewCertificateInfo(bmc.Certificate())
+		bmc.TLS = &hoststatusData.TLSTrust{KnownFingerprint: known.Fingerprint}
+		newController(topohubv1beta1.BmcCertificateSpec{
+			Mode: topohubv1beta1.BmcCertificateModeEnforce,
+		}, true)
+		hostStatus := testhost.Get(r, testhost.HostStatusName, &topohubv1beta1.HostStatus{})
+		hostStatus.Status.Certificate = known
+		Expect(r.Status().Update(context.Background(), hostStatus)).To(Succeed())
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcCertificateStateReady))
+		Expect(host.Fingerprint).NotTo(Equal(known.Fingerprint))
+		hostStatus = testhost.Get(r, testhost.HostStatusName, &topohubv1beta1.HostStatus{})
+		Expect(hostStatus.Status.Certificate.Fingerprint).To(Equal(host.Fingerprint))
+		Expect(hoststatusData.HostCacheDatabase.Get(testhost.HostStatusName).TLS.KnownFingerprint).To(Equal(host.Fingerprint))
+
+		// the new connection verifies the new certificate
+		redfish.CacheClient.Delete(bmc.Host())
+		Expect(reconcile()).To(Equal(host))
+	})
+
+	It("reports the certificate which is not issued by the CA in the Monitor mode", func() {
+		newController(topohubv1beta1.BmcCertificateSpec{
+			Mode: topohubv1beta1.BmcCertificateModeMonitor,
+		}, true)
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcCertificateStatePending))
+		Expect(host.Message).To(Equal("the certificate is not issued by the CA topohub-ca"))
+		Expect(host.Fingerprint).To(Equal(redfish.Fingerprint(bmc.Certificate())))
+		Expect(bmc.CountRequests(http.MethodPost, generateCSRPath)).To(Equal(0))
+	})
+
+	It("renews the certificate which expires soon", func() {
+		// the certificate issued by the CA expires in 10 days
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: bmc.Host()},
+			IPAddresses:  []net.IP{net.ParseIP(bmc.Host())},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(10 * 24 * time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		Expect(err).NotTo(HaveOccurred())
+		bmc.SetHttpsCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+
+		newController(topohubv1beta1.BmcCertificateSpec{
+			Duration:    &metav1.Duration{Duration: 90 * 24 * time.Hour},
+			RenewBefore: &metav1.Duration{Duration: 15 * 24 * time.Hour},
+			Mode:        topohubv1beta1.BmcCertificateModeEnforce,
+		}, true)
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcCertificateStateReady))
+		cert := verify()
+		Expect(cert.SerialNumber).NotTo(Equal(big.NewInt(2)))
+		Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(90*24*time.Hour), time.Minute))
+	})
+
+	It("fails the hosts when the CA is not found", func() {
+		newController(topohubv1beta1.BmcCertificateSpec{
+			Mode: topohubv1beta1.BmcCertificateModeEnforce,
+		}, false)
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcCertificateStateFailed))
+		Expect(host.Message).To(ContainSubstring("failed to get issuer secret topohub/bmc-ca"))
+		Expect(bmc.CountRequests(http.MethodPost, generateCSRPath)).To(Equal(0))
+	})
+})
@@ -0,0 +1,109 @@
+package bmccertificate
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// issuer is the CA which signs the certificate of the bmc
+type issuer struct {
+	certificate *x509.Certificate
+	key         crypto.Signer
+}
+
+// newIssuer loads the CA from the tls.crt and the tls.key of the secret
+func newIssuer(secret *corev1.Secret) (*issuer, error) {
+	pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA in secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	certificate, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA in secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	if !certificate.IsCA {
+		return nil, fmt.Errorf("the certificate in secret %s/%s is not a CA", secret.Namespace, secret.Name)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("the key in secret %s/%s could not sign", secret.Namespace, secret.Name)
+	}
+	return &issuer{certificate: certificate, key: key}, nil
+}
+
+// verify checks whether the certificate of the bmc is issued by the CA for the ip address
+func (i *issuer) verify(certificate *x509.Certificate, ipAddr string) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(i.certificate)
+	_, err := certificate.Verify(x509.VerifyOptions{
+		DNSName:   ipAddr,
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return err
+}
+
+// sign issues the certificate in PEM for the certificate signing request generated by the bmc.
+// The subject and the alternative names are taken from the request, and the ip address of the bmc is always included
+func (i *issuer) sign(csrPem string, ipAddr string, duration time.Duration) (string, *x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(csrPem))
+	if block == nil {
+		return "", nil, fmt.Errorf("the certificate signing request is not in PEM")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid certificate signing request: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return "", nil, fmt.Errorf("invalid signature of the certificate signing request: %v", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", nil, err
+	}
+	ipAddresses := csr.IPAddresses
+	if ip := net.ParseIP(ipAddr); ip != nil && !slices.ContainsFunc(ipAddresses, ip.Equal) {
+		ipAddresses = append(ipAddresses, ip)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		IPAddresses:  ipAddresses,
+		// tolerate the clock skew of the bmc
+		NotBefore:   now.Add(-5 * time.Minute),
+		NotAfter:    now.Add(duration),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, i.certificate, csr.PublicKey, i.key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign the certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), certificate, nil
+}
+
+// parseCertificate parses the first certificate in PEM
+func parseCertificate(certificatePem string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificatePem))
+	if block == nil {
+		return nil, fmt.Errorf("the certificate is not in PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,13 @@
+package bmcprofile
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBmcProfile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BmcProfile Suite")
+}
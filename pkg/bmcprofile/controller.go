@@ -0,0 +1,225 @@
+package bmcprofile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// BmcProfileController enforces the network services of the bmc of the hosts which reference the BmcProfile through
+// their Subnet or HostEndpoint, and reports the drifts
+type BmcProfileController struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	agentConfig *config.AgentConfig
+	log         *zap.SugaredLogger
+}
+
+func NewBmcProfileController(mgr ctrl.Manager, agentConfig *config.AgentConfig) (*BmcProfileController, error) {
+	return &BmcProfileController{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		agentConfig: agentConfig,
+		log:         log.Logger.Named("BmcProfileController"),
+	}, nil
+}
+
+// 只有 leader 才会执行 Reconcile
+// Reconcile checks the network services of every host which references the profile, and it is requeued at the interval
+// of updating the HostStatus to find the drifts and the hosts which reference the profile later
+func (r *BmcProfileController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.With("bmcprofile", req.Name)
+	logger.Debugf("Starting reconcile for BmcProfile %s", req.Name)
+
+	bmcProfile := &topohubv1beta1.BmcProfile{}
+	if err := r.Get(ctx, req.NamespacedName, bmcProfile); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := time.Duration(r.agentConfig.RedfishHostStatusUpdateInterval) * time.Second
+
+	hosts, err := r.selectHosts(ctx, bmcProfile.Name)
+	if err != nil {
+		logger.Errorf("Failed to select hosts: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	previous := map[string]topohubv1beta1.BmcProfileHostStatus{}
+	for _, item := range bmcProfile.Status.Hosts {
+		previous[item.HostStatusName] = item
+	}
+
+	status := topohubv1beta1.BmcProfileStatus{}
+	for i := range hosts {
+		item := r.syncHost(logger, &bmcProfile.Spec, &hosts[i], previous[hosts[i].Name])
+		status.Hosts = append(status.Hosts, item)
+		status.TotalHosts++
+		switch item.State {
+		case topohubv1beta1.BmcProfileStateCompliant:
+			status.CompliantHosts++
+		case topohubv1beta1.BmcProfileStateDrifted:
+			status.DriftedHosts++
+		default:
+			status.FailedHosts++
+		}
+	}
+
+	// ignore the update time when comparing
+	status.LastUpdateTime = bmcProfile.Status.LastUpdateTime
+	if reflect.DeepEqual(status, bmcProfile.Status) {
+		logger.Debugf("no need to update BmcProfile %s", bmcProfile.Name)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+	status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	bmcProfile.Status = status
+	if err := r.Status().Update(ctx, bmcProfile); err != nil {
+		logger.Errorf("Failed to update BmcProfile status: %v", err)
+		return ctrl.Result{}, err
+	}
+	logger.Debugf("Successfully updated BmcProfile %s status, total %d, compliant %d, drifted %d, failed %d",
+		bmcProfile.Name, status.TotalHosts, status.CompliantHosts, status.DriftedHosts, status.FailedHosts)
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// selectHosts returns the HostStatus of the dhcp clients in the subnets which reference the profile, and the HostStatus
+// of the HostEndpoint which references the profile
+func (r *BmcProfileController) selectHosts(ctx context.Context, name string) ([]topohubv1beta1.HostStatus, error) {
+	subnetList := &topohubv1beta1.SubnetList{}
+	if err := r.List(ctx, subnetList); err != nil {
+		return nil, err
+	}
+	subnets := map[string]bool{}
+	for _, item := range subnetList.Items {
+		if item.Spec.BmcProfileName != nil && *item.Spec.BmcProfileName == name {
+			subnets[item.Name] = true
+		}
+	}
+
+	hostStatusList := &topohubv1beta1.HostStatusList{}
+	if err := r.List(ctx, hostStatusList); err != nil {
+		return nil, err
+	}
+	result := []topohubv1beta1.HostStatus{}
+	for _, item := range hostStatusList.Items {
+		basic := item.Status.Basic
+		switch basic.Type {
+		case topohubv1beta1.HostTypeDHCP:
+			if basic.SubnetName != nil && subnets[*basic.SubnetName] {
+				result = append(result, item)
+			}
+		default:
+			if basic.BmcProfileName == name {
+				result = append(result, item)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// syncHost compares the network services of the host with the profile, and corrects the drifts in the Enforce mode
+func (r *BmcProfileController) syncHost(logger *zap.SugaredLogger, spec *topohubv1beta1.BmcProfileSpec,
+	hostStatus *topohubv1beta1.HostStatus, previous topohubv1beta1.BmcProfileHostStatus) topohubv1beta1.BmcProfileHostStatus {
+	item := topohubv1beta1.BmcProfileHostStatus{
+		HostStatusName:  hostStatus.Name,
+		State:           topohubv1beta1.BmcProfileStateFailed,
+		LastEnforceTime: previous.LastEnforceTime,
+		CorrectedDrifts: previous.CorrectedDrifts,
+	}
+	failed := func(message string) topohubv1beta1.BmcProfileHostStatus {
+		item.State = topohubv1beta1.BmcProfileStateFailed
+		item.Message = message
+		return item
+	}
+
+	if !hostStatus.Status.Healthy {
+		return failed(fmt.Sprintf("hostStatus %s is not healthy", hostStatus.Name))
+	}
+	d := hoststatusData.HostCacheDatabase.Get(hostStatus.Name)
+	if d == nil {
+		return failed(fmt.Sprintf("failed to get connect config %s from cache", hostStatus.Name))
+	}
+	c, err := redfish.NewClient(*d, logger)
+	if err != nil {
+		return failed(err.Error())
+	}
+
+	current, err := compareHost(c, spec)
+	if err != nil {
+		logger.Warnf("Failed to get the network services of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	item.Drifts = current.drifts
+	switch {
+	case len(current.drifts) == 0:
+		item.State = topohubv1beta1.BmcProfileStateCompliant
+		return item
+	case spec.Mode == topohubv1beta1.BmcProfileModeMonitor:
+		item.State = topohubv1beta1.BmcProfileStateDrifted
+		return item
+	}
+
+	if err := current.apply(c); err != nil {
+		logger.Errorf("Failed to correct the network services of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	// some bmc accept the settings but ignore them, so check them again
+	remaining, err := compareHost(c, spec)
+	if err != nil {
+		logger.Warnf("Failed to get the network services of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+	item.Drifts = remaining.drifts
+	if len(remaining.drifts) > 0 {
+		logger.Errorf("The bmc of %s does not accept the settings: %v", hostStatus.Name, remaining.drifts)
+		return failed("the bmc does not accept the settings")
+	}
+	logger.Infof("Corrected the network services of %s: %v", hostStatus.Name, current.drifts)
+
+	item.State = topohubv1beta1.BmcProfileStateCompliant
+	item.LastEnforceTime = time.Now().UTC().Format(time.RFC3339)
+	item.CorrectedDrifts = current.drifts
+	return item
+}
+
+// compareHost reads the network services of the host, and compares them with the profile
+func compareHost(c redfish.RefishClient, spec *topohubv1beta1.BmcProfileSpec) (*diff, error) {
+	network, err := c.GetManagerNetwork()
+	if err != nil {
+		return nil, err
+	}
+	var subscriptions []redfish.SyslogSubscription
+	if spec.Syslog != nil {
+		if subscriptions, err = c.GetSyslogSubscriptions(); err != nil {
+			return nil, err
+		}
+	}
+	return compare(spec, network, subscriptions), nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *BmcProfileController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topohubv1beta1.BmcProfile{}).
+		// the status is updated by itself, and the hosts are checked periodically
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
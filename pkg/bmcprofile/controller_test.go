@@ -0,0 +1,157 @@
+package bmcprofile
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const (
+	testDhcpHostName   = "dhcp-host"
+	testBmcProfileName = "baseline"
+	testSubnetName     = "subnet1"
+)
+
+var _ = Describe("BmcProfileController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *BmcProfileController
+
+	// newController creates the controller with the HostStatus of the emulator which references the profile through its
+	// HostEndpoint, the unhealthy dhcp client in the subnet which references the profile, and another host which does not
+	newController := func(spec topohubv1beta1.BmcProfileSpec) {
+		bmc.Basic.BmcProfileName = testBmcProfileName
+		subnetName := testSubnetName
+		profileName := testBmcProfileName
+		c := bmc.Build(
+			bmc.HostStatus(),
+			&topohubv1beta1.HostStatus{
+				ObjectMeta: metav1.ObjectMeta{Name: testDhcpHostName},
+				Status: topohubv1beta1.HostStatusStatus{Basic: topohubv1beta1.BasicInfo{
+					Type:       topohubv1beta1.HostTypeDHCP,
+					IpAddr:     "192.168.0.10",
+					SubnetName: &subnetName,
+				}},
+			},
+			&topohubv1beta1.HostStatus{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Status: topohubv1beta1.HostStatusStatus{Healthy: true, Basic: topohubv1beta1.BasicInfo{
+					Type:   topohubv1beta1.HostTypeEndpoint,
+					IpAddr: "192.168.0.11",
+				}},
+			},
+			&topohubv1beta1.Subnet{
+				ObjectMeta: metav1.ObjectMeta{Name: testSubnetName},
+				Spec:       topohubv1beta1.SubnetSpec{BmcProfileName: &profileName},
+			},
+			&topohubv1beta1.BmcProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: testBmcProfileName},
+				Spec:       spec,
+			},
+		)
+		r = &BmcProfileController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{RedfishHostStatusUpdateInterval: 60},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	// reconcile returns the status of the emulator host, the first host is the unhealthy dhcp client
+	reconcile := func() topohubv1beta1.BmcProfileHostStatus {
+		testhost.Reconcile(r, testBmcProfileName)
+		bmcProfile := testhost.Get(r, testBmcProfileName, &topohubv1beta1.BmcProfile{})
+		Expect(bmcProfile.Status.TotalHosts).To(Equal(int32(2)))
+		Expect(bmcProfile.Status.Hosts).To(HaveLen(2))
+		Expect(bmcProfile.Status.Hosts[0].HostStatusName).To(Equal(testDhcpHostName))
+		Expect(bmcProfile.Status.Hosts[0].State).To(Equal(topohubv1beta1.BmcProfileStateFailed))
+		Expect(bmcProfile.Status.Hosts[1].HostStatusName).To(Equal(testhost.HostStatusName))
+		return bmcProfile.Status.Hosts[1]
+	}
+
+	enabled, disabled := true, false
+	baseline := func(mode string) topohubv1beta1.BmcProfileSpec {
+		return topohubv1beta1.BmcProfileSpec{
+			NTP:       &topohubv1beta1.BmcNTPSpec{Servers: []string{"10.0.0.3", "10.0.0.4"}},
+			Syslog:    &topohubv1beta1.BmcSyslogSpec{Address: "10.0.0.8"},
+			Protocols: &topohubv1beta1.BmcProtocolsSpec{IPMI: &disabled, SSH: &enabled},
+			NetworkInterface: &topohubv1beta1.BmcNetworkInterfaceSpec{
+				NameServers:       []string{"10.0.0.53"},
+				DhcpUseNTPServers: &disabled,
+			},
+			Mode: mode,
+		}
+	}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(true)
+		bmc.SetNetworkProtocol(emulator.NetworkProtocol{
+			Protocols:         map[string]bool{"IPMI": true, "SSH": true, "SNMP": false},
+			DHCPUseNTPServers: true,
+		})
+		bmc.AddSubscription(emulator.Subscription{Destination: "syslog://10.0.0.9:514", SubscriptionType: "Syslog", Protocol: "SyslogUDP"})
+	})
+
+	It("corrects the drifts in the Enforce mode", func() {
+		newController(baseline(topohubv1beta1.BmcProfileModeEnforce))
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcProfileStateCompliant))
+		Expect(host.Drifts).To(BeEmpty())
+		Expect(host.LastEnforceTime).NotTo(BeEmpty())
+		Expect(host.CorrectedDrifts).To(ConsistOf(
+			"IPMI is enabled, expected disabled",
+			"NTP is disabled",
+			"NTP servers are [], expected [10.0.0.3 10.0.0.4]",
+			"DNS servers are [], expected [10.0.0.53]",
+			"the NTP servers from dhcp are used, expected not used",
+			"syslog destination syslog://10.0.0.8:514 SyslogUDP is missing",
+			"unexpected syslog destination syslog://10.0.0.9:514 SyslogUDP",
+		))
+		protocol := bmc.NetworkProtocol()
+		Expect(protocol.Protocols).To(Equal(map[string]bool{"IPMI": false, "SSH": true, "SNMP": false}))
+		Expect(protocol.NTPEnabled).To(BeTrue())
+		Expect(protocol.NTPServers).To(Equal([]string{"10.0.0.3", "10.0.0.4"}))
+		Expect(protocol.StaticNameServers).To(Equal([]string{"10.0.0.53"}))
+		Expect(protocol.DHCPUseNTPServers).To(BeFalse())
+		Expect(bmc.Subscriptions()).To(ConsistOf(HaveField("Destination", "syslog://10.0.0.8:514")))
+
+		// the compliant bmc is not changed again
+		patches := bmc.CountRequests(http.MethodPatch, "/redfish/v1/Managers")
+		Expect(reconcile()).To(Equal(host))
+		Expect(bmc.CountRequests(http.MethodPatch, "/redfish/v1/Managers")).To(Equal(patches))
+	})
+
+	It("reports the drifts in the Monitor mode", func() {
+		newController(baseline(topohubv1beta1.BmcProfileModeMonitor))
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcProfileStateDrifted))
+		Expect(host.Drifts).To(ContainElement("IPMI is enabled, expected disabled"))
+		Expect(host.Drifts).To(HaveLen(7))
+		Expect(host.LastEnforceTime).To(BeEmpty())
+		Expect(bmc.CountRequests(http.MethodPatch, "")).To(BeZero())
+		Expect(bmc.NetworkProtocol().Protocols).To(HaveKeyWithValue("IPMI", true))
+	})
+
+	It("fails the host whose bmc does not support the protocol", func() {
+		newController(topohubv1beta1.BmcProfileSpec{
+			Protocols: &topohubv1beta1.BmcProtocolsSpec{IPMI: &disabled, Telnet: &enabled},
+			Mode:      topohubv1beta1.BmcProfileModeEnforce,
+		})
+
+		host := reconcile()
+		Expect(host.State).To(Equal(topohubv1beta1.BmcProfileStateFailed))
+		Expect(host.Message).To(Equal("the bmc does not accept the settings"))
+		Expect(host.Drifts).To(Equal([]string{"Telnet is not supported by the bmc"}))
+		// the supported protocol is still corrected
+		Expect(bmc.NetworkProtocol().Protocols).To(HaveKeyWithValue("IPMI", false))
+	})
+})
@@ -0,0 +1,164 @@
+package bmcprofile
+
+import (
+	"fmt"
+	"slices"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	gofishredfish "github.com/stmcginnis/gofish/redfish"
+)
+
+const defaultSyslogPort = 514
+
+// diff is the drifts of the bmc from the profile, and the settings to correct them
+type diff struct {
+	drifts []string
+
+	protocolUri string
+	protocol    redfish.NetworkProtocolSetting
+	// interfaceUri is the ethernet interface of the bmc to be set
+	interfaceUri string
+	iface        redfish.EthernetInterfaceSetting
+	syslog       *redfish.SyslogDestination
+}
+
+// compare finds the settings of the bmc which drift from the profile. The subscriptions are only compared when the
+// syslog is specified
+func compare(spec *topohubv1beta1.BmcProfileSpec, network *redfish.ManagerNetwork, subscriptions []redfish.SyslogSubscription) *diff {
+	result := &diff{
+		protocolUri: network.ProtocolUri,
+		protocol:    redfish.NetworkProtocolSetting{Protocols: map[string]bool{}},
+	}
+
+	if spec.Protocols != nil {
+		for _, item := range []struct {
+			name    string
+			enabled *bool
+		}{
+			{redfish.NetworkProtocolIPMI, spec.Protocols.IPMI},
+			{redfish.NetworkProtocolSSH, spec.Protocols.SSH},
+			{redfish.NetworkProtocolSNMP, spec.Protocols.SNMP},
+			{redfish.NetworkProtocolTelnet, spec.Protocols.Telnet},
+		} {
+			if item.enabled == nil {
+				continue
+			}
+			enabled, ok := network.Protocols[item.name]
+			switch {
+			case !ok && *item.enabled:
+				// the protocol which the bmc does not support is regarded as disabled
+				result.drifts = append(result.drifts, fmt.Sprintf("%s is not supported by the bmc", item.name))
+			case ok && enabled != *item.enabled:
+				result.drifts = append(result.drifts, fmt.Sprintf("%s is %s, expected %s", item.name, state(enabled), state(*item.enabled)))
+				result.protocol.Protocols[item.name] = *item.enabled
+			}
+		}
+	}
+
+	if spec.NTP != nil {
+		if !network.NTPEnabled {
+			result.drifts = append(result.drifts, "NTP is disabled")
+		}
+		if !slices.Equal(network.NTPServers, spec.NTP.Servers) {
+			result.drifts = append(result.drifts, fmt.Sprintf("NTP servers are %v, expected %v", network.NTPServers, spec.NTP.Servers))
+		}
+		if !network.NTPEnabled || !slices.Equal(network.NTPServers, spec.NTP.Servers) {
+			result.protocol.NTPServers = spec.NTP.Servers
+		}
+	}
+
+	if spec.NetworkInterface != nil {
+		result.drifts = append(result.drifts, compareInterface(spec.NetworkInterface, network, result)...)
+	}
+
+	if spec.Syslog != nil {
+		destination := redfish.SyslogDestination{
+			Address:  spec.Syslog.Address,
+			Port:     int(spec.Syslog.Port),
+			Protocol: spec.Syslog.Protocol,
+		}
+		if destination.Port == 0 {
+			destination.Port = defaultSyslogPort
+		}
+		if len(destination.Protocol) == 0 {
+			destination.Protocol = string(gofishredfish.SyslogUDPEventDestinationProtocol)
+		}
+		found := false
+		drifts := []string{}
+		for _, item := range subscriptions {
+			if !found && item.Destination == destination.Url() && item.Protocol == destination.Protocol {
+				found = true
+				continue
+			}
+			drifts = append(drifts, fmt.Sprintf("unexpected syslog destination %s %s", item.Destination, item.Protocol))
+		}
+		if !found {
+			drifts = append([]string{fmt.Sprintf("syslog destination %s %s is missing", destination.Url(), destination.Protocol)}, drifts...)
+		}
+		if len(drifts) > 0 {
+			result.drifts = append(result.drifts, drifts...)
+			result.syslog = &destination
+		}
+	}
+	return result
+}
+
+// compareInterface compares the ethernet interface of the bmc, and records the settings to correct the drifts
+func compareInterface(spec *topohubv1beta1.BmcNetworkInterfaceSpec, network *redfish.ManagerNetwork, result *diff) []string {
+	if len(network.InterfaceUri) == 0 {
+		return []string{"the ethernet interface of the bmc is not found"}
+	}
+	drifts := []string{}
+	if spec.NameServers != nil && !slices.Equal(network.StaticNameServers, spec.NameServers) {
+		drifts = append(drifts, fmt.Sprintf("DNS servers are %v, expected %v", network.StaticNameServers, spec.NameServers))
+		result.iface.StaticNameServers = spec.NameServers
+	}
+	if spec.DhcpUseNTPServers != nil && network.DHCPUseNTPServers != *spec.DhcpUseNTPServers {
+		drifts = append(drifts, fmt.Sprintf("the NTP servers from dhcp are %s, expected %s", usage(network.DHCPUseNTPServers), usage(*spec.DhcpUseNTPServers)))
+		result.iface.DHCPUseNTPServers = spec.DhcpUseNTPServers
+	}
+	if spec.DhcpUseDNSServers != nil && network.DHCPUseDNSServers != *spec.DhcpUseDNSServers {
+		drifts = append(drifts, fmt.Sprintf("the DNS servers from dhcp are %s, expected %s", usage(network.DHCPUseDNSServers), usage(*spec.DhcpUseDNSServers)))
+		result.iface.DHCPUseDNSServers = spec.DhcpUseDNSServers
+	}
+	if len(drifts) > 0 {
+		result.interfaceUri = network.InterfaceUri
+	}
+	return drifts
+}
+
+// apply corrects the drifts. The ethernet interface is set before the protocols, since the NTP servers from dhcp may
+// override the static ones
+func (d *diff) apply(c redfish.RefishClient) error {
+	if len(d.interfaceUri) > 0 {
+		if err := c.SetManagerEthernetInterface(d.interfaceUri, d.iface); err != nil {
+			return err
+		}
+	}
+	if len(d.protocol.Protocols) > 0 || d.protocol.NTPServers != nil {
+		if err := c.SetManagerNetworkProtocol(d.protocolUri, d.protocol); err != nil {
+			return err
+		}
+	}
+	if d.syslog != nil {
+		if _, err := c.EnsureSyslogDestination(*d.syslog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func state(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func usage(used bool) string {
+	if used {
+		return "used"
+	}
+	return "not used"
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/snmp"
 	"github.com/infrastructure-io/topohub/pkg/tools"
 )
 
@@ -31,6 +32,7 @@ type AgentConfig struct {
 	StoragePathHttp                     string
 	StoragePathHttpZtp                  string
 	StoragePathHttpIso                  string
+	StoragePathHttpFirmware             string
 	StoragePathTftp                     string
 	StoragePathTftpRelativeDirForPxeEfi string
 	StoragePathTftpAbsoluteDirForPxeEfi string
@@ -46,11 +48,36 @@ type AgentConfig struct {
 	RedfishSecretName               string
 	RedfishSecretNamespace          string
 	RedfishHostStatusUpdateInterval int
+	// the periodic update queries at most RedfishHostStatusUpdateParallelism hosts at the same time, each host waits a
+	// random delay within RedfishHostStatusUpdateJitter seconds, and its update is given up after RedfishHostStatusUpdateTimeout seconds
+	RedfishHostStatusUpdateParallelism int
+	RedfishHostStatusUpdateTimeout     int
+	RedfishHostStatusUpdateJitter      int
 	// DHCP server configuration
 	DhcpServerInterface string
 
 	HttpEnabled bool
 	HttpPort    string
+	// HttpServerAddress is the address of topohub which the BMC accesses to download images and push redfish events,
+	// the self ip of the subnet is used for the dhcp host when it is empty
+	HttpServerAddress string
+
+	// RedfishEventEnabled subscribes the redfish events of the hosts, which are pushed to the https listener on RedfishEventPort
+	RedfishEventEnabled bool
+	RedfishEventPort    string
+
+	// SnmpTrapEnabled configures the snmp trap destination of the hosts, and receives the traps on the udp SnmpTrapPort
+	SnmpTrapEnabled bool
+	SnmpTrapPort    string
+	// SnmpTrapVersion is v2c or v3, the credentials are read from the files in SnmpAuthPath
+	SnmpTrapVersion        string
+	SnmpAuthPath           string
+	SnmpTrapCommunity      string
+	SnmpTrapV3Username     string
+	SnmpTrapV3AuthProtocol string
+	SnmpTrapV3AuthPassword string
+	SnmpTrapV3PrivProtocol string
+	SnmpTrapV3PrivPassword string
 }
 
 // LoadFeatureConfig loads feature configuration from the config file
@@ -98,6 +125,33 @@ func (c *AgentConfig) loadFeatureConfig() error {
 	}
 	c.RedfishHostStatusUpdateInterval = interval
 
+	parallelismBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "redfishHostStatusUpdateParallelism"))
+	if err != nil {
+		return fmt.Errorf("failed to read redfishHostStatusUpdateParallelism: %v", err)
+	}
+	c.RedfishHostStatusUpdateParallelism, err = strconv.Atoi(strings.TrimSpace(string(parallelismBytes)))
+	if err != nil || c.RedfishHostStatusUpdateParallelism <= 0 {
+		return fmt.Errorf("invalid redfishHostStatusUpdateParallelism value: %s", string(parallelismBytes))
+	}
+
+	timeoutBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "redfishHostStatusUpdateTimeout"))
+	if err != nil {
+		return fmt.Errorf("failed to read redfishHostStatusUpdateTimeout: %v", err)
+	}
+	c.RedfishHostStatusUpdateTimeout, err = strconv.Atoi(strings.TrimSpace(string(timeoutBytes)))
+	if err != nil || c.RedfishHostStatusUpdateTimeout <= 0 {
+		return fmt.Errorf("invalid redfishHostStatusUpdateTimeout value: %s", string(timeoutBytes))
+	}
+
+	jitterBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "redfishHostStatusUpdateJitter"))
+	if err != nil {
+		return fmt.Errorf("failed to read redfishHostStatusUpdateJitter: %v", err)
+	}
+	c.RedfishHostStatusUpdateJitter, err = strconv.Atoi(strings.TrimSpace(string(jitterBytes)))
+	if err != nil || c.RedfishHostStatusUpdateJitter < 0 || c.RedfishHostStatusUpdateJitter >= interval {
+		return fmt.Errorf("invalid redfishHostStatusUpdateJitter value %s, it should be less than redfishHostStatusUpdateInterval", string(jitterBytes))
+	}
+
 	// Read dhcpServerInterface
 	interfaceBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "dhcpServerInterface"))
 	if err != nil {
@@ -125,9 +179,117 @@ func (c *AgentConfig) loadFeatureConfig() error {
 	}
 	c.HttpEnabled = strings.ToLower(string(httpEnabledBytes)) == "true"
 
+	httpAddressBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "httpServerAddress"))
+	if err != nil {
+		return fmt.Errorf("failed to read httpServerAddress: %v", err)
+	}
+	c.HttpServerAddress = strings.TrimSpace(string(httpAddressBytes))
+
+	// redfish event
+	eventEnabledBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "redfishEventEnabled"))
+	if err != nil {
+		return fmt.Errorf("failed to read redfishEventEnabled: %v", err)
+	}
+	c.RedfishEventEnabled = strings.ToLower(string(eventEnabledBytes)) == "true"
+
+	eventPortBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "redfishEventPort"))
+	if err != nil {
+		return fmt.Errorf("failed to read redfishEventPort: %v", err)
+	}
+	c.RedfishEventPort = strings.TrimSpace(string(eventPortBytes))
+	if c.RedfishEventEnabled {
+		if _, err := strconv.Atoi(c.RedfishEventPort); err != nil {
+			return fmt.Errorf("invalid redfishEventPort value: %v", err)
+		}
+	}
+
+	// snmp trap
+	trapEnabledBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "snmpTrapEnabled"))
+	if err != nil {
+		return fmt.Errorf("failed to read snmpTrapEnabled: %v", err)
+	}
+	c.SnmpTrapEnabled = strings.ToLower(string(trapEnabledBytes)) == "true"
+	if !c.SnmpTrapEnabled {
+		return nil
+	}
+
+	trapPortBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "snmpTrapPort"))
+	if err != nil {
+		return fmt.Errorf("failed to read snmpTrapPort: %v", err)
+	}
+	c.SnmpTrapPort = strings.TrimSpace(string(trapPortBytes))
+	if _, err := strconv.Atoi(c.SnmpTrapPort); err != nil {
+		return fmt.Errorf("invalid snmpTrapPort value: %v", err)
+	}
+
+	trapVersionBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "snmpTrapVersion"))
+	if err != nil {
+		return fmt.Errorf("failed to read snmpTrapVersion: %v", err)
+	}
+	c.SnmpTrapVersion = strings.TrimSpace(string(trapVersionBytes))
+
+	if c.SnmpAuthPath == "" {
+		return fmt.Errorf("SNMP_AUTH_PATH environment variable not set")
+	}
+	readAuth := func(name string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(c.SnmpAuthPath, name))
+		if err != nil {
+			return "", fmt.Errorf("failed to read snmp %s: %v", name, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	switch c.SnmpTrapVersion {
+	case "v2c":
+		if c.SnmpTrapCommunity, err = readAuth("community"); err != nil {
+			return err
+		}
+		if c.SnmpTrapCommunity == "" {
+			return fmt.Errorf("the snmp community is empty")
+		}
+	case "v3":
+		if c.SnmpTrapV3Username, err = readAuth("v3Username"); err != nil {
+			return err
+		}
+		if c.SnmpTrapV3AuthPassword, err = readAuth("v3AuthPassword"); err != nil {
+			return err
+		}
+		if c.SnmpTrapV3PrivPassword, err = readAuth("v3PrivPassword"); err != nil {
+			return err
+		}
+		authProtocolBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "snmpTrapV3AuthProtocol"))
+		if err != nil {
+			return fmt.Errorf("failed to read snmpTrapV3AuthProtocol: %v", err)
+		}
+		c.SnmpTrapV3AuthProtocol = strings.TrimSpace(string(authProtocolBytes))
+		privProtocolBytes, err := os.ReadFile(filepath.Join(c.FeatureConfigPath, "snmpTrapV3PrivProtocol"))
+		if err != nil {
+			return fmt.Errorf("failed to read snmpTrapV3PrivProtocol: %v", err)
+		}
+		c.SnmpTrapV3PrivProtocol = strings.TrimSpace(string(privProtocolBytes))
+		if err := c.SnmpTrapUser().Validate(); err != nil {
+			return fmt.Errorf("invalid snmp v3 user: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid snmpTrapVersion value %q, it should be v2c or v3", c.SnmpTrapVersion)
+	}
+
 	return nil
 }
 
+// SnmpTrapUser returns the v3 user which the bmc uses to send the traps, it is nil for v2c
+func (c *AgentConfig) SnmpTrapUser() *snmp.User {
+	if c.SnmpTrapVersion != "v3" {
+		return nil
+	}
+	return &snmp.User{
+		Name:         c.SnmpTrapV3Username,
+		AuthProtocol: c.SnmpTrapV3AuthProtocol,
+		AuthPassword: c.SnmpTrapV3AuthPassword,
+		PrivProtocol: c.SnmpTrapV3PrivProtocol,
+		PrivPassword: c.SnmpTrapV3PrivPassword,
+	}
+}
+
 // verifyWebhookCertDir verifies that the webhook certificate directory exists and contains required files
 func (c *AgentConfig) verifyWebhookCertDir() error {
 	requiredFiles := []string{"tls.crt", "tls.key", "ca.crt"}
@@ -155,6 +317,7 @@ func (c *AgentConfig) initStorageDirectory() error {
 	c.StoragePathHttp = filepath.Join(c.StoragePath, "http")
 	c.StoragePathHttpZtp = filepath.Join(c.StoragePathHttp, "ztp")
 	c.StoragePathHttpIso = filepath.Join(c.StoragePathHttp, "iso")
+	c.StoragePathHttpFirmware = filepath.Join(c.StoragePathHttp, "firmware")
 
 	// List of required subdirectories
 	subdirs := []string{
@@ -166,6 +329,7 @@ func (c *AgentConfig) initStorageDirectory() error {
 		c.StoragePathHttp,
 		c.StoragePathHttpIso,
 		c.StoragePathHttpZtp,
+		c.StoragePathHttpFirmware,
 	}
 
 	// Check and create each subdirectory if it doesn't exist
@@ -236,6 +400,9 @@ func LoadAgentConfig() (*AgentConfig, error) {
 		return nil, fmt.Errorf("FEATURE_CONFIG_PATH environment variable not set")
 	}
 
+	// only required when the snmp trap is enabled
+	agentConfig.SnmpAuthPath = os.Getenv("SNMP_AUTH_PATH")
+
 	agentConfig.DhcpConfigTemplatePath = os.Getenv("DHCP_CONFIG_TEMPLATE_PATH")
 	if agentConfig.DhcpConfigTemplatePath == "" {
 		return nil, fmt.Errorf("DHCP_CONFIG_TEMPLATE_PATH environment variable not set")
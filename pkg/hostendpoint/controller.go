@@ -2,6 +2,7 @@ package hostendpoint
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"go.uber.org/zap"
@@ -101,6 +102,15 @@ func (r *HostEndpointReconciler) handleHostEndpoint(ctx context.Context, hostEnd
 		if hostEndpoint.Spec.Port != nil {
 			updated.Status.Basic.Port = *hostEndpoint.Spec.Port
 		}
+		if hostEndpoint.Spec.Protocol != nil {
+			updated.Status.Basic.Protocol = *hostEndpoint.Spec.Protocol
+		}
+		if hostEndpoint.Spec.TLS != nil {
+			updated.Status.Basic.TLS = hostEndpoint.Spec.TLS.DeepCopy()
+		}
+		if hostEndpoint.Spec.BmcProfileName != nil {
+			updated.Status.Basic.BmcProfileName = *hostEndpoint.Spec.BmcProfileName
+		}
 
 		if err := r.client.Update(ctx, updated); err != nil {
 			if errors.IsConflict(err) {
@@ -200,6 +210,15 @@ func (r *HostEndpointReconciler) handleHostEndpoint(ctx context.Context, hostEnd
 	if hostEndpoint.Spec.Port != nil {
 		hostStatus.Status.Basic.Port = *hostEndpoint.Spec.Port
 	}
+	if hostEndpoint.Spec.Protocol != nil {
+		hostStatus.Status.Basic.Protocol = *hostEndpoint.Spec.Protocol
+	}
+	if hostEndpoint.Spec.TLS != nil {
+		hostStatus.Status.Basic.TLS = hostEndpoint.Spec.TLS.DeepCopy()
+	}
+	if hostEndpoint.Spec.BmcProfileName != nil {
+		hostStatus.Status.Basic.BmcProfileName = *hostEndpoint.Spec.BmcProfileName
+	}
 
 	if err := r.client.Status().Update(ctx, hostStatus); err != nil {
 		logger.Errorf("Failed to update status of HostStatus %s: %v", name, err)
@@ -237,12 +256,27 @@ func specEqual(basic topohubv1beta1.BasicInfo, spec topohubv1beta1.HostEndpointS
 	if spec.Port != nil && basic.Port == *spec.Port {
 		t4 = true
 	}
+	protocol := topohubv1beta1.ProtocolRedfish
+	if spec.Protocol != nil && *spec.Protocol != "" {
+		protocol = *spec.Protocol
+	}
+	basicProtocol := basic.Protocol
+	if basicProtocol == "" {
+		basicProtocol = topohubv1beta1.ProtocolRedfish
+	}
+	bmcProfileName := ""
+	if spec.BmcProfileName != nil {
+		bmcProfileName = *spec.BmcProfileName
+	}
 
 	return basic.IpAddr == spec.IPAddr &&
 		t1 &&
 		t2 &&
 		t3 &&
 		t4 &&
+		protocol == basicProtocol &&
+		reflect.DeepEqual(spec.TLS, basic.TLS) &&
+		bmcProfileName == basic.BmcProfileName &&
 		clusterName == basic.ClusterName
 }
 
@@ -0,0 +1,188 @@
+package hostoperation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/infrastructure-io/topohub/pkg/hoststatus"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	gofishredfish "github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	defaultBmcResetTimeout = 15 * time.Minute
+	// the bmc may keep responding for a while before it restarts, so the bmc which is never found unreachable is
+	// regarded as back only after the grace period
+	bmcResetGracePeriod = 2 * time.Minute
+)
+
+// resetBmc restarts the bmc or restores its factory settings, and marks the HostStatus resetting, so that the host is
+// not regarded as unhealthy while the bmc is unreachable
+func (r *HostOperationController) resetBmc(ctx context.Context, logger *zap.SugaredLogger, c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation) error {
+	spec := hostOp.Spec.BmcReset
+	if spec == nil {
+		spec = &topohubv1beta1.BmcResetSpec{}
+	}
+	timeout := defaultBmcResetTimeout
+	if spec.TimeoutMinutes != nil && *spec.TimeoutMinutes > 0 {
+		timeout = time.Duration(*spec.TimeoutMinutes) * time.Minute
+	}
+
+	var resetType string
+	var err error
+	if hostOp.Spec.Action == topohubv1beta1.ActionResetBmcToDefaults {
+		resetType = spec.ResetToDefaultsType
+		if len(resetType) == 0 {
+			resetType = string(gofishredfish.PreserveNetworkAndUsersResetToDefaultsType)
+		}
+		err = c.ResetManagerToDefaults(hostOp.Spec.SystemId, resetType)
+	} else {
+		resetType = spec.ResetType
+		if len(resetType) == 0 {
+			resetType = topohubv1beta1.BootCmdGracefulRestart
+		}
+		err = c.ResetManager(hostOp.Spec.SystemId, resetType)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the bmc drops the sessions when it restarts
+	redfish.CacheClient.Invalidate(hostOp.Status.IpAddr)
+
+	now := time.Now().UTC()
+	hostOp.Status.BmcReset = &topohubv1beta1.BmcResetStatus{
+		ResetType: resetType,
+		ResetTime: now.Format(time.RFC3339),
+		Deadline:  now.Add(timeout).Format(time.RFC3339),
+	}
+	r.setBmcResetting(ctx, logger, hostOp, metav1.Condition{
+		Type:    topohubv1beta1.ConditionBmcResetting,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Resetting",
+		Message: fmt.Sprintf("the BMC is reset by HostOperation %s with %s, it is expected to come back before %s", hostOp.Name, resetType, hostOp.Status.BmcReset.Deadline),
+	})
+	return nil
+}
+
+// waitBmcReset polls the bmc until it comes back after the reset, and fails the HostOperation after the deadline
+func (r *HostOperationController) waitBmcReset(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation) (ctrl.Result, error) {
+	status := hostOp.Status.BmcReset
+	resetTime, _ := time.Parse(time.RFC3339, status.ResetTime)
+	deadline, terr := time.Parse(time.RFC3339, status.Deadline)
+
+	var c redfish.RefishClient
+	var err error
+	d := hoststatusData.HostCacheDatabase.Get(hostOp.Spec.HostStatusName)
+	if d == nil {
+		err = fmt.Errorf("failed to get connect config %s from cache", hostOp.Spec.HostStatusName)
+	} else {
+		c, err = redfish.NewClient(*d, logger)
+	}
+	var certErr *redfish.CertificateError
+	if errors.As(err, &certErr) {
+		c, err = r.trustResetCertificate(ctx, logger, hostOp, d, certErr)
+	}
+	untrusted := errors.As(err, &certErr)
+
+	result := ctrl.Result{}
+	expired := terr != nil || time.Now().After(deadline)
+	switch {
+	case untrusted:
+		// waiting does not make the bmc trusted
+		logger.Errorf("The bmc of %s presents the untrusted certificate after it is reset: %v", hostOp.Spec.HostStatusName, err)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+		hostOp.Status.Message = fmt.Sprintf("the bmc presents the untrusted certificate after it is reset: %v", err)
+	case err != nil && !expired:
+		logger.Debugf("the bmc of %s is unreachable while resetting: %v", hostOp.Spec.HostStatusName, err)
+		status.Unreachable = true
+		hostOp.Status.Message = fmt.Sprintf("the bmc is unreachable while resetting, wait for it until %s", status.Deadline)
+		result.RequeueAfter = taskPollInterval
+	case err != nil:
+		logger.Errorf("The bmc of %s does not come back after it is reset: %v", hostOp.Spec.HostStatusName, err)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+		hostOp.Status.Message = fmt.Sprintf("the bmc does not come back before %s: %v", status.Deadline, err)
+	case !status.Unreachable && time.Since(resetTime) < bmcResetGracePeriod:
+		logger.Debugf("the bmc of %s still responds, it may not restart yet", hostOp.Spec.HostStatusName)
+		hostOp.Status.Message = "wait for the bmc to restart"
+		result.RequeueAfter = taskPollInterval
+	default:
+		logger.Infof("The bmc of %s comes back after it is reset", hostOp.Spec.HostStatusName)
+		status.RecoveredTime = time.Now().UTC().Format(time.RFC3339)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusSuccess
+		hostOp.Status.Message = ""
+	}
+
+	hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	if hostOp.Status.Status != topohubv1beta1.HostOperationStatusPending {
+		condition := metav1.Condition{
+			Type:    topohubv1beta1.ConditionBmcResetting,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Recovered",
+			Message: fmt.Sprintf("the BMC comes back at %s after it is reset by HostOperation %s", status.RecoveredTime, hostOp.Name),
+		}
+		if hostOp.Status.Status == topohubv1beta1.HostOperationStatusFailed {
+			condition.Reason = "NotRecovered"
+			condition.Message = fmt.Sprintf("the BMC does not come back before %s after it is reset by HostOperation %s", status.Deadline, hostOp.Name)
+			if untrusted {
+				condition.Message = fmt.Sprintf("the BMC presents the untrusted certificate %s after it is reset by HostOperation %s", certErr.Certificate.Fingerprint, hostOp.Name)
+			}
+		}
+		r.setBmcResetting(ctx, logger, hostOp, condition)
+		r.finishOperation(ctx, logger, c, hostOp)
+	}
+
+	if err := r.Status().Update(ctx, hostOp); err != nil {
+		logger.Errorf("Failed to update HostOperation status: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+	}
+	return result, nil
+}
+
+// trustResetCertificate trusts the certificate which the bmc regenerates after it is reset by the HostOperation, when
+// the host trusts the certificate on first use. The certificate error is returned for the host which verifies the
+// certificate with the CA or the pinned fingerprints
+func (r *HostOperationController) trustResetCertificate(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation,
+	d *hoststatusData.HostConnectCon, certErr *redfish.CertificateError) (redfish.RefishClient, error) {
+	if certErr.Reason != redfish.CertificateChanged || d.TLS == nil || len(d.TLS.KnownFingerprint) == 0 {
+		return nil, certErr
+	}
+	logger.Infof("trust the certificate %s of the bmc of %s, which is regenerated after the reset", certErr.Certificate.Fingerprint, hostOp.Spec.HostStatusName)
+	if err := hoststatus.RecordReplacedCertificate(ctx, r.Client, hostOp.Spec.HostStatusName, certErr.Certificate); err != nil {
+		return nil, err
+	}
+	d = hoststatusData.HostCacheDatabase.Get(hostOp.Spec.HostStatusName)
+	if d == nil {
+		return nil, fmt.Errorf("failed to get connect config %s from cache", hostOp.Spec.HostStatusName)
+	}
+	return redfish.NewClient(*d, logger)
+}
+
+// setBmcResetting sets the BmcResetting condition of the HostStatus, the hoststatus controller keeps the host healthy
+// while the condition is true
+func (r *HostOperationController) setBmcResetting(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation, condition metav1.Condition) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hostStatus := &topohubv1beta1.HostStatus{}
+		if err := r.Get(ctx, client.ObjectKey{Name: hostOp.Spec.HostStatusName}, hostStatus); err != nil {
+			return err
+		}
+		if !meta.SetStatusCondition(&hostStatus.Status.Conditions, condition) {
+			return nil
+		}
+		return r.Status().Update(ctx, hostStatus)
+	})
+	if err != nil {
+		logger.Errorf("Failed to set the %s condition of HostStatus %s: %v", condition.Type, hostOp.Spec.HostStatusName, err)
+	}
+}
@@ -3,6 +3,7 @@ package hostoperation
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -14,10 +15,19 @@ import (
 	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
 	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
 	"github.com/infrastructure-io/topohub/pkg/redfish"
+	gofishredfish "github.com/stmcginnis/gofish/redfish"
 	"go.uber.org/zap"
 )
 
+const (
+	// the interval to poll the redfish task
+	taskPollInterval = 10 * time.Second
+	// the firmware update of some bmc takes a long time, give up tracking the task after the timeout
+	taskTimeout = 2 * time.Hour
+)
+
 // HostOperationController reconciles a HostOperation object
 type HostOperationController struct {
 	client.Client
@@ -54,10 +64,45 @@ func (r *HostOperationController) Reconcile(ctx context.Context, req ctrl.Reques
 	// 获取关联的 HostStatus
 	hostStatus := &topohubv1beta1.HostStatus{}
 	if err := r.Get(ctx, client.ObjectKey{Name: hostOp.Spec.HostStatusName}, hostStatus); err != nil {
+		switch {
+		case errors.IsNotFound(err) && (hostOp.Status.Status == topohubv1beta1.HostOperationStatusSuccess || hostOp.Status.Status == topohubv1beta1.HostOperationStatusFailed):
+			// the host has been removed, for example, by the Decommission action
+			logger.Debugf("HostOperation %s has been processed, and HostStatus %s is removed", hostOp.Name, hostOp.Spec.HostStatusName)
+			return ctrl.Result{}, nil
+		case errors.IsNotFound(err) && hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending && len(hostOp.Status.Decommission) > 0:
+			return r.decommission(ctx, logger, hostOp, nil)
+		}
 		logger.Errorf("Failed to get HostStatus %s: %v", hostOp.Spec.HostStatusName, err)
 		return ctrl.Result{}, err
 	}
 
+	// 下线主机的各个步骤依次执行
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending && len(hostOp.Status.Decommission) > 0 {
+		return r.decommission(ctx, logger, hostOp, hostStatus)
+	}
+
+	// 已经下发的异步操作，跟踪 redfish task 的进度，task 结束后，从虚拟光驱启动的主机继续等待弹出 iso
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending && hostOp.Status.Task != nil {
+		if finished, _ := redfish.TaskFinished(hostOp.Status.Task); !finished {
+			return r.trackTask(ctx, logger, hostOp)
+		}
+	}
+
+	// 从虚拟光驱启动的主机，到时间后弹出 iso
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending && hostOp.Status.VirtualMedia != nil && len(hostOp.Status.VirtualMedia.EjectTime) > 0 {
+		return r.ejectVirtualMedia(ctx, logger, hostOp)
+	}
+
+	// 点亮的指示灯，到时间后恢复原来的状态
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending && hostOp.Status.IndicatorLED != nil && len(hostOp.Status.IndicatorLED.RevertTime) > 0 {
+		return r.revertIndicatorLED(ctx, logger, hostOp)
+	}
+
+	// 重启的 bmc，等待其恢复访问
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending && hostOp.Status.BmcReset != nil && len(hostOp.Status.BmcReset.RecoveredTime) == 0 {
+		return r.waitBmcReset(ctx, logger, hostOp)
+	}
+
 	// 检查状态是否为空
 	if hostOp.Status.Status == "" || hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending {
 		logger.Infof("Processing HostOperation %s : %+v", hostOp.Name, hostOp.Spec)
@@ -67,6 +112,35 @@ func (r *HostOperationController) Reconcile(ctx context.Context, req ctrl.Reques
 		hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
 		hostOp.Status.ClusterName = hostStatus.Status.Basic.ClusterName
 		hostOp.Status.IpAddr = hostStatus.Status.Basic.IpAddr
+		hostOp.Status.SystemId = hostOp.Spec.SystemId
+
+		if hostOp.Spec.Action == topohubv1beta1.ActionDecommission {
+			return r.startDecommission(ctx, logger, hostOp)
+		}
+
+		// 安装操作系统前，等待 StorageConfig 完成主机 volume 的配置
+		if installsOS(hostOp.Spec.Action) {
+			waiting, err := r.checkStorage(ctx, hostOp, hostStatus)
+			if err != nil || len(waiting) > 0 {
+				result := ctrl.Result{}
+				if err != nil {
+					logger.Errorf("Failed to prepare the storage of %s: %v", hostOp.Spec.HostStatusName, err)
+					hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+					hostOp.Status.Message = err.Error()
+					r.finishOperation(ctx, logger, nil, hostOp)
+				} else {
+					logger.Infof("The storage of %s is not ready: %s", hostOp.Spec.HostStatusName, waiting)
+					hostOp.Status.Message = waiting
+					result.RequeueAfter = taskPollInterval
+				}
+				if err := r.Status().Update(ctx, hostOp); err != nil {
+					logger.Errorf("Failed to update HostOperation status: %v", err)
+					return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+				}
+				return result, nil
+			}
+			hostOp.Status.Message = ""
+		}
 
 		// 调用 redfish 接口 完成操作
 		// get connect config from cache
@@ -79,42 +153,79 @@ func (r *HostOperationController) Reconcile(ctx context.Context, req ctrl.Reques
 		logger.Debugf("get connect config %s from cache: %+v", hostOp.Spec.HostStatusName, d)
 
 		var err error
+		// taskUri is not empty when the bmc finishes the action asynchronously
+		var taskUri string
 		c, terr := redfish.NewClient(*d, logger)
 		if terr != nil {
 			err = terr
-			logger.Errorf("Failed to operate %s: %v", hostOp.Spec.HostStatusName, err)
-			hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
-			hostOp.Status.Message = err.Error()
 		} else {
 			switch hostOp.Spec.Action {
 			case topohubv1beta1.BootCmdOn:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
 			case topohubv1beta1.BootCmdForceOn:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
 			case topohubv1beta1.BootCmdForceOff:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
 			case topohubv1beta1.BootCmdGracefulShutdown:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
 			case topohubv1beta1.BootCmdForceRestart:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
 			case topohubv1beta1.BootCmdGracefulRestart:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
 			case topohubv1beta1.BootCmdResetPxeOnce:
-				err = c.Power(hostOp.Spec.Action)
+				taskUri, err = c.Power(hostOp.Spec.Action, hostOp.Spec.SystemId)
+			case topohubv1beta1.ActionFirmwareUpdate:
+				taskUri, err = r.updateFirmware(ctx, c, hostOp, hostStatus)
+			case topohubv1beta1.ActionVirtualMediaBoot:
+				taskUri, err = r.bootVirtualMedia(ctx, c, hostOp, hostStatus)
+			case topohubv1beta1.ActionSetBoot:
+				if hostOp.Spec.Boot == nil {
+					err = fmt.Errorf("spec.boot is required for the action %s", hostOp.Spec.Action)
+				} else {
+					taskUri, err = c.SetBoot(hostOp.Spec.SystemId, *hostOp.Spec.Boot)
+				}
+			case topohubv1beta1.ActionSetIndicatorLED:
+				err = r.setIndicatorLED(c, hostOp)
+			case topohubv1beta1.ActionResetBmc, topohubv1beta1.ActionResetBmcToDefaults:
+				err = r.resetBmc(ctx, logger, c, hostOp)
 			default:
 				err = fmt.Errorf("invalid action %s", hostOp.Spec.Action)
 			}
 		}
 
 		hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+		result := ctrl.Result{}
 		if err != nil {
 			logger.Errorf("Failed to operate %s: %v", hostOp.Spec.HostStatusName, err)
 			hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
 			hostOp.Status.Message = err.Error()
+		} else if len(taskUri) > 0 {
+			logger.Infof("The bmc of %s accepts the action, track the task %s", hostOp.Spec.HostStatusName, taskUri)
+			hostOp.Status.Task = &topohubv1beta1.TaskInfo{
+				Uri:       taskUri,
+				StartTime: hostOp.Status.LastUpdateTime,
+				State:     string(gofishredfish.NewTaskState),
+			}
+			result.RequeueAfter = taskPollInterval
+		} else if hostOp.Status.VirtualMedia != nil && len(hostOp.Status.VirtualMedia.EjectTime) > 0 {
+			logger.Infof("The host %s boots from the virtual media, the iso will be ejected at %s", hostOp.Spec.HostStatusName, hostOp.Status.VirtualMedia.EjectTime)
+			hostOp.Status.Message = fmt.Sprintf("the iso will be ejected at %s", hostOp.Status.VirtualMedia.EjectTime)
+			result.RequeueAfter = time.Duration(*hostOp.Spec.VirtualMedia.EjectAfterMinutes) * time.Minute
+		} else if hostOp.Status.IndicatorLED != nil && len(hostOp.Status.IndicatorLED.RevertTime) > 0 {
+			logger.Infof("The indicator led of %s is %s, it will be reverted at %s", hostOp.Spec.HostStatusName, hostOp.Status.IndicatorLED.State, hostOp.Status.IndicatorLED.RevertTime)
+			hostOp.Status.Message = fmt.Sprintf("the indicator led will be reverted at %s", hostOp.Status.IndicatorLED.RevertTime)
+			result.RequeueAfter = time.Duration(*hostOp.Spec.IndicatorLED.RevertAfterMinutes) * time.Minute
+		} else if hostOp.Status.BmcReset != nil {
+			logger.Infof("The bmc of %s is reset, wait for it to come back until %s", hostOp.Spec.HostStatusName, hostOp.Status.BmcReset.Deadline)
+			hostOp.Status.Message = fmt.Sprintf("the bmc is reset, wait for it until %s", hostOp.Status.BmcReset.Deadline)
+			result.RequeueAfter = taskPollInterval
 		} else {
 			logger.Infof("Succeeded to operate %s", hostOp.Spec.HostStatusName)
 			hostOp.Status.Status = topohubv1beta1.HostOperationStatusSuccess
 		}
+		if hostOp.Status.Status != topohubv1beta1.HostOperationStatusPending {
+			r.finishOperation(ctx, logger, c, hostOp)
+		}
 
 		// 更新
 		if err := r.Status().Update(ctx, hostOp); err != nil {
@@ -122,13 +233,104 @@ func (r *HostOperationController) Reconcile(ctx context.Context, req ctrl.Reques
 			return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
 		}
 		logger.Debugf("Successfully updated HostOperation %s status", hostOp.Name)
+		return result, nil
 
 	} else {
 		logger.Infof("HostOperation %s has been processed", hostOp.Name)
 		return ctrl.Result{}, nil
 	}
+}
+
+// trackTask polls the redfish task of the HostOperation, and sets the final status when the task finishes
+func (r *HostOperationController) trackTask(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation) (ctrl.Result, error) {
+	task := hostOp.Status.Task
+
+	// the bmc may be unreachable for a while when it is resetting, for example, updating the bmc firmware
+	var c redfish.RefishClient
+	var err error
+	d := hoststatusData.HostCacheDatabase.Get(hostOp.Spec.HostStatusName)
+	if d == nil {
+		err = fmt.Errorf("failed to get connect config %s from cache", hostOp.Spec.HostStatusName)
+	} else {
+		c, err = redfish.NewClient(*d, logger)
+	}
+	var current *topohubv1beta1.TaskInfo
+	if err == nil {
+		current, err = c.GetTask(task.Uri)
+	}
+
+	if err != nil {
+		if !taskExpired(task) {
+			logger.Warnf("Failed to get task %s, retry later: %v", task.Uri, err)
+			return ctrl.Result{RequeueAfter: taskPollInterval}, nil
+		}
+		logger.Errorf("Failed to track task %s: %v", task.Uri, err)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+		hostOp.Status.Message = fmt.Sprintf("failed to track task %s: %v", task.Uri, err)
+	} else {
+		current.StartTime = task.StartTime
+		hostOp.Status.Task = current
+		finished, succeeded := redfish.TaskFinished(current)
+		switch {
+		case !finished && taskExpired(current):
+			logger.Errorf("Failed to operate %s, task %s is still %s after %v", hostOp.Spec.HostStatusName, current.Uri, current.State, taskTimeout)
+			hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+			hostOp.Status.Message = fmt.Sprintf("task %s is still %s after %v, %d%% complete", current.Uri, current.State, taskTimeout, current.PercentComplete)
+		case !finished:
+			logger.Debugf("task %s is %s, %d%% complete", current.Uri, current.State, current.PercentComplete)
+			hostOp.Status.Message = taskProgress(current)
+		case succeeded && hostOp.Status.VirtualMedia != nil && len(hostOp.Status.VirtualMedia.EjectTime) > 0:
+			logger.Infof("The host %s boots from the virtual media, task %s is %s, the iso will be ejected at %s", hostOp.Spec.HostStatusName, current.Uri, current.State, hostOp.Status.VirtualMedia.EjectTime)
+			hostOp.Status.Message = fmt.Sprintf("the iso will be ejected at %s", hostOp.Status.VirtualMedia.EjectTime)
+		case succeeded:
+			logger.Infof("Succeeded to operate %s, task %s is %s", hostOp.Spec.HostStatusName, current.Uri, current.State)
+			hostOp.Status.Status = topohubv1beta1.HostOperationStatusSuccess
+			hostOp.Status.Message = ""
+		default:
+			logger.Errorf("Failed to operate %s, task %s is %s: %v", hostOp.Spec.HostStatusName, current.Uri, current.State, current.Messages)
+			hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+			hostOp.Status.Message = fmt.Sprintf("task %s is %s with status %s: %s", current.Uri, current.State, current.Health, strings.Join(current.Messages, "; "))
+		}
+	}
 
-	return ctrl.Result{}, nil
+	hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	result := ctrl.Result{}
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusPending {
+		result.RequeueAfter = taskPollInterval
+	} else {
+		r.finishOperation(ctx, logger, c, hostOp)
+	}
+
+	if err := r.Status().Update(ctx, hostOp); err != nil {
+		logger.Errorf("Failed to update HostOperation status: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+	}
+	return result, nil
+}
+
+// taskExpired returns true when the task has been tracked for longer than taskTimeout
+func taskExpired(task *topohubv1beta1.TaskInfo) bool {
+	startTime, err := time.Parse(time.RFC3339, task.StartTime)
+	return err != nil || time.Since(startTime) >= taskTimeout
+}
+
+// taskProgress describes the progress of the running task with its latest message
+func taskProgress(task *topohubv1beta1.TaskInfo) string {
+	progress := fmt.Sprintf("task %s is %s, %d%% complete", task.Uri, task.State, task.PercentComplete)
+	if len(task.Messages) > 0 {
+		progress += ": " + task.Messages[len(task.Messages)-1]
+	}
+	return progress
+}
+
+// finishOperation does the follow-up work after the action finishes, the client could be nil when the bmc is unreachable
+func (r *HostOperationController) finishOperation(ctx context.Context, logger *zap.SugaredLogger, c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation) {
+	metrics.HostOperationsTotal.WithLabelValues(hostOp.Spec.Action, hostOp.Status.Status).Inc()
+
+	switch hostOp.Spec.Action {
+	case topohubv1beta1.ActionFirmwareUpdate:
+		r.finishFirmwareUpdate(ctx, logger, c, hostOp)
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager
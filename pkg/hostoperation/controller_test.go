@@ -0,0 +1,576 @@
+package hostoperation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const testHostOperationName = "operation"
+
+// selfSignedCertificate generates the certificate which the bmc regenerates after it is reset
+func selfSignedCertificate(host string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}
+}
+
+var _ = Describe("HostOperationController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *HostOperationController
+
+	// newController creates the controller with the HostStatus of the emulator and the HostOperation
+	newController := func(spec topohubv1beta1.HostOperationSpec, objects ...client.Object) {
+		spec.HostStatusName = testhost.HostStatusName
+		c := bmc.Build(append(objects, bmc.HostStatus(), &topohubv1beta1.HostOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: testHostOperationName},
+			Spec:       spec,
+		})...)
+		r = &HostOperationController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	reconcile := func() ctrl.Result {
+		return testhost.Reconcile(r, testHostOperationName)
+	}
+
+	getHostOperation := func() *topohubv1beta1.HostOperation {
+		return testhost.Get(r, testHostOperationName, &topohubv1beta1.HostOperation{})
+	}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(false)
+	})
+
+	It("powers off the host", func() {
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdForceOff})
+		reconcile()
+
+		Expect(getHostOperation().Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		system, _ := bmc.System("1")
+		Expect(system.PowerState).To(Equal(emulator.PowerOff))
+	})
+
+	It("fails when the bmc refuses the reset", func() {
+		bmc.Fail(http.MethodPost, "/redfish/v1/Systems/1/Actions", http.StatusInternalServerError, 0)
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdForceRestart})
+		reconcile()
+
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Message).NotTo(BeEmpty())
+
+		// the failed operation is not retried
+		reconcile()
+		Expect(bmc.CountRequests(http.MethodPost, "/redfish/v1/Systems/1/Actions")).To(Equal(1))
+	})
+
+	It("retries when the host is not cached", func() {
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdForceOff})
+		hoststatusData.HostCacheDatabase.Delete(testhost.HostStatusName)
+
+		Expect(reconcile().RequeueAfter).NotTo(BeZero())
+		Expect(bmc.CountRequests("", "/redfish/v1")).To(Equal(0))
+	})
+
+	It("sets the boot override", func() {
+		newController(topohubv1beta1.HostOperationSpec{
+			Action: topohubv1beta1.ActionSetBoot,
+			Boot:   &topohubv1beta1.BootSpec{Target: "Pxe"},
+		})
+		reconcile()
+
+		Expect(getHostOperation().Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		system, _ := bmc.System("1")
+		Expect(system.Boot.OverrideTarget).To(Equal("Pxe"))
+		Expect(system.Boot.OverrideEnabled).To(Equal("Once"))
+	})
+
+	It("tracks the task of the firmware update", func() {
+		httpDir := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(httpDir, "firmware"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(httpDir, "firmware", "bios.bin"), []byte("bios"), 0644)).To(Succeed())
+		bmc.SetFirmware(emulator.Firmware{Id: "BIOS", Name: "BIOS", Version: "1.0.0", Updateable: true})
+
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:   topohubv1beta1.ActionFirmwareUpdate,
+			Firmware: &topohubv1beta1.FirmwareUpdateSpec{ImagePath: "firmware/bios.bin"},
+		})
+		r.agentConfig.StoragePathHttp = httpDir
+		r.agentConfig.HttpEnabled = true
+		r.agentConfig.HttpServerAddress = "10.0.0.1"
+		r.agentConfig.HttpPort = "80"
+
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.Task).NotTo(BeNil())
+		Expect(bmc.Requests()).To(ContainElement(HaveField("Body", ContainSubstring("http://10.0.0.1:80/firmware/bios.bin"))))
+
+		// the task is still running
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		Expect(getHostOperation().Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+
+		bmc.SetFirmware(emulator.Firmware{Id: "BIOS", Name: "BIOS", Version: "1.1.0", Updateable: true})
+		bmc.SetTask(hostOp.Status.Task.Uri, emulator.Task{State: "Completed", Status: "OK", PercentComplete: 100})
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(hostOp.Status.FirmwareVersions).To(ContainElement(HaveField("Version", "1.1.0")))
+
+		hostStatus := &topohubv1beta1.HostStatus{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, hostStatus)).To(Succeed())
+		Expect(hostStatus.Status.LastFirmwareUpdate).NotTo(BeNil())
+		Expect(hostStatus.Status.LastFirmwareUpdate.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+	})
+
+	It("tracks the task of the asynchronous reset", func() {
+		bmc.SetSystems(emulator.System{Id: "1", PowerState: emulator.PowerOn, Health: "OK", AsyncReset: true})
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdGracefulRestart})
+
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.Task).NotTo(BeNil())
+
+		bmc.SetTask(hostOp.Status.Task.Uri, emulator.Task{State: "Running", Status: "OK", PercentComplete: 40, Messages: []string{"shutting down the os"}})
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.Task.PercentComplete).To(Equal(int32(40)))
+		Expect(hostOp.Status.Message).To(ContainSubstring("40% complete: shutting down the os"))
+
+		bmc.SetTask(hostOp.Status.Task.Uri, emulator.Task{State: "Exception", Status: "Critical", Messages: []string{"the os does not respond"}})
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Message).To(ContainSubstring("the os does not respond"))
+	})
+
+	It("fails when the task does not finish in time", func() {
+		bmc.SetSystems(emulator.System{Id: "1", PowerState: emulator.PowerOn, Health: "OK", AsyncReset: true})
+		newController(topohubv1beta1.HostOperationSpec{
+			Action: topohubv1beta1.ActionSetBoot,
+			Boot:   &topohubv1beta1.BootSpec{Target: "Pxe", ResetType: topohubv1beta1.BootCmdForceRestart},
+		})
+
+		reconcile()
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Task).NotTo(BeNil())
+
+		// the task has been tracked for longer than the timeout
+		hostOp.Status.Task.StartTime = time.Now().Add(-taskTimeout).UTC().Format(time.RFC3339)
+		Expect(r.Status().Update(context.Background(), hostOp)).To(Succeed())
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Message).To(ContainSubstring("still Running"))
+	})
+
+	It("tracks the task of the virtual media boot and ejects the iso after the task", func() {
+		httpDir := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(httpDir, "iso"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(httpDir, "iso", "os.iso"), []byte("iso"), 0644)).To(Succeed())
+		bmc.SetSystems(emulator.System{Id: "1", PowerState: emulator.PowerOn, Health: "OK", AsyncReset: true})
+
+		ejectAfterMinutes := int32(30)
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:       topohubv1beta1.ActionVirtualMediaBoot,
+			VirtualMedia: &topohubv1beta1.VirtualMediaBootSpec{IsoFile: "os.iso", EjectAfterMinutes: &ejectAfterMinutes},
+		})
+		r.agentConfig.StoragePathHttp = httpDir
+		r.agentConfig.StoragePathHttpIso = filepath.Join(httpDir, "iso")
+		r.agentConfig.HttpEnabled = true
+		r.agentConfig.HttpServerAddress = "10.0.0.1"
+		r.agentConfig.HttpPort = "80"
+
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.Task).NotTo(BeNil())
+		Expect(hostOp.Status.Task.Uri).To(Equal(bmc.Tasks()[0]))
+		Expect(hostOp.Status.VirtualMedia.EjectTime).NotTo(BeEmpty())
+		system, _ := bmc.System("1")
+		Expect(system.MediaImage).To(Equal("http://10.0.0.1:80/iso/os.iso"))
+
+		// the iso is kept after the reset finishes
+		bmc.SetTask(hostOp.Status.Task.Uri, emulator.Task{State: "Completed", Status: "OK", PercentComplete: 100})
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.Task.State).To(Equal("Completed"))
+		Expect(hostOp.Status.Message).To(ContainSubstring("the iso will be ejected at"))
+		Expect(reconcile().RequeueAfter).To(BeNumerically(">", 29*time.Minute))
+		system, _ = bmc.System("1")
+		Expect(system.MediaImage).NotTo(BeEmpty())
+
+		hostOp = getHostOperation()
+		hostOp.Status.VirtualMedia.EjectTime = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		Expect(r.Status().Update(context.Background(), hostOp)).To(Succeed())
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(hostOp.Status.VirtualMedia.Ejected).To(BeTrue())
+		system, _ = bmc.System("1")
+		Expect(system.MediaImage).To(BeEmpty())
+	})
+
+	It("blinks the indicator led and reverts it after the timeout", func() {
+		revertAfterMinutes := int32(30)
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:       topohubv1beta1.ActionSetIndicatorLED,
+			IndicatorLED: &topohubv1beta1.IndicatorLEDSpec{State: topohubv1beta1.IndicatorLEDBlinking, RevertAfterMinutes: &revertAfterMinutes},
+		})
+
+		Expect(reconcile().RequeueAfter).To(Equal(30 * time.Minute))
+		Expect(bmc.IndicatorLED()).To(Equal(topohubv1beta1.IndicatorLEDBlinking))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.IndicatorLED.PreviousState).To(Equal(topohubv1beta1.IndicatorLEDOff))
+		Expect(hostOp.Status.IndicatorLED.RevertTime).NotTo(BeEmpty())
+
+		// it is not the time to revert
+		Expect(reconcile().RequeueAfter).NotTo(BeZero())
+		Expect(bmc.IndicatorLED()).To(Equal(topohubv1beta1.IndicatorLEDBlinking))
+
+		hostOp.Status.IndicatorLED.RevertTime = time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+		Expect(r.Status().Update(context.Background(), hostOp)).To(Succeed())
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		Expect(bmc.IndicatorLED()).To(Equal(topohubv1beta1.IndicatorLEDOff))
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(hostOp.Status.IndicatorLED.Reverted).To(BeTrue())
+	})
+
+	It("turns on the LocationIndicatorActive without reverting", func() {
+		bmc.SetIndicatorLED(topohubv1beta1.IndicatorLEDOff, true)
+		revertAfterMinutes := int32(0)
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:       topohubv1beta1.ActionSetIndicatorLED,
+			IndicatorLED: &topohubv1beta1.IndicatorLEDSpec{State: topohubv1beta1.IndicatorLEDLit, RevertAfterMinutes: &revertAfterMinutes},
+		})
+
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		Expect(bmc.IndicatorLED()).To(Equal(topohubv1beta1.IndicatorLEDLit))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(hostOp.Status.IndicatorLED.RevertTime).To(BeEmpty())
+	})
+
+	It("resets the bmc and waits for it to come back", func() {
+		bmc.SetManagerResetDelay(2 * time.Second)
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.ActionResetBmc})
+		getCondition := func() *metav1.Condition {
+			hostStatus := &topohubv1beta1.HostStatus{}
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, hostStatus)).To(Succeed())
+			return meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionBmcResetting)
+		}
+
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		manager, _ := bmc.Manager("bmc")
+		Expect(manager.ResetType).To(Equal(topohubv1beta1.BootCmdGracefulRestart))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.BmcReset).NotTo(BeNil())
+		Expect(getCondition()).To(HaveField("Status", metav1.ConditionTrue))
+
+		// the bmc is restarting
+		Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.BmcReset.Unreachable).To(BeTrue())
+
+		Eventually(func() string {
+			reconcile()
+			return getHostOperation().Status.Status
+		}).WithTimeout(10 * time.Second).WithPolling(500 * time.Millisecond).Should(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(getHostOperation().Status.BmcReset.RecoveredTime).NotTo(BeEmpty())
+		Expect(getCondition()).To(And(HaveField("Status", metav1.ConditionFalse), HaveField("Reason", "Recovered")))
+	})
+
+	It("fails when the bmc does not come back after it is reset to defaults", func() {
+		bmc.SetManagerResetDelay(time.Hour)
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:   topohubv1beta1.ActionResetBmcToDefaults,
+			BmcReset: &topohubv1beta1.BmcResetSpec{ConfirmHostStatusName: testhost.HostStatusName},
+		})
+
+		reconcile()
+		manager, _ := bmc.Manager("bmc")
+		Expect(manager.ResetToDefaultsType).To(Equal("PreserveNetworkAndUsers"))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.BmcReset.ResetType).To(Equal("PreserveNetworkAndUsers"))
+
+		// the deadline passes
+		hostOp.Status.BmcReset.Deadline = time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+		Expect(r.Status().Update(context.Background(), hostOp)).To(Succeed())
+		Expect(reconcile().RequeueAfter).To(BeZero())
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Message).To(ContainSubstring("does not come back"))
+
+		hostStatus := &topohubv1beta1.HostStatus{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, hostStatus)).To(Succeed())
+		condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionBmcResetting)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("NotRecovered"))
+	})
+
+	Context("the bmc regenerates its certificate after it is reset", func() {
+		var regenerated tls.Certificate
+
+		// resetToDefaults resets the bmc of https, and the bmc serves the new certificate while it is restarting
+		resetToDefaults := func() {
+			bmc.SetManagerResetDelay(2 * time.Second)
+			newController(topohubv1beta1.HostOperationSpec{
+				Action:   topohubv1beta1.ActionResetBmcToDefaults,
+				BmcReset: &topohubv1beta1.BmcResetSpec{ConfirmHostStatusName: testhost.HostStatusName},
+			})
+			Expect(reconcile().RequeueAfter).To(Equal(taskPollInterval))
+			regenerated = selfSignedCertificate(bmc.Host())
+			bmc.ServeCertificate(regenerated)
+		}
+
+		BeforeEach(func() {
+			bmc = testhost.Start(true)
+		})
+
+		It("trusts the new certificate of the host which trusts the certificate on first use", func() {
+			bmc.TLS = &hoststatusData.TLSTrust{KnownFingerprint: redfish.Fingerprint(bmc.Certificate())}
+			resetToDefaults()
+
+			Eventually(func() string {
+				reconcile()
+				return getHostOperation().Status.Status
+			}).WithTimeout(10 * time.Second).WithPolling(500 * time.Millisecond).Should(Equal(topohubv1beta1.HostOperationStatusSuccess))
+			fingerprint := redfish.Fingerprint(regenerated.Leaf)
+			Expect(hoststatusData.HostCacheDatabase.Get(testhost.HostStatusName).TLS.KnownFingerprint).To(Equal(fingerprint))
+			hostStatus := testhost.Get(r, testhost.HostStatusName, &topohubv1beta1.HostStatus{})
+			Expect(hostStatus.Status.Certificate).NotTo(BeNil())
+			Expect(hostStatus.Status.Certificate.Fingerprint).To(Equal(fingerprint))
+			Expect(meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionBmcResetting)).To(HaveField("Reason", "Recovered"))
+		})
+
+		It("fails at once when the new certificate is not trusted by the pinned fingerprint", func() {
+			bmc.TLS = &hoststatusData.TLSTrust{Fingerprints: []string{redfish.Fingerprint(bmc.Certificate())}}
+			resetToDefaults()
+
+			Expect(reconcile().RequeueAfter).To(BeZero())
+			hostOp := getHostOperation()
+			Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+			Expect(hostOp.Status.Message).To(ContainSubstring("untrusted certificate"))
+			hostStatus := testhost.Get(r, testhost.HostStatusName, &topohubv1beta1.HostStatus{})
+			Expect(hostStatus.Status.Certificate).To(BeNil())
+			condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionBmcResetting)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("NotRecovered"))
+			Expect(condition.Message).To(ContainSubstring(redfish.Fingerprint(regenerated.Leaf)))
+		})
+	})
+
+	It("waits for the volumes before booting from pxe", func() {
+		storageConfig := &topohubv1beta1.StorageConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "raid"},
+			Spec:       topohubv1beta1.StorageConfigSpec{HostStatusNames: []string{testhost.HostStatusName}},
+		}
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdResetPxeOnce}, storageConfig)
+
+		// the StorageConfig has not checked the host
+		result := reconcile()
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+		Expect(hostOp.Status.Message).To(ContainSubstring("raid"))
+		Expect(bmc.CountRequests(http.MethodPost, "/redfish/v1/Systems/1/Actions")).To(Equal(0))
+
+		setState := func(state string) {
+			Expect(r.Get(context.Background(), types.NamespacedName{Name: "raid"}, storageConfig)).To(Succeed())
+			storageConfig.Status.Hosts = []topohubv1beta1.StorageHostStatus{{HostStatusName: testhost.HostStatusName, SystemId: "1", State: state}}
+			Expect(r.Status().Update(context.Background(), storageConfig)).To(Succeed())
+		}
+		setState(topohubv1beta1.StorageStatePending)
+		reconcile()
+		Expect(getHostOperation().Status.Status).To(Equal(topohubv1beta1.HostOperationStatusPending))
+
+		setState(topohubv1beta1.StorageStateInSync)
+		reconcile()
+		hostOp = getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(hostOp.Status.Message).To(BeEmpty())
+		Expect(bmc.CountRequests(http.MethodPost, "/redfish/v1/Systems/1/Actions")).To(Equal(1))
+	})
+
+	It("fails to boot from pxe when the volumes could not be configured", func() {
+		storageConfig := &topohubv1beta1.StorageConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "raid"},
+			Spec: topohubv1beta1.StorageConfigSpec{HostSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test"},
+			}},
+			Status: topohubv1beta1.StorageConfigStatus{Hosts: []topohubv1beta1.StorageHostStatus{{
+				HostStatusName: testhost.HostStatusName,
+				State:          topohubv1beta1.StorageStateFailed,
+				Message:        "drive Disk.9 of volume os is not found",
+			}}},
+		}
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdResetPxeOnce}, storageConfig)
+
+		// the host is not selected without the label
+		reconcile()
+		Expect(getHostOperation().Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+
+		newController(topohubv1beta1.HostOperationSpec{Action: topohubv1beta1.BootCmdResetPxeOnce}, storageConfig)
+		hostStatus := &topohubv1beta1.HostStatus{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, hostStatus)).To(Succeed())
+		hostStatus.Labels = map[string]string{"app": "test"}
+		Expect(r.Update(context.Background(), hostStatus)).To(Succeed())
+		reconcile()
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Message).To(ContainSubstring("Disk.9"))
+	})
+
+	It("decommissions the host step by step", func() {
+		bmc.SetStorage("1", emulator.Storage{
+			Id:      "RAID.1",
+			Drives:  []emulator.Drive{{Id: "Disk.0"}, {Id: "Disk.1"}},
+			Volumes: []emulator.Volume{{Id: "os", Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.0", "Disk.1"}}},
+		})
+		bmc.AddLogEntry("1", emulator.LogEntry{Severity: "OK", Message: "the host is powered on"})
+		hostEndpoint := &topohubv1beta1.HostEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: testhost.HostStatusName},
+			Spec:       topohubv1beta1.HostEndpointSpec{IPAddr: bmc.Host()},
+		}
+		bindingIp := &topohubv1beta1.BindingIp{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding"},
+			Spec:       topohubv1beta1.BindingIpSpec{Subnet: "subnet", IpAddr: bmc.Host(), MacAddr: "00:11:22:33:44:55"},
+		}
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:       topohubv1beta1.ActionDecommission,
+			Decommission: &topohubv1beta1.DecommissionSpec{ConfirmHostStatusName: testhost.HostStatusName},
+		}, hostEndpoint, bindingIp)
+
+		stepStates := func() []string {
+			states := []string{}
+			for _, step := range getHostOperation().Status.Decommission {
+				states = append(states, step.Name+"="+step.State)
+			}
+			return states
+		}
+
+		reconcile()
+		Expect(stepStates()).To(Equal([]string{"ResetStorage=Pending", "EraseDrives=Pending", "ResetBios=Pending", "ClearLogs=Pending", "RemoveHost=Pending"}))
+
+		reconcile()
+		Expect(stepStates()[0]).To(Equal("ResetStorage=Completed"))
+		Expect(bmc.Volumes("1", "RAID.1")).To(BeEmpty())
+
+		// the drives are erased by the tasks
+		result := reconcile()
+		Expect(result.RequeueAfter).To(Equal(taskPollInterval))
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Decommission[1].State).To(Equal(topohubv1beta1.DecommissionStepRunning))
+		Expect(hostOp.Status.Decommission[1].Tasks).To(HaveLen(2))
+		reconcile()
+		Expect(stepStates()[1]).To(Equal("EraseDrives=Running"))
+		for _, task := range hostOp.Status.Decommission[1].Tasks {
+			bmc.SetTask(task.Uri, emulator.Task{State: "Completed", Status: "OK", PercentComplete: 100})
+		}
+		reconcile()
+		Expect(stepStates()[1]).To(Equal("EraseDrives=Completed"))
+
+		reconcile()
+		Expect(stepStates()[2]).To(Equal("ResetBios=Completed"))
+		system, _ := bmc.System("1")
+		Expect(system.BiosReset).To(BeTrue())
+
+		reconcile()
+		Expect(stepStates()[3]).To(Equal("ClearLogs=Completed"))
+		Expect(bmc.CountRequests(http.MethodPost, "/redfish/v1/Systems/1/LogServices/Log/Actions")).To(Equal(1))
+
+		reconcile()
+		Expect(stepStates()[4]).To(Equal("RemoveHost=Completed"))
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, &topohubv1beta1.HostStatus{})).NotTo(Succeed())
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, &topohubv1beta1.HostEndpoint{})).NotTo(Succeed())
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: "binding"}, &topohubv1beta1.BindingIp{})).NotTo(Succeed())
+
+		// the HostStatus has been removed
+		reconcile()
+		Expect(getHostOperation().Status.Status).To(Equal(topohubv1beta1.HostOperationStatusSuccess))
+		Expect(reconcile()).To(Equal(ctrl.Result{}))
+	})
+
+	It("does not erase any drive when one of them does not support SecureErase", func() {
+		bmc.SetStorage("1", emulator.Storage{
+			Id:     "RAID.1",
+			Drives: []emulator.Drive{{Id: "Disk.0"}, {Id: "Disk.1", SecureEraseUnsupported: true}},
+		})
+		newController(topohubv1beta1.HostOperationSpec{
+			Action: topohubv1beta1.ActionDecommission,
+			Decommission: &topohubv1beta1.DecommissionSpec{
+				ConfirmHostStatusName: testhost.HostStatusName,
+				Steps:                 []string{topohubv1beta1.DecommissionStepRemoveHost, topohubv1beta1.DecommissionStepEraseDrives},
+			},
+		})
+
+		reconcile()
+		reconcile()
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Message).To(ContainSubstring("Disk.1"))
+		Expect(hostOp.Status.Decommission).To(HaveLen(2))
+		Expect(hostOp.Status.Decommission[1].State).To(Equal(topohubv1beta1.DecommissionStepPending))
+		Expect(bmc.Tasks()).To(BeEmpty())
+		Expect(r.Get(context.Background(), types.NamespacedName{Name: testhost.HostStatusName}, &topohubv1beta1.HostStatus{})).To(Succeed())
+	})
+
+	It("refuses to decommission the host without the confirmation", func() {
+		newController(topohubv1beta1.HostOperationSpec{
+			Action:       topohubv1beta1.ActionDecommission,
+			Decommission: &topohubv1beta1.DecommissionSpec{ConfirmHostStatusName: "other"},
+		})
+
+		reconcile()
+		hostOp := getHostOperation()
+		Expect(hostOp.Status.Status).To(Equal(topohubv1beta1.HostOperationStatusFailed))
+		Expect(hostOp.Status.Decommission).To(BeEmpty())
+	})
+})
@@ -0,0 +1,286 @@
+package hostoperation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	gofishredfish "github.com/stmcginnis/gofish/redfish"
+)
+
+// decommissionSteps are all the steps of the Decommission action in the order to run
+var decommissionSteps = []string{
+	topohubv1beta1.DecommissionStepResetStorage,
+	topohubv1beta1.DecommissionStepEraseDrives,
+	topohubv1beta1.DecommissionStepResetBios,
+	topohubv1beta1.DecommissionStepClearLogs,
+	topohubv1beta1.DecommissionStepRemoveHost,
+}
+
+// startDecommission records the steps to run in the status, they are run one by one in the following reconciliations
+func (r *HostOperationController) startDecommission(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation) (ctrl.Result, error) {
+	if hostOp.Spec.Decommission == nil || hostOp.Spec.Decommission.ConfirmHostStatusName != hostOp.Spec.HostStatusName {
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+		hostOp.Status.Message = fmt.Sprintf("spec.decommission.confirmHostStatusName must be %s for the action %s", hostOp.Spec.HostStatusName, hostOp.Spec.Action)
+		r.finishOperation(ctx, logger, nil, hostOp)
+	} else {
+		selected := map[string]bool{}
+		for _, name := range hostOp.Spec.Decommission.Steps {
+			selected[name] = true
+		}
+		for _, name := range decommissionSteps {
+			if len(hostOp.Spec.Decommission.Steps) == 0 || selected[name] {
+				hostOp.Status.Decommission = append(hostOp.Status.Decommission, topohubv1beta1.DecommissionStepStatus{
+					Name:  name,
+					State: topohubv1beta1.DecommissionStepPending,
+				})
+			}
+		}
+		logger.Infof("Decommission %s with the steps %v", hostOp.Spec.HostStatusName, hostOp.Spec.Decommission.Steps)
+	}
+
+	if err := r.Status().Update(ctx, hostOp); err != nil {
+		logger.Errorf("Failed to update HostOperation status: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// decommission runs the first unfinished step of the Decommission action. The hostStatus is nil when the host has been removed
+func (r *HostOperationController) decommission(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation, hostStatus *topohubv1beta1.HostStatus) (ctrl.Result, error) {
+	result := ctrl.Result{}
+	var step *topohubv1beta1.DecommissionStepStatus
+	for i := range hostOp.Status.Decommission {
+		s := &hostOp.Status.Decommission[i]
+		if s.State == topohubv1beta1.DecommissionStepPending || s.State == topohubv1beta1.DecommissionStepRunning {
+			step = s
+			break
+		}
+	}
+
+	if step != nil {
+		switch {
+		case step.Name == topohubv1beta1.DecommissionStepRemoveHost:
+			r.removeHost(ctx, logger, hostOp, hostStatus, step)
+		case hostStatus == nil:
+			setStepState(step, topohubv1beta1.DecommissionStepFailed, fmt.Sprintf("hostStatus %s is not found", hostOp.Spec.HostStatusName))
+		default:
+			d := hoststatusData.HostCacheDatabase.Get(hostOp.Spec.HostStatusName)
+			if d == nil {
+				logger.Warnf("Failed to get connect config %s from cache, retry later", hostOp.Spec.HostStatusName)
+				return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+			}
+			c, err := redfish.NewClient(*d, logger)
+			if err != nil {
+				// the bmc may be resetting, for example, after resetting the storage
+				logger.Warnf("Failed to connect the bmc of %s, retry later: %v", hostOp.Spec.HostStatusName, err)
+				return ctrl.Result{RequeueAfter: taskPollInterval}, nil
+			}
+			if step.State == topohubv1beta1.DecommissionStepPending {
+				r.runDecommissionStep(logger, c, hostOp, step)
+			} else {
+				trackStepTasks(logger, c, step)
+			}
+		}
+
+		hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+		switch step.State {
+		case topohubv1beta1.DecommissionStepRunning:
+			result.RequeueAfter = taskPollInterval
+		case topohubv1beta1.DecommissionStepFailed:
+			hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+			hostOp.Status.Message = fmt.Sprintf("step %s failed: %s", step.Name, step.Message)
+		default:
+			result.Requeue = true
+		}
+	} else {
+		logger.Infof("Succeeded to decommission %s", hostOp.Spec.HostStatusName)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusSuccess
+		hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	if hostOp.Status.Status != topohubv1beta1.HostOperationStatusPending {
+		r.finishOperation(ctx, logger, nil, hostOp)
+	}
+	if err := r.Status().Update(ctx, hostOp); err != nil {
+		logger.Errorf("Failed to update HostOperation status: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+	}
+	return result, nil
+}
+
+// runDecommissionStep requests the bmc to do the step, the step is running when the bmc creates the redfish tasks
+func (r *HostOperationController) runDecommissionStep(logger *zap.SugaredLogger, c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation, step *topohubv1beta1.DecommissionStepStatus) {
+	step.StartTime = time.Now().UTC().Format(time.RFC3339)
+	systemId := hostOp.Spec.SystemId
+
+	var tasks []string
+	var err error
+	switch step.Name {
+	case topohubv1beta1.DecommissionStepResetStorage:
+		tasks, err = c.ResetStorage(systemId)
+		if errors.Is(err, redfish.ErrNotSupported) {
+			// the drives are still erased by the next step
+			logger.Infof("Skip resetting the storage of %s: %v", hostOp.Spec.HostStatusName, err)
+			setStepState(step, topohubv1beta1.DecommissionStepSkipped, err.Error())
+			return
+		}
+	case topohubv1beta1.DecommissionStepEraseDrives:
+		tasks, err = c.SecureEraseDrives(systemId)
+	case topohubv1beta1.DecommissionStepResetBios:
+		var taskUri string
+		taskUri, err = c.ResetBios(systemId)
+		if len(taskUri) > 0 {
+			tasks = append(tasks, taskUri)
+		}
+	case topohubv1beta1.DecommissionStepClearLogs:
+		err = c.ClearLogs(systemId)
+	default:
+		err = fmt.Errorf("unknown step %s", step.Name)
+	}
+
+	if err != nil {
+		logger.Errorf("Failed to run step %s of %s: %v", step.Name, hostOp.Spec.HostStatusName, err)
+		setStepState(step, topohubv1beta1.DecommissionStepFailed, err.Error())
+		return
+	}
+	if len(tasks) == 0 {
+		logger.Infof("Succeeded to run step %s of %s", step.Name, hostOp.Spec.HostStatusName)
+		setStepState(step, topohubv1beta1.DecommissionStepCompleted, "")
+		return
+	}
+
+	logger.Infof("The bmc of %s accepts step %s, track the tasks %v", hostOp.Spec.HostStatusName, step.Name, tasks)
+	step.State = topohubv1beta1.DecommissionStepRunning
+	for _, taskUri := range tasks {
+		step.Tasks = append(step.Tasks, topohubv1beta1.TaskInfo{
+			Uri:       taskUri,
+			StartTime: step.StartTime,
+			State:     string(gofishredfish.NewTaskState),
+		})
+	}
+}
+
+// trackStepTasks polls the unfinished redfish tasks of the step, the step completes when all of them succeed
+func trackStepTasks(logger *zap.SugaredLogger, c redfish.RefishClient, step *topohubv1beta1.DecommissionStepStatus) {
+	running := 0
+	failures := []string{}
+	for i := range step.Tasks {
+		task := &step.Tasks[i]
+		if finished, succeeded := redfish.TaskFinished(task); finished {
+			if !succeeded {
+				failures = append(failures, fmt.Sprintf("task %s is %s: %s", task.Uri, task.State, strings.Join(task.Messages, "; ")))
+			}
+			continue
+		}
+
+		current, err := c.GetTask(task.Uri)
+		if err != nil {
+			if !taskExpired(task) {
+				logger.Warnf("Failed to get task %s, retry later: %v", task.Uri, err)
+				running++
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("failed to track task %s: %v", task.Uri, err))
+			continue
+		}
+		current.StartTime = task.StartTime
+		*task = *current
+		finished, succeeded := redfish.TaskFinished(task)
+		switch {
+		case !finished && taskExpired(task):
+			failures = append(failures, fmt.Sprintf("task %s is still %s after %v, %d%% complete", task.Uri, task.State, taskTimeout, task.PercentComplete))
+		case !finished:
+			logger.Debugf("task %s is %s, %d%% complete", task.Uri, task.State, task.PercentComplete)
+			running++
+		case !succeeded:
+			failures = append(failures, fmt.Sprintf("task %s is %s: %s", task.Uri, task.State, strings.Join(task.Messages, "; ")))
+		}
+	}
+
+	// wait for all the tasks to finish, so the failure of a drive does not hide the progress of others
+	switch {
+	case running > 0:
+		return
+	case len(failures) > 0:
+		setStepState(step, topohubv1beta1.DecommissionStepFailed, strings.Join(failures, "; "))
+	default:
+		setStepState(step, topohubv1beta1.DecommissionStepCompleted, "")
+	}
+}
+
+// removeHost deletes the HostEndpoint, BindingIp and HostStatus of the host, the HostInventory is deleted with the HostStatus
+func (r *HostOperationController) removeHost(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation, hostStatus *topohubv1beta1.HostStatus, step *topohubv1beta1.DecommissionStepStatus) {
+	step.StartTime = time.Now().UTC().Format(time.RFC3339)
+	name := hostOp.Spec.HostStatusName
+
+	// the HostStatus of a multi-node host is shared by all the systems
+	if hostStatus != nil && len(hostStatus.Status.Systems) > 1 {
+		setStepState(step, topohubv1beta1.DecommissionStepSkipped, fmt.Sprintf("hostStatus %s has %d systems, remove it after all of them are decommissioned", name, len(hostStatus.Status.Systems)))
+		return
+	}
+
+	var objects []client.Object
+	// the HostStatus of the HostEndpoint has the same name
+	hostEndpoint := &topohubv1beta1.HostEndpoint{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, hostEndpoint); err == nil {
+		objects = append(objects, hostEndpoint)
+	} else if !apierrors.IsNotFound(err) {
+		setStepState(step, topohubv1beta1.DecommissionStepFailed, fmt.Sprintf("failed to get hostEndpoint %s: %v", name, err))
+		return
+	}
+
+	ip := strings.Split(hostOp.Status.IpAddr, "/")[0]
+	if hostStatus != nil {
+		ip = strings.Split(hostStatus.Status.Basic.IpAddr, "/")[0]
+	}
+	bindingIpList := &topohubv1beta1.BindingIpList{}
+	if err := r.List(ctx, bindingIpList); err != nil {
+		setStepState(step, topohubv1beta1.DecommissionStepFailed, fmt.Sprintf("failed to list bindingIp: %v", err))
+		return
+	}
+	for i := range bindingIpList.Items {
+		if len(ip) > 0 && bindingIpList.Items[i].Spec.IpAddr == ip {
+			objects = append(objects, &bindingIpList.Items[i])
+		}
+	}
+
+	if hostStatus != nil {
+		objects = append(objects, hostStatus)
+	}
+
+	removed := []string{}
+	for _, obj := range objects {
+		kind := fmt.Sprintf("%T", obj)
+		kind = kind[strings.LastIndex(kind, ".")+1:]
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			setStepState(step, topohubv1beta1.DecommissionStepFailed, fmt.Sprintf("failed to delete %s %s: %v", kind, obj.GetName(), err))
+			return
+		}
+		logger.Infof("Deleted %s %s of the decommissioned host", kind, obj.GetName())
+		removed = append(removed, fmt.Sprintf("%s %s", kind, obj.GetName()))
+	}
+	if len(removed) == 0 {
+		setStepState(step, topohubv1beta1.DecommissionStepCompleted, "the host has been removed")
+		return
+	}
+	setStepState(step, topohubv1beta1.DecommissionStepCompleted, fmt.Sprintf("deleted %s", strings.Join(removed, ", ")))
+}
+
+func setStepState(step *topohubv1beta1.DecommissionStepStatus, state, message string) {
+	step.State = state
+	step.Message = message
+	if state != topohubv1beta1.DecommissionStepRunning {
+		step.CompletionTime = time.Now().UTC().Format(time.RFC3339)
+	}
+}
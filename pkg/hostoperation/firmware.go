@@ -0,0 +1,83 @@
+package hostoperation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// updateFirmware delivers the firmware image to the bmc, and returns the uri of the redfish task
+func (r *HostOperationController) updateFirmware(ctx context.Context, c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation, hostStatus *topohubv1beta1.HostStatus) (string, error) {
+	spec := hostOp.Spec.Firmware
+	if spec == nil || len(spec.ImagePath) == 0 {
+		return "", fmt.Errorf("spec.firmware.imagePath is required for the action %s", hostOp.Spec.Action)
+	}
+
+	// the image is a file under the root directory of the http server
+	relativePath := path.Clean("/" + spec.ImagePath)
+	imageFile := filepath.Join(r.agentConfig.StoragePathHttp, relativePath)
+	if _, err := os.Stat(imageFile); err != nil {
+		return "", fmt.Errorf("failed to find the firmware image %s: %v", imageFile, err)
+	}
+
+	if spec.TransferMethod == topohubv1beta1.FirmwareTransferMultipartPush {
+		return c.MultipartUpdate(imageFile, spec.Targets)
+	}
+
+	imageUri, err := r.imageUri(ctx, hostStatus, relativePath)
+	if err != nil {
+		return "", err
+	}
+	return c.SimpleUpdate(imageUri, spec.Targets)
+}
+
+// finishFirmwareUpdate records the firmware version after updating to the HostOperation and the HostStatus
+func (r *HostOperationController) finishFirmwareUpdate(ctx context.Context, logger *zap.SugaredLogger, c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation) {
+	var targets []string
+	imagePath := ""
+	if hostOp.Spec.Firmware != nil {
+		targets = hostOp.Spec.Firmware.Targets
+		imagePath = hostOp.Spec.Firmware.ImagePath
+	}
+
+	if hostOp.Status.Status == topohubv1beta1.HostOperationStatusSuccess && c != nil {
+		versions, err := c.GetFirmwareVersions(targets)
+		if err != nil {
+			logger.Warnf("Failed to get firmware version of %s: %v", hostOp.Spec.HostStatusName, err)
+		} else {
+			hostOp.Status.FirmwareVersions = versions
+		}
+	}
+
+	record := &topohubv1beta1.FirmwareUpdateRecord{
+		HostOperation: hostOp.Name,
+		ImagePath:     imagePath,
+		Status:        hostOp.Status.Status,
+		Time:          time.Now().UTC().Format(time.RFC3339),
+		Message:       hostOp.Status.Message,
+		Firmware:      hostOp.Status.FirmwareVersions,
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hostStatus := &topohubv1beta1.HostStatus{}
+		if err := r.Get(ctx, client.ObjectKey{Name: hostOp.Spec.HostStatusName}, hostStatus); err != nil {
+			return err
+		}
+		hostStatus.Status.LastFirmwareUpdate = record
+		return r.Status().Update(ctx, hostStatus)
+	})
+	if err != nil {
+		logger.Errorf("Failed to record the firmware update to HostStatus %s: %v", hostOp.Spec.HostStatusName, err)
+		return
+	}
+	logger.Infof("Recorded the firmware update to HostStatus %s", hostOp.Spec.HostStatusName)
+}
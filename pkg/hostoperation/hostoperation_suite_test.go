@@ -0,0 +1,13 @@
+package hostoperation
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHostOperation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HostOperation Suite")
+}
@@ -0,0 +1,96 @@
+package hostoperation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// keep retrying to revert the indicator led for a while, the bmc may be unreachable for a moment
+const revertRetryDuration = 10 * time.Minute
+
+// setIndicatorLED sets the indicator led of the chassis, and schedules to revert it to the previous state
+func (r *HostOperationController) setIndicatorLED(c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation) error {
+	spec := hostOp.Spec.IndicatorLED
+	if spec == nil {
+		return fmt.Errorf("spec.indicatorLED is required for the action %s", hostOp.Spec.Action)
+	}
+
+	previous, err := c.SetIndicatorLED(hostOp.Spec.SystemId, spec.State)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	status := &topohubv1beta1.IndicatorLEDStatus{
+		PreviousState: previous,
+		State:         spec.State,
+		SetTime:       now.Format(time.RFC3339),
+	}
+	// the led which is turned off is not turned on again
+	if spec.State != topohubv1beta1.IndicatorLEDOff && previous != spec.State && spec.RevertAfterMinutes != nil && *spec.RevertAfterMinutes > 0 {
+		status.RevertTime = now.Add(time.Duration(*spec.RevertAfterMinutes) * time.Minute).Format(time.RFC3339)
+	}
+	hostOp.Status.IndicatorLED = status
+	return nil
+}
+
+// revertIndicatorLED reverts the indicator led to the previous state when it is time, and finishes the HostOperation
+func (r *HostOperationController) revertIndicatorLED(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation) (ctrl.Result, error) {
+	led := hostOp.Status.IndicatorLED
+	revertTime, terr := time.Parse(time.RFC3339, led.RevertTime)
+	if terr == nil {
+		if remain := time.Until(revertTime); remain > 0 {
+			logger.Debugf("the indicator led of %s will be reverted after %s", hostOp.Spec.HostStatusName, remain)
+			return ctrl.Result{RequeueAfter: remain}, nil
+		}
+	}
+
+	// the bmc may report the Unknown state, turn off the led in that case
+	state := led.PreviousState
+	if state != topohubv1beta1.IndicatorLEDLit && state != topohubv1beta1.IndicatorLEDBlinking {
+		state = topohubv1beta1.IndicatorLEDOff
+	}
+
+	var err error
+	d := hoststatusData.HostCacheDatabase.Get(hostOp.Spec.HostStatusName)
+	if d == nil {
+		err = fmt.Errorf("failed to get connect config %s from cache", hostOp.Spec.HostStatusName)
+	} else {
+		var c redfish.RefishClient
+		c, err = redfish.NewClient(*d, logger)
+		if err == nil {
+			_, err = c.SetIndicatorLED(hostOp.Spec.SystemId, state)
+		}
+	}
+
+	if err != nil {
+		if terr == nil && time.Since(revertTime) < revertRetryDuration {
+			logger.Warnf("Failed to revert the indicator led of %s, retry later: %v", hostOp.Spec.HostStatusName, err)
+			return ctrl.Result{RequeueAfter: taskPollInterval}, nil
+		}
+		logger.Errorf("Failed to revert the indicator led of %s: %v", hostOp.Spec.HostStatusName, err)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+		hostOp.Status.Message = fmt.Sprintf("failed to revert the indicator led to %s: %v", state, err)
+	} else {
+		logger.Infof("Succeeded to revert the indicator led of %s to %s", hostOp.Spec.HostStatusName, state)
+		led.Reverted = true
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusSuccess
+		hostOp.Status.Message = ""
+	}
+	r.finishOperation(ctx, logger, nil, hostOp)
+
+	hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Status().Update(ctx, hostOp); err != nil {
+		logger.Errorf("Failed to update HostOperation status: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
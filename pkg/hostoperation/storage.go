@@ -0,0 +1,74 @@
+package hostoperation
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+)
+
+// installsOS returns true for the actions which boot the host to install the operating system
+func installsOS(action string) bool {
+	return action == topohubv1beta1.BootCmdResetPxeOnce || action == topohubv1beta1.ActionVirtualMediaBoot
+}
+
+// checkStorage checks the StorageConfig which selects the host before installing the operating system.
+// It returns the reason to wait when the volumes are not ready yet, and the error when they could not be configured
+func (r *HostOperationController) checkStorage(ctx context.Context, hostOp *topohubv1beta1.HostOperation, hostStatus *topohubv1beta1.HostStatus) (string, error) {
+	storageConfigList := &topohubv1beta1.StorageConfigList{}
+	if err := r.List(ctx, storageConfigList); err != nil {
+		return "", fmt.Errorf("failed to list StorageConfig: %v", err)
+	}
+
+	for _, storageConfig := range storageConfigList.Items {
+		selected, err := selectsHost(&storageConfig, hostStatus)
+		if err != nil {
+			return "", fmt.Errorf("StorageConfig %s: %v", storageConfig.Name, err)
+		}
+		if !selected {
+			continue
+		}
+
+		reported := false
+		for _, item := range storageConfig.Status.Hosts {
+			if item.HostStatusName != hostStatus.Name {
+				continue
+			}
+			if len(hostOp.Spec.SystemId) > 0 && len(item.SystemId) > 0 && item.SystemId != hostOp.Spec.SystemId {
+				continue
+			}
+			reported = true
+			switch item.State {
+			case topohubv1beta1.StorageStateInSync:
+			case topohubv1beta1.StorageStatePending:
+				return fmt.Sprintf("waiting for the volumes of StorageConfig %s", storageConfig.Name), nil
+			default:
+				return "", fmt.Errorf("the volumes of StorageConfig %s are %s: %s", storageConfig.Name, item.State, item.Message)
+			}
+		}
+		if !reported {
+			return fmt.Sprintf("waiting for StorageConfig %s to check the volumes", storageConfig.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// selectsHost returns true when the StorageConfig selects the host by the name or the labels
+func selectsHost(storageConfig *topohubv1beta1.StorageConfig, hostStatus *topohubv1beta1.HostStatus) (bool, error) {
+	for _, name := range storageConfig.Spec.HostStatusNames {
+		if name == hostStatus.Name {
+			return true, nil
+		}
+	}
+	if storageConfig.Spec.HostSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(storageConfig.Spec.HostSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid hostSelector: %v", err)
+	}
+	return selector.Matches(labels.Set(hostStatus.Labels)), nil
+}
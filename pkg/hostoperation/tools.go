@@ -0,0 +1,30 @@
+package hostoperation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/tools"
+)
+
+// imageUri returns the url of the file on the http server of topohub, which the bmc of the host could access
+func (r *HostOperationController) imageUri(ctx context.Context, hostStatus *topohubv1beta1.HostStatus, relativePath string) (string, error) {
+	if !r.agentConfig.HttpEnabled {
+		return "", fmt.Errorf("the http server of topohub is disabled")
+	}
+
+	address, err := tools.GetAgentAddress(ctx, r.Client, r.agentConfig.HttpServerAddress, hostStatus)
+	if err != nil {
+		return "", err
+	}
+
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(address, r.agentConfig.HttpPort),
+		Path:   relativePath,
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,107 @@
+package hostoperation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// keep retrying to eject the iso for a while, the bmc may be busy when the os is installing
+const ejectRetryDuration = 10 * time.Minute
+
+// bootVirtualMedia inserts the ISO on the http server to the virtual media of the host, and boots the host from it. It
+// returns the uri of the redfish task when the bmc resets the host asynchronously
+func (r *HostOperationController) bootVirtualMedia(ctx context.Context, c redfish.RefishClient, hostOp *topohubv1beta1.HostOperation, hostStatus *topohubv1beta1.HostStatus) (string, error) {
+	spec := hostOp.Spec.VirtualMedia
+	if spec == nil || len(spec.IsoFile) == 0 {
+		return "", fmt.Errorf("spec.virtualMedia.isoFile is required for the action %s", hostOp.Spec.Action)
+	}
+
+	isoFile := filepath.Join(r.agentConfig.StoragePathHttpIso, path.Clean("/"+spec.IsoFile))
+	if _, err := os.Stat(isoFile); err != nil {
+		return "", fmt.Errorf("failed to find the iso %s: %v", isoFile, err)
+	}
+	relativePath, err := filepath.Rel(r.agentConfig.StoragePathHttp, isoFile)
+	if err != nil {
+		return "", err
+	}
+	imageUri, err := r.imageUri(ctx, hostStatus, "/"+filepath.ToSlash(relativePath))
+	if err != nil {
+		return "", err
+	}
+
+	mediaUri, taskUri, err := c.VirtualMediaBoot(hostOp.Spec.SystemId, imageUri)
+	if len(mediaUri) == 0 {
+		return "", err
+	}
+
+	// record the virtual media even if the boot fails, so that the administrator knows the iso is inserted
+	now := time.Now().UTC()
+	status := &topohubv1beta1.VirtualMediaStatus{
+		MediaUri:     mediaUri,
+		ImageUri:     imageUri,
+		InsertedTime: now.Format(time.RFC3339),
+	}
+	if err == nil && spec.EjectAfterMinutes != nil && *spec.EjectAfterMinutes > 0 {
+		status.EjectTime = now.Add(time.Duration(*spec.EjectAfterMinutes) * time.Minute).Format(time.RFC3339)
+	}
+	hostOp.Status.VirtualMedia = status
+	return taskUri, err
+}
+
+// ejectVirtualMedia ejects the ISO from the virtual media when it is time, and finishes the HostOperation
+func (r *HostOperationController) ejectVirtualMedia(ctx context.Context, logger *zap.SugaredLogger, hostOp *topohubv1beta1.HostOperation) (ctrl.Result, error) {
+	vm := hostOp.Status.VirtualMedia
+	ejectTime, terr := time.Parse(time.RFC3339, vm.EjectTime)
+	if terr == nil {
+		if remain := time.Until(ejectTime); remain > 0 {
+			logger.Debugf("the iso of %s will be ejected after %s", hostOp.Spec.HostStatusName, remain)
+			return ctrl.Result{RequeueAfter: remain}, nil
+		}
+	}
+
+	var err error
+	d := hoststatusData.HostCacheDatabase.Get(hostOp.Spec.HostStatusName)
+	if d == nil {
+		err = fmt.Errorf("failed to get connect config %s from cache", hostOp.Spec.HostStatusName)
+	} else {
+		var c redfish.RefishClient
+		c, err = redfish.NewClient(*d, logger)
+		if err == nil {
+			err = c.EjectVirtualMedia(vm.MediaUri)
+		}
+	}
+
+	if err != nil {
+		if terr == nil && time.Since(ejectTime) < ejectRetryDuration {
+			logger.Warnf("Failed to eject the iso of %s, retry later: %v", hostOp.Spec.HostStatusName, err)
+			return ctrl.Result{RequeueAfter: taskPollInterval}, nil
+		}
+		logger.Errorf("Failed to eject the iso of %s: %v", hostOp.Spec.HostStatusName, err)
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusFailed
+		hostOp.Status.Message = fmt.Sprintf("failed to eject the iso: %v", err)
+	} else {
+		logger.Infof("Succeeded to eject the iso of %s", hostOp.Spec.HostStatusName)
+		vm.Ejected = true
+		hostOp.Status.Status = topohubv1beta1.HostOperationStatusSuccess
+		hostOp.Status.Message = ""
+	}
+	r.finishOperation(ctx, logger, nil, hostOp)
+
+	hostOp.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Status().Update(ctx, hostOp); err != nil {
+		logger.Errorf("Failed to update HostOperation status: %v", err)
+		return ctrl.Result{}, fmt.Errorf("failed to update HostOperation status: %v", err)
+	}
+	return ctrl.Result{}, nil
+}
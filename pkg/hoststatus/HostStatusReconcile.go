@@ -5,16 +5,18 @@ package hoststatus
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
 	"github.com/infrastructure-io/topohub/pkg/subnet/dhcpserver"
 
 	//"github.com/infrastructure-io/topohub/pkg/lock"
-	"github.com/infrastructure-io/topohub/pkg/redfish"
-
 	gofishredfish "github.com/stmcginnis/gofish/redfish"
 
 	"go.uber.org/zap"
@@ -24,9 +26,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-// the lock-holding timeout is long because it needs to send http request to redfish for each host
+// hostStatusLocks serializes the updates of the same host, while the different hosts are updated in parallel.
+// The lock-holding timeout is long because it needs to send http request to redfish for each host
 // so it uses sync.Mutex instead of lock.Mutex
-var hostStatusLock = &sync.Mutex{}
+var hostStatusLocks sync.Map
+
+func hostStatusLock(name string) *sync.Mutex {
+	l, _ := hostStatusLocks.LoadOrStore(name, &sync.Mutex{})
+	return l.(*sync.Mutex)
+}
 
 // ------------------------------  update the spec.info of the hoststatus
 
@@ -88,16 +96,21 @@ func (c *hostStatusController) GenerateEvents(logEntrys []*gofishredfish.LogEntr
 	return
 }
 
-// this is called by UpdateHostStatusAtInterval and UpdateHostStatusWrapper
+// this is called by UpdateHostStatusWrapper, it waits for the running update of the same host
 func (c *hostStatusController) UpdateHostStatusInfo(name string, d *hoststatusdata.HostConnectCon) (bool, error) {
-
 	// local lock for updateing each hostStatus
-	hostStatusLock.Lock()
-	defer hostStatusLock.Unlock()
+	l := hostStatusLock(name)
+	l.Lock()
+	defer l.Unlock()
+	return c.updateHostStatusInfo(name, d)
+}
 
+// updateHostStatusInfo queries the bmc and updates the status of the hostStatus, the caller holds the lock of the host
+func (c *hostStatusController) updateHostStatusInfo(name string, d *hoststatusdata.HostConnectCon) (bool, error) {
 	// 创建 redfish 客户端
 	var healthy bool
-	client, err1 := redfish.NewClient(*d, c.log)
+	basic := d.Info
+	client, err1 := c.connectHost(d)
 	if err1 != nil {
 		c.log.Warnf("Failed to create redfish client for HostStatus %s: %v", name, err1)
 		healthy = false
@@ -124,8 +137,27 @@ func (c *hostStatusController) UpdateHostStatusInfo(name string, d *hoststatusda
 	}
 	updated := existing.DeepCopy()
 
+	// bmc 重启期间无法访问，保留原来的状态，而不是标记为不健康
+	resetting := err1 != nil && c.unreachableWhileResetting(updated)
+	if resetting {
+		c.log.Infof("HostStatus %s is unreachable while its bmc is resetting", name)
+	}
+
+	// 记录 bmc 的证书，证书被意外替换时告警
+	c.syncCertificate(d, client, err1, updated)
+
+	// the protocol of the dhcp host is detected
+	if d.Info != basic {
+		updated.Status.Basic.Protocol = d.Info.Protocol
+		updated.Status.Basic.Port = d.Info.Port
+		updated.Status.Basic.Https = d.Info.Https
+		hoststatusdata.HostCacheDatabase.Add(name, *d)
+	}
+
 	// 检查健康状态
-	updated.Status.Healthy = healthy
+	if !resetting {
+		updated.Status.Healthy = healthy
+	}
 	if healthy {
 		infoData, err := client.GetInfo()
 		if err != nil {
@@ -135,9 +167,48 @@ func (c *hostStatusController) UpdateHostStatusInfo(name string, d *hoststatusda
 			updated.Status.Info = infoData
 		}
 	}
-	if !healthy {
+	if healthy {
+		systems, err := client.GetSystems()
+		if err != nil {
+			c.log.Errorf("Failed to get systems of HostStatus %s: %v", name, err)
+			healthy = false
+		} else {
+			updated.Status.Systems = systems
+		}
+	}
+	if healthy {
+		managers, err := client.GetManagers()
+		if err != nil {
+			c.log.Errorf("Failed to get managers of HostStatus %s: %v", name, err)
+			healthy = false
+		} else {
+			updated.Status.Managers = managers
+		}
+	}
+	if healthy {
+		// the details of the hardware are recorded in the HostInventory, rather than the flat status.info
+		inventory, err := client.GetInventory()
+		if err != nil {
+			c.log.Warnf("Failed to get inventory of HostStatus %s: %v", name, err)
+		} else if err := c.syncHostInventory(existing, inventory); err != nil {
+			c.log.Warnf("Failed to sync HostInventory %s: %v", name, err)
+		}
+	}
+	// the sensors are only read over redfish
+	if healthy && d.Info.Protocol != topohubv1beta1.ProtocolIPMI {
+		readings, err := client.GetSensors()
+		if err != nil {
+			c.log.Warnf("Failed to get sensors of HostStatus %s: %v", name, err)
+		} else {
+			c.exportSensorMetrics(updated, readings)
+		}
+	}
+	if !healthy && !resetting {
 		c.log.Debugf("HostStatus %s is not healthy, set info to empty", name)
 		updated.Status.Info = map[string]string{}
+		updated.Status.Systems = nil
+		updated.Status.Managers = nil
+		metrics.DeleteHostSensors(name)
 	}
 	if updated.Status.Healthy != existing.Status.Healthy {
 		c.log.Infof("HostStatus %s change from %v to %v , update status", name, existing.Status.Healthy, healthy)
@@ -170,6 +241,13 @@ func (c *hostStatusController) UpdateHostStatusInfo(name string, d *hoststatusda
 		}
 	}
 
+	// 订阅 redfish 事件，bmc 重置后订阅会丢失，需要重新订阅
+	if healthy && d.Info.Protocol != topohubv1beta1.ProtocolIPMI {
+		c.syncEventSubscription(client, updated)
+		// 配置 bmc 的 snmp trap 目的地址
+		c.syncSnmpTrap(client, updated)
+	}
+
 	// 更新 HostStatus
 	if !compareHostStatus(updated.Status, existing.Status, c.log) {
 		c.log.Debugf("status changed, existing: %v, updated: %v", existing.Status, updated.Status)
@@ -177,6 +255,8 @@ func (c *hostStatusController) UpdateHostStatusInfo(name string, d *hoststatusda
 		if err := c.client.Status().Update(context.Background(), updated); err != nil {
 			return true, err
 		}
+		// the events are accepted with the token of the subscription once it is recorded
+		hoststatusdata.HostCacheDatabase.UpdateEventToken(name, subscriptionToken(updated))
 		c.log.Infof("Successfully updated HostStatus %s status", name)
 		return true, nil
 	}
@@ -185,47 +265,132 @@ func (c *hostStatusController) UpdateHostStatusInfo(name string, d *hoststatusda
 
 // this is called by UpdateHostStatusAtInterval and
 func (c *hostStatusController) UpdateHostStatusInfoWrapper(name string) error {
-	syncData := make(map[string]hoststatusdata.HostConnectCon)
-	modeinfo := ""
 	if len(name) == 0 {
-		syncData = hoststatusdata.HostCacheDatabase.GetAll()
+		syncData := hoststatusdata.HostCacheDatabase.GetAll()
 		if len(syncData) == 0 {
 			return nil
 		}
-		modeinfo = " during periodic update"
+		return c.updateHostStatusInParallel(syncData)
+	}
+
+	d := hoststatusdata.HostCacheDatabase.Get(name)
+	if d == nil {
+		c.log.Errorf("no cache data found for hostStatus %s ", name)
+		return fmt.Errorf("no cache data found for hostStatus %s ", name)
+	}
+	c.log.Debugf("updating status of the hostStatus %s", name)
+	updated, err := c.UpdateHostStatusInfo(name, d)
+	if err != nil {
+		c.log.Errorf("failed to update status of HostStatus %s, during hoststatus reconcile: %v", name, err)
+		return fmt.Errorf("failed to update hostStatus")
+	}
+	if updated {
+		c.log.Debugf("succeeded to update status of the hostStatus %s, during hoststatus reconcile", name)
 	} else {
-		d := hoststatusdata.HostCacheDatabase.Get(name)
-		if d != nil {
-			syncData[name] = *d
-		}
-		if len(syncData) == 0 {
-			c.log.Errorf("no cache data found for hostStatus %s ", name)
-			return fmt.Errorf("no cache data found for hostStatus %s ", name)
-		}
-		modeinfo = " during hoststatus reconcile"
+		c.log.Debugf("no need to update status of the hostStatus %s, during hoststatus reconcile", name)
 	}
+	return nil
+}
 
-	failed := false
-	for item, t := range syncData {
-		c.log.Debugf("updating status of the hostStatus %s", item)
-		if updated, err := c.UpdateHostStatusInfo(item, &t); err != nil {
-			c.log.Errorf("failed to update status of HostStatus %s, %s: %v", item, modeinfo, err)
-			failed = true
-		} else {
-			if updated {
-				c.log.Debugf("succeeded to update status of the hostStatus %s, %s", item, modeinfo)
-			} else {
-				c.log.Debugf("no need to update status of the hostStatus %s, %s", item, modeinfo)
+// updateHostStatusInParallel updates the hosts with a bounded number of workers. Each host waits a random delay to
+// spread the requests to the bmc. A host whose former update is still running, for example, its bmc does not respond,
+// is skipped, so a slow bmc does not stall the other hosts
+func (c *hostStatusController) updateHostStatusInParallel(syncData map[string]hoststatusdata.HostConnectCon) error {
+	start := time.Now()
+	defer func() {
+		metrics.HostStatusUpdateCycleDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	workers := c.config.RedfishHostStatusUpdateParallelism
+	if workers > len(syncData) {
+		workers = len(syncData)
+	}
+	names := make(chan string)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				if jitter := c.config.RedfishHostStatusUpdateJitter; jitter > 0 {
+					select {
+					case <-c.stopCh:
+						continue
+					case <-time.After(time.Duration(rand.Int63n(int64(jitter) * int64(time.Second)))):
+					}
+				}
+				d := syncData[name]
+				if err := c.updateHostStatusWithTimeout(name, &d); err != nil {
+					c.log.Errorf("failed to update status of HostStatus %s, during periodic update: %v", name, err)
+					failed.Store(true)
+				}
 			}
+		}()
+	}
+
+	stopped := false
+	for name := range syncData {
+		if stopped {
+			break
+		}
+		select {
+		case names <- name:
+		case <-c.stopCh:
+			stopped = true
 		}
 	}
+	close(names)
+	wg.Wait()
 
-	if failed {
+	if failed.Load() {
 		return fmt.Errorf("failed to update hostStatus")
 	}
 	return nil
 }
 
+// updateHostStatusWithTimeout updates the host in the periodic cycle. The update keeps running in the background
+// after the timeout, and holds the lock of the host, so the host is skipped in the following cycles until it finishes
+func (c *hostStatusController) updateHostStatusWithTimeout(name string, d *hoststatusdata.HostConnectCon) error {
+	l := hostStatusLock(name)
+	if !l.TryLock() {
+		c.log.Warnf("skip the periodic update of hostStatus %s, its former update is still running", name)
+		metrics.HostStatusUpdatesTotal.WithLabelValues("skipped").Inc()
+		return nil
+	}
+
+	c.log.Debugf("updating status of the hostStatus %s", name)
+	type result struct {
+		updated bool
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer l.Unlock()
+		updated, err := c.updateHostStatusInfo(name, d)
+		done <- result{updated: updated, err: err}
+	}()
+
+	timeout := time.Duration(c.config.RedfishHostStatusUpdateTimeout) * time.Second
+	select {
+	case r := <-done:
+		if r.err != nil {
+			metrics.HostStatusUpdatesTotal.WithLabelValues("failure").Inc()
+			return r.err
+		}
+		metrics.HostStatusUpdatesTotal.WithLabelValues("success").Inc()
+		if r.updated {
+			c.log.Debugf("succeeded to update status of the hostStatus %s, during periodic update", name)
+		} else {
+			c.log.Debugf("no need to update status of the hostStatus %s, during periodic update", name)
+		}
+		return nil
+	case <-time.After(timeout):
+		metrics.HostStatusUpdatesTotal.WithLabelValues("timeout").Inc()
+		return fmt.Errorf("the update does not finish in %v, it keeps running in the background", timeout)
+	}
+}
+
 // ------------------------------  hoststatus spec.info 的	周期更新
 func (c *hostStatusController) UpdateHostStatusAtInterval() {
 	interval := time.Duration(c.config.RedfishHostStatusUpdateInterval) * time.Second
@@ -243,6 +408,7 @@ func (c *hostStatusController) UpdateHostStatusAtInterval() {
 			if err := c.UpdateHostStatusInfoWrapper(""); err != nil {
 				c.log.Errorf("Failed to update host status: %v", err)
 			}
+			c.exportHostStatusCount()
 		}
 	}
 }
@@ -277,11 +443,19 @@ func (c *hostStatusController) processHostStatus(hostStatus *topohubv1beta1.Host
 		logger.Debugf("Adding/Updating HostStatus %s in cache with empty username", hostStatus.Name)
 	}
 
+	trust, err := c.tlsTrust(&hostStatus.Status.Basic, hostStatus.Status.Certificate)
+	if err != nil {
+		logger.Errorf("Failed to get the tls configuration for HostStatus %s: %v", hostStatus.Name, err)
+		return err
+	}
+
 	hoststatusdata.HostCacheDatabase.Add(hostStatus.Name, hoststatusdata.HostConnectCon{
-		Info:     &hostStatus.Status.Basic,
-		Username: username,
-		Password: password,
-		DhcpHost: hostStatus.Status.Basic.Type == topohubv1beta1.HostTypeDHCP,
+		Info:       &hostStatus.Status.Basic,
+		Username:   username,
+		Password:   password,
+		DhcpHost:   hostStatus.Status.Basic.Type == topohubv1beta1.HostTypeDHCP,
+		TLS:        trust,
+		EventToken: subscriptionToken(hostStatus),
 	})
 
 	if len(hostStatus.Status.Info) == 0 {
@@ -324,6 +498,10 @@ func (c *hostStatusController) Reconcile(ctx context.Context, req ctrl.Request)
 				// try to delete the binding setting in dhcp server config
 				logger.Infof("delete hostStatus %s in cache, %+v", req.Name, *data)
 				hoststatusdata.HostCacheDatabase.Delete(req.Name)
+				metrics.DeleteHostSensors(req.Name)
+				metrics.DeleteRedfishHost(data.Info.IpAddr)
+				redfish.CacheClient.Delete(data.Info.IpAddr)
+				hostStatusLocks.Delete(req.Name)
 			}
 			return ctrl.Result{}, nil
 		}
@@ -12,6 +12,24 @@ type HostConnectCon struct {
 	Username string
 	Password string
 	DhcpHost bool
+	// TLS is the trust of the certificate of the bmc, the certificate is not verified when it is nil
+	TLS *TLSTrust
+	// EventToken is the token in the destination of the redfish event subscription, the events pushed by the bmc are
+	// only accepted with it
+	EventToken string
+}
+
+// TLSTrust 定义 bmc 证书的校验方式，它由 HostEndpoint 或者 Subnet 的 tls 配置解析而来
+type TLSTrust struct {
+	// CAPem holds the PEM encoded CA certificates, the certificate chain of the bmc is verified when it is not empty
+	CAPem []byte
+	// ServerName is verified against the certificate issued by the CA, it defaults to the ip address of the bmc
+	ServerName string
+	// Fingerprints pins the SHA-256 fingerprints of the certificate
+	Fingerprints []string
+	// KnownFingerprint is the fingerprint recorded on first use, it is trusted when neither CAPem nor Fingerprints is set
+	KnownFingerprint   string
+	InsecureSkipVerify bool
 }
 
 // HostCache 定义主机缓存结构
@@ -100,6 +118,31 @@ func (c *HostCache) GetStaticClientInfo() map[string]HostConnectCon {
 	return result
 }
 
+// UpdateKnownFingerprint replaces the fingerprint of the certificate trusted on first use, it returns false when the
+// host does not trust the certificate on first use
+func (c *HostCache) UpdateKnownFingerprint(name, fingerprint string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	v, exists := c.data[name]
+	if !exists || v.TLS == nil || len(v.TLS.KnownFingerprint) == 0 {
+		return false
+	}
+	// the trust is shared with the copies returned by Get
+	trust := *v.TLS
+	trust.KnownFingerprint = fingerprint
+	v.TLS = &trust
+	return true
+}
+
+// UpdateEventToken replaces the token of the redfish event subscription of the host
+func (c *HostCache) UpdateEventToken(name, token string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if v, exists := c.data[name]; exists {
+		v.EventToken = token
+	}
+}
+
 func (c *HostCache) UpdateSecet(secretName, secretNamespace, username, password string) []string {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -7,8 +7,6 @@ import (
 	"time"
 
 	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
-	"github.com/infrastructure-io/topohub/pkg/redfish"
-
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
 	"github.com/infrastructure-io/topohub/pkg/subnet/dhcpserver"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -133,16 +131,24 @@ func (c *hostStatusController) handleDHCPAdd(client dhcpserver.DhcpClientInfo) e
 		c.log.Errorf("Failed to get secret data from secret %s/%s when creating HostStatus for %s: %v", c.config.RedfishSecretNamespace, c.config.RedfishSecretName, client.IP, err)
 		return err
 	}
+	trust, err := c.tlsTrust(&basicInfo, nil)
+	if err != nil {
+		c.log.Errorf("Failed to get the tls configuration when creating HostStatus for %s: %v", client.IP, err)
+		return err
+	}
 	d := hoststatusdata.HostConnectCon{
 		Info:     &basicInfo,
 		Username: username,
 		Password: password,
 		DhcpHost: true,
+		TLS:      trust,
 	}
-	if _, err := redfish.NewClient(d, c.log); err != nil {
+	// the protocol of the bmc is detected, ipmi is used when it has no usable redfish
+	if _, err := c.connectHost(&d); err != nil {
 		c.log.Warnf("ignore creating hoststatus for dhcp client %s, failed to connect: %v", client.IP, err)
 		return nil
 	}
+	basicInfo = *d.Info
 
 	c.log.Debugf("succeed to checking the hoststatus %s, and create hoststatus for it", client.IP)
 	hostStatus := &topohubv1beta1.HostStatus{
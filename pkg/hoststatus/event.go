@@ -0,0 +1,328 @@
+// 接收 bmc 通过 redfish EventService 推送的事件
+
+package hoststatus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	gofishredfish "github.com/stmcginnis/gofish/redfish"
+
+	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/tools"
+)
+
+const (
+	// the bmc pushes the events of the host to /redfish/events/<hostStatusName>/<token>, the token is generated randomly
+	// for the subscription of the host, so the events could not be forged without the token
+	redfishEventPath = "/redfish/events/"
+	// the size limit of an event request
+	maxEventBodySize = 1 << 20
+	// the events of a host are written to its HostStatus at most once in the interval, the bmc may push a burst of events
+	eventFlushInterval = 10 * time.Second
+)
+
+// pendingEvents counts the events of a host which have not been written to its HostStatus
+type pendingEvents struct {
+	total               int
+	warningCount        int
+	lastestEvent        *topohubv1beta1.LogEntry
+	lastestWarningEvent *topohubv1beta1.LogEntry
+}
+
+// newEventServer creates the https listener for the redfish events, it uses the certificate of the webhook
+func (c *hostStatusController) newEventServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(redfishEventPath, c.handleRedfishEvent)
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%s", c.config.RedfishEventPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+func (c *hostStatusController) runEventServer() {
+	certFile := filepath.Join(c.config.WebhookCertDir, "tls.crt")
+	keyFile := filepath.Join(c.config.WebhookCertDir, "tls.key")
+	c.log.Infof("Starting redfish event listener on address %s", c.eventServer.Addr)
+	if err := c.eventServer.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
+		c.log.Errorf("redfish event listener error: %v", err)
+	}
+}
+
+// handleRedfishEvent receives the events pushed by the bmc
+func (c *hostStatusController) handleRedfishEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, token, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, redfishEventPath), "/")
+	d := hoststatusdata.HostCacheDatabase.Get(name)
+	if d == nil {
+		c.log.Warnf("receive redfish event from %s for unknown hostStatus %s", r.RemoteAddr, name)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// only accept the events from the bmc of the host
+	remoteIp, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || remoteIp != d.Info.IpAddr {
+		c.log.Warnf("reject redfish event for hostStatus %s from %s, which is not the bmc address %s", name, r.RemoteAddr, d.Info.IpAddr)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if !validEventToken(d, token) {
+		c.log.Warnf("reject redfish event for hostStatus %s from %s, whose token does not match the subscription", name, r.RemoteAddr)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	event := gofishredfish.Event{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.log.Warnf("failed to decode redfish event for hostStatus %s: %v", name, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.processRedfishEvent(name, &event)
+	w.WriteHeader(http.StatusOK)
+}
+
+// processRedfishEvent generates the kubernetes events, and records the latest event to the HostStatus
+func (c *hostStatusController) processRedfishEvent(name string, event *gofishredfish.Event) {
+	if len(event.Events) == 0 {
+		return
+	}
+
+	t := &corev1.ObjectReference{
+		Kind:       topohubv1beta1.KindHostStatus,
+		Name:       name,
+		Namespace:  c.config.PodNamespace,
+		APIVersion: topohubv1beta1.APIVersion,
+	}
+
+	var lastestEvent, lastestWarningEvent *topohubv1beta1.LogEntry
+	warningCount := 0
+	for _, record := range event.Events {
+		severity := string(record.MessageSeverity)
+		if len(severity) == 0 {
+			severity = record.Severity
+		}
+		timestamp := record.EventTimestamp
+		if len(timestamp) == 0 {
+			timestamp = time.Now().UTC().Format(time.RFC3339)
+		}
+		msg := fmt.Sprintf("[%s][%s]: %s %s", timestamp, severity, record.MessageID, record.Message)
+
+		ty := corev1.EventTypeNormal
+		entry := &topohubv1beta1.LogEntry{Time: timestamp, Message: msg}
+		if len(severity) > 0 && !strings.EqualFold(severity, string(gofishredfish.OKEventSeverity)) {
+			ty = corev1.EventTypeWarning
+			lastestWarningEvent = entry
+			warningCount++
+		}
+		lastestEvent = entry
+
+		c.log.Infof("receive redfish event for hostStatus %s: %s", name, msg)
+		c.recorder.Event(t, ty, "BMCEvent", msg)
+	}
+
+	c.recordEvents(name, len(event.Events), warningCount, lastestEvent, lastestWarningEvent)
+}
+
+// recordEvents counts the events pushed by the bmc, which are written to the HostStatus after eventFlushInterval, so
+// a burst of events only updates the HostStatus once
+func (c *hostStatusController) recordEvents(name string, total, warningCount int, lastestEvent, lastestWarningEvent *topohubv1beta1.LogEntry) {
+	c.eventLock.Lock()
+	defer c.eventLock.Unlock()
+	if c.pendingEvents == nil {
+		c.pendingEvents = map[string]*pendingEvents{}
+	}
+	pending, ok := c.pendingEvents[name]
+	if !ok {
+		pending = &pendingEvents{}
+		c.pendingEvents[name] = pending
+		time.AfterFunc(eventFlushInterval, func() {
+			c.flushEvents(name)
+		})
+	}
+	pending.total += total
+	pending.warningCount += warningCount
+	pending.lastestEvent = lastestEvent
+	if lastestWarningEvent != nil {
+		pending.lastestWarningEvent = lastestWarningEvent
+	}
+}
+
+// flushEvents writes the pending events of the host to the status of the HostStatus
+func (c *hostStatusController) flushEvents(name string) {
+	c.eventLock.Lock()
+	pending, ok := c.pendingEvents[name]
+	delete(c.pendingEvents, name)
+	c.eventLock.Unlock()
+	if !ok {
+		return
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hostStatus := &topohubv1beta1.HostStatus{}
+		if err := c.client.Get(context.Background(), types.NamespacedName{Name: name}, hostStatus); err != nil {
+			return err
+		}
+		hostStatus.Status.Log.TotalEventAccount += int32(pending.total)
+		hostStatus.Status.Log.WarningEventAccount += int32(pending.warningCount)
+		hostStatus.Status.Log.LastestEvent = pending.lastestEvent
+		if pending.lastestWarningEvent != nil {
+			hostStatus.Status.Log.LastestWarningEvent = pending.lastestWarningEvent
+		}
+		return c.client.Status().Update(context.Background(), hostStatus)
+	})
+	if err != nil {
+		c.log.Errorf("Failed to record %d events to HostStatus %s: %v", pending.total, name, err)
+	}
+}
+
+// validEventToken checks the token of the pushed event with the one in the destination of the subscription of the
+// host, which is cached along with the host, so the pushed events do not load the api server
+func validEventToken(d *hoststatusdata.HostConnectCon, token string) bool {
+	return len(d.EventToken) > 0 && subtle.ConstantTimeCompare([]byte(d.EventToken), []byte(token)) == 1
+}
+
+// subscriptionToken returns the token of the event subscription recorded in the HostStatus
+func subscriptionToken(hostStatus *topohubv1beta1.HostStatus) string {
+	if hostStatus.Status.EventSubscription == nil {
+		return ""
+	}
+	return eventToken(hostStatus.Status.EventSubscription.Destination, hostStatus.Name)
+}
+
+// eventToken returns the token in the destination of the subscription, it is empty for the destination without the
+// token, which is subscribed by the former version
+func eventToken(destination, name string) string {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return ""
+	}
+	token, ok := strings.CutPrefix(u.Path, redfishEventPath+name+"/")
+	if !ok || strings.Contains(token, "/") {
+		return ""
+	}
+	return token
+}
+
+// newEventToken generates the random token for the subscription of a host
+func newEventToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// eventDestinationPrefix returns the url of the event listener for the host, which the bmc of the host could access.
+// The destination of the subscription is the prefix followed by the token
+func (c *hostStatusController) eventDestinationPrefix(hostStatus *topohubv1beta1.HostStatus) (string, error) {
+	address, err := tools.GetAgentAddress(context.Background(), c.client, c.config.HttpServerAddress, hostStatus)
+	if err != nil {
+		return "", err
+	}
+	u := url.URL{
+		Scheme: "https",
+		Host:   net.JoinHostPort(address, c.config.RedfishEventPort),
+		Path:   redfishEventPath + hostStatus.Name + "/",
+	}
+	return u.String(), nil
+}
+
+// syncEventSubscription makes sure the bmc pushes the events to topohub. It subscribes again when the subscription
+// is lost after the bmc is reset, and records the subscription to the status of the HostStatus
+func (c *hostStatusController) syncEventSubscription(client redfish.RefishClient, hostStatus *topohubv1beta1.HostStatus) {
+	existing := hostStatus.Status.EventSubscription
+
+	if !c.config.RedfishEventEnabled {
+		if existing != nil {
+			c.log.Infof("delete the event subscription %s of hostStatus %s", existing.Uri, hostStatus.Name)
+			if err := client.DeleteEventSubscription(existing.Uri); err != nil {
+				c.log.Warnf("Failed to delete the event subscription of hostStatus %s: %v", hostStatus.Name, err)
+			}
+			hostStatus.Status.EventSubscription = nil
+		}
+		return
+	}
+
+	// keep the token of the existing subscription, the events are rejected until the new token is recorded to the HostStatus
+	token := ""
+	if existing != nil {
+		token = eventToken(existing.Destination, hostStatus.Name)
+	}
+	if len(token) == 0 {
+		var err error
+		if token, err = newEventToken(); err != nil {
+			c.log.Warnf("Failed to generate the event token of hostStatus %s: %v", hostStatus.Name, err)
+			return
+		}
+	}
+	prefix, err := c.eventDestinationPrefix(hostStatus)
+	if err != nil {
+		c.log.Warnf("Failed to subscribe redfish events of hostStatus %s: %v", hostStatus.Name, err)
+		return
+	}
+	destination := prefix + token
+
+	subscriptionUri := ""
+	if existing != nil {
+		if existing.Destination == destination {
+			subscriptionUri = existing.Uri
+		} else {
+			// the address of topohub is changed, or the subscription of the former version has no token
+			c.log.Infof("delete the event subscription %s of hostStatus %s to the old destination %s", existing.Uri, hostStatus.Name, existing.Destination)
+			if err := client.DeleteEventSubscription(existing.Uri); err != nil {
+				c.log.Warnf("Failed to delete the event subscription of hostStatus %s: %v", hostStatus.Name, err)
+			}
+		}
+	}
+
+	// the subscriptions of the host with another token are stale, for example, the HostStatus failed to be updated
+	// after the former subscription was created
+	uri, created, err := client.EnsureEventSubscription(subscriptionUri, destination, prefix, hostStatus.Name)
+	if err != nil {
+		c.log.Warnf("Failed to subscribe redfish events of hostStatus %s: %v", hostStatus.Name, err)
+		return
+	}
+	if !created {
+		return
+	}
+	if len(subscriptionUri) > 0 {
+		c.log.Infof("the event subscription %s of hostStatus %s is lost, maybe the bmc is reset, subscribe again: %s", subscriptionUri, hostStatus.Name, uri)
+	} else {
+		c.log.Infof("subscribe redfish events of hostStatus %s to %s: %s", hostStatus.Name, destination, uri)
+	}
+	hostStatus.Status.EventSubscription = &topohubv1beta1.EventSubscriptionInfo{
+		Uri:           uri,
+		Destination:   destination,
+		SubscribeTime: time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,13 @@
+package hoststatus
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHostStatus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HostStatus Suite")
+}
@@ -0,0 +1,390 @@
+package hoststatus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+)
+
+const (
+	testHostStatusName = "emulator"
+	testSecretName     = "bmc-credential"
+	testNamespace      = "topohub"
+)
+
+var _ = Describe("HostStatusController", Label("unitest"), func() {
+	var bmc *emulator.Server
+	var recorder *record.FakeRecorder
+	var c *hostStatusController
+
+	// newController creates the controller with the HostStatus of the emulator
+	newController := func(certificate *topohubv1beta1.CertificateInfo) {
+		scheme := runtime.NewScheme()
+		Expect(topohubv1beta1.AddToScheme(scheme)).To(Succeed())
+		hostStatus := &topohubv1beta1.HostStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: testHostStatusName},
+			Status: topohubv1beta1.HostStatusStatus{
+				Basic: topohubv1beta1.BasicInfo{
+					Type:            topohubv1beta1.HostTypeEndpoint,
+					IpAddr:          bmc.Host(),
+					Port:            bmc.Port(),
+					Https:           true,
+					SecretName:      testSecretName,
+					SecretNamespace: testNamespace,
+				},
+				Certificate: certificate,
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testSecretName, Namespace: testNamespace},
+			Data: map[string][]byte{
+				"username": []byte("admin"),
+				"password": []byte("password"),
+			},
+		}
+		recorder = record.NewFakeRecorder(100)
+		c = &hostStatusController{
+			client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(hostStatus).
+				WithStatusSubresource(&topohubv1beta1.HostStatus{}, &topohubv1beta1.HostInventory{}).
+				Build(),
+			kubeClient: kubefake.NewSimpleClientset(secret),
+			config: &config.AgentConfig{
+				PodNamespace:                   testNamespace,
+				RedfishHostStatusUpdateTimeout: 1,
+			},
+			stopCh:   make(chan struct{}),
+			recorder: recorder,
+			log:      zap.NewNop().Sugar(),
+		}
+	}
+
+	getHostStatus := func() *topohubv1beta1.HostStatus {
+		hostStatus := &topohubv1beta1.HostStatus{}
+		Expect(c.client.Get(context.Background(), types.NamespacedName{Name: testHostStatusName}, hostStatus)).To(Succeed())
+		return hostStatus
+	}
+
+	BeforeEach(func() {
+		bmc = emulator.New()
+		bmc.SetCredential("admin", "password")
+	})
+
+	AfterEach(func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		hoststatusdata.HostCacheDatabase.Delete(testHostStatusName)
+		bmc.Close()
+	})
+
+	It("updates the status of the healthy host", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+
+		hostStatus := getHostStatus()
+		Expect(hostStatus.Status.Healthy).To(BeTrue())
+		Expect(hostStatus.Status.Info).To(HaveKeyWithValue("Manufacturer", "Emulator"))
+		Expect(hostStatus.Status.Systems).To(HaveLen(1))
+		Expect(hostStatus.Status.Managers).To(HaveLen(1))
+
+		// the certificate is trusted on first use
+		Expect(hostStatus.Status.Certificate).NotTo(BeNil())
+		Expect(hostStatus.Status.Certificate.Fingerprint).To(Equal(redfish.Fingerprint(bmc.Certificate())))
+		condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateTrusted)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("TrustOnFirstUse"))
+		condition = meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateExpiring)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("warns the certificate which expires soon", func() {
+		newController(nil)
+		notAfter := time.Now().Add(10 * 24 * time.Hour).UTC().Format(time.RFC3339)
+		hostStatus := getHostStatus()
+		hostStatus.Status.Certificate = &topohubv1beta1.CertificateInfo{Fingerprint: "00:11:22", NotAfter: notAfter}
+
+		c.syncCertificateExpiry(hostStatus)
+		condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateExpiring)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("Expiring"))
+		Expect(condition.Message).To(ContainSubstring(notAfter))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BMCCertificateExpiring")))
+		// the warning is not repeated
+		c.syncCertificateExpiry(hostStatus)
+		Expect(recorder.Events).NotTo(Receive())
+
+		hostStatus.Status.Certificate.NotAfter = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		c.syncCertificateExpiry(hostStatus)
+		Expect(meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateExpiring).Reason).To(Equal("Expired"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BMCCertificateExpired")))
+	})
+
+	It("generates the events for the new log entries", func() {
+		bmc.AddLogEntry("1", emulator.LogEntry{Created: "2026-01-01T00:00:00Z", Severity: "Critical", Message: "the fan is failed"})
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+
+		hostStatus := getHostStatus()
+		Expect(hostStatus.Status.Log.TotalLogAccount).To(Equal(int32(1)))
+		Expect(hostStatus.Status.Log.WarningLogAccount).To(Equal(int32(1)))
+		Expect(hostStatus.Status.Log.LastestWarningLog.Message).To(ContainSubstring("the fan is failed"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BMCLogEntry")))
+
+		// the known log entry does not generate the event again
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("marks the host unhealthy when the bmc fails", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+		Expect(getHostStatus().Status.Healthy).To(BeTrue())
+
+		bmc.Fail("", "/redfish/v1", http.StatusServiceUnavailable, 0)
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		hostStatus := getHostStatus()
+		Expect(hostStatus.Status.Healthy).To(BeFalse())
+		Expect(hostStatus.Status.Info).To(BeEmpty())
+
+		bmc.Reset()
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		Expect(getHostStatus().Status.Healthy).To(BeTrue())
+	})
+
+	It("keeps the host healthy while its bmc is resetting", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+		hostStatus := getHostStatus()
+		meta.SetStatusCondition(&hostStatus.Status.Conditions, metav1.Condition{
+			Type:    topohubv1beta1.ConditionBmcResetting,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Resetting",
+			Message: "the BMC is reset by HostOperation reset-bmc",
+		})
+		Expect(c.client.Status().Update(context.Background(), hostStatus)).To(Succeed())
+
+		bmc.Fail("", "/redfish/v1", http.StatusServiceUnavailable, 0)
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		hostStatus = getHostStatus()
+		Expect(hostStatus.Status.Healthy).To(BeTrue())
+		Expect(hostStatus.Status.Systems).To(HaveLen(1))
+		Expect(meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionBmcResetting).Reason).To(Equal("Unreachable"))
+
+		// the bmc is regarded as unhealthy once the reset is over
+		meta.SetStatusCondition(&hostStatus.Status.Conditions, metav1.Condition{
+			Type:   topohubv1beta1.ConditionBmcResetting,
+			Status: metav1.ConditionFalse,
+			Reason: "NotRecovered",
+		})
+		Expect(c.client.Status().Update(context.Background(), hostStatus)).To(Succeed())
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		Expect(getHostStatus().Status.Healthy).To(BeFalse())
+	})
+
+	It("refuses the changed certificate", func() {
+		newController(&topohubv1beta1.CertificateInfo{Fingerprint: "00:11:22"})
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+
+		hostStatus := getHostStatus()
+		Expect(hostStatus.Status.Healthy).To(BeFalse())
+		Expect(hostStatus.Status.Certificate.Fingerprint).To(Equal("00:11:22"))
+		condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateTrusted)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(redfish.CertificateChanged))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BMCCertificateChanged")))
+		// the credential is not sent to the untrusted bmc
+		Expect(bmc.Sessions()).To(Equal(0))
+	})
+
+	It("trusts the certificate replaced by topohub on first use", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+		Expect(getHostStatus().Status.Certificate.Fingerprint).To(Equal(redfish.Fingerprint(bmc.Certificate())))
+
+		// the bmc restarts with the certificate issued by BmcCertificate
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: bmc.Host()},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		cert, err := x509.ParseCertificate(der)
+		Expect(err).NotTo(HaveOccurred())
+		bmc.ServeCertificate(tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert})
+		Expect(RecordReplacedCertificate(context.Background(), c.client, testHostStatusName, redfish.NewCertificateInfo(cert))).To(Succeed())
+
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		hostStatus := getHostStatus()
+		Expect(hostStatus.Status.Healthy).To(BeTrue())
+		Expect(hostStatus.Status.Certificate.Fingerprint).To(Equal(redfish.Fingerprint(cert)))
+		condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateTrusted)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("TrustOnFirstUse"))
+		Expect(recorder.Events).NotTo(Receive())
+
+		// the trust rebuilt from the HostStatus is the same
+		Expect(c.processHostStatus(hostStatus, c.log)).To(Succeed())
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		Expect(getHostStatus().Status.Healthy).To(BeTrue())
+	})
+
+	It("accepts the redfish events with the token of the subscription", func() {
+		newController(nil)
+		c.config.RedfishEventEnabled = true
+		c.config.RedfishEventPort = "8084"
+		c.config.HttpServerAddress = "10.0.0.1"
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+
+		subscription := getHostStatus().Status.EventSubscription
+		Expect(subscription).NotTo(BeNil())
+		token := eventToken(subscription.Destination, testHostStatusName)
+		Expect(token).To(HaveLen(32))
+		Expect(bmc.Subscriptions()).To(ConsistOf(HaveField("Destination", subscription.Destination)))
+		// the token is checked with the cache rather than the HostStatus
+		Expect(hoststatusdata.HostCacheDatabase.Get(testHostStatusName).EventToken).To(Equal(token))
+
+		// the token is kept in the later updates
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		Expect(getHostStatus().Status.EventSubscription.Destination).To(Equal(subscription.Destination))
+		Expect(bmc.Subscriptions()).To(HaveLen(1))
+
+		push := func(path, remoteAddr string) int {
+			body := `{"Events":[{"EventTimestamp":"2026-10-17T08:00:00Z","MessageSeverity":"Critical","MessageId":"PSU0003","Message":"The power input is lost."}]}`
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			req.RemoteAddr = remoteAddr
+			w := httptest.NewRecorder()
+			c.handleRedfishEvent(w, req)
+			return w.Code
+		}
+		remoteAddr := net.JoinHostPort(bmc.Host(), "40000")
+		Expect(push(redfishEventPath+testHostStatusName, remoteAddr)).To(Equal(http.StatusForbidden))
+		Expect(push(redfishEventPath+testHostStatusName+"/"+strings.Repeat("0", 32), remoteAddr)).To(Equal(http.StatusForbidden))
+		Expect(push(redfishEventPath+testHostStatusName+"/"+token, "192.0.2.1:40000")).To(Equal(http.StatusForbidden))
+		Expect(recorder.Events).NotTo(Receive())
+
+		Expect(push(redfishEventPath+testHostStatusName+"/"+token, remoteAddr)).To(Equal(http.StatusOK))
+		Expect(push(redfishEventPath+testHostStatusName+"/"+token, remoteAddr)).To(Equal(http.StatusOK))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BMCEvent")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BMCEvent")))
+
+		// the burst of events is written to the HostStatus once
+		Expect(getHostStatus().Status.Log.TotalEventAccount).To(BeZero())
+		c.flushEvents(testHostStatusName)
+		hostStatus := getHostStatus()
+		Expect(hostStatus.Status.Log.TotalEventAccount).To(Equal(int32(2)))
+		Expect(hostStatus.Status.Log.WarningEventAccount).To(Equal(int32(2)))
+		Expect(hostStatus.Status.Log.LastestWarningEvent.Message).To(ContainSubstring("The power input is lost."))
+	})
+
+	It("subscribes again with a token for the subscription without the token", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+		c.config.RedfishEventEnabled = true
+		c.config.RedfishEventPort = "8084"
+		c.config.HttpServerAddress = "10.0.0.1"
+
+		// the subscription is created by the former version
+		oldDestination := "https://10.0.0.1:8084" + redfishEventPath + testHostStatusName
+		bmc.AddSubscription(emulator.Subscription{Id: "old", Destination: oldDestination, SubscriptionType: "RedfishEvent"})
+		hostStatus := getHostStatus()
+		hostStatus.Status.EventSubscription = &topohubv1beta1.EventSubscriptionInfo{
+			Uri:         "/redfish/v1/EventService/Subscriptions/old",
+			Destination: oldDestination,
+		}
+		Expect(c.client.Status().Update(context.Background(), hostStatus)).To(Succeed())
+
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		subscription := getHostStatus().Status.EventSubscription
+		Expect(eventToken(subscription.Destination, testHostStatusName)).NotTo(BeEmpty())
+		Expect(bmc.Subscriptions()).To(ConsistOf(HaveField("Destination", subscription.Destination)))
+	})
+
+	It("deletes the subscriptions of the host with a former token", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+		c.config.RedfishEventEnabled = true
+		c.config.RedfishEventPort = "8084"
+		c.config.HttpServerAddress = "10.0.0.1"
+
+		// the HostStatus failed to be updated after the subscription was created, so its token is lost
+		staleDestination := "https://10.0.0.1:8084" + redfishEventPath + testHostStatusName + "/" + strings.Repeat("a", 32)
+		bmc.AddSubscription(emulator.Subscription{Id: "stale", Destination: staleDestination, SubscriptionType: "RedfishEvent"})
+		// the subscriptions of the other host and the other receiver are kept
+		otherHost := "https://10.0.0.1:8084" + redfishEventPath + testHostStatusName + "-2/" + strings.Repeat("b", 32)
+		bmc.AddSubscription(emulator.Subscription{Id: "other-host", Destination: otherHost, SubscriptionType: "RedfishEvent"})
+		otherReceiver := "https://10.0.0.2:8084" + redfishEventPath + testHostStatusName + "/" + strings.Repeat("c", 32)
+		bmc.AddSubscription(emulator.Subscription{Id: "other-receiver", Destination: otherReceiver, SubscriptionType: "RedfishEvent"})
+
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		subscription := getHostStatus().Status.EventSubscription
+		Expect(subscription.Destination).NotTo(Equal(staleDestination))
+		Expect(bmc.Subscriptions()).To(ConsistOf(
+			HaveField("Destination", subscription.Destination),
+			HaveField("Destination", otherHost),
+			HaveField("Destination", otherReceiver),
+		))
+
+		// a stale subscription is deleted even if the recorded one exists
+		bmc.AddSubscription(emulator.Subscription{Id: "stale", Destination: staleDestination, SubscriptionType: "RedfishEvent"})
+		Expect(c.UpdateHostStatusInfoWrapper(testHostStatusName)).To(Succeed())
+		Expect(getHostStatus().Status.EventSubscription.Uri).To(Equal(subscription.Uri))
+		Expect(bmc.Subscriptions()).To(HaveLen(3))
+		Expect(bmc.Subscriptions()).NotTo(ContainElement(HaveField("Destination", staleDestination)))
+	})
+
+	It("gives up the update of the slow bmc after the timeout", func() {
+		newController(nil)
+		Expect(c.processHostStatus(getHostStatus(), c.log)).To(Succeed())
+
+		bmc.SetLatency("/redfish/v1/Systems", 2*time.Second)
+		d := hoststatusdata.HostCacheDatabase.Get(testHostStatusName)
+		Expect(c.updateHostStatusWithTimeout(testHostStatusName, d)).To(MatchError(ContainSubstring("does not finish")))
+		// the host is skipped while its former update is running
+		Expect(c.updateHostStatusWithTimeout(testHostStatusName, d)).To(Succeed())
+
+		bmc.Reset()
+		Eventually(func() bool {
+			l := hostStatusLock(testHostStatusName)
+			if !l.TryLock() {
+				return false
+			}
+			l.Unlock()
+			return true
+		}).WithTimeout(30 * time.Second).Should(BeTrue())
+	})
+})
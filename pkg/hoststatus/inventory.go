@@ -0,0 +1,80 @@
+package hoststatus
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// syncHostInventory creates or updates the HostInventory which has the same name as the hostStatus and is owned by it
+func (c *hostStatusController) syncHostInventory(hostStatus *topohubv1beta1.HostStatus, inventory *topohubv1beta1.HostInventoryStatus) error {
+	name := hostStatus.Name
+	inventory.ClusterName = hostStatus.Status.Basic.ClusterName
+	inventory.IpAddr = hostStatus.Status.Basic.IpAddr
+
+	existing := &topohubv1beta1.HostInventory{}
+	err := c.client.Get(context.Background(), types.NamespacedName{Name: name}, existing)
+	if err == nil {
+		// ignore the update time when comparing
+		inventory.LastUpdateTime = existing.Status.LastUpdateTime
+		if reflect.DeepEqual(existing.Status, *inventory) {
+			c.log.Debugf("no need to update HostInventory %s", name)
+			return nil
+		}
+		updated := existing.DeepCopy()
+		updated.Status = *inventory
+		updated.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+		if err := c.client.Status().Update(context.Background(), updated); err != nil {
+			c.log.Errorf("Failed to update HostInventory %s: %v", name, err)
+			return err
+		}
+		c.log.Infof("Successfully updated HostInventory %s", name)
+		return nil
+	}
+
+	if !errors.IsNotFound(err) {
+		c.log.Errorf("Failed to get HostInventory %s: %v", name, err)
+		return err
+	}
+
+	hostInventory := &topohubv1beta1.HostInventory{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				topohubv1beta1.LabelClusterName: hostStatus.Labels[topohubv1beta1.LabelClusterName],
+				topohubv1beta1.LabelIPAddr:      hostStatus.Labels[topohubv1beta1.LabelIPAddr],
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         topohubv1beta1.APIVersion,
+					Kind:               topohubv1beta1.KindHostStatus,
+					Name:               hostStatus.Name,
+					UID:                hostStatus.UID,
+					Controller:         &[]bool{true}[0],
+					BlockOwnerDeletion: &[]bool{true}[0],
+				},
+			},
+		},
+	}
+
+	// the status could not be set during creation, so update it after creating
+	c.log.Debugf("Creating new HostInventory %s", name)
+	if err := c.client.Create(context.Background(), hostInventory); err != nil {
+		c.log.Errorf("Failed to create HostInventory %s: %v", name, err)
+		return err
+	}
+
+	hostInventory.Status = *inventory
+	hostInventory.Status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	if err := c.client.Status().Update(context.Background(), hostInventory); err != nil {
+		c.log.Errorf("Failed to update status of HostInventory %s: %v", name, err)
+		return err
+	}
+	c.log.Infof("Successfully created HostInventory %s", name)
+	return nil
+}
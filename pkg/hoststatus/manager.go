@@ -1,7 +1,9 @@
 package hoststatus
 
 import (
+	"context"
 	"go.uber.org/zap"
+	"net/http"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
@@ -14,7 +16,9 @@ import (
 	"github.com/infrastructure-io/topohub/pkg/config"
 	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/lock"
 	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/snmp"
 	"github.com/infrastructure-io/topohub/pkg/subnet/dhcpserver"
 )
 
@@ -38,6 +42,13 @@ type hostStatusController struct {
 	addChan              chan dhcpserver.DhcpClientInfo
 	deleteChan           chan dhcpserver.DhcpClientInfo
 	deleteHostStatusChan chan dhcpserver.DhcpClientInfo
+	// eventServer receives the redfish events pushed by the bmc, it is nil when the redfish event is disabled
+	eventServer *http.Server
+	// trapListener receives the snmp traps sent by the bmc, it is nil when the snmp trap is disabled
+	trapListener *snmp.Listener
+	// pendingEvents holds the redfish events and snmp traps of the hosts, which have not been written to the HostStatus
+	eventLock     lock.Mutex
+	pendingEvents map[string]*pendingEvents
 
 	log *zap.SugaredLogger
 }
@@ -61,6 +72,12 @@ func NewHostStatusController(kubeClient kubernetes.Interface, config *config.Age
 		recorder:             recorder,
 		log:                  log.Logger.Named("hoststatus"),
 	}
+	if config.RedfishEventEnabled {
+		controller.eventServer = controller.newEventServer()
+	}
+	if config.SnmpTrapEnabled {
+		controller.trapListener = controller.newTrapListener()
+	}
 
 	log.Logger.Debugf("HostStatus controller created successfully")
 	return controller
@@ -69,6 +86,16 @@ func NewHostStatusController(kubeClient kubernetes.Interface, config *config.Age
 func (c *hostStatusController) Stop() {
 	c.log.Info("Stopping HostStatus controller")
 	close(c.stopCh)
+	if c.eventServer != nil {
+		if err := c.eventServer.Shutdown(context.Background()); err != nil {
+			c.log.Errorf("Error shutting down redfish event listener: %v", err)
+		}
+	}
+	if c.trapListener != nil {
+		if err := c.trapListener.Close(); err != nil {
+			c.log.Errorf("Error shutting down snmp trap listener: %v", err)
+		}
+	}
 	c.wg.Wait()
 	c.log.Info("HostStatus controller stopped successfully")
 }
@@ -83,6 +110,14 @@ func (c *hostStatusController) SetupWithManager(mgr ctrl.Manager) error {
 		go c.processDHCPEvents()
 		// 启动 hoststatus spec.info 的	周期更新
 		go c.UpdateHostStatusAtInterval()
+		// 接收 bmc 推送的 redfish 事件
+		if c.eventServer != nil {
+			go c.runEventServer()
+		}
+		// 接收 bmc 发送的 snmp trap
+		if c.trapListener != nil {
+			go c.runTrapListener()
+		}
 	}()
 
 	return ctrl.NewControllerManagedBy(mgr).
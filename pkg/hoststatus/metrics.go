@@ -0,0 +1,58 @@
+package hoststatus
+
+import (
+	"context"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// exportSensorMetrics exports the sensor readings of the host as the prometheus gauges
+func (c *hostStatusController) exportSensorMetrics(hostStatus *topohubv1beta1.HostStatus, readings []redfish.SensorReading) {
+	host := hostStatus.Name
+	clusterName := hostStatus.Status.Basic.ClusterName
+	subnet := ""
+	if hostStatus.Status.Basic.SubnetName != nil {
+		subnet = *hostStatus.Status.Basic.SubnetName
+	}
+
+	sensors := metrics.NewHostSensors(host)
+	for _, r := range readings {
+		switch r.Type {
+		case redfish.SensorTypeTemperature:
+			sensors.Set(metrics.HostTemperatureCelsius, r.Value, host, clusterName, subnet, r.Chassis, r.Name)
+		case redfish.SensorTypeFan:
+			sensors.Set(metrics.HostFanSpeed, r.Value, host, clusterName, subnet, r.Chassis, r.Name, r.Unit)
+		case redfish.SensorTypePowerInput:
+			sensors.Set(metrics.HostPowerSupplyInputWatts, r.Value, host, clusterName, subnet, r.Chassis, r.Name)
+		case redfish.SensorTypePowerConsumed:
+			sensors.Set(metrics.HostPowerConsumedWatts, r.Value, host, clusterName, subnet, r.Chassis, r.Name)
+		case redfish.SensorTypeVoltage:
+			sensors.Set(metrics.HostVoltageVolts, r.Value, host, clusterName, subnet, r.Chassis, r.Name)
+		}
+	}
+	// the sensors may disappear, for example, the power supply is removed
+	sensors.Commit()
+	c.log.Debugf("exported %d sensor readings of hostStatus %s", len(readings), host)
+}
+
+// exportHostStatusCount counts the healthy and unhealthy HostStatus of each cluster
+func (c *hostStatusController) exportHostStatusCount() {
+	hostStatusList := &topohubv1beta1.HostStatusList{}
+	if err := c.client.List(context.Background(), hostStatusList); err != nil {
+		c.log.Warnf("Failed to list HostStatus for metrics: %v", err)
+		return
+	}
+
+	healthy := map[string]int{}
+	unhealthy := map[string]int{}
+	for _, item := range hostStatusList.Items {
+		if item.Status.Healthy {
+			healthy[item.Status.Basic.ClusterName]++
+		} else {
+			unhealthy[item.Status.Basic.ClusterName]++
+		}
+	}
+	metrics.SetHostStatusCount(healthy, unhealthy)
+}
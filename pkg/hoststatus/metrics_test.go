@@ -0,0 +1,98 @@
+package hoststatus
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// gaugeValues returns the values of the gauges whose label is the value, by the value of the key label
+func gaugeValues(c *prometheus.GaugeVec, label, value, key string) map[string]float64 {
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+	result := map[string]float64{}
+	for m := range ch {
+		metric := &dto.Metric{}
+		Expect(m.Write(metric)).To(Succeed())
+		labels := map[string]string{}
+		for _, pair := range metric.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if labels[label] == value {
+			result[labels[key]] = metric.GetGauge().GetValue()
+		}
+	}
+	return result
+}
+
+var _ = Describe("metrics", Label("unitest"), func() {
+	It("replaces the sensor readings of the host without removing the current ones", func() {
+		const host = "metrics-sensors"
+		DeferCleanup(metrics.DeleteHostSensors, host)
+		c := &hostStatusController{log: zap.NewNop().Sugar()}
+		hostStatus := &topohubv1beta1.HostStatus{}
+		hostStatus.Name = host
+
+		c.exportSensorMetrics(hostStatus, []redfish.SensorReading{
+			{Type: redfish.SensorTypeTemperature, Chassis: "1", Name: "CPU1", Value: 60},
+			{Type: redfish.SensorTypeTemperature, Chassis: "1", Name: "CPU2", Value: 62},
+			{Type: redfish.SensorTypePowerInput, Chassis: "1", Name: "PSU1", Value: 300},
+		})
+		Expect(gaugeValues(metrics.HostTemperatureCelsius, "host", host, "sensor")).To(Equal(map[string]float64{"CPU1": 60, "CPU2": 62}))
+		Expect(gaugeValues(metrics.HostPowerSupplyInputWatts, "host", host, "sensor")).To(Equal(map[string]float64{"PSU1": 300}))
+
+		// the power supply and a cpu disappear
+		c.exportSensorMetrics(hostStatus, []redfish.SensorReading{
+			{Type: redfish.SensorTypeTemperature, Chassis: "1", Name: "CPU1", Value: 65},
+		})
+		Expect(gaugeValues(metrics.HostTemperatureCelsius, "host", host, "sensor")).To(Equal(map[string]float64{"CPU1": 65}))
+		Expect(gaugeValues(metrics.HostPowerSupplyInputWatts, "host", host, "sensor")).To(BeEmpty())
+
+		metrics.DeleteHostSensors(host)
+		Expect(gaugeValues(metrics.HostTemperatureCelsius, "host", host, "sensor")).To(BeEmpty())
+	})
+
+	It("counts the HostStatus of each cluster without resetting the counts", func() {
+		scheme := runtime.NewScheme()
+		Expect(topohubv1beta1.AddToScheme(scheme)).To(Succeed())
+		hostStatus := func(name, clusterName string, healthy bool) *topohubv1beta1.HostStatus {
+			return &topohubv1beta1.HostStatus{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status: topohubv1beta1.HostStatusStatus{
+					Healthy: healthy,
+					Basic:   topohubv1beta1.BasicInfo{ClusterName: clusterName},
+				},
+			}
+		}
+		c := &hostStatusController{
+			client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(hostStatus("host1", "cluster-a", true), hostStatus("host2", "cluster-a", true)).
+				Build(),
+			log: zap.NewNop().Sugar(),
+		}
+		DeferCleanup(metrics.SetHostStatusCount, map[string]int{}, map[string]int{})
+
+		// the unhealthy count of the cluster is zero rather than absent
+		c.exportHostStatusCount()
+		Expect(gaugeValues(metrics.HostStatusCount, "cluster_name", "cluster-a", "healthy")).To(Equal(map[string]float64{"true": 2, "false": 0}))
+
+		Expect(c.client.Delete(context.Background(), hostStatus("host1", "", false))).To(Succeed())
+		Expect(c.client.Delete(context.Background(), hostStatus("host2", "", false))).To(Succeed())
+		Expect(c.client.Create(context.Background(), hostStatus("host3", "cluster-b", false))).To(Succeed())
+		c.exportHostStatusCount()
+		Expect(gaugeValues(metrics.HostStatusCount, "cluster_name", "cluster-a", "healthy")).To(BeEmpty())
+		Expect(gaugeValues(metrics.HostStatusCount, "cluster_name", "cluster-b", "healthy")).To(Equal(map[string]float64{"true": 0, "false": 1}))
+	})
+})
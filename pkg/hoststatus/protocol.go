@@ -0,0 +1,49 @@
+package hoststatus
+
+import (
+	"net"
+	"strconv"
+
+	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	"github.com/infrastructure-io/topohub/pkg/ipmi"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// connectHost connects the bmc of the host. For the dhcp host, the protocol is not configured by the user,
+// so the other protocol is tried when the bmc could not be connected with the current one. When the other protocol
+// succeeds, d.Info is replaced with the new protocol and port, and the caller should record it in the HostStatus
+func (c *hostStatusController) connectHost(d *hoststatusdata.HostConnectCon) (redfish.RefishClient, error) {
+	client, err := redfish.NewClient(*d, c.log)
+	if err == nil || !d.DhcpHost {
+		return client, err
+	}
+
+	info := *d.Info
+	if info.Protocol == topohubv1beta1.ProtocolIPMI {
+		// the bmc may be upgraded to the firmware with redfish
+		info.Protocol = topohubv1beta1.ProtocolRedfish
+		info.Port = int32(c.config.RedfishPort)
+		info.Https = c.config.RedfishHttps
+	} else {
+		// probe the ipmi service first, so the host without ipmi is not authenticated in vain
+		addr := net.JoinHostPort(info.IpAddr, strconv.Itoa(ipmi.DefaultPort))
+		if e := ipmi.Probe(addr, ipmi.DefaultTimeout); e != nil {
+			c.log.Debugf("no ipmi service at %s: %v", addr, e)
+			return nil, err
+		}
+		info.Protocol = topohubv1beta1.ProtocolIPMI
+		info.Port = ipmi.DefaultPort
+	}
+
+	detected := *d
+	detected.Info = &info
+	client, e := redfish.NewClient(detected, c.log)
+	if e != nil {
+		c.log.Debugf("failed to connect the dhcp host %s with %s: %v", info.IpAddr, info.Protocol, e)
+		return nil, err
+	}
+	c.log.Infof("the bmc of the dhcp host %s is connected with %s on port %d", info.IpAddr, info.Protocol, info.Port)
+	d.Info = &info
+	return client, nil
+}
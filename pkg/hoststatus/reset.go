@@ -0,0 +1,42 @@
+// bmc 重启期间的主机状态
+
+package hoststatus
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+)
+
+// maxBmcResetDuration is the longest timeout of the HostOperation which resets the bmc. The BmcResetting condition is
+// ignored after that, in case the HostOperation is removed before it clears the condition
+const maxBmcResetDuration = time.Hour
+
+// unreachableWhileResetting checks whether the unreachable bmc is being reset by the HostOperation, and records it in
+// the BmcResetting condition. The HostStatus keeps the last status rather than turning unhealthy in that case
+func (c *hostStatusController) unreachableWhileResetting(hostStatus *topohubv1beta1.HostStatus) bool {
+	condition := meta.FindStatusCondition(hostStatus.Status.Conditions, topohubv1beta1.ConditionBmcResetting)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return false
+	}
+	if time.Since(condition.LastTransitionTime.Time) > maxBmcResetDuration {
+		c.log.Warnf("hostStatus %s: the BMC is still unreachable %v after it is reset", hostStatus.Name, maxBmcResetDuration)
+		meta.SetStatusCondition(&hostStatus.Status.Conditions, metav1.Condition{
+			Type:    topohubv1beta1.ConditionBmcResetting,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotRecovered",
+			Message: "the BMC does not come back after it is reset",
+		})
+		return false
+	}
+	meta.SetStatusCondition(&hostStatus.Status.Conditions, metav1.Condition{
+		Type:    topohubv1beta1.ConditionBmcResetting,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Unreachable",
+		Message: condition.Message,
+	})
+	return true
+}
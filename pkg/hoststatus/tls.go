@@ -0,0 +1,236 @@
+// 校验 bmc 的 https 证书
+
+package hoststatus
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+const defaultCABundleKey = "ca.crt"
+
+// certificateExpiryWarning is how long in advance the expiry of the certificate of the bmc is warned
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// tlsSpecOf returns the tls configuration of the host, which is copied from the HostEndpoint, or read from the
+// Subnet of the dhcp host
+func (c *hostStatusController) tlsSpecOf(basic *topohubv1beta1.BasicInfo) (*topohubv1beta1.BmcTLSSpec, error) {
+	if basic.Type != topohubv1beta1.HostTypeDHCP {
+		return basic.TLS, nil
+	}
+	if basic.SubnetName == nil || len(*basic.SubnetName) == 0 {
+		return nil, nil
+	}
+	subnet := &topohubv1beta1.Subnet{}
+	if err := c.client.Get(context.Background(), types.NamespacedName{Name: *basic.SubnetName}, subnet); err != nil {
+		return nil, fmt.Errorf("failed to get subnet %s: %v", *basic.SubnetName, err)
+	}
+	return subnet.Spec.BmcTLS, nil
+}
+
+// getCABundle reads the PEM encoded CA certificates from the Secret or the ConfigMap
+func (c *hostStatusController) getCABundle(source *topohubv1beta1.CABundleSource) ([]byte, error) {
+	key := source.Key
+	if len(key) == 0 {
+		key = defaultCABundleKey
+	}
+	var data []byte
+	if source.Kind == "Secret" {
+		secret, err := c.kubeClient.CoreV1().Secrets(source.Namespace).Get(context.TODO(), source.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %v", source.Namespace, source.Name, err)
+		}
+		data = secret.Data[key]
+	} else {
+		configMap, err := c.kubeClient.CoreV1().ConfigMaps(source.Namespace).Get(context.TODO(), source.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap %s/%s: %v", source.Namespace, source.Name, err)
+		}
+		data = []byte(configMap.Data[key])
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM encoded CA certificates in the key %s of %s %s/%s", key, source.Kind, source.Namespace, source.Name)
+	}
+	return data, nil
+}
+
+// tlsTrust resolves how the certificate of the bmc is verified. The certificate is trusted on first use when the host
+// specifies neither the CA bundle nor the fingerprints, and known is the certificate recorded in the HostStatus
+func (c *hostStatusController) tlsTrust(basic *topohubv1beta1.BasicInfo, known *topohubv1beta1.CertificateInfo) (*hoststatusdata.TLSTrust, error) {
+	if !basic.Https || basic.Protocol == topohubv1beta1.ProtocolIPMI {
+		return nil, nil
+	}
+	spec, err := c.tlsSpecOf(basic)
+	if err != nil {
+		return nil, err
+	}
+
+	trust := &hoststatusdata.TLSTrust{}
+	if spec != nil {
+		trust.InsecureSkipVerify = spec.InsecureSkipVerify
+		trust.Fingerprints = spec.Fingerprints
+		if spec.ServerName != nil {
+			trust.ServerName = *spec.ServerName
+		}
+		if spec.CABundle != nil {
+			if trust.CAPem, err = c.getCABundle(spec.CABundle); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if known != nil && len(trust.CAPem) == 0 && len(trust.Fingerprints) == 0 {
+		trust.KnownFingerprint = known.Fingerprint
+	}
+	return trust, nil
+}
+
+// trustMode describes how the certificate is trusted, it is the reason of the CertificateTrusted condition
+func trustMode(trust *hoststatusdata.TLSTrust) string {
+	switch {
+	case trust.InsecureSkipVerify:
+		return "InsecureSkipVerify"
+	case len(trust.Fingerprints) > 0:
+		return "FingerprintPinned"
+	case len(trust.CAPem) > 0:
+		return "CAVerified"
+	}
+	return "TrustOnFirstUse"
+}
+
+// syncCertificate records the certificate of the bmc in the HostStatus, and sets the CertificateTrusted condition.
+// A warning event is generated when the certificate is changed unexpectedly, the connection is refused until the
+// new certificate is trusted
+func (c *hostStatusController) syncCertificate(d *hoststatusdata.HostConnectCon, client redfish.RefishClient, connectErr error, hostStatus *topohubv1beta1.HostStatus) {
+	if d.TLS == nil {
+		hostStatus.Status.Certificate = nil
+		meta.RemoveStatusCondition(&hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateTrusted)
+		meta.RemoveStatusCondition(&hostStatus.Status.Conditions, topohubv1beta1.ConditionCertificateExpiring)
+		return
+	}
+
+	var certErr *redfish.CertificateError
+	if errors.As(connectErr, &certErr) {
+		msg := fmt.Sprintf("the certificate %s of the BMC is refused: %s", certErr.Certificate.Fingerprint, certErr.Message)
+		changed := meta.SetStatusCondition(&hostStatus.Status.Conditions, metav1.Condition{
+			Type:    topohubv1beta1.ConditionCertificateTrusted,
+			Status:  metav1.ConditionFalse,
+			Reason:  certErr.Reason,
+			Message: msg,
+		})
+		if changed {
+			c.log.Warnf("hostStatus %s: %s", hostStatus.Name, msg)
+			c.recorder.Event(c.hostStatusReference(hostStatus.Name), corev1.EventTypeWarning, "BMC"+certErr.Reason, msg)
+		}
+		return
+	}
+	if connectErr != nil || client == nil {
+		return
+	}
+
+	cert := client.GetCertificate()
+	if cert == nil {
+		return
+	}
+	existing := hostStatus.Status.Certificate
+	switch {
+	case existing == nil:
+		c.log.Infof("record the certificate %s of hostStatus %s", cert.Fingerprint, hostStatus.Name)
+		hostStatus.Status.Certificate = cert
+	case existing.Fingerprint != cert.Fingerprint:
+		// the new certificate is verified by the CA or the pinned fingerprints, or the verification is skipped
+		msg := fmt.Sprintf("the certificate of the BMC is renewed from %s to %s", existing.Fingerprint, cert.Fingerprint)
+		c.log.Infof("hostStatus %s: %s", hostStatus.Name, msg)
+		c.recorder.Event(c.hostStatusReference(hostStatus.Name), corev1.EventTypeNormal, "BMCCertificateRenewed", msg)
+		hostStatus.Status.Certificate = cert
+	}
+	meta.SetStatusCondition(&hostStatus.Status.Conditions, metav1.Condition{
+		Type:    topohubv1beta1.ConditionCertificateTrusted,
+		Status:  metav1.ConditionTrue,
+		Reason:  trustMode(d.TLS),
+		Message: fmt.Sprintf("the certificate %s of the BMC is trusted", cert.Fingerprint),
+	})
+	c.syncCertificateExpiry(hostStatus)
+}
+
+// RecordReplacedCertificate records the certificate which is installed on the bmc by topohub, or regenerated by the bmc
+// which is reset by topohub, in the HostStatus. For the host which trusts the certificate on first use, the new
+// certificate is trusted instead of being refused as a changed one
+func RecordReplacedCertificate(ctx context.Context, c client.Client, name string, info *topohubv1beta1.CertificateInfo) error {
+	// the cache is updated firstly, so the next update of the HostStatus trusts the new certificate even if the HostStatus
+	// fails to be updated. The cached client is dropped since it verifies the new connections with the old fingerprint
+	if hoststatusdata.HostCacheDatabase.UpdateKnownFingerprint(name, info.Fingerprint) {
+		if d := hoststatusdata.HostCacheDatabase.Get(name); d != nil {
+			redfish.CacheClient.Invalidate(d.Info.IpAddr)
+		}
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hostStatus := &topohubv1beta1.HostStatus{}
+		if err := c.Get(ctx, client.ObjectKey{Name: name}, hostStatus); err != nil {
+			return err
+		}
+		hostStatus.Status.Certificate = info
+		return c.Status().Update(ctx, hostStatus)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record the certificate %s in HostStatus %s: %v", info.Fingerprint, name, err)
+	}
+	return nil
+}
+
+// syncCertificateExpiry sets the CertificateExpiring condition by the expiry date of the recorded certificate,
+// and a warning event is generated when the certificate starts expiring or has expired
+func (c *hostStatusController) syncCertificateExpiry(hostStatus *topohubv1beta1.HostStatus) {
+	cert := hostStatus.Status.Certificate
+	if cert == nil {
+		return
+	}
+	notAfter, err := time.Parse(time.RFC3339, cert.NotAfter)
+	if err != nil {
+		c.log.Warnf("hostStatus %s: invalid expiry date %q of the certificate: %v", hostStatus.Name, cert.NotAfter, err)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    topohubv1beta1.ConditionCertificateExpiring,
+		Status:  metav1.ConditionTrue,
+		Message: fmt.Sprintf("the certificate %s of the BMC expires at %s", cert.Fingerprint, cert.NotAfter),
+	}
+	switch remaining := time.Until(notAfter); {
+	case remaining <= 0:
+		condition.Reason = "Expired"
+		condition.Message = fmt.Sprintf("the certificate %s of the BMC has expired at %s", cert.Fingerprint, cert.NotAfter)
+	case remaining < certificateExpiryWarning:
+		condition.Reason = "Expiring"
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Valid"
+	}
+	changed := meta.SetStatusCondition(&hostStatus.Status.Conditions, condition)
+	if changed && condition.Status == metav1.ConditionTrue {
+		c.log.Warnf("hostStatus %s: %s", hostStatus.Name, condition.Message)
+		c.recorder.Event(c.hostStatusReference(hostStatus.Name), corev1.EventTypeWarning, "BMCCertificate"+condition.Reason, condition.Message)
+	}
+}
+
+func (c *hostStatusController) hostStatusReference(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       topohubv1beta1.KindHostStatus,
+		Name:       name,
+		Namespace:  c.config.PodNamespace,
+		APIVersion: topohubv1beta1.APIVersion,
+	}
+}
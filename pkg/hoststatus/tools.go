@@ -2,6 +2,7 @@ package hoststatus
 
 import (
 	"context"
+	"reflect"
 	"strings"
 
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
@@ -131,5 +132,43 @@ func compareHostStatus(a, b topohubv1beta1.HostStatusStatus, logger *zap.Sugared
 			return false
 		}
 	}
+
+	// 比较 Systems 和 Managers
+	if !reflect.DeepEqual(a.Systems, b.Systems) {
+		if logger != nil {
+			logger.Debugf("compareHostStatus Systems changed: %+v -> %+v", b.Systems, a.Systems)
+		}
+		return false
+	}
+	if !reflect.DeepEqual(a.Managers, b.Managers) {
+		if logger != nil {
+			logger.Debugf("compareHostStatus Managers changed: %+v -> %+v", b.Managers, a.Managers)
+		}
+		return false
+	}
+	if !reflect.DeepEqual(a.EventSubscription, b.EventSubscription) {
+		if logger != nil {
+			logger.Debugf("compareHostStatus EventSubscription changed: %+v -> %+v", b.EventSubscription, a.EventSubscription)
+		}
+		return false
+	}
+	if !reflect.DeepEqual(a.SnmpTrap, b.SnmpTrap) {
+		if logger != nil {
+			logger.Debugf("compareHostStatus SnmpTrap changed: %+v -> %+v", b.SnmpTrap, a.SnmpTrap)
+		}
+		return false
+	}
+	if !reflect.DeepEqual(a.Certificate, b.Certificate) {
+		if logger != nil {
+			logger.Debugf("compareHostStatus Certificate changed: %+v -> %+v", b.Certificate, a.Certificate)
+		}
+		return false
+	}
+	if !reflect.DeepEqual(a.Conditions, b.Conditions) {
+		if logger != nil {
+			logger.Debugf("compareHostStatus Conditions changed: %+v -> %+v", b.Conditions, a.Conditions)
+		}
+		return false
+	}
 	return true
 }
@@ -0,0 +1,156 @@
+// 接收 bmc 发送的 snmp trap
+
+package hoststatus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hoststatusdata "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/snmp"
+	"github.com/infrastructure-io/topohub/pkg/tools"
+)
+
+// newTrapListener creates the udp listener for the snmp traps, it returns nil when the credentials are invalid
+func (c *hostStatusController) newTrapListener() *snmp.Listener {
+	config := snmp.Config{
+		Community: c.config.SnmpTrapCommunity,
+		User:      c.config.SnmpTrapUser(),
+	}
+	listener, err := snmp.NewListener(fmt.Sprintf(":%s", c.config.SnmpTrapPort), config, c.log.Named("snmp"))
+	if err != nil {
+		c.log.Errorf("Failed to create snmp trap listener: %v", err)
+		return nil
+	}
+	return listener
+}
+
+func (c *hostStatusController) runTrapListener() {
+	c.log.Infof("Starting snmp trap listener on address %s", c.trapListener.Addr())
+	if err := c.trapListener.ListenAndServe(c.handleSnmpTrap); err != nil && err != snmp.ErrListenerClosed {
+		c.log.Errorf("snmp trap listener error: %v", err)
+	}
+}
+
+// hostOfAddress returns the name of the HostStatus whose bmc has the address
+func hostOfAddress(ip string) string {
+	for name, d := range hoststatusdata.HostCacheDatabase.GetAll() {
+		if d.Info != nil && d.Info.IpAddr == ip {
+			return name
+		}
+	}
+	return ""
+}
+
+// handleSnmpTrap generates the kubernetes event for the trap, and records it to the HostStatus of the bmc
+func (c *hostStatusController) handleSnmpTrap(trap *snmp.Trap) {
+	// only accept the traps from the bmc of the hosts
+	name := hostOfAddress(trap.Source.String())
+	if len(name) == 0 {
+		c.log.Warnf("reject snmp trap %s from %s, which is not the bmc of any host", trap.TrapOID, trap.Source)
+		return
+	}
+
+	alert := snmp.Decode(trap)
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	msg := fmt.Sprintf("[%s][%s]: %s", timestamp, alert.Severity, alert.Message)
+	if len(alert.MIB) > 0 {
+		msg = fmt.Sprintf("[%s][%s]: %s %s", timestamp, alert.Severity, alert.MIB, alert.Message)
+	}
+
+	t := &corev1.ObjectReference{
+		Kind:       topohubv1beta1.KindHostStatus,
+		Name:       name,
+		Namespace:  c.config.PodNamespace,
+		APIVersion: topohubv1beta1.APIVersion,
+	}
+	entry := &topohubv1beta1.LogEntry{Time: timestamp, Message: msg}
+	ty := corev1.EventTypeNormal
+	var warningEntry *topohubv1beta1.LogEntry
+	warningCount := 0
+	if alert.Severity != snmp.SeverityOK {
+		ty = corev1.EventTypeWarning
+		warningEntry = entry
+		warningCount = 1
+	}
+
+	c.log.Infof("receive snmp %s trap %s for hostStatus %s: %s", trap.Version, trap.TrapOID, name, msg)
+	c.recorder.Event(t, ty, "BMCTrap", msg)
+	c.recordEvents(name, 1, warningCount, entry, warningEntry)
+}
+
+// trapDestination returns the address of the trap listener, which the bmc of the host could access
+func (c *hostStatusController) trapDestination(hostStatus *topohubv1beta1.HostStatus) (redfish.SnmpTrapDestination, error) {
+	address, err := tools.GetAgentAddress(context.Background(), c.client, c.config.HttpServerAddress, hostStatus)
+	if err != nil {
+		return redfish.SnmpTrapDestination{}, err
+	}
+	port, _ := strconv.Atoi(c.config.SnmpTrapPort)
+	return redfish.SnmpTrapDestination{
+		Address:   address,
+		Port:      port,
+		Community: c.config.SnmpTrapCommunity,
+	}, nil
+}
+
+// syncSnmpTrap makes sure the bmc sends the snmp traps to topohub. It configures the destination again when it is lost
+// after the bmc is reset, and records the destination to the status of the HostStatus.
+// Only the v2c destination is configured, since the bmc could not be told the v3 user with the standard redfish
+func (c *hostStatusController) syncSnmpTrap(client redfish.RefishClient, hostStatus *topohubv1beta1.HostStatus) {
+	existing := hostStatus.Status.SnmpTrap
+
+	if !c.config.SnmpTrapEnabled || c.config.SnmpTrapVersion != snmp.VersionV2c {
+		if existing != nil {
+			c.log.Infof("delete the snmp trap destination %s of hostStatus %s", existing.Uri, hostStatus.Name)
+			if err := client.DeleteSnmpTrapDestination(existing.Uri); err != nil {
+				c.log.Warnf("Failed to delete the snmp trap destination of hostStatus %s: %v", hostStatus.Name, err)
+			}
+			hostStatus.Status.SnmpTrap = nil
+		}
+		return
+	}
+
+	destination, err := c.trapDestination(hostStatus)
+	if err != nil {
+		c.log.Warnf("Failed to configure snmp trap destination of hostStatus %s: %v", hostStatus.Name, err)
+		return
+	}
+
+	destinationUri := ""
+	if existing != nil {
+		if existing.Destination == destination.Url() {
+			destinationUri = existing.Uri
+		} else {
+			// the address of topohub is changed
+			c.log.Infof("delete the snmp trap destination %s of hostStatus %s to the old address %s", existing.Uri, hostStatus.Name, existing.Destination)
+			if err := client.DeleteSnmpTrapDestination(existing.Uri); err != nil {
+				c.log.Warnf("Failed to delete the snmp trap destination of hostStatus %s: %v", hostStatus.Name, err)
+			}
+		}
+	}
+
+	uri, created, err := client.EnsureSnmpTrapDestination(destinationUri, destination)
+	if err != nil {
+		c.log.Warnf("Failed to configure snmp trap destination of hostStatus %s: %v", hostStatus.Name, err)
+		return
+	}
+	if !created {
+		return
+	}
+	if len(destinationUri) > 0 {
+		c.log.Infof("the snmp trap destination %s of hostStatus %s is lost or changed, configure again: %s", destinationUri, hostStatus.Name, uri)
+	} else {
+		c.log.Infof("configure snmp trap destination of hostStatus %s to %s: %s", hostStatus.Name, destination.Url(), uri)
+	}
+	hostStatus.Status.SnmpTrap = &topohubv1beta1.SnmpTrapInfo{
+		Uri:           uri,
+		Destination:   destination.Url(),
+		ConfigureTime: time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,164 @@
+package ipmi
+
+import (
+	"fmt"
+)
+
+// the actions of Chassis Control
+const (
+	ChassisPowerDown    = 0x00
+	ChassisPowerUp      = 0x01
+	ChassisPowerCycle   = 0x02
+	ChassisHardReset    = 0x03
+	ChassisSoftShutdown = 0x05
+)
+
+// the boot devices of the boot flags parameter of Set System Boot Options
+const (
+	BootDeviceNone      = 0x00
+	BootDevicePxe       = 0x04
+	BootDeviceDisk      = 0x08
+	BootDeviceCdrom     = 0x14
+	BootDeviceBiosSetup = 0x18
+)
+
+const (
+	bootParamBootFlags = 0x05
+	bootFlagValid      = 0x80
+	bootFlagPersistent = 0x40
+	bootFlagEFI        = 0x20
+)
+
+type DeviceID struct {
+	DeviceID        byte
+	DeviceRevision  byte
+	FirmwareVersion string
+	IpmiVersion     string
+	// ManufacturerID is the IANA enterprise number of the manufacturer
+	ManufacturerID uint32
+	ProductID      uint16
+}
+
+// ResetBMC restarts the bmc, the cold reset also reinitializes the bmc hardware. The bmc may restart before it responds
+func (c *Client) ResetBMC(cold bool) error {
+	cmd := byte(cmdWarmReset)
+	if cold {
+		cmd = cmdColdReset
+	}
+	_, err := c.Send(NetFnApp, cmd, nil)
+	return err
+}
+
+// GetDeviceID returns the device id of the bmc, it is also used to check the session
+func (c *Client) GetDeviceID() (*DeviceID, error) {
+	data, err := c.Send(NetFnApp, cmdGetDeviceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 11 {
+		return nil, fmt.Errorf("invalid length %d of the device id", len(data))
+	}
+	return &DeviceID{
+		DeviceID:       data[0],
+		DeviceRevision: data[1] & 0x0f,
+		// the minor revision is BCD encoded
+		FirmwareVersion: fmt.Sprintf("%d.%02x", data[2]&0x7f, data[3]),
+		IpmiVersion:     fmt.Sprintf("%d.%d", data[4]&0x0f, data[4]>>4),
+		ManufacturerID:  uint32(data[6]) | uint32(data[7])<<8 | uint32(data[8]&0x0f)<<16,
+		ProductID:       uint16(data[9]) | uint16(data[10])<<8,
+	}, nil
+}
+
+type ChassisStatus struct {
+	PowerOn           bool
+	PowerOverload     bool
+	PowerInterlock    bool
+	PowerFault        bool
+	PowerControlFault bool
+	// PowerRestorePolicy is "AlwaysOff", "LastState", "AlwaysOn" or "Unknown"
+	PowerRestorePolicy string
+	Intrusion          bool
+	FrontPanelLockout  bool
+	DriveFault         bool
+	CoolingFault       bool
+	// IdentifyState is "Off", "TemporaryOn" or "IndefiniteOn", it is empty when the bmc does not report it
+	IdentifyState string
+}
+
+// Faulted returns true when the chassis reports any fault
+func (s *ChassisStatus) Faulted() bool {
+	return s.PowerOverload || s.PowerFault || s.PowerControlFault || s.DriveFault || s.CoolingFault
+}
+
+func (c *Client) GetChassisStatus() (*ChassisStatus, error) {
+	data, err := c.Send(NetFnChassis, cmdGetChassisStatus, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 3 {
+		return nil, fmt.Errorf("invalid length %d of the chassis status", len(data))
+	}
+	policy := "Unknown"
+	switch (data[0] >> 5) & 0x03 {
+	case 0:
+		policy = "AlwaysOff"
+	case 1:
+		policy = "LastState"
+	case 2:
+		policy = "AlwaysOn"
+	}
+	identify := ""
+	if data[2]&0x40 != 0 {
+		switch (data[2] >> 4) & 0x03 {
+		case 0:
+			identify = "Off"
+		case 1:
+			identify = "TemporaryOn"
+		case 2:
+			identify = "IndefiniteOn"
+		}
+	}
+	return &ChassisStatus{
+		PowerOn:            data[0]&0x01 != 0,
+		PowerOverload:      data[0]&0x02 != 0,
+		PowerInterlock:     data[0]&0x04 != 0,
+		PowerFault:         data[0]&0x08 != 0,
+		PowerControlFault:  data[0]&0x10 != 0,
+		PowerRestorePolicy: policy,
+		Intrusion:          data[2]&0x01 != 0,
+		FrontPanelLockout:  data[2]&0x02 != 0,
+		DriveFault:         data[2]&0x04 != 0,
+		CoolingFault:       data[2]&0x08 != 0,
+		IdentifyState:      identify,
+	}, nil
+}
+
+// ChassisControl powers on, powers off or resets the system
+func (c *Client) ChassisControl(action byte) error {
+	_, err := c.Send(NetFnChassis, cmdChassisControl, []byte{action})
+	return err
+}
+
+// ChassisIdentify turns on the identify led for the interval in seconds, or until it is turned off when force is true.
+// The led is turned off when the interval is 0 and force is false
+func (c *Client) ChassisIdentify(interval byte, force bool) error {
+	data := []byte{interval, 0}
+	if force {
+		data[1] = 0x01
+	}
+	_, err := c.Send(NetFnChassis, cmdChassisIdentify, data)
+	return err
+}
+
+// SetBootDevice sets the boot device of the next boot, or all the following boots when persistent is true
+func (c *Client) SetBootDevice(device byte, persistent bool, efi bool) error {
+	flags := byte(bootFlagValid)
+	if persistent {
+		flags |= bootFlagPersistent
+	}
+	if efi {
+		flags |= bootFlagEFI
+	}
+	_, err := c.Send(NetFnChassis, cmdSetSystemBootOptions, []byte{bootParamBootFlags, flags, device, 0, 0, 0})
+	return err
+}
@@ -0,0 +1,105 @@
+package ipmi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrastructure-io/topohub/pkg/ipmi/emulator"
+)
+
+var _ = Describe("Chassis", Label("unitest"), func() {
+	It("gets the device id of the bmc", func() {
+		bmc, c := startBmc()
+		bmc.SetDevice(emulator.Device{FirmwareMajor: 4, FirmwareMinor: 0x40, ManufacturerID: 10876, ProductID: 0x1b0a})
+		device, err := c.GetDeviceID()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(device).To(Equal(&DeviceID{
+			DeviceID:        0x20,
+			DeviceRevision:  0x01,
+			FirmwareVersion: "4.40",
+			IpmiVersion:     "2.0",
+			ManufacturerID:  10876,
+			ProductID:       0x1b0a,
+		}))
+	})
+
+	DescribeTable("gets the chassis status",
+		func(chassis emulator.Chassis, expected ChassisStatus, faulted bool) {
+			bmc, c := startBmc()
+			bmc.SetChassis(chassis)
+			status, err := c.GetChassisStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*status).To(Equal(expected))
+			Expect(status.Faulted()).To(Equal(faulted))
+		},
+		Entry("powered on",
+			emulator.Chassis{PowerOn: true, PowerRestorePolicy: 2, Identify: emulator.IdentifyOff},
+			ChassisStatus{PowerOn: true, PowerRestorePolicy: "AlwaysOn", IdentifyState: "Off"}, false),
+		Entry("powered off with the fault",
+			emulator.Chassis{PowerRestorePolicy: 0, PowerFault: true, CoolingFault: true, Identify: emulator.IdentifyTemporaryOn},
+			ChassisStatus{PowerFault: true, CoolingFault: true, PowerRestorePolicy: "AlwaysOff", IdentifyState: "TemporaryOn"}, true),
+		Entry("the identify state is not reported",
+			emulator.Chassis{PowerOn: true, PowerRestorePolicy: 3},
+			ChassisStatus{PowerOn: true, PowerRestorePolicy: "Unknown"}, false),
+	)
+
+	DescribeTable("controls the power of the chassis",
+		func(powerOn bool, action byte, expectedPowerOn bool, expectedErr string) {
+			bmc, c := startBmc()
+			bmc.SetChassis(emulator.Chassis{PowerOn: powerOn})
+			err := c.ChassisControl(action)
+			if len(expectedErr) > 0 {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(bmc.Chassis().PowerOn).To(Equal(expectedPowerOn))
+		},
+		Entry("power up", false, byte(ChassisPowerUp), true, ""),
+		Entry("power down", true, byte(ChassisPowerDown), false, ""),
+		Entry("soft shutdown", true, byte(ChassisSoftShutdown), false, ""),
+		Entry("hard reset", true, byte(ChassisHardReset), true, ""),
+		Entry("power cycle of the powered off system", false, byte(ChassisPowerCycle), false, "command not supported in present state"),
+	)
+
+	DescribeTable("turns on the identify led",
+		func(interval byte, force bool, expected string) {
+			bmc, c := startBmc()
+			Expect(c.ChassisIdentify(interval, force)).To(Succeed())
+			Expect(bmc.Chassis().Identify).To(Equal(expected))
+		},
+		Entry("until it is turned off", byte(0), true, emulator.IdentifyIndefiniteOn),
+		Entry("for the interval", byte(30), false, emulator.IdentifyTemporaryOn),
+		Entry("turned off", byte(0), false, emulator.IdentifyOff),
+	)
+
+	DescribeTable("sets the boot device",
+		func(device byte, persistent, efi bool, flags []byte) {
+			bmc, c := startBmc()
+			Expect(c.SetBootDevice(device, persistent, efi)).To(Succeed())
+			Expect(bmc.BootFlags()).To(Equal(flags))
+		},
+		Entry("pxe for the next boot", byte(BootDevicePxe), false, false, []byte{0x80, 0x04, 0, 0, 0}),
+		Entry("disk for all the boots in uefi", byte(BootDeviceDisk), true, true, []byte{0xe0, 0x08, 0, 0, 0}),
+		Entry("bios setup", byte(BootDeviceBiosSetup), false, true, []byte{0xa0, 0x18, 0, 0, 0}),
+	)
+
+	It("resets the bmc", func() {
+		bmc, c := startBmc()
+		Expect(c.ResetBMC(false)).To(Succeed())
+		Expect(c.ResetBMC(true)).To(Succeed())
+		Expect(bmc.CountCommands(emulator.NetFnApp, emulator.CmdWarmReset)).To(Equal(1))
+		Expect(bmc.CountCommands(emulator.NetFnApp, emulator.CmdColdReset)).To(Equal(1))
+	})
+
+	It("fails with the completion code", func() {
+		bmc, c := startBmc()
+		bmc.Fail(emulator.NetFnChassis, emulator.CmdGetChassisStatus, CompletionInsufficientPriv)
+		_, err := c.GetChassisStatus()
+		Expect(IsCompletionCode(err, CompletionInsufficientPriv)).To(BeTrue())
+
+		bmc.Fail(emulator.NetFnChassis, emulator.CmdGetChassisStatus, CompletionOK)
+		_, err = c.GetChassisStatus()
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
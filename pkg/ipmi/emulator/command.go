@@ -0,0 +1,232 @@
+package emulator
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// the completion codes responded by the emulator
+const (
+	completionOK                 = 0x00
+	completionInvalidCommand     = 0xc1
+	completionInvalidReservation = 0xc5
+	completionInvalidLength      = 0xc7
+	completionOutOfRange         = 0xc9
+	completionDataNotPresent     = 0xcb
+	completionInvalidData        = 0xcc
+	completionNotInPresentState  = 0xd5
+)
+
+const (
+	bmcSlaveAddr       = 0x20
+	selLastRecordID    = 0xffff
+	bootParamBootFlags = 0x05
+	selClearInitiate   = 0xaa
+	selEraseCompleted  = 0x01
+)
+
+// handleMessage responds the ipmi lan message, the message out of the session could only get the channel
+// authentication capabilities. It returns nil when the message is dropped
+func (s *Server) handleMessage(msg []byte, inSession bool) []byte {
+	// rsAddr, netFn/rsLUN, checksum, rqAddr, rqSeq/rqLUN, cmd, data, checksum
+	if len(msg) < 7 || msg[0] != bmcSlaveAddr || checksum(msg[:3]) != 0 || checksum(msg[3:]) != 0 {
+		return nil
+	}
+	netFn, seq, cmd := msg[1]>>2, msg[4]>>2, msg[5]
+	if !inSession && (netFn != NetFnApp || cmd != CmdGetChannelAuthCapabilities) {
+		return nil
+	}
+	data := append([]byte{}, msg[6:len(msg)-1]...)
+	s.commands = append(s.commands, Command{NetFn: netFn, Cmd: cmd, Data: data})
+
+	code, result := s.command(netFn, cmd, data)
+	if failure, ok := s.failures[commandKey(netFn, cmd)]; ok {
+		code, result = failure, nil
+	}
+	resp := []byte{msg[3], (netFn + 1) << 2, 0}
+	resp[2] = checksum(resp[:2])
+	body := []byte{bmcSlaveAddr, seq << 2, cmd, code}
+	body = append(body, result...)
+	body = append(body, checksum(body))
+	return append(resp, body...)
+}
+
+// command runs the command, and returns the completion code and the response data
+func (s *Server) command(netFn, cmd byte, data []byte) (byte, []byte) {
+	switch commandKey(netFn, cmd) {
+	case commandKey(NetFnApp, CmdGetChannelAuthCapabilities):
+		// channel 1 supports the ipmi v2.0 extended capabilities, and the user name and password are required
+		return completionOK, []byte{0x01, 0x80, 0x04, 0x02, 0, 0, 0, 0}
+
+	case commandKey(NetFnApp, CmdGetDeviceID):
+		d := s.device
+		return completionOK, []byte{
+			0x20, 0x01, d.FirmwareMajor & 0x7f, d.FirmwareMinor, 0x02, 0xbf,
+			byte(d.ManufacturerID), byte(d.ManufacturerID >> 8), byte(d.ManufacturerID>>16) & 0x0f,
+			byte(d.ProductID), byte(d.ProductID >> 8),
+		}
+
+	case commandKey(NetFnApp, CmdSetSessionPrivilege):
+		if len(data) < 1 {
+			return completionInvalidLength, nil
+		}
+		return completionOK, []byte{data[0]}
+
+	case commandKey(NetFnApp, CmdCloseSession), commandKey(NetFnApp, CmdColdReset), commandKey(NetFnApp, CmdWarmReset):
+		return completionOK, nil
+
+	case commandKey(NetFnChassis, CmdGetChassisStatus):
+		return completionOK, s.chassisStatus()
+
+	case commandKey(NetFnChassis, CmdChassisControl):
+		if len(data) < 1 {
+			return completionInvalidLength, nil
+		}
+		switch data[0] & 0x0f {
+		case 0x00, 0x05:
+			s.chassis.PowerOn = false
+		case 0x01:
+			s.chassis.PowerOn = true
+		case 0x02, 0x03:
+			// the power cycle and the hard reset are refused when the system is off
+			if !s.chassis.PowerOn {
+				return completionNotInPresentState, nil
+			}
+		default:
+			return completionInvalidData, nil
+		}
+		return completionOK, nil
+
+	case commandKey(NetFnChassis, CmdChassisIdentify):
+		interval := byte(15)
+		if len(data) > 0 {
+			interval = data[0]
+		}
+		switch {
+		case len(data) > 1 && data[1]&0x01 != 0:
+			s.chassis.Identify = IdentifyIndefiniteOn
+		case interval > 0:
+			s.chassis.Identify = IdentifyTemporaryOn
+		default:
+			s.chassis.Identify = IdentifyOff
+		}
+		return completionOK, nil
+
+	case commandKey(NetFnChassis, CmdSetSystemBootOptions):
+		if len(data) < 1 {
+			return completionInvalidLength, nil
+		}
+		if data[0]&0x7f == bootParamBootFlags {
+			s.bootFlags = append([]byte{}, data[1:]...)
+		}
+		return completionOK, nil
+
+	case commandKey(NetFnStorage, CmdGetFRUInventoryAreaInfo):
+		if s.fru == nil {
+			return completionDataNotPresent, nil
+		}
+		return completionOK, append(le16(uint16(len(s.fru))), 0x00)
+
+	case commandKey(NetFnStorage, CmdReadFRUData):
+		if len(data) < 4 {
+			return completionInvalidLength, nil
+		}
+		if s.fru == nil {
+			return completionDataNotPresent, nil
+		}
+		offset, count := int(binary.LittleEndian.Uint16(data[1:3])), int(data[3])
+		if offset >= len(s.fru) {
+			return completionOutOfRange, nil
+		}
+		if offset+count > len(s.fru) {
+			count = len(s.fru) - offset
+		}
+		return completionOK, append([]byte{byte(count)}, s.fru[offset:offset+count]...)
+
+	case commandKey(NetFnStorage, CmdGetSELInfo):
+		result := []byte{0x51}
+		result = append(result, le16(uint16(len(s.sel)))...)
+		result = append(result, 0xff, 0xff)
+		result = append(result, le32(s.selAddition)...)
+		result = append(result, le32(s.selErase)...)
+		return completionOK, append(result, 0x02)
+
+	case commandKey(NetFnStorage, CmdReserveSEL):
+		s.reservation++
+		return completionOK, le16(s.reservation)
+
+	case commandKey(NetFnStorage, CmdGetSELEntry):
+		if len(data) < 6 {
+			return completionInvalidLength, nil
+		}
+		return s.selEntry(binary.LittleEndian.Uint16(data[2:4]))
+
+	case commandKey(NetFnStorage, CmdClearSEL):
+		if len(data) < 6 {
+			return completionInvalidLength, nil
+		}
+		if binary.LittleEndian.Uint16(data[0:2]) != s.reservation {
+			return completionInvalidReservation, nil
+		}
+		if string(data[2:5]) != "CLR" {
+			return completionInvalidData, nil
+		}
+		if data[5] == selClearInitiate {
+			s.sel = nil
+			s.selErase = uint32(time.Now().Unix())
+		}
+		return completionOK, []byte{selEraseCompleted}
+	}
+	return completionInvalidCommand, nil
+}
+
+// chassisStatus returns the current power state, the last power event and the misc chassis state
+func (s *Server) chassisStatus() []byte {
+	c := s.chassis
+	power := (c.PowerRestorePolicy & 0x03) << 5
+	if c.PowerOn {
+		power |= 0x01
+	}
+	if c.PowerFault {
+		power |= 0x08
+	}
+	var misc byte
+	if c.CoolingFault {
+		misc |= 0x08
+	}
+	switch c.Identify {
+	case IdentifyOff:
+		misc |= 0x40
+	case IdentifyTemporaryOn:
+		misc |= 0x40 | 0x10
+	case IdentifyIndefiniteOn:
+		misc |= 0x40 | 0x20
+	}
+	return []byte{power, 0x00, misc}
+}
+
+// selEntry returns the record with the id and the id of the next record, the id 0 is the first record and the id
+// 0xffff is the last one
+func (s *Server) selEntry(id uint16) (byte, []byte) {
+	for i, record := range s.sel {
+		current := binary.LittleEndian.Uint16(record[0:2])
+		if id != current && !(id == 0 && i == 0) && !(id == selLastRecordID && i == len(s.sel)-1) {
+			continue
+		}
+		next := uint16(selLastRecordID)
+		if i+1 < len(s.sel) {
+			next = binary.LittleEndian.Uint16(s.sel[i+1][0:2])
+		}
+		return completionOK, append(le16(next), record[:]...)
+	}
+	return completionDataNotPresent, nil
+}
+
+// checksum is the 2's complement of the sum of the bytes
+func checksum(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
@@ -0,0 +1,289 @@
+// Package emulator serves a scriptable IPMI v2.0 over lan (RMCP+) bmc on the udp port of the loopback address, so the
+// ipmi client could be tested without the real bmc. It supports the cipher suite 3 like the client, the chassis, FRU
+// and SEL are set by the test, and the packets could be dropped and the commands could fail with the completion code.
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// the network functions of the requests
+const (
+	NetFnChassis = 0x00
+	NetFnApp     = 0x06
+	NetFnStorage = 0x0a
+)
+
+// the commands of the App network function
+const (
+	CmdGetDeviceID                = 0x01
+	CmdColdReset                  = 0x02
+	CmdWarmReset                  = 0x03
+	CmdGetChannelAuthCapabilities = 0x38
+	CmdSetSessionPrivilege        = 0x3b
+	CmdCloseSession               = 0x3c
+)
+
+// the commands of the Chassis network function
+const (
+	CmdGetChassisStatus     = 0x01
+	CmdChassisControl       = 0x02
+	CmdChassisIdentify      = 0x04
+	CmdSetSystemBootOptions = 0x08
+)
+
+// the commands of the Storage network function
+const (
+	CmdGetFRUInventoryAreaInfo = 0x10
+	CmdReadFRUData             = 0x11
+	CmdGetSELInfo              = 0x40
+	CmdReserveSEL              = 0x42
+	CmdGetSELEntry             = 0x43
+	CmdClearSEL                = 0x47
+)
+
+// the states of the identify led of the chassis
+const (
+	IdentifyOff          = "Off"
+	IdentifyTemporaryOn  = "TemporaryOn"
+	IdentifyIndefiniteOn = "IndefiniteOn"
+)
+
+// Device is reported by Get Device ID
+type Device struct {
+	FirmwareMajor byte
+	// FirmwareMinor is BCD encoded, such as 0x15 for the version x.15
+	FirmwareMinor  byte
+	ManufacturerID uint32
+	ProductID      uint16
+}
+
+// Chassis is the state reported by Get Chassis Status, which is changed by Chassis Control and Chassis Identify
+type Chassis struct {
+	PowerOn bool
+	// PowerRestorePolicy is 0 for AlwaysOff, 1 for LastState and 2 for AlwaysOn
+	PowerRestorePolicy byte
+	PowerFault         bool
+	CoolingFault       bool
+	// Identify is the state of the identify led, it is not reported when it is empty
+	Identify string
+}
+
+// Command is a request received in the session
+type Command struct {
+	NetFn byte
+	Cmd   byte
+	Data  []byte
+}
+
+// Server is the ipmi service of the emulator
+type Server struct {
+	conn net.PacketConn
+
+	lock     sync.Mutex
+	username string
+	password string
+	guid     []byte
+
+	device    Device
+	chassis   Chassis
+	bootFlags []byte
+	fru       []byte
+
+	sel         [][16]byte
+	selId       uint16
+	selAddition uint32
+	selErase    uint32
+	reservation uint16
+
+	sessions  map[uint32]*session
+	sessionId uint32
+
+	// failures maps the netFn and the command to the completion code
+	failures map[uint16]byte
+	drops    int
+	commands []Command
+}
+
+// New starts the emulator with a powered on chassis, the user name and the password are empty until SetCredential is called
+func New() *Server {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		panic("emulator: failed to listen on a port: " + err.Error())
+	}
+	guid := make([]byte, 16)
+	if _, err := rand.Read(guid); err != nil {
+		panic("emulator: failed to generate the guid: " + err.Error())
+	}
+	s := &Server{
+		conn: conn,
+		guid: guid,
+		device: Device{
+			FirmwareMajor:  2,
+			FirmwareMinor:  0x15,
+			ManufacturerID: 674,
+			ProductID:      256,
+		},
+		chassis: Chassis{
+			PowerOn:            true,
+			PowerRestorePolicy: 1,
+			Identify:           IdentifyOff,
+		},
+		sessions: map[uint32]*session{},
+		failures: map[uint16]byte{},
+	}
+	go s.serve()
+	return s
+}
+
+// Close stops the emulator
+func (s *Server) Close() {
+	s.conn.Close()
+}
+
+// Host returns the ip address of the emulator
+func (s *Server) Host() string {
+	return s.conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
+
+// Port returns the udp port of the emulator
+func (s *Server) Port() int32 {
+	return int32(s.conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+// Addr returns the host:port of the emulator
+func (s *Server) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// SetCredential sets the user which is allowed to establish the session
+func (s *Server) SetCredential(username, password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.username = username
+	s.password = password
+}
+
+// SetDevice replaces the device id of the bmc
+func (s *Server) SetDevice(device Device) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.device = device
+}
+
+// SetChassis replaces the state of the chassis
+func (s *Server) SetChassis(chassis Chassis) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.chassis = chassis
+}
+
+// Chassis returns the current state of the chassis
+func (s *Server) Chassis() Chassis {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.chassis
+}
+
+// BootFlags returns the data of the last boot flags parameter set by Set System Boot Options
+func (s *Server) BootFlags() []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]byte{}, s.bootFlags...)
+}
+
+// SetFRU replaces the FRU data of the builtin device, the bmc reports no FRU when it is nil
+func (s *Server) SetFRU(raw []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.fru = append([]byte(nil), raw...)
+}
+
+// AddSEL appends the records to the SEL, the record id in the first 2 bytes of each record is set by the emulator
+func (s *Server) AddSEL(records ...[16]byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, record := range records {
+		s.selId++
+		binary.LittleEndian.PutUint16(record[0:2], s.selId)
+		s.sel = append(s.sel, record)
+	}
+	s.selAddition = uint32(time.Now().Unix())
+}
+
+// SEL returns the records of the SEL
+func (s *Server) SEL() [][16]byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([][16]byte{}, s.sel...)
+}
+
+// Fail makes the command complete with the code, the failure is removed when the code is 0
+func (s *Server) Fail(netFn, cmd, code byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if code == completionOK {
+		delete(s.failures, commandKey(netFn, cmd))
+		return
+	}
+	s.failures[commandKey(netFn, cmd)] = code
+}
+
+// Drop drops the next n packets from the client without responding them, like the lost udp packets
+func (s *Server) Drop(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.drops = n
+}
+
+// Commands returns the commands received in the sessions and out of the session
+func (s *Server) Commands() []Command {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Command{}, s.commands...)
+}
+
+// CountCommands returns the number of the received commands with the netFn and the command
+func (s *Server) CountCommands(netFn, cmd byte) int {
+	count := 0
+	for _, c := range s.Commands() {
+		if c.NetFn == netFn && c.Cmd == cmd {
+			count++
+		}
+	}
+	return count
+}
+
+// Sessions returns the number of the activated sessions which are not closed
+func (s *Server) Sessions() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	count := 0
+	for _, item := range s.sessions {
+		if item.active {
+			count++
+		}
+	}
+	return count
+}
+
+func commandKey(netFn, cmd byte) uint16 {
+	return uint16(netFn)<<8 | uint16(cmd)
+}
+
+func (s *Server) serve() {
+	buf := make([]byte, 1024)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.handle(buf[:n]); resp != nil {
+			_, _ = s.conn.WriteTo(resp, addr)
+		}
+	}
+}
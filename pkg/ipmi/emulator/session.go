@@ -0,0 +1,302 @@
+package emulator
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+)
+
+// the constants of the rmcp+ packets are defined again instead of shared with the client, so the emulator checks the
+// client against the specification
+const (
+	rmcpVersion   = 0x06
+	rmcpSeqNoAck  = 0xff
+	rmcpClassIPMI = 0x07
+
+	authTypeNone     = 0x00
+	authTypeRMCPPlus = 0x06
+
+	payloadIPMI            = 0x00
+	payloadOpenSessionReq  = 0x10
+	payloadOpenSessionResp = 0x11
+	payloadRAKP1           = 0x12
+	payloadRAKP2           = 0x13
+	payloadRAKP3           = 0x14
+	payloadRAKP4           = 0x15
+	payloadTypeMask        = 0x3f
+	payloadEncrypted       = 0x80
+	payloadAuthenticated   = 0x40
+
+	sessionHeaderLen     = 12
+	sessionLessHeaderLen = 10
+	authCodeLen          = 12
+	maxPasswordLen       = 20
+	nextHeaderRMCP       = 0x07
+	integrityPadByte     = 0xff
+	privilegeAdmin       = 0x04
+
+	rakpStatusNoErrors              = 0x00
+	rakpStatusInvalidSessionID      = 0x02
+	rakpStatusUnauthorizedName      = 0x0d
+	rakpStatusInvalidIntegrityCheck = 0x0f
+	rakpStatusNoCipherSuite         = 0x11
+)
+
+// session is an rmcp+ session, it is activated after the RAKP 3 is verified
+type session struct {
+	consoleId uint32
+	bmcId     uint32
+	rm        []byte
+	rc        []byte
+	userInfo  []byte
+	k1        []byte
+	k2        []byte
+	active    bool
+	seq       uint32
+}
+
+// handle responds the packet from the client, it returns nil when the packet is dropped
+func (s *Server) handle(b []byte) []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.drops > 0 {
+		s.drops--
+		return nil
+	}
+	if len(b) < 5 || b[0] != rmcpVersion || b[3] != rmcpClassIPMI {
+		return nil
+	}
+	b = b[4:]
+
+	switch b[0] {
+	case authTypeNone:
+		// the ipmi v1.5 packet out of the session, only Get Channel Authentication Capabilities is responded
+		if len(b) < sessionLessHeaderLen || len(b) < sessionLessHeaderLen+int(b[9]) {
+			return nil
+		}
+		resp := s.handleMessage(b[sessionLessHeaderLen:sessionLessHeaderLen+int(b[9])], false)
+		if resp == nil {
+			return nil
+		}
+		packet := []byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeNone, 0, 0, 0, 0, 0, 0, 0, 0, byte(len(resp))}
+		return append(packet, resp...)
+
+	case authTypeRMCPPlus:
+		if len(b) < sessionHeaderLen {
+			return nil
+		}
+		payloadType := b[1]
+		length := int(binary.LittleEndian.Uint16(b[10:]))
+		if len(b) < sessionHeaderLen+length {
+			return nil
+		}
+		payload := b[sessionHeaderLen : sessionHeaderLen+length]
+		switch payloadType & payloadTypeMask {
+		case payloadOpenSessionReq:
+			return handshakePacket(payloadOpenSessionResp, s.openSession(payload))
+		case payloadRAKP1:
+			return handshakePacket(payloadRAKP2, s.rakp1(payload))
+		case payloadRAKP3:
+			return handshakePacket(payloadRAKP4, s.rakp3(payload))
+		case payloadIPMI:
+			return s.sessionMessage(b, payloadType, payload)
+		}
+	}
+	return nil
+}
+
+// handshakePacket builds the packet of the open session response and the RAKP messages, which are out of the session
+func handshakePacket(payloadType byte, payload []byte) []byte {
+	if payload == nil {
+		return nil
+	}
+	packet := []byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeRMCPPlus, payloadType, 0, 0, 0, 0, 0, 0, 0, 0}
+	packet = append(packet, le16(uint16(len(payload)))...)
+	return append(packet, payload...)
+}
+
+// openSession creates the session for the cipher suite 3, other algorithms are refused
+func (s *Server) openSession(req []byte) []byte {
+	if len(req) < 32 {
+		return nil
+	}
+	consoleId := binary.LittleEndian.Uint32(req[4:])
+	if req[12] != 0x01 || req[20] != 0x01 || req[28] != 0x01 {
+		return append([]byte{req[0], rakpStatusNoCipherSuite, 0, 0}, le32(consoleId)...)
+	}
+	s.sessionId++
+	item := &session{consoleId: consoleId, bmcId: s.sessionId}
+	s.sessions[item.bmcId] = item
+
+	resp := []byte{req[0], rakpStatusNoErrors, privilegeAdmin, 0}
+	resp = append(resp, le32(consoleId)...)
+	resp = append(resp, le32(item.bmcId)...)
+	resp = append(resp, 0x00, 0, 0, 0x08, 0x01, 0, 0, 0)
+	resp = append(resp, 0x01, 0, 0, 0x08, 0x01, 0, 0, 0)
+	return append(resp, 0x02, 0, 0, 0x08, 0x01, 0, 0, 0)
+}
+
+// rakp1 checks the user name, and responds the RAKP 2 which proves that the bmc knows the password
+func (s *Server) rakp1(req []byte) []byte {
+	if len(req) < 28 || len(req) < 28+int(req[27]) {
+		return nil
+	}
+	item, ok := s.sessions[binary.LittleEndian.Uint32(req[4:])]
+	if !ok {
+		return []byte{req[0], rakpStatusInvalidSessionID, 0, 0, 0, 0, 0, 0}
+	}
+	user := req[28 : 28+int(req[27])]
+	if string(user) != s.username {
+		delete(s.sessions, item.bmcId)
+		return append([]byte{req[0], rakpStatusUnauthorizedName, 0, 0}, le32(item.consoleId)...)
+	}
+	item.rm = append([]byte{}, req[8:24]...)
+	item.rc = make([]byte, 16)
+	if _, err := rand.Read(item.rc); err != nil {
+		return nil
+	}
+	item.userInfo = append([]byte{req[24], req[27]}, user...)
+
+	resp := []byte{req[0], rakpStatusNoErrors, 0, 0}
+	resp = append(resp, le32(item.consoleId)...)
+	resp = append(resp, item.rc...)
+	resp = append(resp, s.guid...)
+	return append(resp, hmacSha1(s.kuid(), le32(item.consoleId), le32(item.bmcId), item.rm, item.rc, s.guid, item.userInfo)...)
+}
+
+// rakp3 checks that the client knows the password, and activates the session with the keys derived from the SIK
+func (s *Server) rakp3(req []byte) []byte {
+	if len(req) < 8 {
+		return nil
+	}
+	item, ok := s.sessions[binary.LittleEndian.Uint32(req[4:])]
+	if !ok || item.rc == nil {
+		return []byte{req[0], rakpStatusInvalidSessionID, 0, 0, 0, 0, 0, 0}
+	}
+	if req[1] != rakpStatusNoErrors || len(req) < 8+sha1.Size {
+		delete(s.sessions, item.bmcId)
+		return nil
+	}
+	expected := hmacSha1(s.kuid(), item.rc, le32(item.consoleId), item.userInfo)
+	if !hmac.Equal(expected, req[8:8+sha1.Size]) {
+		delete(s.sessions, item.bmcId)
+		return append([]byte{req[0], rakpStatusInvalidIntegrityCheck, 0, 0}, le32(item.consoleId)...)
+	}
+
+	sik := hmacSha1(s.kuid(), item.rm, item.rc, item.userInfo)
+	item.k1 = hmacSha1(sik, bytes.Repeat([]byte{0x01}, sha1.Size))
+	item.k2 = hmacSha1(sik, bytes.Repeat([]byte{0x02}, sha1.Size))
+	item.active = true
+
+	resp := []byte{req[0], rakpStatusNoErrors, 0, 0}
+	resp = append(resp, le32(item.consoleId)...)
+	return append(resp, hmacSha1(sik, item.rm, le32(item.bmcId), s.guid)[:authCodeLen]...)
+}
+
+// sessionMessage verifies and decrypts the message in the session, and responds it in the session. The packet which
+// is not authenticated and encrypted is dropped, since the session uses the cipher suite 3
+func (s *Server) sessionMessage(b []byte, payloadType byte, payload []byte) []byte {
+	item, ok := s.sessions[binary.LittleEndian.Uint32(b[2:])]
+	if !ok || !item.active {
+		return nil
+	}
+	if payloadType&payloadAuthenticated == 0 || payloadType&payloadEncrypted == 0 {
+		return nil
+	}
+	// the integrity pad aligns the data from the auth type to the next header with 4 bytes
+	end := len(b) - authCodeLen
+	if end < sessionHeaderLen+len(payload)+2 || end%4 != 0 || b[end-1] != nextHeaderRMCP ||
+		end != sessionHeaderLen+len(payload)+int(b[end-2])+2 {
+		return nil
+	}
+	if !hmac.Equal(hmacSha1(item.k1, b[:end])[:authCodeLen], b[end:]) {
+		return nil
+	}
+	msg := decrypt(item.k2, payload)
+	if msg == nil {
+		return nil
+	}
+	resp := s.handleMessage(msg, true)
+	if resp == nil {
+		return nil
+	}
+	// the response of Close Session is sent in the session before it is closed
+	if len(msg) > 5 && msg[1]>>2 == NetFnApp && msg[5] == CmdCloseSession && resp[6] == completionOK {
+		delete(s.sessions, item.bmcId)
+	}
+
+	encrypted := encrypt(item.k2, resp)
+	item.seq++
+	data := []byte{authTypeRMCPPlus, payloadIPMI | payloadEncrypted | payloadAuthenticated}
+	data = append(data, le32(item.consoleId)...)
+	data = append(data, le32(item.seq)...)
+	data = append(data, le16(uint16(len(encrypted)))...)
+	data = append(data, encrypted...)
+	padLen := (4 - (len(data)+2)%4) % 4
+	data = append(data, bytes.Repeat([]byte{integrityPadByte}, padLen)...)
+	data = append(data, byte(padLen), nextHeaderRMCP)
+	data = append(data, hmacSha1(item.k1, data)[:authCodeLen]...)
+	return append([]byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI}, data...)
+}
+
+// kuid is the password padded to 20 bytes
+func (s *Server) kuid() []byte {
+	kuid := make([]byte, maxPasswordLen)
+	copy(kuid, s.password)
+	return kuid
+}
+
+// encrypt encrypts the payload with AES-CBC-128 with a random IV, the confidentiality pad is 1, 2, 3... and its length
+func encrypt(k2, data []byte) []byte {
+	block, _ := aes.NewCipher(k2[:aes.BlockSize])
+	padLen := (aes.BlockSize - (len(data)+1)%aes.BlockSize) % aes.BlockSize
+	plain := append([]byte{}, data...)
+	for i := 1; i <= padLen; i++ {
+		plain = append(plain, byte(i))
+	}
+	plain = append(plain, byte(padLen))
+	out := make([]byte, aes.BlockSize+len(plain))
+	_, _ = rand.Read(out[:aes.BlockSize])
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], plain)
+	return out
+}
+
+// decrypt returns nil when the payload or its confidentiality pad is invalid
+func decrypt(k2, data []byte) []byte {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil
+	}
+	block, _ := aes.NewCipher(k2[:aes.BlockSize])
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	padLen := int(out[len(out)-1])
+	if padLen+1 > len(out) {
+		return nil
+	}
+	for i := 0; i < padLen; i++ {
+		if out[len(out)-1-padLen+i] != byte(i+1) {
+			return nil
+		}
+	}
+	return out[:len(out)-1-padLen]
+}
+
+func hmacSha1(key []byte, data ...[]byte) []byte {
+	h := hmac.New(sha1.New, key)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func le16(v uint16) []byte {
+	return binary.LittleEndian.AppendUint16(nil, v)
+}
+
+func le32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
@@ -0,0 +1,202 @@
+package ipmi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	fruHeaderLen     = 8
+	fruReadChunkSize = 16
+	fruEndOfFields   = 0xc1
+	// the bmc returns the FRU of the system board with the device id 0
+	FRUDeviceBuiltin = 0x00
+)
+
+// fruEpoch is the base time of the manufacturing date of the board area
+var fruEpoch = time.Date(1996, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// FRU is the inventory of the chassis, board and product areas
+type FRU struct {
+	ChassisPartNumber   string
+	ChassisSerialNumber string
+
+	BoardMfgDate      time.Time
+	BoardManufacturer string
+	BoardProductName  string
+	BoardSerialNumber string
+	BoardPartNumber   string
+
+	ProductManufacturer string
+	ProductName         string
+	ProductPartNumber   string
+	ProductVersion      string
+	ProductSerialNumber string
+	ProductAssetTag     string
+}
+
+// ReadFRU reads and parses the FRU of the device
+func (c *Client) ReadFRU(deviceID byte) (*FRU, error) {
+	data, err := c.Send(NetFnStorage, cmdGetFRUInventoryAreaInfo, []byte{deviceID})
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 3 {
+		return nil, fmt.Errorf("invalid length %d of the fru inventory area info", len(data))
+	}
+	size := int(binary.LittleEndian.Uint16(data[0:2]))
+	wordAccess := data[2]&0x01 != 0
+
+	raw := make([]byte, 0, size)
+	for len(raw) < size {
+		count := size - len(raw)
+		if count > fruReadChunkSize {
+			count = fruReadChunkSize
+		}
+		offset := len(raw)
+		if wordAccess {
+			offset /= 2
+			count = (count + 1) / 2
+		}
+		req := []byte{deviceID}
+		req = append(req, le16(uint16(offset))...)
+		req = append(req, byte(count))
+		data, err := c.Send(NetFnStorage, cmdReadFRUData, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fru data at offset %d: %+v", len(raw), err)
+		}
+		if len(data) < 1 || data[0] == 0 {
+			break
+		}
+		returned := int(data[0])
+		if wordAccess {
+			returned *= 2
+		}
+		if len(data) < 1+returned {
+			returned = len(data) - 1
+		}
+		raw = append(raw, data[1:1+returned]...)
+	}
+	return ParseFRU(raw)
+}
+
+// ParseFRU parses the FRU data according to the Platform Management FRU Information Storage Definition
+func ParseFRU(raw []byte) (*FRU, error) {
+	if len(raw) < fruHeaderLen {
+		return nil, fmt.Errorf("the fru data is too short")
+	}
+	if raw[0]&0x0f != 0x01 || checksum(raw[:fruHeaderLen-1]) != raw[fruHeaderLen-1] {
+		return nil, fmt.Errorf("invalid common header of the fru data")
+	}
+
+	fru := &FRU{}
+	if area := fruArea(raw, raw[2]); area != nil && len(area) > 3 {
+		// version, length, chassis type, then the fields
+		fields := fruFields(area, 3)
+		fru.ChassisPartNumber = fieldAt(fields, 0)
+		fru.ChassisSerialNumber = fieldAt(fields, 1)
+	}
+	if area := fruArea(raw, raw[3]); area != nil && len(area) > 6 {
+		// version, length, language, 3 bytes of manufacturing date, then the fields
+		if minutes := uint32(area[3]) | uint32(area[4])<<8 | uint32(area[5])<<16; minutes != 0 {
+			fru.BoardMfgDate = fruEpoch.Add(time.Duration(minutes) * time.Minute)
+		}
+		fields := fruFields(area, 6)
+		fru.BoardManufacturer = fieldAt(fields, 0)
+		fru.BoardProductName = fieldAt(fields, 1)
+		fru.BoardSerialNumber = fieldAt(fields, 2)
+		fru.BoardPartNumber = fieldAt(fields, 3)
+	}
+	if area := fruArea(raw, raw[4]); area != nil && len(area) > 3 {
+		// version, length, language, then the fields
+		fields := fruFields(area, 3)
+		fru.ProductManufacturer = fieldAt(fields, 0)
+		fru.ProductName = fieldAt(fields, 1)
+		fru.ProductPartNumber = fieldAt(fields, 2)
+		fru.ProductVersion = fieldAt(fields, 3)
+		fru.ProductSerialNumber = fieldAt(fields, 4)
+		fru.ProductAssetTag = fieldAt(fields, 5)
+	}
+	return fru, nil
+}
+
+// fruArea returns the area at the offset in multiples of 8 bytes, or nil when the area is absent or truncated
+func fruArea(raw []byte, offset byte) []byte {
+	start := int(offset) * 8
+	if offset == 0 || start+2 > len(raw) {
+		return nil
+	}
+	length := int(raw[start+1]) * 8
+	if length == 0 || start+length > len(raw) {
+		return nil
+	}
+	return raw[start : start+length]
+}
+
+// fruFields decodes the type/length fields from pos until the end-of-fields marker
+func fruFields(area []byte, pos int) []string {
+	var fields []string
+	for pos < len(area) && area[pos] != fruEndOfFields {
+		typeLength := area[pos]
+		n := int(typeLength & 0x3f)
+		if pos+1+n > len(area) {
+			break
+		}
+		fields = append(fields, decodeField(typeLength>>6, area[pos+1:pos+1+n]))
+		pos += 1 + n
+	}
+	return fields
+}
+
+func fieldAt(fields []string, i int) string {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return ""
+}
+
+func decodeField(fieldType byte, data []byte) string {
+	var s string
+	switch fieldType {
+	case 0:
+		// binary or unspecified
+		s = hex.EncodeToString(data)
+	case 1:
+		s = decodeBCDPlus(data)
+	case 2:
+		s = decode6BitASCII(data)
+	default:
+		s = string(data)
+	}
+	return strings.TrimSpace(strings.Trim(s, "\x00"))
+}
+
+func decodeBCDPlus(data []byte) string {
+	const chars = "0123456789 -.:,_"
+	var b strings.Builder
+	for _, v := range data {
+		b.WriteByte(chars[v>>4])
+		b.WriteByte(chars[v&0x0f])
+	}
+	return b.String()
+}
+
+// decode6BitASCII decodes the packed 6-bit ASCII, the first character is in the lowest bits
+func decode6BitASCII(data []byte) string {
+	var b strings.Builder
+	var acc uint32
+	bits := 0
+	for _, v := range data {
+		acc |= uint32(v) << bits
+		bits += 8
+		for bits >= 6 {
+			b.WriteByte(byte(acc&0x3f) + 0x20)
+			acc >>= 6
+			bits -= 6
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package ipmi
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrastructure-io/topohub/pkg/ipmi/emulator"
+)
+
+// newFRUArea builds the area with the header bytes after the version and the length, and the 8-bit ascii fields.
+// It is padded to the multiple of 8 bytes with the checksum
+func newFRUArea(header []byte, fields ...string) []byte {
+	area := append([]byte{0x01, 0}, header...)
+	for _, field := range fields {
+		area = append(area, 0xc0|byte(len(field)))
+		area = append(area, field...)
+	}
+	area = append(area, fruEndOfFields)
+	for (len(area)+1)%8 != 0 {
+		area = append(area, 0)
+	}
+	area[1] = byte((len(area) + 1) / 8)
+	return append(area, checksum(area))
+}
+
+// newFRU builds the FRU data with the common header and the chassis, board and product areas which are not nil
+func newFRU(chassis, board, product []byte) []byte {
+	header := []byte{0x01, 0, 0, 0, 0, 0, 0}
+	offset := 1
+	for i, area := range [][]byte{chassis, board, product} {
+		if area != nil {
+			header[2+i] = byte(offset)
+			offset += len(area) / 8
+		}
+	}
+	raw := append(header, checksum(header))
+	raw = append(raw, chassis...)
+	raw = append(raw, board...)
+	return append(raw, product...)
+}
+
+var (
+	testChassisArea = newFRUArea([]byte{0x17}, "CPN-001", "CSN-001")
+	// the board is manufactured at 2023-06-01 00:00 UTC
+	testBoardArea   = newFRUArea([]byte{0x00, 0x20, 0x03, 0xdc}, "Acme", "X11DPi", "BSN-001", "BPN-001")
+	testProductArea = newFRUArea([]byte{0x00}, "Acme Inc.", "Server 2U", "PPN-001", "1.0", "PSN-001", "ASSET-01")
+)
+
+var _ = Describe("FRU", Label("unitest"), func() {
+	DescribeTable("decodes the field by its type",
+		func(fieldType byte, data []byte, expected string) {
+			Expect(decodeField(fieldType, data)).To(Equal(expected))
+		},
+		Entry("binary", byte(0), []byte{0xde, 0xad, 0xbe, 0xef}, "deadbeef"),
+		Entry("BCD plus", byte(1), []byte{0x12, 0xa3, 0xbc, 0x4f}, "12 3-.4_"),
+		// the example of the Platform Management FRU Information Storage Definition
+		Entry("6-bit ascii", byte(2), []byte{0x29, 0xdc, 0xa6}, "IPMI"),
+		Entry("6-bit ascii of several groups", byte(2), []byte{0xa1, 0x38, 0x92, 0xa5, 0x19, 0x49}, "ABCDEF12"),
+		Entry("8-bit ascii", byte(3), []byte("Acme"), "Acme"),
+		Entry("8-bit ascii padded with the spaces and the nulls", byte(3), []byte(" SN01  \x00\x00"), "SN01"),
+	)
+
+	DescribeTable("parses the FRU data",
+		func(raw []byte, expected *FRU) {
+			fru, err := ParseFRU(raw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fru).To(Equal(expected))
+		},
+		Entry("with all the areas", newFRU(testChassisArea, testBoardArea, testProductArea), &FRU{
+			ChassisPartNumber:   "CPN-001",
+			ChassisSerialNumber: "CSN-001",
+			BoardMfgDate:        time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			BoardManufacturer:   "Acme",
+			BoardProductName:    "X11DPi",
+			BoardSerialNumber:   "BSN-001",
+			BoardPartNumber:     "BPN-001",
+			ProductManufacturer: "Acme Inc.",
+			ProductName:         "Server 2U",
+			ProductPartNumber:   "PPN-001",
+			ProductVersion:      "1.0",
+			ProductSerialNumber: "PSN-001",
+			ProductAssetTag:     "ASSET-01",
+		}),
+		Entry("with the board area only", newFRU(nil, newFRUArea([]byte{0, 0, 0, 0}, "Acme", "X11DPi"), nil), &FRU{
+			BoardManufacturer: "Acme",
+			BoardProductName:  "X11DPi",
+		}),
+		Entry("without the areas", newFRU(nil, nil, nil), &FRU{}),
+		Entry("with the area beyond the data", func() []byte {
+			raw := newFRU(testChassisArea, nil, nil)
+			raw[4], raw[7] = 0x20, raw[7]-0x20
+			return raw
+		}(), &FRU{ChassisPartNumber: "CPN-001", ChassisSerialNumber: "CSN-001"}),
+		Entry("with the field longer than the area", func() []byte {
+			raw := newFRU(testChassisArea, nil, nil)
+			// the type/length byte of the serial number
+			raw[8+3+8] = 0xc0 | 0x3f
+			return raw
+		}(), &FRU{ChassisPartNumber: "CPN-001"}),
+	)
+
+	DescribeTable("refuses the invalid FRU data",
+		func(raw []byte) {
+			_, err := ParseFRU(raw)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("too short", []byte{0x01, 0, 0, 0, 0, 0, 0}),
+		Entry("with the unknown version", []byte{0x02, 0, 0, 0, 0, 0, 0, 0xfe}),
+		Entry("with the wrong checksum", []byte{0x01, 0, 1, 0, 0, 0, 0, 0xff}),
+	)
+
+	It("does not panic on the truncated FRU data", func() {
+		raw := newFRU(testChassisArea, testBoardArea, testProductArea)
+		for n := 0; n <= len(raw); n++ {
+			Expect(func() { _, _ = ParseFRU(raw[:n]) }).NotTo(Panic())
+		}
+		// the lengths of the areas and the fields are changed
+		for i := fruHeaderLen; i < len(raw); i++ {
+			changed := append([]byte{}, raw...)
+			changed[i] = 0xff
+			Expect(func() { _, _ = ParseFRU(changed) }).NotTo(Panic())
+		}
+	})
+
+	It("reads the FRU of the bmc in chunks", func() {
+		bmc, c := startBmc()
+		// the last chunk is shorter than the others
+		raw := newFRU(nil, testBoardArea, testProductArea)
+		Expect(len(raw) % fruReadChunkSize).NotTo(BeZero())
+		bmc.SetFRU(raw)
+
+		fru, err := c.ReadFRU(FRUDeviceBuiltin)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fru.ChassisSerialNumber).To(BeEmpty())
+		Expect(fru.ProductSerialNumber).To(Equal("PSN-001"))
+		Expect(fru.BoardMfgDate).To(Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(bmc.CountCommands(emulator.NetFnStorage, emulator.CmdReadFRUData)).To(Equal((len(raw) + fruReadChunkSize - 1) / fruReadChunkSize))
+	})
+
+	It("fails when the bmc has no FRU", func() {
+		_, c := startBmc()
+		_, err := c.ReadFRU(FRUDeviceBuiltin)
+		Expect(IsCompletionCode(err, CompletionDataNotPresent)).To(BeTrue())
+	})
+})
@@ -0,0 +1,13 @@
+package ipmi
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestIpmi(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ipmi Suite")
+}
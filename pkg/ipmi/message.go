@@ -0,0 +1,152 @@
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// network function codes of the requests, the response uses netFn+1
+const (
+	NetFnChassis = 0x00
+	NetFnApp     = 0x06
+	NetFnStorage = 0x0a
+)
+
+// commands of the App network function
+const (
+	cmdGetDeviceID                = 0x01
+	cmdColdReset                  = 0x02
+	cmdWarmReset                  = 0x03
+	cmdGetChannelAuthCapabilities = 0x38
+	cmdSetSessionPrivilege        = 0x3b
+	cmdCloseSession               = 0x3c
+)
+
+// commands of the Chassis network function
+const (
+	cmdGetChassisStatus     = 0x01
+	cmdChassisControl       = 0x02
+	cmdChassisIdentify      = 0x04
+	cmdSetSystemBootOptions = 0x08
+)
+
+// commands of the Storage network function
+const (
+	cmdGetFRUInventoryAreaInfo = 0x10
+	cmdReadFRUData             = 0x11
+	cmdGetSELInfo              = 0x40
+	cmdReserveSEL              = 0x42
+	cmdGetSELEntry             = 0x43
+	cmdClearSEL                = 0x47
+)
+
+const (
+	bmcSlaveAddr  = 0x20
+	remoteSwID    = 0x81
+	minMessageLen = 8
+)
+
+// completion codes which are checked by the commands
+const (
+	CompletionOK               = 0x00
+	CompletionInvalidCommand   = 0xc1
+	CompletionDataNotPresent   = 0xcb
+	CompletionInsufficientPriv = 0xd4
+)
+
+// CompletionError is returned when the bmc completes the command with a non-zero completion code
+type CompletionError struct {
+	NetFn   byte
+	Command byte
+	Code    byte
+}
+
+func (e *CompletionError) Error() string {
+	return fmt.Sprintf("ipmi command 0x%02x of netfn 0x%02x failed with completion code 0x%02x: %s", e.Command, e.NetFn, e.Code, completionCodeText(e.Code))
+}
+
+// IsCompletionCode returns true when the err is a CompletionError with the code
+func IsCompletionCode(err error, code byte) bool {
+	e, ok := err.(*CompletionError)
+	return ok && e.Code == code
+}
+
+func completionCodeText(code byte) string {
+	switch code {
+	case 0xc0:
+		return "node busy"
+	case CompletionInvalidCommand:
+		return "invalid command"
+	case 0xc3:
+		return "timeout while processing command"
+	case 0xc7:
+		return "request data length invalid"
+	case 0xc9:
+		return "parameter out of range"
+	case CompletionDataNotPresent:
+		return "requested data not present"
+	case 0xcc:
+		return "invalid data field in request"
+	case 0xce:
+		return "response could not be provided"
+	case 0xd5:
+		return "command not supported in present state"
+	case CompletionInsufficientPriv:
+		return "insufficient privilege level"
+	case 0xff:
+		return "unspecified error"
+	}
+	return "unknown"
+}
+
+// checksum is the 2's complement of the sum of the bytes
+func checksum(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
+
+// buildMessage builds the ipmi lan message from the remote console to the bmc
+func buildMessage(netFn, cmd, seq byte, data []byte) []byte {
+	msg := []byte{bmcSlaveAddr, netFn << 2, 0}
+	msg[2] = checksum(msg[:2])
+	body := []byte{remoteSwID, seq << 2, cmd}
+	body = append(body, data...)
+	body = append(body, checksum(body))
+	return append(msg, body...)
+}
+
+// errUnexpected means the packet is not the response of the request, such as the late response of the former retry
+var errUnexpected = errors.New("unexpected ipmi response")
+
+// parseMessage checks the response of the request, and returns the data after the completion code
+func parseMessage(msg []byte, netFn, cmd, seq byte) ([]byte, error) {
+	if len(msg) < minMessageLen {
+		return nil, errUnexpected
+	}
+	if msg[1]>>2 != netFn+1 || msg[5] != cmd || msg[4]>>2 != seq {
+		return nil, errUnexpected
+	}
+	if checksum(msg[:2]) != msg[2] || checksum(msg[3:len(msg)-1]) != msg[len(msg)-1] {
+		return nil, fmt.Errorf("invalid checksum of the ipmi response")
+	}
+	if code := msg[6]; code != CompletionOK {
+		return nil, &CompletionError{NetFn: netFn, Command: cmd, Code: code}
+	}
+	return msg[7 : len(msg)-1], nil
+}
+
+func le16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func le32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
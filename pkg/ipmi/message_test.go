@@ -0,0 +1,78 @@
+package ipmi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// response builds the response message of the bmc to the request of buildMessage
+func response(netFn, cmd, seq, code byte, data ...byte) []byte {
+	msg := []byte{remoteSwID, (netFn + 1) << 2, 0}
+	msg[2] = checksum(msg[:2])
+	body := append([]byte{bmcSlaveAddr, seq << 2, cmd, code}, data...)
+	return append(msg, append(body, checksum(body))...)
+}
+
+var _ = Describe("ipmi message", Label("unitest"), func() {
+	DescribeTable("computes the checksum which makes the sum zero",
+		func(data []byte, expected byte) {
+			Expect(checksum(data)).To(Equal(expected))
+			var sum byte
+			for _, b := range append(data, expected) {
+				sum += b
+			}
+			Expect(sum).To(BeZero())
+		},
+		Entry("empty", []byte{}, byte(0x00)),
+		Entry("the header of the request", []byte{0x20, 0x18}, byte(0xc8)),
+		Entry("overflow", []byte{0xff, 0xff, 0x03}, byte(0xff)),
+	)
+
+	It("builds the request message", func() {
+		Expect(buildMessage(NetFnApp, cmdGetDeviceID, 1, nil)).To(Equal([]byte{0x20, 0x18, 0xc8, 0x81, 0x04, 0x01, 0x7a}))
+		Expect(buildMessage(NetFnChassis, cmdChassisControl, 0x3f, []byte{ChassisPowerUp})).
+			To(Equal([]byte{0x20, 0x00, 0xe0, 0x81, 0xfc, 0x02, 0x01, 0x80}))
+	})
+
+	DescribeTable("parses the response message",
+		func(msg []byte, data []byte, expectedErr interface{}) {
+			result, err := parseMessage(msg, NetFnStorage, cmdGetSELInfo, 5)
+			if expectedErr != nil {
+				Expect(err).To(MatchError(expectedErr))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(data))
+		},
+		Entry("with the data", response(NetFnStorage, cmdGetSELInfo, 5, CompletionOK, 0x51, 0x02), []byte{0x51, 0x02}, nil),
+		Entry("without the data", response(NetFnStorage, cmdGetSELInfo, 5, CompletionOK), []byte{}, nil),
+		Entry("of another netFn", response(NetFnApp, cmdGetSELInfo, 5, CompletionOK), nil, errUnexpected),
+		Entry("of another command", response(NetFnStorage, cmdGetSELEntry, 5, CompletionOK), nil, errUnexpected),
+		Entry("of the former request", response(NetFnStorage, cmdGetSELInfo, 4, CompletionOK), nil, errUnexpected),
+		Entry("too short", response(NetFnStorage, cmdGetSELInfo, 5, CompletionOK)[:7], nil, errUnexpected),
+		Entry("with the wrong checksum of the header",
+			func() []byte { m := response(NetFnStorage, cmdGetSELInfo, 5, CompletionOK); m[2]++; return m }(), nil, "invalid checksum of the ipmi response"),
+		Entry("with the wrong checksum of the body",
+			func() []byte { m := response(NetFnStorage, cmdGetSELInfo, 5, CompletionOK, 1); m[7]++; return m }(), nil, "invalid checksum of the ipmi response"),
+		Entry("with the completion code", response(NetFnStorage, cmdGetSELInfo, 5, CompletionInsufficientPriv), nil,
+			&CompletionError{NetFn: NetFnStorage, Command: cmdGetSELInfo, Code: CompletionInsufficientPriv}),
+	)
+
+	It("reports the completion code", func() {
+		_, err := parseMessage(response(NetFnStorage, cmdGetSELEntry, 1, CompletionDataNotPresent), NetFnStorage, cmdGetSELEntry, 1)
+		Expect(IsCompletionCode(err, CompletionDataNotPresent)).To(BeTrue())
+		Expect(IsCompletionCode(err, CompletionInvalidCommand)).To(BeFalse())
+		Expect(IsCompletionCode(errUnexpected, CompletionDataNotPresent)).To(BeFalse())
+		Expect(err).To(MatchError("ipmi command 0x43 of netfn 0x0a failed with completion code 0xcb: requested data not present"))
+	})
+
+	It("does not panic on the truncated message", func() {
+		msg := response(NetFnStorage, cmdGetSELInfo, 5, CompletionOK, 0x51, 0x02, 0x00)
+		for n := 0; n < len(msg); n++ {
+			Expect(func() {
+				_, err := parseMessage(msg[:n], NetFnStorage, cmdGetSELInfo, 5)
+				Expect(err).To(HaveOccurred())
+			}).NotTo(Panic())
+		}
+	})
+})
@@ -0,0 +1,408 @@
+package ipmi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const (
+	selRecordLen         = 16
+	selLastRecordID      = 0xffff
+	selRecordTypeSystem  = 0x02
+	eventTypeThreshold   = 0x01
+	eventTypeSensorSpec  = 0x6f
+	eventDirDeassertion  = 0x80
+	eventOffsetMask      = 0x0f
+	eventTypeMask        = 0x7f
+	selTimestampUnknown  = 0xffffffff
+	selTimestampPostInit = 0x20000000
+	selClearInitiate     = 0xaa
+
+	SeverityOK       = "OK"
+	SeverityWarning  = "Warning"
+	SeverityCritical = "Critical"
+)
+
+type SELInfo struct {
+	Entries uint16
+	// the timestamps change when the entries are added or erased
+	LastAddition uint32
+	LastErase    uint32
+}
+
+// SELRecord is a record of the system event log
+type SELRecord struct {
+	RecordID   uint16
+	RecordType byte
+	// Timestamp is zero when the bmc does not know the time, such as the event before the clock is synchronized
+	Timestamp    time.Time
+	SensorType   byte
+	SensorNumber byte
+	EventType    byte
+	Deassertion  bool
+	EventData    [3]byte
+}
+
+func (c *Client) GetSELInfo() (*SELInfo, error) {
+	data, err := c.Send(NetFnStorage, cmdGetSELInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 13 {
+		return nil, fmt.Errorf("invalid length %d of the sel info", len(data))
+	}
+	return &SELInfo{
+		Entries:      binary.LittleEndian.Uint16(data[1:3]),
+		LastAddition: binary.LittleEndian.Uint32(data[5:9]),
+		LastErase:    binary.LittleEndian.Uint32(data[9:13]),
+	}, nil
+}
+
+// GetSELEntries reads all records of the SEL, from the oldest to the newest
+func (c *Client) GetSELEntries() ([]SELRecord, error) {
+	var records []SELRecord
+	id := uint16(0)
+	// the count of the records is limited, in case of the bmc links the records in a loop
+	for i := 0; i < selLastRecordID; i++ {
+		req := []byte{0, 0}
+		req = append(req, le16(id)...)
+		req = append(req, 0, 0xff)
+		data, err := c.Send(NetFnStorage, cmdGetSELEntry, req)
+		if err != nil {
+			// the sel is empty
+			if i == 0 && IsCompletionCode(err, CompletionDataNotPresent) {
+				return nil, nil
+			}
+			return records, fmt.Errorf("failed to get sel entry %d: %+v", id, err)
+		}
+		if len(data) < 2+selRecordLen {
+			return records, fmt.Errorf("invalid length %d of the sel entry %d", len(data), id)
+		}
+		records = append(records, parseSELRecord(data[2:2+selRecordLen]))
+
+		next := binary.LittleEndian.Uint16(data[0:2])
+		if next == selLastRecordID || next == id {
+			break
+		}
+		id = next
+	}
+	return records, nil
+}
+
+// ClearSEL initiates the erasure of all the records of the SEL
+func (c *Client) ClearSEL() error {
+	data, err := c.Send(NetFnStorage, cmdReserveSEL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to reserve sel: %+v", err)
+	}
+	if len(data) < 2 {
+		return fmt.Errorf("invalid length %d of the sel reservation", len(data))
+	}
+	req := []byte{data[0], data[1], 'C', 'L', 'R', selClearInitiate}
+	if _, err := c.Send(NetFnStorage, cmdClearSEL, req); err != nil {
+		return fmt.Errorf("failed to clear sel: %+v", err)
+	}
+	return nil
+}
+
+func parseSELRecord(data []byte) SELRecord {
+	r := SELRecord{
+		RecordID:   binary.LittleEndian.Uint16(data[0:2]),
+		RecordType: data[2],
+	}
+	// the OEM records have no timestamp or sensor
+	if r.RecordType >= 0xe0 {
+		return r
+	}
+	if ts := binary.LittleEndian.Uint32(data[3:7]); ts != selTimestampUnknown && ts > selTimestampPostInit {
+		r.Timestamp = time.Unix(int64(ts), 0).UTC()
+	}
+	if r.RecordType == selRecordTypeSystem {
+		r.SensorType = data[10]
+		r.SensorNumber = data[11]
+		r.EventType = data[12] & eventTypeMask
+		r.Deassertion = data[12]&eventDirDeassertion != 0
+		copy(r.EventData[:], data[13:16])
+	}
+	return r
+}
+
+// Offset returns the event offset of the discrete or threshold event
+func (r *SELRecord) Offset() byte {
+	return r.EventData[0] & eventOffsetMask
+}
+
+// Severity returns OK, Warning or Critical according to the sensor type and the event offset
+func (r *SELRecord) Severity() string {
+	if r.RecordType != selRecordTypeSystem || r.Deassertion {
+		return SeverityOK
+	}
+	offset := r.Offset()
+	switch r.EventType {
+	case eventTypeThreshold:
+		switch offset {
+		case 0x00, 0x01, 0x06, 0x07:
+			return SeverityWarning
+		default:
+			return SeverityCritical
+		}
+	case eventTypeSensorSpec:
+		if severities, ok := sensorSpecificSeverity[r.SensorType]; ok {
+			if severity, ok := severities[offset]; ok {
+				return severity
+			}
+		}
+	}
+	return SeverityOK
+}
+
+// SensorTypeName returns the name of the sensor type
+func (r *SELRecord) SensorTypeName() string {
+	if name, ok := sensorTypeNames[r.SensorType]; ok {
+		return name
+	}
+	return fmt.Sprintf("Sensor Type 0x%02x", r.SensorType)
+}
+
+// Message describes the event like ipmitool
+func (r *SELRecord) Message() string {
+	if r.RecordType != selRecordTypeSystem {
+		return fmt.Sprintf("OEM record type 0x%02x", r.RecordType)
+	}
+	direction := "Asserted"
+	if r.Deassertion {
+		direction = "Deasserted"
+	}
+	return fmt.Sprintf("%s #0x%02x | %s | %s", r.SensorTypeName(), r.SensorNumber, r.description(), direction)
+}
+
+func (r *SELRecord) description() string {
+	offset := r.Offset()
+	switch r.EventType {
+	case eventTypeThreshold:
+		if int(offset) < len(thresholdEvents) {
+			return thresholdEvents[offset]
+		}
+	case eventTypeSensorSpec:
+		if descriptions, ok := sensorSpecificEvents[r.SensorType]; ok {
+			if description, ok := descriptions[offset]; ok {
+				return description
+			}
+		}
+	}
+	return fmt.Sprintf("Event type 0x%02x offset 0x%02x", r.EventType, offset)
+}
+
+var thresholdEvents = []string{
+	"Lower Non-critical going low",
+	"Lower Non-critical going high",
+	"Lower Critical going low",
+	"Lower Critical going high",
+	"Lower Non-recoverable going low",
+	"Lower Non-recoverable going high",
+	"Upper Non-critical going low",
+	"Upper Non-critical going high",
+	"Upper Critical going low",
+	"Upper Critical going high",
+	"Upper Non-recoverable going low",
+	"Upper Non-recoverable going high",
+}
+
+var sensorTypeNames = map[byte]string{
+	0x01: "Temperature",
+	0x02: "Voltage",
+	0x03: "Current",
+	0x04: "Fan",
+	0x05: "Physical Security",
+	0x06: "Platform Security",
+	0x07: "Processor",
+	0x08: "Power Supply",
+	0x09: "Power Unit",
+	0x0a: "Cooling Device",
+	0x0b: "Other Units-based Sensor",
+	0x0c: "Memory",
+	0x0d: "Drive Slot",
+	0x0e: "POST Memory Resize",
+	0x0f: "System Firmware Progress",
+	0x10: "Event Logging Disabled",
+	0x11: "Watchdog 1",
+	0x12: "System Event",
+	0x13: "Critical Interrupt",
+	0x14: "Button / Switch",
+	0x15: "Module / Board",
+	0x16: "Microcontroller / Coprocessor",
+	0x17: "Add-in Card",
+	0x18: "Chassis",
+	0x19: "Chip Set",
+	0x1a: "Other FRU",
+	0x1b: "Cable / Interconnect",
+	0x1c: "Terminator",
+	0x1d: "System Boot Initiated",
+	0x1e: "Boot Error",
+	0x1f: "OS Boot",
+	0x20: "OS Critical Stop",
+	0x21: "Slot / Connector",
+	0x22: "System ACPI Power State",
+	0x23: "Watchdog 2",
+	0x24: "Platform Alert",
+	0x25: "Entity Presence",
+	0x26: "Monitor ASIC",
+	0x27: "LAN",
+	0x28: "Management Subsystem Health",
+	0x29: "Battery",
+	0x2a: "Session Audit",
+	0x2b: "Version Change",
+	0x2c: "FRU State",
+}
+
+// sensorSpecificEvents describes the offsets of the sensor specific events of the common sensor types
+var sensorSpecificEvents = map[byte]map[byte]string{
+	0x05: {
+		0x00: "General Chassis intrusion",
+		0x01: "Drive Bay intrusion",
+		0x02: "I/O Card area intrusion",
+		0x03: "Processor area intrusion",
+		0x04: "System unplugged from LAN",
+		0x05: "Unauthorized dock",
+		0x06: "FAN area intrusion",
+	},
+	0x07: {
+		0x00: "IERR",
+		0x01: "Thermal Trip",
+		0x02: "FRB1/BIST failure",
+		0x03: "FRB2/Hang in POST failure",
+		0x04: "FRB3/Processor Startup/Initialization failure",
+		0x05: "Configuration Error",
+		0x06: "SM BIOS Uncorrectable CPU-complex Error",
+		0x07: "Presence detected",
+		0x08: "Disabled",
+		0x09: "Terminator presence detected",
+		0x0a: "Throttled",
+		0x0b: "Uncorrectable machine check exception",
+		0x0c: "Correctable machine check error",
+	},
+	0x08: {
+		0x00: "Presence detected",
+		0x01: "Failure detected",
+		0x02: "Predictive failure",
+		0x03: "Power Supply AC lost",
+		0x04: "AC lost or out-of-range",
+		0x05: "AC out-of-range, but present",
+		0x06: "Configuration error",
+	},
+	0x09: {
+		0x00: "Power off/down",
+		0x01: "Power cycle",
+		0x02: "240VA power down",
+		0x03: "Interlock power down",
+		0x04: "AC lost",
+		0x05: "Soft-power control failure",
+		0x06: "Failure detected",
+		0x07: "Predictive failure",
+	},
+	0x0c: {
+		0x00: "Correctable ECC",
+		0x01: "Uncorrectable ECC",
+		0x02: "Parity",
+		0x03: "Memory Scrub Failed",
+		0x04: "Memory Device Disabled",
+		0x05: "Correctable ECC logging limit reached",
+		0x06: "Presence detected",
+		0x07: "Configuration error",
+		0x08: "Spare",
+		0x09: "Throttled",
+		0x0a: "Critical Overtemperature",
+	},
+	0x0d: {
+		0x00: "Drive Present",
+		0x01: "Drive Fault",
+		0x02: "Predictive Failure",
+		0x03: "Hot Spare",
+		0x04: "Parity Check In Progress",
+		0x05: "In Critical Array",
+		0x06: "In Failed Array",
+		0x07: "Rebuild in Progress",
+		0x08: "Rebuild Aborted",
+	},
+	0x10: {
+		0x00: "Correctable memory error logging disabled",
+		0x01: "Event logging disabled",
+		0x02: "Log area reset/cleared",
+		0x03: "All event logging disabled",
+		0x04: "Log full",
+		0x05: "Log almost full",
+	},
+	0x12: {
+		0x00: "System Reconfigured",
+		0x01: "OEM System boot event",
+		0x02: "Undetermined system hardware failure",
+		0x03: "Entry added to auxiliary log",
+		0x04: "PEF Action",
+		0x05: "Timestamp Clock Sync",
+	},
+	0x13: {
+		0x00: "Front Panel NMI/Diagnostic Interrupt",
+		0x01: "Bus Timeout",
+		0x02: "I/O channel check NMI",
+		0x03: "Software NMI",
+		0x04: "PCI PERR",
+		0x05: "PCI SERR",
+		0x06: "EISA failsafe timeout",
+		0x07: "Bus Correctable error",
+		0x08: "Bus Uncorrectable error",
+		0x09: "Fatal NMI",
+		0x0a: "Bus Fatal Error",
+		0x0b: "Bus Degraded",
+	},
+	0x23: {
+		0x00: "Timer expired",
+		0x01: "Hard reset",
+		0x02: "Power down",
+		0x03: "Power cycle",
+		0x08: "Timer interrupt",
+	},
+}
+
+// sensorSpecificSeverity is the severity of the sensor specific events which indicate the failure,
+// the other events such as the presence are OK
+var sensorSpecificSeverity = map[byte]map[byte]string{
+	0x05: {
+		0x00: SeverityWarning, 0x01: SeverityWarning, 0x02: SeverityWarning, 0x03: SeverityWarning,
+		0x04: SeverityWarning, 0x05: SeverityWarning, 0x06: SeverityWarning,
+	},
+	0x07: {
+		0x00: SeverityCritical, 0x01: SeverityCritical, 0x02: SeverityCritical, 0x03: SeverityCritical,
+		0x04: SeverityCritical, 0x05: SeverityCritical, 0x06: SeverityCritical, 0x08: SeverityWarning,
+		0x0a: SeverityWarning, 0x0b: SeverityCritical, 0x0c: SeverityWarning,
+	},
+	0x08: {
+		0x01: SeverityCritical, 0x02: SeverityWarning, 0x03: SeverityCritical, 0x04: SeverityWarning,
+		0x05: SeverityWarning, 0x06: SeverityWarning,
+	},
+	0x09: {
+		0x04: SeverityCritical, 0x05: SeverityCritical, 0x06: SeverityCritical, 0x07: SeverityWarning,
+	},
+	0x0c: {
+		0x00: SeverityWarning, 0x01: SeverityCritical, 0x02: SeverityCritical, 0x03: SeverityCritical,
+		0x04: SeverityWarning, 0x05: SeverityWarning, 0x07: SeverityWarning, 0x0a: SeverityCritical,
+	},
+	0x0d: {
+		0x01: SeverityCritical, 0x02: SeverityWarning, 0x05: SeverityWarning, 0x06: SeverityCritical,
+		0x08: SeverityWarning,
+	},
+	0x10: {
+		0x03: SeverityWarning, 0x04: SeverityWarning,
+	},
+	0x12: {
+		0x02: SeverityCritical,
+	},
+	0x13: {
+		0x00: SeverityWarning, 0x01: SeverityCritical, 0x02: SeverityCritical, 0x03: SeverityWarning,
+		0x04: SeverityCritical, 0x05: SeverityCritical, 0x07: SeverityWarning, 0x08: SeverityCritical,
+		0x09: SeverityCritical, 0x0a: SeverityCritical, 0x0b: SeverityWarning,
+	},
+	0x23: {
+		0x00: SeverityWarning, 0x01: SeverityWarning, 0x02: SeverityWarning, 0x03: SeverityWarning,
+	},
+}
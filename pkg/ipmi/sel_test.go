@@ -0,0 +1,143 @@
+package ipmi
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrastructure-io/topohub/pkg/ipmi/emulator"
+)
+
+// newSELRecord builds the system event record at 2024-03-01 08:30:00 UTC, the record id is set by the emulator
+func newSELRecord(sensorType, sensorNumber, eventDirType, offset byte) [selRecordLen]byte {
+	return [selRecordLen]byte{0, 0, selRecordTypeSystem, 0x08, 0x92, 0xe1, 0x65, 0x20, 0x00, 0x04,
+		sensorType, sensorNumber, eventDirType, offset, 0xff, 0xff}
+}
+
+var _ = Describe("SEL", Label("unitest"), func() {
+	DescribeTable("parses the record",
+		func(data [selRecordLen]byte, expected SELRecord) {
+			Expect(parseSELRecord(data[:])).To(Equal(expected))
+		},
+		Entry("system event record",
+			[selRecordLen]byte{0x2a, 0x00, 0x02, 0x08, 0x92, 0xe1, 0x65, 0x20, 0x00, 0x04, 0x0c, 0x31, 0x6f, 0x01, 0xff, 0x02},
+			SELRecord{
+				RecordID:     42,
+				RecordType:   selRecordTypeSystem,
+				Timestamp:    time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+				SensorType:   0x0c,
+				SensorNumber: 0x31,
+				EventType:    eventTypeSensorSpec,
+				EventData:    [3]byte{0x01, 0xff, 0x02},
+			}),
+		Entry("deassertion event",
+			[selRecordLen]byte{0x01, 0x00, 0x02, 0x08, 0x92, 0xe1, 0x65, 0x20, 0x00, 0x04, 0x01, 0x02, 0x81, 0x09, 0x5a, 0x50},
+			SELRecord{
+				RecordID:     1,
+				RecordType:   selRecordTypeSystem,
+				Timestamp:    time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+				SensorType:   0x01,
+				SensorNumber: 0x02,
+				EventType:    eventTypeThreshold,
+				Deassertion:  true,
+				EventData:    [3]byte{0x09, 0x5a, 0x50},
+			}),
+		Entry("the timestamp is unknown",
+			[selRecordLen]byte{0x02, 0x00, 0x02, 0xff, 0xff, 0xff, 0xff, 0x20, 0x00, 0x04, 0x12, 0x01, 0x6f, 0x05, 0xff, 0xff},
+			SELRecord{RecordID: 2, RecordType: selRecordTypeSystem, SensorType: 0x12, SensorNumber: 0x01, EventType: eventTypeSensorSpec, EventData: [3]byte{0x05, 0xff, 0xff}}),
+		Entry("the timestamp is relative to the initialization of the bmc",
+			[selRecordLen]byte{0x03, 0x00, 0x02, 0x10, 0x00, 0x00, 0x00, 0x20, 0x00, 0x04, 0x12, 0x01, 0x6f, 0x05, 0xff, 0xff},
+			SELRecord{RecordID: 3, RecordType: selRecordTypeSystem, SensorType: 0x12, SensorNumber: 0x01, EventType: eventTypeSensorSpec, EventData: [3]byte{0x05, 0xff, 0xff}}),
+		Entry("OEM record without the timestamp",
+			[selRecordLen]byte{0x04, 0x00, 0xe0, 0x08, 0x92, 0xe1, 0x65, 0x20, 0x00, 0x04, 0x12, 0x01, 0x6f, 0x05, 0xff, 0xff},
+			SELRecord{RecordID: 4, RecordType: 0xe0}),
+		Entry("OEM record with the timestamp",
+			[selRecordLen]byte{0x05, 0x00, 0xc0, 0x08, 0x92, 0xe1, 0x65, 0x57, 0x01, 0x00, 0x12, 0x01, 0x6f, 0x05, 0xff, 0xff},
+			SELRecord{RecordID: 5, RecordType: 0xc0, Timestamp: time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)}),
+	)
+
+	DescribeTable("describes the severity and the message of the event",
+		func(record SELRecord, severity, message string) {
+			Expect(record.Severity()).To(Equal(severity))
+			Expect(record.Message()).To(Equal(message))
+		},
+		Entry("uncorrectable ECC",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0x0c, SensorNumber: 0x31, EventType: eventTypeSensorSpec, EventData: [3]byte{0x01}},
+			SeverityCritical, "Memory #0x31 | Uncorrectable ECC | Asserted"),
+		Entry("the presence of the power supply",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0x08, SensorNumber: 0x01, EventType: eventTypeSensorSpec, EventData: [3]byte{0x00}},
+			SeverityOK, "Power Supply #0x01 | Presence detected | Asserted"),
+		Entry("threshold going high",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0x01, SensorNumber: 0x02, EventType: eventTypeThreshold, EventData: [3]byte{0x07}},
+			SeverityWarning, "Temperature #0x02 | Upper Non-critical going high | Asserted"),
+		Entry("critical threshold",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0x01, SensorNumber: 0x02, EventType: eventTypeThreshold, EventData: [3]byte{0x09}},
+			SeverityCritical, "Temperature #0x02 | Upper Critical going high | Asserted"),
+		Entry("deasserted threshold",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0x01, SensorNumber: 0x02, EventType: eventTypeThreshold, Deassertion: true, EventData: [3]byte{0x09}},
+			SeverityOK, "Temperature #0x02 | Upper Critical going high | Deasserted"),
+		Entry("the offset is out of the events",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0x2c, SensorNumber: 0x07, EventType: 0x0b, EventData: [3]byte{0x0e}},
+			SeverityOK, "FRU State #0x07 | Event type 0x0b offset 0x0e | Asserted"),
+		Entry("unknown sensor type",
+			SELRecord{RecordType: selRecordTypeSystem, SensorType: 0xc0, SensorNumber: 0x01, EventType: eventTypeSensorSpec, EventData: [3]byte{0x01}},
+			SeverityOK, "Sensor Type 0xc0 #0x01 | Event type 0x6f offset 0x01 | Asserted"),
+		Entry("OEM record", SELRecord{RecordType: 0xe0}, SeverityOK, "OEM record type 0xe0"),
+	)
+
+	It("reads the records from the oldest to the newest", func() {
+		bmc, c := startBmc()
+		bmc.AddSEL(
+			newSELRecord(0x12, 0x01, eventTypeSensorSpec, 0x05),
+			newSELRecord(0x0c, 0x31, eventTypeSensorSpec, 0x01),
+			newSELRecord(0x01, 0x02, eventTypeThreshold|eventDirDeassertion, 0x09),
+		)
+
+		info, err := c.GetSELInfo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Entries).To(Equal(uint16(3)))
+		Expect(info.LastAddition).NotTo(BeZero())
+
+		records, err := c.GetSELEntries()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+		for i, record := range records {
+			Expect(record.RecordID).To(Equal(uint16(i + 1)))
+			Expect(record.Timestamp).To(Equal(time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)))
+		}
+		Expect(records[1].Message()).To(Equal("Memory #0x31 | Uncorrectable ECC | Asserted"))
+		Expect(records[2].Deassertion).To(BeTrue())
+	})
+
+	It("reads nothing from the empty SEL", func() {
+		_, c := startBmc()
+		records, err := c.GetSELEntries()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(BeEmpty())
+	})
+
+	It("returns the records read before the failure", func() {
+		bmc, c := startBmc()
+		bmc.AddSEL(newSELRecord(0x12, 0x01, eventTypeSensorSpec, 0x05))
+		records, err := c.GetSELEntries()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+
+		bmc.Fail(emulator.NetFnStorage, emulator.CmdGetSELEntry, 0xc0)
+		records, err = c.GetSELEntries()
+		Expect(err).To(MatchError(ContainSubstring("node busy")))
+		Expect(records).To(BeEmpty())
+	})
+
+	It("clears the SEL with the reservation", func() {
+		bmc, c := startBmc()
+		bmc.AddSEL(newSELRecord(0x12, 0x01, eventTypeSensorSpec, 0x05))
+		Expect(c.ClearSEL()).To(Succeed())
+		Expect(bmc.SEL()).To(BeEmpty())
+		info, err := c.GetSELInfo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Entries).To(BeZero())
+		Expect(info.LastErase).NotTo(BeZero())
+	})
+})
@@ -0,0 +1,523 @@
+// Package ipmi implements the IPMI v2.0 over lan (RMCP+) client to manage the old bmc which has no usable redfish.
+// It supports the cipher suite 3 (RAKP-HMAC-SHA1, HMAC-SHA1-96, AES-CBC-128), which is enabled by default on most bmc
+package ipmi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPort = 623
+
+	DefaultTimeout = 2 * time.Second
+	defaultRetries = 2
+
+	// the limits of the user name and the password of ipmi v2.0
+	maxUsernameLen = 16
+	maxPasswordLen = 20
+)
+
+const (
+	rmcpVersion   = 0x06
+	rmcpSeqNoAck  = 0xff
+	rmcpClassIPMI = 0x07
+
+	authTypeNone     = 0x00
+	authTypeRMCPPlus = 0x06
+
+	payloadIPMI            = 0x00
+	payloadOpenSessionReq  = 0x10
+	payloadOpenSessionResp = 0x11
+	payloadRAKP1           = 0x12
+	payloadRAKP2           = 0x13
+	payloadRAKP3           = 0x14
+	payloadRAKP4           = 0x15
+	payloadTypeMask        = 0x3f
+	payloadEncrypted       = 0x80
+	payloadAuthenticated   = 0x40
+
+	privilegeAdministrator = 0x04
+	rakpNameOnlyLookup     = 0x10
+
+	// the algorithms of the cipher suite 3
+	authAlgRakpHmacSha1    = 0x01
+	integrityAlgHmacSha196 = 0x01
+	confidentialityAlgAes  = 0x01
+
+	integrityPadByte       = 0xff
+	nextHeaderRMCP         = 0x07
+	authCodeLen            = 12
+	sessionHeaderLen       = 12
+	sessionLessHeaderLen   = 10
+	openSessionResponseLen = 36
+	rakp2Len               = 60
+	rakp4Len               = 20
+	rakpRandomLen          = 16
+	maxPacketLen           = 1024
+	rakpStatusNoErrors     = 0x00
+)
+
+// Client is an ipmi v2.0 session with the bmc. The requests of a session are sent one by one
+type Client struct {
+	addr     string
+	username string
+	password string
+	timeout  time.Duration
+	retries  int
+
+	mu   sync.Mutex
+	conn net.Conn
+	// the session is activated after the RAKP handshake
+	active           bool
+	consoleSessionID uint32
+	bmcSessionID     uint32
+	sessionSeq       uint32
+	rqSeq            byte
+	// k1 authenticates the packets and k2 encrypts the payloads
+	k1 []byte
+	k2 []byte
+}
+
+// NewClient returns the client of the bmc at addr (host:port), the session is established by Open
+func NewClient(addr, username, password string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		addr:     addr,
+		username: username,
+		password: password,
+		timeout:  timeout,
+		retries:  defaultRetries,
+	}
+}
+
+// Open establishes the session with the administrator privilege
+func (c *Client) Open() error {
+	if len(c.username) > maxUsernameLen {
+		return fmt.Errorf("the username is longer than %d characters", maxUsernameLen)
+	}
+	if len(c.password) > maxPasswordLen {
+		return fmt.Errorf("the password is longer than %d characters", maxPasswordLen)
+	}
+
+	conn, err := net.DialTimeout("udp", c.addr, c.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to dial %s: %+v", c.addr, err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	err = c.openSession()
+	if err != nil {
+		c.conn = nil
+	}
+	c.mu.Unlock()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if _, err := c.Send(NetFnApp, cmdSetSessionPrivilege, []byte{privilegeAdministrator}); err != nil {
+		c.Close()
+		return fmt.Errorf("failed to set the session privilege: %+v", err)
+	}
+	return nil
+}
+
+// Close closes the session on the bmc, so the session slot of the bmc is released
+func (c *Client) Close() error {
+	if c.isActive() {
+		_, _ = c.Send(NetFnApp, cmdCloseSession, le32(c.bmcSessionID))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active = false
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *Client) isActive() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.active
+}
+
+// Send sends the command in the session, and returns the response data after the completion code
+func (c *Client) Send(netFn, cmd byte, data []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.active || c.conn == nil {
+		return nil, fmt.Errorf("the ipmi session of %s is not established", c.addr)
+	}
+
+	c.rqSeq = (c.rqSeq + 1) & 0x3f
+	seq := c.rqSeq
+	msg := buildMessage(netFn, cmd, seq, data)
+	return c.roundTrip(
+		func() ([]byte, error) {
+			// each retry uses a new session sequence number, the bmc drops the duplicate one
+			return c.sessionPacket(msg)
+		},
+		func(payloadType byte, payload []byte) ([]byte, error) {
+			if payloadType != payloadIPMI {
+				return nil, errUnexpected
+			}
+			return parseMessage(payload, netFn, cmd, seq)
+		},
+	)
+}
+
+// openSession runs the Open Session and RAKP handshake, and derives the keys of the session
+func (c *Client) openSession() error {
+	var idBytes [4]byte
+	for binary.LittleEndian.Uint32(idBytes[:]) == 0 {
+		if _, err := rand.Read(idBytes[:]); err != nil {
+			return err
+		}
+	}
+	c.consoleSessionID = binary.LittleEndian.Uint32(idBytes[:])
+	c.sessionSeq = 0
+	c.active = false
+
+	// open session request for the cipher suite 3
+	req := make([]byte, 32)
+	req[1] = privilegeAdministrator
+	binary.LittleEndian.PutUint32(req[4:], c.consoleSessionID)
+	copy(req[8:], []byte{0x00, 0, 0, 0x08, authAlgRakpHmacSha1, 0, 0, 0})
+	copy(req[16:], []byte{0x01, 0, 0, 0x08, integrityAlgHmacSha196, 0, 0, 0})
+	copy(req[24:], []byte{0x02, 0, 0, 0x08, confidentialityAlgAes, 0, 0, 0})
+	resp, err := c.handshake(payloadOpenSessionReq, payloadOpenSessionResp, req)
+	if err != nil {
+		return fmt.Errorf("failed to open session: %+v", err)
+	}
+	if resp[1] != rakpStatusNoErrors {
+		return fmt.Errorf("failed to open session: %s", rakpStatusText(resp[1]))
+	}
+	if len(resp) < openSessionResponseLen || binary.LittleEndian.Uint32(resp[4:]) != c.consoleSessionID {
+		return fmt.Errorf("invalid open session response")
+	}
+	c.bmcSessionID = binary.LittleEndian.Uint32(resp[8:])
+
+	// RAKP 1 and 2, the bmc proves that it knows the password
+	rm := make([]byte, rakpRandomLen)
+	if _, err := rand.Read(rm); err != nil {
+		return err
+	}
+	user := []byte(c.username)
+	role := byte(rakpNameOnlyLookup | privilegeAdministrator)
+	rakp1 := make([]byte, 28, 28+len(user))
+	binary.LittleEndian.PutUint32(rakp1[4:], c.bmcSessionID)
+	copy(rakp1[8:], rm)
+	rakp1[24] = role
+	rakp1[27] = byte(len(user))
+	rakp1 = append(rakp1, user...)
+	resp, err = c.handshake(payloadRAKP1, payloadRAKP2, rakp1)
+	if err != nil {
+		return fmt.Errorf("failed to send rakp1: %+v", err)
+	}
+	if resp[1] != rakpStatusNoErrors {
+		return fmt.Errorf("failed to authenticate: %s", rakpStatusText(resp[1]))
+	}
+	if len(resp) < rakp2Len {
+		return fmt.Errorf("invalid rakp2 message")
+	}
+	rc := resp[8:24]
+	guid := resp[24:40]
+
+	kuid := make([]byte, maxPasswordLen)
+	copy(kuid, c.password)
+	userInfo := append([]byte{role, byte(len(user))}, user...)
+	expected := hmacSha1(kuid, le32(c.consoleSessionID), le32(c.bmcSessionID), rm, rc, guid, userInfo)
+	if !hmac.Equal(expected, resp[40:60]) {
+		return fmt.Errorf("failed to authenticate: the password is wrong")
+	}
+
+	var sik []byte
+	sik, c.k1, c.k2 = sessionKeys(kuid, rm, rc, userInfo)
+
+	// RAKP 3 and 4, the remote console proves that it knows the password
+	rakp3 := make([]byte, 8, 8+sha1.Size)
+	binary.LittleEndian.PutUint32(rakp3[4:], c.bmcSessionID)
+	rakp3 = append(rakp3, hmacSha1(kuid, rc, le32(c.consoleSessionID), userInfo)...)
+	resp, err = c.handshake(payloadRAKP3, payloadRAKP4, rakp3)
+	if err != nil {
+		return fmt.Errorf("failed to send rakp3: %+v", err)
+	}
+	if resp[1] != rakpStatusNoErrors {
+		return fmt.Errorf("failed to authenticate: %s", rakpStatusText(resp[1]))
+	}
+	if len(resp) < rakp4Len {
+		return fmt.Errorf("invalid rakp4 message")
+	}
+	if !hmac.Equal(hmacSha1(sik, rm, le32(c.bmcSessionID), guid)[:authCodeLen], resp[8:20]) {
+		return fmt.Errorf("failed to authenticate: invalid integrity check value of rakp4")
+	}
+
+	c.active = true
+	return nil
+}
+
+// handshake sends the payload out of the session, and returns the response payload
+func (c *Client) handshake(reqType, respType byte, payload []byte) ([]byte, error) {
+	packet := []byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeRMCPPlus, reqType, 0, 0, 0, 0, 0, 0, 0, 0}
+	packet = append(packet, le16(uint16(len(payload)))...)
+	packet = append(packet, payload...)
+	return c.roundTrip(
+		func() ([]byte, error) {
+			return packet, nil
+		},
+		func(payloadType byte, resp []byte) ([]byte, error) {
+			// the message tag is the first byte
+			if payloadType != respType || len(resp) < 2 || resp[0] != payload[0] {
+				return nil, errUnexpected
+			}
+			return resp, nil
+		},
+	)
+}
+
+// sessionPacket builds the authenticated and encrypted packet of the message
+func (c *Client) sessionPacket(msg []byte) ([]byte, error) {
+	payload, err := c.encrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+	c.sessionSeq++
+	data := []byte{authTypeRMCPPlus, payloadIPMI | payloadEncrypted | payloadAuthenticated}
+	data = append(data, le32(c.bmcSessionID)...)
+	data = append(data, le32(c.sessionSeq)...)
+	data = append(data, le16(uint16(len(payload)))...)
+	data = append(data, payload...)
+	// the integrity pad aligns the data from the auth type to the next header with 4 bytes
+	padLen := (4 - (len(data)+2)%4) % 4
+	for i := 0; i < padLen; i++ {
+		data = append(data, integrityPadByte)
+	}
+	data = append(data, byte(padLen), nextHeaderRMCP)
+	data = append(data, hmacSha1(c.k1, data)[:authCodeLen]...)
+	return append([]byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI}, data...), nil
+}
+
+// parsePacket checks the rmcp packet from the bmc, and returns the payload type and the decrypted payload
+func (c *Client) parsePacket(b []byte) (byte, []byte, error) {
+	if len(b) < 5 || b[0] != rmcpVersion || b[3] != rmcpClassIPMI {
+		return 0, nil, errUnexpected
+	}
+	b = b[4:]
+
+	switch b[0] {
+	case authTypeNone:
+		// ipmi v1.5 packet without the session, it is the response of Probe
+		if len(b) < sessionLessHeaderLen {
+			return 0, nil, errUnexpected
+		}
+		length := int(b[9])
+		if len(b) < sessionLessHeaderLen+length {
+			return 0, nil, errUnexpected
+		}
+		return payloadIPMI, b[sessionLessHeaderLen : sessionLessHeaderLen+length], nil
+
+	case authTypeRMCPPlus:
+		if len(b) < sessionHeaderLen {
+			return 0, nil, errUnexpected
+		}
+		payloadType := b[1]
+		length := int(binary.LittleEndian.Uint16(b[10:]))
+		if len(b) < sessionHeaderLen+length {
+			return 0, nil, errUnexpected
+		}
+		payload := b[sessionHeaderLen : sessionHeaderLen+length]
+
+		if payloadType&payloadAuthenticated != 0 {
+			if c.k1 == nil || len(b) < sessionHeaderLen+length+2+authCodeLen {
+				return 0, nil, errUnexpected
+			}
+			if binary.LittleEndian.Uint32(b[2:]) != c.consoleSessionID {
+				return 0, nil, errUnexpected
+			}
+			end := len(b) - authCodeLen
+			if !hmac.Equal(hmacSha1(c.k1, b[:end])[:authCodeLen], b[end:]) {
+				return 0, nil, fmt.Errorf("invalid auth code of the ipmi packet")
+			}
+		}
+		if payloadType&payloadEncrypted != 0 {
+			var err error
+			if payload, err = c.decrypt(payload); err != nil {
+				return 0, nil, err
+			}
+		}
+		return payloadType & payloadTypeMask, payload, nil
+	}
+	return 0, nil, errUnexpected
+}
+
+// encrypt encrypts the payload with AES-CBC-128, the IV is prepended to the encrypted data
+func (c *Client) encrypt(data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(c.k2[:aes.BlockSize])
+	if err != nil {
+		return nil, err
+	}
+	padLen := (aes.BlockSize - (len(data)+1)%aes.BlockSize) % aes.BlockSize
+	plain := make([]byte, 0, len(data)+padLen+1)
+	plain = append(plain, data...)
+	for i := 1; i <= padLen; i++ {
+		plain = append(plain, byte(i))
+	}
+	plain = append(plain, byte(padLen))
+
+	out := make([]byte, aes.BlockSize+len(plain))
+	if _, err := rand.Read(out[:aes.BlockSize]); err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], plain)
+	return out, nil
+}
+
+func (c *Client) decrypt(data []byte) ([]byte, error) {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid length of the encrypted ipmi payload")
+	}
+	block, err := aes.NewCipher(c.k2[:aes.BlockSize])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	padLen := int(out[len(out)-1])
+	if padLen+1 > len(out) {
+		return nil, fmt.Errorf("invalid confidentiality pad of the ipmi payload")
+	}
+	return out[:len(out)-1-padLen], nil
+}
+
+// roundTrip sends the packet and waits for the response accepted by the accept function, the packet is resent
+// when the response times out. The packets which are not accepted, such as the late response of the former retry, are ignored
+func (c *Client) roundTrip(build func() ([]byte, error), accept func(payloadType byte, payload []byte) ([]byte, error)) ([]byte, error) {
+	buf := make([]byte, maxPacketLen)
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		packet, err := build()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := c.conn.Write(packet); err != nil {
+			return nil, fmt.Errorf("failed to send to %s: %+v", c.addr, err)
+		}
+
+		deadline := time.Now().Add(c.timeout)
+		for {
+			if err := c.conn.SetReadDeadline(deadline); err != nil {
+				return nil, err
+			}
+			n, err := c.conn.Read(buf)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					lastErr = err
+					break
+				}
+				return nil, fmt.Errorf("failed to receive from %s: %+v", c.addr, err)
+			}
+			payloadType, payload, err := c.parsePacket(buf[:n])
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			result, err := accept(payloadType, payload)
+			if errors.Is(err, errUnexpected) {
+				continue
+			}
+			return result, err
+		}
+	}
+	return nil, fmt.Errorf("no response from %s: %+v", c.addr, lastErr)
+}
+
+// Probe checks whether the ipmi service is listening at addr, it sends Get Channel Authentication Capabilities
+// which does not require the session
+func Probe(addr string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	c := &Client{addr: addr, timeout: timeout, retries: 1, conn: conn}
+
+	// request the ipmi v2.0 capabilities of the current channel
+	msg := buildMessage(NetFnApp, cmdGetChannelAuthCapabilities, 0, []byte{0x8e, privilegeAdministrator})
+	packet := []byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeNone, 0, 0, 0, 0, 0, 0, 0, 0, byte(len(msg))}
+	packet = append(packet, msg...)
+	_, err = c.roundTrip(
+		func() ([]byte, error) {
+			return packet, nil
+		},
+		func(payloadType byte, payload []byte) ([]byte, error) {
+			return parseMessage(payload, NetFnApp, cmdGetChannelAuthCapabilities, 0)
+		},
+	)
+	// the bmc which only supports ipmi v1.5 rejects the request with a completion code, it is still an ipmi service
+	var completionErr *CompletionError
+	if errors.As(err, &completionErr) {
+		return nil
+	}
+	return err
+}
+
+// sessionKeys derives the session integrity key from the random numbers of the RAKP messages, and the additional keys
+// K1 and K2 from the SIK
+func sessionKeys(kuid, rm, rc, userInfo []byte) (sik, k1, k2 []byte) {
+	sik = hmacSha1(kuid, rm, rc, userInfo)
+	k1 = hmacSha1(sik, bytes.Repeat([]byte{0x01}, sha1.Size))
+	k2 = hmacSha1(sik, bytes.Repeat([]byte{0x02}, sha1.Size))
+	return sik, k1, k2
+}
+
+func hmacSha1(key []byte, data ...[]byte) []byte {
+	h := hmac.New(sha1.New, key)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// rakpStatusText describes the status code of the open session response and the RAKP messages
+func rakpStatusText(code byte) string {
+	texts := map[byte]string{
+		0x01: "insufficient resources to create a session",
+		0x02: "invalid session id",
+		0x04: "invalid authentication algorithm",
+		0x05: "invalid integrity algorithm",
+		0x08: "inactive session id",
+		0x09: "invalid role",
+		0x0a: "unauthorized role or privilege level requested",
+		0x0c: "invalid name length",
+		0x0d: "unauthorized name",
+		0x0f: "invalid integrity check value",
+		0x10: "invalid confidentiality algorithm",
+		0x11: "no cipher suite match with the proposed security algorithms",
+		0x12: "illegal or unrecognized parameter",
+	}
+	if text, ok := texts[code]; ok {
+		return text
+	}
+	return fmt.Sprintf("status code 0x%02x", code)
+}
@@ -0,0 +1,286 @@
+package ipmi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrastructure-io/topohub/pkg/ipmi/emulator"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "password"
+	testTimeout  = 200 * time.Millisecond
+)
+
+// startBmc starts the emulator and opens the session with it, the session and the emulator are closed after the spec
+func startBmc() (*emulator.Server, *Client) {
+	bmc := emulator.New()
+	bmc.SetCredential(testUsername, testPassword)
+	DeferCleanup(bmc.Close)
+	c := NewClient(bmc.Addr(), testUsername, testPassword, testTimeout)
+	Expect(c.Open()).To(Succeed())
+	DeferCleanup(c.Close)
+	return bmc, c
+}
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	Expect(err).NotTo(HaveOccurred())
+	return b
+}
+
+// sessionClient returns the client with the keys of the session, whose packets are parsed by itself
+func sessionClient() *Client {
+	_, k1, k2 := sessionKeys(bytes.Repeat([]byte{0x5a}, maxPasswordLen), make([]byte, rakpRandomLen), make([]byte, rakpRandomLen), nil)
+	return &Client{consoleSessionID: 0x01020304, bmcSessionID: 0x01020304, k1: k1, k2: k2, active: true}
+}
+
+// encryptBlock encrypts the plain block with the K2 of sessionClient and the zero IV, without the confidentiality pad
+func encryptBlock(plain []byte) []byte {
+	block, err := aes.NewCipher(sessionClient().k2[:aes.BlockSize])
+	Expect(err).NotTo(HaveOccurred())
+	out := make([]byte, 2*aes.BlockSize)
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], plain)
+	return out
+}
+
+var _ = Describe("RMCP+ session", Label("unitest"), func() {
+	Context("the keys of the cipher suite 3", func() {
+		It("derives the SIK, K1 and K2 from the RAKP messages", func() {
+			kuid := make([]byte, maxPasswordLen)
+			copy(kuid, "password")
+			rm, rc := make([]byte, rakpRandomLen), make([]byte, rakpRandomLen)
+			for i := range rm {
+				rm[i], rc[i] = byte(i), byte(i+rakpRandomLen)
+			}
+			userInfo := append([]byte{rakpNameOnlyLookup | privilegeAdministrator, 5}, "admin"...)
+
+			sik, k1, k2 := sessionKeys(kuid, rm, rc, userInfo)
+			Expect(hex.EncodeToString(sik)).To(Equal("122c77c4b11ccd93251cbae6c34a9cb6310da154"))
+			Expect(hex.EncodeToString(k1)).To(Equal("e4472be78f9a81fa68297aab696a7be8c97fc9f8"))
+			Expect(hex.EncodeToString(k2)).To(Equal("2b6552012a2517cb3b5713901d757a6efc7d8301"))
+		})
+
+		// RFC 2202, the auth code of HMAC-SHA1-96 is the first 12 bytes
+		DescribeTable("computes HMAC-SHA1",
+			func(key, data []byte, digest string) {
+				Expect(hex.EncodeToString(hmacSha1(key, data))).To(Equal(digest))
+			},
+			Entry("test case 1", bytes.Repeat([]byte{0x0b}, 20), []byte("Hi There"), "b617318655057264e28bc0b6fb378c8ef146be00"),
+			Entry("test case 2", []byte("Jefe"), []byte("what do ya want for nothing?"), "effcdf6ae5eb2fa2d27416d5f184df9c259a7c79"),
+			Entry("test case 5", bytes.Repeat([]byte{0x0c}, 20), []byte("Test With Truncation"), "4c1a03424b55e07fe7f27be1d58bb9324a9a5a04"),
+		)
+
+		It("computes the data in several parts like the whole", func() {
+			Expect(hmacSha1([]byte("Jefe"), []byte("what do ya "), nil, []byte("want for nothing?"))).
+				To(Equal(unhex("effcdf6ae5eb2fa2d27416d5f184df9c259a7c79")))
+		})
+	})
+
+	Context("AES-CBC-128", func() {
+		It("decrypts the payload with the IV in the first block", func() {
+			// NIST SP 800-38A F.2.2, the last byte of the plain text 0x10 is taken as the length of the confidentiality pad
+			c := &Client{k2: unhex("2b7e151628aed2a6abf7158809cf4f3c")}
+			data := unhex("000102030405060708090a0b0c0d0e0f" +
+				"7649abac8119b246cee98e9b12e9197d5086cb9b507219ee95db113a917678b2" +
+				"73bed6b8e3c1743b7116e69e222295163ff1caa1681fac09120eca307586e1a7")
+			plain, err := c.decrypt(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plain).To(Equal(unhex("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51" +
+				"30c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c37")[:47]))
+		})
+
+		DescribeTable("encrypts the payload with the confidentiality pad to the whole blocks",
+			func(length int) {
+				c := sessionClient()
+				data := bytes.Repeat([]byte{0xa5}, length)
+				encrypted, err := c.encrypt(data)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(encrypted) % 16).To(BeZero())
+				Expect(len(encrypted)).To(Equal(16 + (length/16+1)*16))
+				plain, err := c.decrypt(encrypted)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plain).To(Equal(data))
+			},
+			Entry("empty", 0),
+			Entry("one byte", 1),
+			Entry("the pad length fills the block", 15),
+			Entry("one block", 16),
+			Entry("several blocks", 45),
+		)
+
+		DescribeTable("refuses the invalid encrypted payload",
+			func(data []byte, message string) {
+				_, err := sessionClient().decrypt(data)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("without the encrypted block", make([]byte, 16), "invalid length"),
+			Entry("not the whole blocks", make([]byte, 40), "invalid length"),
+			Entry("the pad is longer than the payload", encryptBlock(bytes.Repeat([]byte{0x10}, 16)), "invalid confidentiality pad"),
+		)
+	})
+
+	Context("the packet of the session", func() {
+		It("builds the authenticated and encrypted packet which is parsed back", func() {
+			c := sessionClient()
+			msg := buildMessage(NetFnApp, cmdGetDeviceID, 1, nil)
+			for seq := uint32(1); seq <= 4; seq++ {
+				packet, err := c.sessionPacket(msg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(packet[:4]).To(Equal([]byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI}))
+				Expect(packet[4]).To(Equal(byte(authTypeRMCPPlus)))
+				Expect(packet[5]).To(Equal(byte(payloadIPMI | payloadEncrypted | payloadAuthenticated)))
+				Expect(packet[6:10]).To(Equal(le32(c.bmcSessionID)))
+				Expect(packet[10:14]).To(Equal(le32(seq)))
+				// the integrity pad aligns the data from the auth type to the next header
+				Expect((len(packet) - 4 - authCodeLen) % 4).To(BeZero())
+				Expect(packet[len(packet)-authCodeLen-1]).To(Equal(byte(nextHeaderRMCP)))
+
+				payloadType, payload, err := c.parsePacket(packet)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payloadType).To(Equal(byte(payloadIPMI)))
+				Expect(payload).To(Equal(msg))
+			}
+		})
+
+		It("refuses the packet whose any authenticated byte is changed", func() {
+			c := sessionClient()
+			packet, err := c.sessionPacket(buildMessage(NetFnApp, cmdGetDeviceID, 1, nil))
+			Expect(err).NotTo(HaveOccurred())
+			// the auth code covers the data from the auth type after the rmcp header
+			for i := 4; i < len(packet); i++ {
+				changed := append([]byte{}, packet...)
+				changed[i] ^= 0x01
+				_, _, err := c.parsePacket(changed)
+				Expect(err).To(HaveOccurred(), "byte %d is changed", i)
+			}
+		})
+
+		It("refuses the authenticated packet of another session or the session without the keys", func() {
+			c := sessionClient()
+			packet, err := c.sessionPacket(buildMessage(NetFnApp, cmdGetDeviceID, 1, nil))
+			Expect(err).NotTo(HaveOccurred())
+			c.consoleSessionID++
+			_, _, err = c.parsePacket(packet)
+			Expect(err).To(MatchError(errUnexpected))
+			_, _, err = (&Client{}).parsePacket(packet)
+			Expect(err).To(MatchError(errUnexpected))
+		})
+
+		It("parses the ipmi v1.5 packet out of the session", func() {
+			msg := []byte{0x81, 0x1c, 0x63, 0x20, 0x00, 0x38, 0x00, 0x01, 0x80, 0x04, 0x02, 0, 0, 0, 0, 0x1f}
+			packet := append([]byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeNone, 0, 0, 0, 0, 0, 0, 0, 0, byte(len(msg))}, msg...)
+			payloadType, payload, err := (&Client{}).parsePacket(packet)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payloadType).To(Equal(byte(payloadIPMI)))
+			Expect(payload).To(Equal(msg))
+		})
+
+		It("refuses the truncated packets without panic", func() {
+			c := sessionClient()
+			session, err := c.sessionPacket(buildMessage(NetFnApp, cmdGetDeviceID, 1, nil))
+			Expect(err).NotTo(HaveOccurred())
+			msg := buildMessage(NetFnApp, cmdGetChannelAuthCapabilities, 0, []byte{0x8e, privilegeAdministrator})
+			sessionless := append([]byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeNone, 0, 0, 0, 0, 0, 0, 0, 0, byte(len(msg))}, msg...)
+			handshake := append([]byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, authTypeRMCPPlus, payloadRAKP2, 0, 0, 0, 0, 0, 0, 0, 0, 60, 0}, make([]byte, 60)...)
+
+			for _, packet := range [][]byte{session, sessionless, handshake} {
+				for n := 0; n < len(packet); n++ {
+					Expect(func() {
+						_, _, err := c.parsePacket(packet[:n])
+						Expect(err).To(HaveOccurred(), "the packet is truncated to %d bytes", n)
+					}).NotTo(Panic())
+				}
+			}
+		})
+
+		It("does not panic on the random packets", func() {
+			c := sessionClient()
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 5000; i++ {
+				packet := make([]byte, r.Intn(128))
+				r.Read(packet)
+				// most of the random packets have the valid rmcp header, so the parsers after the header are reached
+				if len(packet) > 5 && i%4 != 0 {
+					copy(packet, []byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI, []byte{authTypeNone, authTypeRMCPPlus}[i%2]})
+					packet[5] |= byte(r.Intn(2)) * (payloadEncrypted | payloadAuthenticated)
+				}
+				Expect(func() { _, _, _ = c.parsePacket(packet) }).NotTo(Panic())
+			}
+		})
+	})
+
+	Context("the handshake with the bmc", func() {
+		It("opens the session and closes it", func() {
+			bmc, c := startBmc()
+			Expect(bmc.Sessions()).To(Equal(1))
+			Expect(bmc.CountCommands(emulator.NetFnApp, emulator.CmdSetSessionPrivilege)).To(Equal(1))
+
+			device, err := c.GetDeviceID()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device.FirmwareVersion).To(Equal("2.15"))
+
+			Expect(c.Close()).To(Succeed())
+			Expect(bmc.Sessions()).To(BeZero())
+			_, err = c.GetDeviceID()
+			Expect(err).To(MatchError(ContainSubstring("is not established")))
+		})
+
+		DescribeTable("fails to authenticate",
+			func(username, password, message string) {
+				bmc := emulator.New()
+				bmc.SetCredential(testUsername, testPassword)
+				DeferCleanup(bmc.Close)
+
+				c := NewClient(bmc.Addr(), username, password, testTimeout)
+				Expect(c.Open()).To(MatchError(ContainSubstring(message)))
+				Expect(bmc.Sessions()).To(BeZero())
+				Expect(bmc.Commands()).To(BeEmpty())
+			},
+			Entry("with the wrong password", testUsername, "wrong", "the password is wrong"),
+			Entry("with the unknown user", "operator", testPassword, "unauthorized name"),
+			Entry("with the too long user name", "a-user-name-longer-than-16", testPassword, "longer than 16"),
+			Entry("with the too long password", testUsername, "a-password-longer-than-20", "longer than 20"),
+		)
+
+		It("resends the request after the packet is lost", func() {
+			bmc, c := startBmc()
+			bmc.Drop(2)
+			_, err := c.GetDeviceID()
+			Expect(err).NotTo(HaveOccurred())
+
+			bmc.Drop(defaultRetries + 1)
+			_, err = c.GetDeviceID()
+			Expect(err).To(MatchError(ContainSubstring("no response from")))
+		})
+
+		It("probes the ipmi service", func() {
+			bmc := emulator.New()
+			DeferCleanup(bmc.Close)
+			Expect(Probe(bmc.Addr(), testTimeout)).To(Succeed())
+
+			// the bmc which only supports ipmi v1.5 refuses the request of the v2.0 capabilities
+			bmc.Fail(emulator.NetFnApp, emulator.CmdGetChannelAuthCapabilities, 0xcc)
+			Expect(Probe(bmc.Addr(), testTimeout)).To(Succeed())
+
+			bmc.Drop(2)
+			Expect(Probe(bmc.Addr(), testTimeout)).NotTo(Succeed())
+		})
+	})
+
+	DescribeTable("describes the status code of the RAKP messages",
+		func(code byte, text string) {
+			Expect(rakpStatusText(code)).To(Equal(text))
+		},
+		Entry("known", byte(0x0d), "unauthorized name"),
+		Entry("unknown", byte(0x7f), "status code 0x7f"),
+	)
+})
@@ -0,0 +1,122 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BiosConfigModeEnforce applies the differences to the hosts
+	BiosConfigModeEnforce = "Enforce"
+	// BiosConfigModeMonitor only reports the drift
+	BiosConfigModeMonitor = "Monitor"
+
+	BiosStateInSync        = "InSync"
+	BiosStatePendingReboot = "PendingReboot"
+	BiosStateDrifted       = "Drifted"
+	BiosStateFailed        = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.totalHosts"
+// +kubebuilder:printcolumn:name="INSYNC",type="integer",JSONPath=".status.inSyncHosts"
+// +kubebuilder:printcolumn:name="PENDING_REBOOT",type="integer",JSONPath=".status.pendingRebootHosts"
+// +kubebuilder:printcolumn:name="DRIFTED",type="integer",JSONPath=".status.driftedHosts"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failedHosts"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BiosConfig declares the desired bios attributes for a set of hosts
+type BiosConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BiosConfigSpec   `json:"spec,omitempty"`
+	Status BiosConfigStatus `json:"status,omitempty"`
+}
+
+type BiosConfigSpec struct {
+	// HostStatusNames lists the name of the HostStatus to configure
+	// +optional
+	HostStatusNames []string `json:"hostStatusNames,omitempty"`
+
+	// HostSelector selects the HostStatus by the labels, such as topohub.infrastructure.io/cluster-name
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// Attributes are the desired bios attributes. The key is the attribute name in the redfish Bios resource, which is vendor specific,
+	// and the value is converted to the type of the current value
+	// +kubebuilder:validation:MinProperties=1
+	Attributes map[string]string `json:"attributes"`
+
+	// ApplyTime is the @Redfish.SettingsApplyTime when the BMC applies the attributes
+	// +kubebuilder:validation:Enum=Immediate;OnReset;AtMaintenanceWindowStart;InMaintenanceWindowOnReset
+	// +kubebuilder:default=OnReset
+	// +optional
+	ApplyTime string `json:"applyTime,omitempty"`
+
+	// Mode decides what to do for the drift. Enforce: apply the desired attributes. Monitor: only report the drift
+	// +kubebuilder:validation:Enum=Enforce;Monitor
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+type BiosConfigStatus struct {
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	TotalHosts         int32 `json:"totalHosts"`
+	InSyncHosts        int32 `json:"inSyncHosts"`
+	PendingRebootHosts int32 `json:"pendingRebootHosts"`
+	DriftedHosts       int32 `json:"driftedHosts"`
+	FailedHosts        int32 `json:"failedHosts"`
+
+	// +optional
+	Hosts []BiosHostStatus `json:"hosts,omitempty"`
+}
+
+type BiosHostStatus struct {
+	HostStatusName string `json:"hostStatusName"`
+	// SystemId is the id of the ComputerSystem, a host may expose several systems
+	// +optional
+	SystemId string `json:"systemId,omitempty"`
+
+	// State is InSync when all attributes equal to the desired values, PendingReboot when the desired values
+	// are pending in the Bios/Settings, Drifted when the values differ, and Failed when the attributes could not be read or applied
+	// +kubebuilder:validation:Enum=InSync;PendingReboot;Drifted;Failed
+	State string `json:"state"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastApplyTime is the time when topohub applies the attributes to the host last time
+	// +optional
+	LastApplyTime string `json:"lastApplyTime,omitempty"`
+
+	// Drift lists the attributes which are not equal to the desired value
+	// +optional
+	Drift []BiosAttributeDrift `json:"drift,omitempty"`
+}
+
+type BiosAttributeDrift struct {
+	Name    string `json:"name"`
+	Desired string `json:"desired"`
+	// Current is the value which takes effect now, it is empty when the host does not have the attribute
+	// +optional
+	Current string `json:"current,omitempty"`
+	// Pending is the value in the Bios/Settings, which takes effect after the reboot
+	// +optional
+	Pending string `json:"pending,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BiosConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BiosConfig `json:"items"`
+}
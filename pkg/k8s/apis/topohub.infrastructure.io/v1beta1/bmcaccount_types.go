@@ -0,0 +1,142 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	BmcAccountStateReady   = "Ready"
+	BmcAccountStateFailed  = "Failed"
+	BmcAccountStatePending = "Pending"
+
+	// ConditionRollbackFailed reports that the service account fails to be rolled back on some hosts after a failed
+	// rotation, so its password on their bmc is unknown. The rotation stops until the spec of the BmcAccount is updated
+	ConditionRollbackFailed = "RollbackFailed"
+
+	// AnnotationBmcAccountRotateTime records the time when the password in the secret is rotated by the BmcAccount
+	AnnotationBmcAccountRotateTime = GroupName + "/bmc-account-rotate-time"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="USERNAME",type="string",JSONPath=".spec.username"
+// +kubebuilder:printcolumn:name="SECRET",type="string",JSONPath=".spec.secretName"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.totalHosts"
+// +kubebuilder:printcolumn:name="LAST_ROTATION",type="string",JSONPath=".status.lastRotationTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BmcAccount manages a dedicated service account on the bmc of all hosts which use the secret, and rotates its password
+type BmcAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BmcAccountSpec   `json:"spec,omitempty"`
+	Status BmcAccountStatus `json:"status,omitempty"`
+}
+
+type BmcAccountSpec struct {
+	// SecretName is the secret which holds the credential of the hosts, it is the secret referenced by the HostEndpoint
+	// or the default secret of the dhcp hosts. All hosts using the secret share the same service account
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+
+	// +kubebuilder:validation:Required
+	SecretNamespace string `json:"secretNamespace"`
+
+	// Username is the name of the service account created on the bmc
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=16
+	Username string `json:"username"`
+
+	// RoleId is the role of the service account in the AccountService
+	// +kubebuilder:default=Administrator
+	// +optional
+	RoleId string `json:"roleId,omitempty"`
+
+	// RotationInterval is the interval to rotate the password, such as 720h. The password is never rotated when it is not set
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// PasswordLength is the length of the generated password
+	// +kubebuilder:default=16
+	// +kubebuilder:validation:Minimum=8
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	PasswordLength int32 `json:"passwordLength,omitempty"`
+
+	// AdminSecretName is the secret holding the administrator credential, which is used to create the service account
+	// and restore its password when the rotation fails. When it is not set, the credential in the secret is used
+	// +optional
+	AdminSecretName *string `json:"adminSecretName,omitempty"`
+
+	// +optional
+	AdminSecretNamespace *string `json:"adminSecretNamespace,omitempty"`
+}
+
+type BmcAccountStatus struct {
+	// State is Ready when the secret holds the credential of the service account on all hosts,
+	// Pending when the rotation waits for the unhealthy hosts, and Failed when the last rotation is rolled back
+	// +kubebuilder:validation:Enum=Ready;Failed;Pending
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastRotationTime is the time when the secret is updated with the new password
+	// +optional
+	LastRotationTime string `json:"lastRotationTime,omitempty"`
+
+	// LastFailureTime is the time when the rotation is rolled back, the failed rotation is retried after 30 minutes
+	// +optional
+	LastFailureTime string `json:"lastFailureTime,omitempty"`
+
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	// +optional
+	TotalHosts int32 `json:"totalHosts"`
+
+	// +optional
+	Hosts []BmcAccountHostStatus `json:"hosts,omitempty"`
+
+	// Conditions represent the latest available observations of the BmcAccount
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+type BmcAccountHostStatus struct {
+	HostStatusName string `json:"hostStatusName"`
+
+	// AccountUri is the uri of the ManagerAccount of the service account on the bmc
+	// +optional
+	AccountUri string `json:"accountUri,omitempty"`
+
+	// +kubebuilder:validation:Enum=Ready;Failed;Pending
+	State string `json:"state"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastFailureTime is the time when the service account fails to be set on the host which is added after the
+	// rotation, it is retried after 30 minutes
+	// +optional
+	LastFailureTime string `json:"lastFailureTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BmcAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BmcAccount `json:"items"`
+}
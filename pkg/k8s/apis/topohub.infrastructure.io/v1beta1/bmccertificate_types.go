@@ -0,0 +1,150 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BmcCertificateModeEnforce replaces the certificate of the hosts which is not issued by the CA or is expiring
+	BmcCertificateModeEnforce = "Enforce"
+	// BmcCertificateModeMonitor only reports the certificate which needs to be replaced
+	BmcCertificateModeMonitor = "Monitor"
+
+	BmcCertificateStateReady   = "Ready"
+	BmcCertificateStatePending = "Pending"
+	BmcCertificateStateFailed  = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="ISSUER",type="string",JSONPath=".spec.issuerSecretName"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.totalHosts"
+// +kubebuilder:printcolumn:name="READY",type="integer",JSONPath=".status.readyHosts"
+// +kubebuilder:printcolumn:name="PENDING",type="integer",JSONPath=".status.pendingHosts"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failedHosts"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BmcCertificate replaces the https certificate of the bmc of a set of hosts with the certificate issued by the CA,
+// and renews it before it expires
+type BmcCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BmcCertificateSpec   `json:"spec,omitempty"`
+	Status BmcCertificateStatus `json:"status,omitempty"`
+}
+
+type BmcCertificateSpec struct {
+	// HostStatusNames lists the name of the HostStatus whose certificate is managed
+	// +optional
+	HostStatusNames []string `json:"hostStatusNames,omitempty"`
+
+	// HostSelector selects the HostStatus by the labels, such as topohub.infrastructure.io/cluster-name
+	// and topohub.infrastructure.io/subnet-name
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// IssuerSecretName is the secret holding the certificate and the key of the CA in tls.crt and tls.key,
+	// which signs the certificate signing request generated by the bmc
+	// +kubebuilder:validation:Required
+	IssuerSecretName string `json:"issuerSecretName"`
+
+	// +kubebuilder:validation:Required
+	IssuerSecretNamespace string `json:"issuerSecretNamespace"`
+
+	// the subject of the certificate signing request, its common name is the ip address of the bmc.
+	// Some bmc require all of them to be set
+	// +optional
+	Organization string `json:"organization,omitempty"`
+	// +optional
+	OrganizationalUnit string `json:"organizationalUnit,omitempty"`
+	// +optional
+	City string `json:"city,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+	// +optional
+	Country string `json:"country,omitempty"`
+
+	// AlternativeNames are the additional host names of the bmc, the ip address of the bmc is always included
+	// +optional
+	AlternativeNames []string `json:"alternativeNames,omitempty"`
+
+	// KeyPairAlgorithm is the algorithm of the key generated by the bmc, such as TPM_ALG_RSA or TPM_ALG_ECDSA,
+	// it is decided by the bmc when it is empty
+	// +optional
+	KeyPairAlgorithm string `json:"keyPairAlgorithm,omitempty"`
+
+	// KeyBitLength is the length of the key generated by the bmc, it is decided by the bmc when it is empty
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeyBitLength *int32 `json:"keyBitLength,omitempty"`
+
+	// Duration is the validity of the issued certificate
+	// +kubebuilder:default="8760h"
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// RenewBefore renews the certificate when it expires within the duration
+	// +kubebuilder:default="720h"
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// Mode decides what to do for the certificate which is not issued by the CA or is expiring.
+	// Enforce: replace the certificate. Monitor: only report it
+	// +kubebuilder:validation:Enum=Enforce;Monitor
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+type BmcCertificateStatus struct {
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	TotalHosts   int32 `json:"totalHosts"`
+	ReadyHosts   int32 `json:"readyHosts"`
+	PendingHosts int32 `json:"pendingHosts"`
+	FailedHosts  int32 `json:"failedHosts"`
+
+	// +optional
+	Hosts []BmcCertificateHostStatus `json:"hosts,omitempty"`
+}
+
+type BmcCertificateHostStatus struct {
+	HostStatusName string `json:"hostStatusName"`
+
+	// State is Ready when the certificate is issued by the CA and is not expiring, Pending when it needs to be replaced
+	// in the Monitor mode, and Failed when the certificate could not be read or replaced
+	// +kubebuilder:validation:Enum=Ready;Pending;Failed
+	State string `json:"state"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// CertificateUri is the uri of the https certificate on the bmc
+	// +optional
+	CertificateUri string `json:"certificateUri,omitempty"`
+
+	// Fingerprint is the SHA-256 fingerprint of the https certificate
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// +optional
+	NotAfter string `json:"notAfter,omitempty"`
+
+	// LastRenewTime is the time when the certificate is replaced with the one issued by the CA
+	// +optional
+	LastRenewTime string `json:"lastRenewTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BmcCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BmcCertificate `json:"items"`
+}
@@ -0,0 +1,165 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BmcProfileModeEnforce corrects the settings of the bmc which drift from the profile
+	BmcProfileModeEnforce = "Enforce"
+	// BmcProfileModeMonitor only reports the drifts
+	BmcProfileModeMonitor = "Monitor"
+
+	BmcProfileStateCompliant = "Compliant"
+	BmcProfileStateDrifted   = "Drifted"
+	BmcProfileStateFailed    = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.totalHosts"
+// +kubebuilder:printcolumn:name="COMPLIANT",type="integer",JSONPath=".status.compliantHosts"
+// +kubebuilder:printcolumn:name="DRIFTED",type="integer",JSONPath=".status.driftedHosts"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failedHosts"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BmcProfile is the baseline of the network services of the bmc, such as the NTP servers, the syslog target and the
+// enabled protocols. It is referenced by the Subnet or the HostEndpoint, and it is enforced through the
+// ManagerNetworkProtocol and the EthernetInterface of the manager
+type BmcProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BmcProfileSpec   `json:"spec,omitempty"`
+	Status BmcProfileStatus `json:"status,omitempty"`
+}
+
+// BmcProfileSpec defines the network services of the bmc, the settings which are not specified are left unchanged
+type BmcProfileSpec struct {
+	// NTP enables the NTP of the bmc with the servers
+	// +optional
+	NTP *BmcNTPSpec `json:"ntp,omitempty"`
+
+	// Syslog forwards the logs of the bmc to the syslog server, the other syslog targets are removed
+	// +optional
+	Syslog *BmcSyslogSpec `json:"syslog,omitempty"`
+
+	// Protocols enables or disables the network protocols of the bmc
+	// +optional
+	Protocols *BmcProtocolsSpec `json:"protocols,omitempty"`
+
+	// NetworkInterface configures the ethernet interface of the bmc which holds its ip address
+	// +optional
+	NetworkInterface *BmcNetworkInterfaceSpec `json:"networkInterface,omitempty"`
+
+	// Mode decides what to do for the settings which drift from the profile.
+	// Enforce: correct the settings. Monitor: only report the drifts
+	// +kubebuilder:validation:Enum=Enforce;Monitor
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+type BmcNTPSpec struct {
+	// Servers are the address of the NTP servers, in the order of the preference
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=4
+	Servers []string `json:"servers"`
+}
+
+type BmcSyslogSpec struct {
+	// Address is the ip address or the host name of the syslog server
+	// +kubebuilder:validation:Required
+	Address string `json:"address"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=514
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Protocol is the transport of the syslog
+	// +kubebuilder:validation:Enum=SyslogUDP;SyslogTCP;SyslogTLS
+	// +kubebuilder:default=SyslogUDP
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// BmcProtocolsSpec enables the protocol when it is true and disables it when it is false, the protocol is left unchanged
+// when it is not set
+type BmcProtocolsSpec struct {
+	// IPMI is the IPMI over LAN of the bmc, the host managed by ipmi is not reachable after it is disabled
+	// +optional
+	IPMI *bool `json:"ipmi,omitempty"`
+	// +optional
+	SSH *bool `json:"ssh,omitempty"`
+	// SNMP is the snmp agent of the bmc, it is also set by the snmp trap of the agent
+	// +optional
+	SNMP *bool `json:"snmp,omitempty"`
+	// +optional
+	Telnet *bool `json:"telnet,omitempty"`
+}
+
+type BmcNetworkInterfaceSpec struct {
+	// NameServers are the static DNS servers of the bmc
+	// +kubebuilder:validation:MaxItems=4
+	// +optional
+	NameServers []string `json:"nameServers,omitempty"`
+
+	// DhcpUseNTPServers decides whether the bmc uses the NTP servers from the dhcp server
+	// +optional
+	DhcpUseNTPServers *bool `json:"dhcpUseNtpServers,omitempty"`
+
+	// DhcpUseDNSServers decides whether the bmc uses the DNS servers from the dhcp server
+	// +optional
+	DhcpUseDNSServers *bool `json:"dhcpUseDnsServers,omitempty"`
+}
+
+type BmcProfileStatus struct {
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	TotalHosts     int32 `json:"totalHosts"`
+	CompliantHosts int32 `json:"compliantHosts"`
+	DriftedHosts   int32 `json:"driftedHosts"`
+	FailedHosts    int32 `json:"failedHosts"`
+
+	// +optional
+	Hosts []BmcProfileHostStatus `json:"hosts,omitempty"`
+}
+
+type BmcProfileHostStatus struct {
+	HostStatusName string `json:"hostStatusName"`
+
+	// State is Compliant when the settings of the bmc match the profile, Drifted when they drift in the Monitor mode,
+	// and Failed when the settings could not be read or corrected
+	// +kubebuilder:validation:Enum=Compliant;Drifted;Failed
+	State string `json:"state"`
+
+	// Drifts describes the settings which drift from the profile
+	// +optional
+	Drifts []string `json:"drifts,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastEnforceTime is the time when the drifts are corrected
+	// +optional
+	LastEnforceTime string `json:"lastEnforceTime,omitempty"`
+
+	// CorrectedDrifts are the drifts corrected at the LastEnforceTime
+	// +optional
+	CorrectedDrifts []string `json:"correctedDrifts,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BmcProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BmcProfile `json:"items"`
+}
@@ -43,6 +43,21 @@ func (in *HostEndpointSpec) DeepCopyInto(out *HostEndpointSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(BmcTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BmcProfileName != nil {
+		in, out := &in.BmcProfileName, &out.BmcProfileName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostEndpointSpec.
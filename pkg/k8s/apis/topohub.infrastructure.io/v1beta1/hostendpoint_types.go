@@ -42,10 +42,64 @@ type HostEndpointSpec struct {
 	// +kubebuilder:default=true
 	HTTPS *bool `json:"https,omitempty"`
 
-	// Port specifies the port number for communication
+	// Port specifies the port number for communication, it defaults to 443 for redfish and 623 for ipmi
 	// +optional
-	// +kubebuilder:default=443
 	Port *int32 `json:"port,omitempty"`
+
+	// Protocol specifies the protocol to manage the bmc, ipmi is used for the old bmc without usable redfish
+	// +optional
+	// +kubebuilder:validation:Enum=redfish;ipmi
+	// +kubebuilder:default=redfish
+	Protocol *string `json:"protocol,omitempty"`
+
+	// TLS configures the verification of the certificate of the BMC when https is used. The certificate is trusted
+	// on first use when neither the CA bundle nor the fingerprints are specified
+	// +optional
+	TLS *BmcTLSSpec `json:"tls,omitempty"`
+
+	// BmcProfileName is the BmcProfile which configures the network services of the BMC
+	// +optional
+	BmcProfileName *string `json:"bmcProfileName,omitempty"`
+}
+
+// BmcTLSSpec defines how the certificate of the BMC is verified
+type BmcTLSSpec struct {
+	// CABundle references the CA certificates which issue the certificate of the BMC
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// ServerName is the name verified against the certificate issued by the CA bundle, it defaults to the IP address of the BMC
+	// +optional
+	ServerName *string `json:"serverName,omitempty"`
+
+	// Fingerprints pins the SHA-256 fingerprints of the certificate of the BMC, such as AB:CD:...,
+	// the certificate matching any of them is trusted
+	// +optional
+	Fingerprints []string `json:"fingerprints,omitempty"`
+
+	// InsecureSkipVerify skips the verification of the certificate, it is not recommended
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CABundleSource references the key of a Secret or a ConfigMap which holds the PEM encoded CA certificates
+type CABundleSource struct {
+	// Kind is Secret or ConfigMap
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+
+	// Key is the key of the CA certificates in the data
+	// +kubebuilder:default=ca.crt
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,331 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="CLUSTERNAME",type="string",JSONPath=".status.clusterName"
+// +kubebuilder:printcolumn:name="IPADDR",type="string",JSONPath=".status.ipAddr"
+// +kubebuilder:printcolumn:name="CPU",type="integer",JSONPath=".status.systems[0].processorCount"
+// +kubebuilder:printcolumn:name="MEMORY_GIB",type="integer",JSONPath=".status.systems[0].totalMemoryGiB"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HostInventory records the hardware inventory of the host, it has the same name as the HostStatus which owns it
+type HostInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status HostInventoryStatus `json:"status,omitempty"`
+}
+
+// HostInventoryStatus defines the observed hardware of the host.
+// Every item is identified by its redfish @odata.id and the lists are sorted by it,
+// so the order of the arrays returned by the BMC does not make the object churn
+type HostInventoryStatus struct {
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+	ClusterName    string `json:"clusterName,omitempty"`
+	IpAddr         string `json:"ipAddr,omitempty"`
+
+	// +optional
+	Systems []SystemInventory `json:"systems,omitempty"`
+
+	// Firmware lists the firmware inventory of the UpdateService, such as BIOS, BMC, NIC
+	// +optional
+	Firmware []FirmwareInventory `json:"firmware,omitempty"`
+}
+
+type SystemInventory struct {
+	// Id is the redfish id of the ComputerSystem
+	Id      string `json:"id"`
+	ODataId string `json:"odataId"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	ProcessorCount        int32 `json:"processorCount"`
+	LogicalProcessorCount int32 `json:"logicalProcessorCount"`
+	TotalMemoryGiB        int32 `json:"totalMemoryGiB"`
+
+	// +optional
+	Processors []ProcessorInventory `json:"processors,omitempty"`
+	// +optional
+	Memory []MemoryInventory `json:"memory,omitempty"`
+	// +optional
+	Drives []DriveInventory `json:"drives,omitempty"`
+	// Storage lists the redfish Storage, such as the RAID controller, with its volumes
+	// +optional
+	Storage []StorageInventory `json:"storage,omitempty"`
+	// +optional
+	PCIeDevices []PCIeDeviceInventory `json:"pcieDevices,omitempty"`
+	// +optional
+	EthernetInterfaces []EthernetInterfaceInventory `json:"ethernetInterfaces,omitempty"`
+	// NetworkAdapters lists the network adapters in the chassis of the system, with their ports
+	// +optional
+	NetworkAdapters []NetworkAdapterInventory `json:"networkAdapters,omitempty"`
+}
+
+type ProcessorInventory struct {
+	// ODataId is the redfish @odata.id of the processor
+	ODataId string `json:"odataId"`
+	// +optional
+	Socket string `json:"socket,omitempty"`
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// +optional
+	Model string `json:"model,omitempty"`
+	// +optional
+	ProcessorType string `json:"processorType,omitempty"`
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+	// +optional
+	TotalCores int32 `json:"totalCores,omitempty"`
+	// +optional
+	TotalThreads int32 `json:"totalThreads,omitempty"`
+	// +optional
+	MaxSpeedMHz int32 `json:"maxSpeedMHz,omitempty"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type MemoryInventory struct {
+	// ODataId is the redfish @odata.id of the DIMM
+	ODataId string `json:"odataId"`
+	// +optional
+	DeviceLocator string `json:"deviceLocator,omitempty"`
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// +optional
+	PartNumber string `json:"partNumber,omitempty"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// +optional
+	MemoryDeviceType string `json:"memoryDeviceType,omitempty"`
+	// +optional
+	CapacityMiB int32 `json:"capacityMiB,omitempty"`
+	// +optional
+	OperatingSpeedMhz int32 `json:"operatingSpeedMhz,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type DriveInventory struct {
+	// ODataId is the redfish @odata.id of the drive.
+	// For the drive of SimpleStorage which has no own resource, it is the @odata.id of the SimpleStorage with the device name as the fragment
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// +optional
+	Model string `json:"model,omitempty"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// +optional
+	MediaType string `json:"mediaType,omitempty"`
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+	// +optional
+	CapacityBytes int64 `json:"capacityBytes,omitempty"`
+	// +optional
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type StorageInventory struct {
+	// Id is the redfish id of the Storage, which is referred by spec.volumes[].storageId of the StorageConfig
+	Id      string `json:"id"`
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// SupportedRAIDTypes are the raid types supported by the controllers of the storage
+	// +optional
+	SupportedRAIDTypes []string `json:"supportedRAIDTypes,omitempty"`
+	// Drives are the @odata.id of the drives attached to the storage
+	// +optional
+	Drives []string `json:"drives,omitempty"`
+	// +optional
+	Volumes []VolumeInventory `json:"volumes,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+}
+
+type VolumeInventory struct {
+	// ODataId is the redfish @odata.id of the volume
+	ODataId string `json:"odataId"`
+	// +optional
+	Id string `json:"id,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	RAIDType string `json:"raidType,omitempty"`
+	// +optional
+	CapacityBytes int64 `json:"capacityBytes,omitempty"`
+	// Drives are the @odata.id of the member drives
+	// +optional
+	Drives []string `json:"drives,omitempty"`
+	// Operations are the running operations of the volume, such as Initialize
+	// +optional
+	Operations []string `json:"operations,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type PCIeDeviceInventory struct {
+	// ODataId is the redfish @odata.id of the PCIe device
+	ODataId string `json:"odataId"`
+	// +kubebuilder:validation:Enum=GPU;STORAGE;NIC;Unknown
+	DeviceType string `json:"deviceType"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// +optional
+	Model string `json:"model,omitempty"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// +optional
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// +optional
+	PCIeType string `json:"pcieType,omitempty"`
+	// +optional
+	MaxPCIeType string `json:"maxPCIeType,omitempty"`
+	// +optional
+	LanesInUse int32 `json:"lanesInUse,omitempty"`
+	// +optional
+	MaxLanes int32 `json:"maxLanes,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type EthernetInterfaceInventory struct {
+	// ODataId is the redfish @odata.id of the ethernet interface
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+	// +optional
+	PermanentMACAddress string `json:"permanentMACAddress,omitempty"`
+	// +optional
+	SpeedMbps int32 `json:"speedMbps,omitempty"`
+	// +optional
+	LinkStatus string `json:"linkStatus,omitempty"`
+	// PCIeDevice is the @odata.id of the PCIe device which the interface belongs to
+	// +optional
+	PCIeDevice string `json:"pcieDevice,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type NetworkAdapterInventory struct {
+	// ODataId is the redfish @odata.id of the network adapter
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// +optional
+	Model string `json:"model,omitempty"`
+	// +optional
+	PartNumber string `json:"partNumber,omitempty"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// FirmwareVersion is the firmware package version of the controller of the adapter
+	// +optional
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+	// +optional
+	Ports []NetworkPortInventory `json:"ports,omitempty"`
+}
+
+type NetworkPortInventory struct {
+	// ODataId is the redfish @odata.id of the Port, or the NetworkPort of the old BMC
+	ODataId string `json:"odataId"`
+	// PortId is the label of the physical port on the adapter
+	// +optional
+	PortId string `json:"portId,omitempty"`
+	// LinkStatus is LinkUp, LinkDown, NoLink and so on
+	// +optional
+	LinkStatus string `json:"linkStatus,omitempty"`
+	// +optional
+	SpeedMbps int32 `json:"speedMbps,omitempty"`
+	// MACAddresses are the MAC addresses of the port, which are used to map the ethernet interfaces of the host
+	// +optional
+	MACAddresses []string `json:"macAddresses,omitempty"`
+	// LLDP is the neighbor received by LLDP, such as the switch port which the port connects to
+	// +optional
+	LLDP *LLDPNeighbor `json:"lldp,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+type LLDPNeighbor struct {
+	// +optional
+	ChassisId string `json:"chassisId,omitempty"`
+	// +optional
+	ChassisIdSubtype string `json:"chassisIdSubtype,omitempty"`
+	// +optional
+	PortId string `json:"portId,omitempty"`
+	// +optional
+	PortIdSubtype string `json:"portIdSubtype,omitempty"`
+	// SystemName is the name of the neighbor, usually the hostname of the switch
+	// +optional
+	SystemName string `json:"systemName,omitempty"`
+	// +optional
+	SystemDescription string `json:"systemDescription,omitempty"`
+	// +optional
+	ManagementAddressIPv4 string `json:"managementAddressIPv4,omitempty"`
+	// +optional
+	ManagementAddressIPv6 string `json:"managementAddressIPv6,omitempty"`
+	// +optional
+	ManagementVlanId int32 `json:"managementVlanId,omitempty"`
+}
+
+type FirmwareInventory struct {
+	// ODataId is the redfish @odata.id of the firmware inventory
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	SoftwareId string `json:"softwareId,omitempty"`
+	// +optional
+	Updateable bool `json:"updateable,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type HostInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []HostInventory `json:"items"`
+}
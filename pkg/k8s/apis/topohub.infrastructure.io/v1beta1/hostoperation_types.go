@@ -28,6 +28,61 @@ const (
 	BootCmdGracefulRestart = string(redfish.GracefulRestartResetType)
 	// "PxeReboot"
 	BootCmdResetPxeOnce string = "PxeReboot"
+
+	// firmware
+	// "FirmwareUpdate"
+	ActionFirmwareUpdate string = "FirmwareUpdate"
+
+	// virtual media
+	// "VirtualMediaBoot"
+	ActionVirtualMediaBoot string = "VirtualMediaBoot"
+
+	// boot
+	// "SetBoot"
+	ActionSetBoot string = "SetBoot"
+
+	// decommission
+	// "Decommission"
+	ActionDecommission string = "Decommission"
+
+	// indicator led
+	// "SetIndicatorLED"
+	ActionSetIndicatorLED string = "SetIndicatorLED"
+
+	// bmc
+	// "ResetBmc"
+	ActionResetBmc string = "ResetBmc"
+	// "ResetBmcToDefaults"
+	ActionResetBmcToDefaults string = "ResetBmcToDefaults"
+)
+
+const (
+	// the states of the indicator led of the chassis
+	IndicatorLEDLit      = "Lit"
+	IndicatorLEDBlinking = "Blinking"
+	IndicatorLEDOff      = "Off"
+)
+
+const (
+	// the steps of the Decommission action, they run in this order
+	DecommissionStepResetStorage = "ResetStorage"
+	DecommissionStepEraseDrives  = "EraseDrives"
+	DecommissionStepResetBios    = "ResetBios"
+	DecommissionStepClearLogs    = "ClearLogs"
+	DecommissionStepRemoveHost   = "RemoveHost"
+
+	DecommissionStepPending   = "Pending"
+	DecommissionStepRunning   = "Running"
+	DecommissionStepCompleted = "Completed"
+	DecommissionStepSkipped   = "Skipped"
+	DecommissionStepFailed    = "Failed"
+)
+
+const (
+	// the BMC downloads the image from the http server of topohub by UpdateService.SimpleUpdate
+	FirmwareTransferSimpleUpdate = "SimpleUpdate"
+	// topohub uploads the image to the MultipartHttpPushUri of the UpdateService
+	FirmwareTransferMultipartPush = "MultipartPush"
 )
 
 // +genclient
@@ -39,6 +94,8 @@ const (
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.status"
 // +kubebuilder:printcolumn:name="CLUSTERNAME",type="string",JSONPath=".status.clusterName"
 // +kubebuilder:printcolumn:name="HOSTIP",type="string",JSONPath=".status.ipAddr"
+// +kubebuilder:printcolumn:name="SYSTEM",type="string",JSONPath=".status.systemId",priority=1
+// +kubebuilder:printcolumn:name="PROGRESS",type="integer",JSONPath=".status.task.percentComplete",priority=1
 
 type HostOperation struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -49,12 +106,165 @@ type HostOperation struct {
 }
 
 type HostOperationSpec struct {
-	// +kubebuilder:validation:Enum=ForceOn;On;ForceOff;GracefulShutdown;ForceRestart;GracefulRestart;PxeReboot
+	// +kubebuilder:validation:Enum=ForceOn;On;ForceOff;GracefulShutdown;ForceRestart;GracefulRestart;PxeReboot;FirmwareUpdate;VirtualMediaBoot;SetBoot;Decommission;SetIndicatorLED;ResetBmc;ResetBmcToDefaults
 	// +kubebuilder:validation:Required
 	Action string `json:"action"`
 
 	// +kubebuilder:validation:Required
 	HostStatusName string `json:"hostStatusName"`
+
+	// SystemId specifies which ComputerSystem of the host to operate, it refers to status.systems[].id of the HostStatus.
+	// It is required when the host exposes more than one system
+	// +optional
+	SystemId string `json:"systemId,omitempty"`
+
+	// Firmware specifies the image for the FirmwareUpdate action
+	// +optional
+	Firmware *FirmwareUpdateSpec `json:"firmware,omitempty"`
+
+	// VirtualMedia specifies the ISO for the VirtualMediaBoot action
+	// +optional
+	VirtualMedia *VirtualMediaBootSpec `json:"virtualMedia,omitempty"`
+
+	// Boot specifies the boot configuration for the SetBoot action
+	// +optional
+	Boot *BootSpec `json:"boot,omitempty"`
+
+	// Decommission specifies the steps for the Decommission action
+	// +optional
+	Decommission *DecommissionSpec `json:"decommission,omitempty"`
+
+	// IndicatorLED specifies the state of the indicator led for the SetIndicatorLED action
+	// +optional
+	IndicatorLED *IndicatorLEDSpec `json:"indicatorLED,omitempty"`
+
+	// BmcReset specifies how to reset the bmc for the ResetBmc and the ResetBmcToDefaults action
+	// +optional
+	BmcReset *BmcResetSpec `json:"bmcReset,omitempty"`
+}
+
+type BmcResetSpec struct {
+	// ResetType is the type to restart the bmc for the ResetBmc action
+	// +kubebuilder:validation:Enum=GracefulRestart;ForceRestart
+	// +kubebuilder:default=GracefulRestart
+	// +optional
+	ResetType string `json:"resetType,omitempty"`
+
+	// ResetToDefaultsType decides which settings are preserved for the ResetBmcToDefaults action.
+	// The bmc may get a new ip address or lose the account of topohub with ResetAll
+	// +kubebuilder:validation:Enum=ResetAll;PreserveNetworkAndUsers;PreserveNetwork
+	// +kubebuilder:default=PreserveNetworkAndUsers
+	// +optional
+	ResetToDefaultsType string `json:"resetToDefaultsType,omitempty"`
+
+	// ConfirmHostStatusName must be the same as spec.hostStatusName for the ResetBmcToDefaults action,
+	// since the settings of the bmc are lost
+	// +optional
+	ConfirmHostStatusName string `json:"confirmHostStatusName,omitempty"`
+
+	// TimeoutMinutes is the time to wait for the bmc to come back after it is reset
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +kubebuilder:default=15
+	// +optional
+	TimeoutMinutes *int32 `json:"timeoutMinutes,omitempty"`
+}
+
+type IndicatorLEDSpec struct {
+	// State is the state to set to the indicator led of the chassis. The chassis which only supports the
+	// LocationIndicatorActive does not distinguish Lit from Blinking
+	// +kubebuilder:validation:Enum=Lit;Blinking;Off
+	// +kubebuilder:validation:Required
+	State string `json:"state"`
+
+	// RevertAfterMinutes is the time to revert the indicator led to its previous state, so that the led is not left on
+	// after the host is found. The led is not reverted when it is 0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=30
+	// +optional
+	RevertAfterMinutes *int32 `json:"revertAfterMinutes,omitempty"`
+}
+
+type DecommissionSpec struct {
+	// ConfirmHostStatusName must be the same as spec.hostStatusName, since the data on the drives of the host is erased
+	// +kubebuilder:validation:Required
+	ConfirmHostStatusName string `json:"confirmHostStatusName"`
+
+	// Steps are the steps to run. No matter how they are listed, they run in the order of
+	// ResetStorage, EraseDrives, ResetBios, ClearLogs and RemoveHost
+	// +kubebuilder:validation:items:Enum=ResetStorage;EraseDrives;ResetBios;ClearLogs;RemoveHost
+	// +kubebuilder:default={ResetStorage,EraseDrives,ResetBios,ClearLogs,RemoveHost}
+	// +optional
+	Steps []string `json:"steps,omitempty"`
+}
+
+type BootSpec struct {
+	// Target is the BootSourceOverrideTarget, the boot override is not changed when it is empty
+	// +kubebuilder:validation:Enum=None;Pxe;Hdd;Cd;Usb;UefiHttp;BiosSetup;UefiTarget;UefiShell;Diags;Utilities;SDCard;RemoteDrive
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// OverrideEnabled is the BootSourceOverrideEnabled. Once: boot from the target at the next boot only.
+	// Continuous: boot from the target until it is disabled. Disabled: cancel the boot override.
+	// It is Once when the target is specified and it is empty
+	// +kubebuilder:validation:Enum=Once;Continuous;Disabled
+	// +optional
+	OverrideEnabled string `json:"overrideEnabled,omitempty"`
+
+	// BootMode is the BootSourceOverrideMode, which chooses UEFI or Legacy BIOS boot
+	// +kubebuilder:validation:Enum=UEFI;Legacy
+	// +optional
+	BootMode string `json:"bootMode,omitempty"`
+
+	// UefiTarget is the UefiTargetBootSourceOverride, the UEFI device path to boot from, which is required for the target UefiTarget
+	// +optional
+	UefiTarget string `json:"uefiTarget,omitempty"`
+
+	// HttpBootUri is the uri to boot from for the target UefiHttp
+	// +optional
+	HttpBootUri string `json:"httpBootUri,omitempty"`
+
+	// BootOrder is the persistent boot order, which is the list of BootOptionReference, such as Boot0001.
+	// Refer to status.systems[].boot.bootOrder of the HostStatus for the current one
+	// +optional
+	BootOrder []string `json:"bootOrder,omitempty"`
+
+	// ResetType resets the system after setting the boot configuration, the system is not reset when it is empty
+	// +kubebuilder:validation:Enum=On;ForceOn;ForceRestart;GracefulRestart
+	// +optional
+	ResetType string `json:"resetType,omitempty"`
+}
+
+type FirmwareUpdateSpec struct {
+	// ImagePath is the path of the firmware image relative to the root directory of the http server of topohub, such as firmware/bios-v1.2.bin
+	// +kubebuilder:validation:Required
+	ImagePath string `json:"imagePath"`
+
+	// TransferMethod decides how the image is delivered to the BMC.
+	// SimpleUpdate: the BMC downloads the image from the http server of topohub.
+	// MultipartPush: topohub uploads the image to the BMC, it works when the BMC could not reach the http server
+	// +kubebuilder:validation:Enum=SimpleUpdate;MultipartPush
+	// +kubebuilder:default=SimpleUpdate
+	// +optional
+	TransferMethod string `json:"transferMethod,omitempty"`
+
+	// Targets are the @odata.id of the firmware to update, which refer to status.firmware[].odataId of the HostInventory.
+	// The BMC applies the image to all applicable components when it is empty
+	// +optional
+	Targets []string `json:"targets,omitempty"`
+}
+
+type VirtualMediaBootSpec struct {
+	// IsoFile is the path of the ISO relative to the iso directory of the http server of topohub, such as ubuntu-24.04.iso
+	// +kubebuilder:validation:Required
+	IsoFile string `json:"isoFile"`
+
+	// EjectAfterMinutes is the time to eject the ISO after the host reboots, it should be long enough to finish the installation.
+	// The ISO is not ejected when it is 0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=120
+	// +optional
+	EjectAfterMinutes *int32 `json:"ejectAfterMinutes,omitempty"`
 }
 
 type HostOperationStatus struct {
@@ -68,6 +278,108 @@ type HostOperationStatus struct {
 	ClusterName string `json:"clusterName,omitempty"`
 
 	IpAddr string `json:"ipAddr,omitempty"`
+
+	// SystemId is the id of the ComputerSystem which has been operated
+	SystemId string `json:"systemId,omitempty"`
+
+	// Task tracks the redfish task which the BMC creates for the asynchronous action
+	// +optional
+	Task *TaskInfo `json:"task,omitempty"`
+
+	// FirmwareVersions records the version of the target firmware after the FirmwareUpdate action finishes
+	// +optional
+	FirmwareVersions []FirmwareInventory `json:"firmwareVersions,omitempty"`
+
+	// VirtualMedia records the virtual media which the ISO is inserted to by the VirtualMediaBoot action
+	// +optional
+	VirtualMedia *VirtualMediaStatus `json:"virtualMedia,omitempty"`
+
+	// Decommission tracks the progress of each step of the Decommission action
+	// +optional
+	Decommission []DecommissionStepStatus `json:"decommission,omitempty"`
+
+	// IndicatorLED records the indicator led which is set by the SetIndicatorLED action
+	// +optional
+	IndicatorLED *IndicatorLEDStatus `json:"indicatorLED,omitempty"`
+
+	// BmcReset tracks the bmc which is reset by the ResetBmc or the ResetBmcToDefaults action until it comes back
+	// +optional
+	BmcReset *BmcResetStatus `json:"bmcReset,omitempty"`
+}
+
+type BmcResetStatus struct {
+	// ResetType is the reset type of the ResetBmc action, or the ResetToDefaultsType of the ResetBmcToDefaults action
+	ResetType string `json:"resetType"`
+	ResetTime string `json:"resetTime"`
+	// Deadline is the time to give up waiting for the bmc
+	Deadline string `json:"deadline"`
+	// Unreachable is true once the bmc stops responding after it is reset
+	// +optional
+	Unreachable bool `json:"unreachable,omitempty"`
+	// RecoveredTime is the time when the bmc responds again
+	// +optional
+	RecoveredTime string `json:"recoveredTime,omitempty"`
+}
+
+type IndicatorLEDStatus struct {
+	// PreviousState is the state of the indicator led before the action, it is restored at the RevertTime
+	// +optional
+	PreviousState string `json:"previousState,omitempty"`
+	State         string `json:"state"`
+	SetTime       string `json:"setTime"`
+	// RevertTime is the time when the indicator led is going to be reverted
+	// +optional
+	RevertTime string `json:"revertTime,omitempty"`
+	// +optional
+	Reverted bool `json:"reverted,omitempty"`
+}
+
+type DecommissionStepStatus struct {
+	// Name is the name of the step, such as EraseDrives
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Skipped;Failed
+	State string `json:"state"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime string `json:"completionTime,omitempty"`
+	// Tasks are the redfish tasks created by the BMC for the step, such as erasing each drive
+	// +optional
+	Tasks []TaskInfo `json:"tasks,omitempty"`
+}
+
+type VirtualMediaStatus struct {
+	// MediaUri is the @odata.id of the virtual media
+	MediaUri string `json:"mediaUri"`
+	// ImageUri is the url of the ISO on the http server of topohub
+	ImageUri     string `json:"imageUri"`
+	InsertedTime string `json:"insertedTime"`
+	// EjectTime is the time when the ISO is going to be ejected
+	// +optional
+	EjectTime string `json:"ejectTime,omitempty"`
+	// +optional
+	Ejected bool `json:"ejected,omitempty"`
+}
+
+type TaskInfo struct {
+	// Uri is the @odata.id of the redfish task or the task monitor
+	Uri string `json:"uri"`
+	// StartTime is the time when topohub starts tracking the task
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// State is the TaskState of the redfish task, such as Running, Completed, Exception
+	// +optional
+	State string `json:"state,omitempty"`
+	// Health is the TaskStatus of the redfish task, such as OK, Warning, Critical
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	PercentComplete int32 `json:"percentComplete,omitempty"`
+	// Messages are the messages reported by the task
+	// +optional
+	Messages []string `json:"messages,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
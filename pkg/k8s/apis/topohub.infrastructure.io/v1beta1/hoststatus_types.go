@@ -13,6 +13,18 @@ const (
 
 	HostTypeDHCP     = "dhcp"
 	HostTypeEndpoint = "hostendpoint"
+
+	// the protocol to manage the bmc, ipmi is the fallback for the old bmc without usable redfish
+	ProtocolRedfish = "redfish"
+	ProtocolIPMI    = "ipmi"
+
+	// ConditionCertificateTrusted reports whether the certificate of the BMC is trusted
+	ConditionCertificateTrusted = "CertificateTrusted"
+	// ConditionCertificateExpiring reports whether the certificate of the BMC expires soon or has expired
+	ConditionCertificateExpiring = "CertificateExpiring"
+	// ConditionBmcResetting reports that the BMC is reset by the HostOperation, the HostStatus is not marked unhealthy
+	// while the BMC is unreachable during the reset
+	ConditionBmcResetting = "BmcResetting"
 )
 
 // +genclient
@@ -38,7 +50,141 @@ type HostStatusStatus struct {
 	LastUpdateTime string            `json:"lastUpdateTime"`
 	Basic          BasicInfo         `json:"basic"`
 	Info           map[string]string `json:"info"`
-	Log            LogStruct         `json:"log"`
+	// Systems records each ComputerSystem exposed by the BMC, a blade chassis or a multi-node enclosure may expose several ones
+	// +optional
+	Systems []SystemInfo `json:"systems,omitempty"`
+	// Managers records each manager exposed by the redfish service
+	// +optional
+	Managers []ManagerInfo `json:"managers,omitempty"`
+	Log      LogStruct     `json:"log"`
+	// LastFirmwareUpdate records the result of the latest FirmwareUpdate HostOperation
+	// +optional
+	LastFirmwareUpdate *FirmwareUpdateRecord `json:"lastFirmwareUpdate,omitempty"`
+	// EventSubscription records the redfish event subscription which pushes the events of the BMC to topohub
+	// +optional
+	EventSubscription *EventSubscriptionInfo `json:"eventSubscription,omitempty"`
+	// SnmpTrap records the snmp trap destination which sends the traps of the BMC to topohub
+	// +optional
+	SnmpTrap *SnmpTrapInfo `json:"snmpTrap,omitempty"`
+	// Certificate records the certificate presented by the BMC, it is trusted on first use when the tls of the host
+	// specifies neither the CA bundle nor the fingerprints
+	// +optional
+	Certificate *CertificateInfo `json:"certificate,omitempty"`
+	// Conditions represent the latest available observations of the HostStatus
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+type CertificateInfo struct {
+	// Fingerprint is the SHA-256 fingerprint of the certificate, such as AB:CD:...
+	Fingerprint string `json:"fingerprint"`
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+	// +optional
+	NotBefore string `json:"notBefore,omitempty"`
+	// +optional
+	NotAfter string `json:"notAfter,omitempty"`
+	// RecordTime is the time when the certificate is recorded
+	RecordTime string `json:"recordTime"`
+}
+
+type EventSubscriptionInfo struct {
+	// Uri is the odata id of the EventDestination on the BMC
+	Uri string `json:"uri"`
+	// Destination is the url of topohub which receives the events
+	Destination string `json:"destination"`
+	// SubscribeTime is the time when the subscription is created
+	SubscribeTime string `json:"subscribeTime"`
+}
+
+type SnmpTrapInfo struct {
+	// Uri is the odata id of the EventDestination on the BMC, or the name of the OEM destination such as SNMPAlert.1 of iDRAC
+	Uri string `json:"uri"`
+	// Destination is the address of topohub which receives the traps, such as snmp://10.0.0.1:162
+	Destination string `json:"destination"`
+	// ConfigureTime is the time when the destination is configured
+	ConfigureTime string `json:"configureTime"`
+}
+
+type FirmwareUpdateRecord struct {
+	// HostOperation is the name of the HostOperation which updates the firmware
+	HostOperation string `json:"hostOperation"`
+	ImagePath     string `json:"imagePath"`
+	// +kubebuilder:validation:Enum=success;failure
+	Status string `json:"status"`
+	Time   string `json:"time"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Firmware records the version of the target firmware after updating
+	// +optional
+	Firmware []FirmwareInventory `json:"firmware,omitempty"`
+}
+
+type SystemInfo struct {
+	// Id is the redfish id of the ComputerSystem, it could be used as spec.systemId of the HostOperation
+	Id      string `json:"id"`
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// +optional
+	Model string `json:"model,omitempty"`
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// +optional
+	HostName string `json:"hostName,omitempty"`
+	// +optional
+	BiosVersion string `json:"biosVersion,omitempty"`
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	SupportedResetTypes []string `json:"supportedResetTypes,omitempty"`
+	// ManagedBy is the id list of the managers which manage this system
+	// +optional
+	ManagedBy []string `json:"managedBy,omitempty"`
+	// Boot is the boot configuration of the system
+	// +optional
+	Boot *BootInfo `json:"boot,omitempty"`
+	// IndicatorLED is the state of the indicator led of the chassis which contains the system, such as Lit, Blinking or Off
+	// +optional
+	IndicatorLED string `json:"indicatorLED,omitempty"`
+}
+
+type BootInfo struct {
+	// +optional
+	OverrideTarget string `json:"overrideTarget,omitempty"`
+	// +optional
+	OverrideEnabled string `json:"overrideEnabled,omitempty"`
+	// +optional
+	OverrideMode string `json:"overrideMode,omitempty"`
+	// +optional
+	UefiTarget string `json:"uefiTarget,omitempty"`
+	// +optional
+	BootOrder []string `json:"bootOrder,omitempty"`
+}
+
+type ManagerInfo struct {
+	Id      string `json:"id"`
+	ODataId string `json:"odataId"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	ManagerType string `json:"managerType,omitempty"`
+	// +optional
+	Model string `json:"model,omitempty"`
+	// +optional
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// +optional
+	Health string `json:"health,omitempty"`
 }
 
 type LogStruct struct {
@@ -49,6 +195,15 @@ type LogStruct struct {
 	LastestLog *LogEntry `json:"lastestLog,omitempty"`
 	// +optional
 	LastestWarningLog *LogEntry `json:"lastestWarningLog,omitempty"`
+	// TotalEventAccount counts the redfish events and the snmp traps pushed by the BMC
+	// +optional
+	TotalEventAccount int32 `json:"totalEventAccount,omitempty"`
+	// +optional
+	WarningEventAccount int32 `json:"warningEventAccount,omitempty"`
+	// +optional
+	LastestEvent *LogEntry `json:"lastestEvent,omitempty"`
+	// +optional
+	LastestWarningEvent *LogEntry `json:"lastestWarningEvent,omitempty"`
 }
 
 type LogEntry struct {
@@ -71,6 +226,15 @@ type BasicInfo struct {
 	DhcpExpireTime   *string `json:"dhcpExpireTime,omitempty"`
 	SubnetName       *string `json:"subnetName,omitempty"`
 	Hostname         *string `json:"hostname,omitempty"`
+	// Protocol is redfish or ipmi, it is redfish when it is empty
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+	// TLS is copied from the HostEndpoint
+	// +optional
+	TLS *BmcTLSSpec `json:"tls,omitempty"`
+	// BmcProfileName is copied from the HostEndpoint
+	// +optional
+	BmcProfileName string `json:"bmcProfileName,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
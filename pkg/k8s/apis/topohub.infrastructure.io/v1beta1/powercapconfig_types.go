@@ -0,0 +1,134 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PowerCapConfigModeEnforce sets the power limit of the hosts
+	PowerCapConfigModeEnforce = "Enforce"
+	// PowerCapConfigModeMonitor only reports the consumption and the drift of the power limit
+	PowerCapConfigModeMonitor = "Monitor"
+
+	PowerCapStateInSync  = "InSync"
+	PowerCapStateDrifted = "Drifted"
+	PowerCapStateFailed  = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="LIMIT_WATTS",type="integer",JSONPath=".spec.limitWatts"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.totalHosts"
+// +kubebuilder:printcolumn:name="INSYNC",type="integer",JSONPath=".status.inSyncHosts"
+// +kubebuilder:printcolumn:name="DRIFTED",type="integer",JSONPath=".status.driftedHosts"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failedHosts"
+// +kubebuilder:printcolumn:name="CONSUMED_WATTS",type="integer",JSONPath=".status.totalConsumedWatts"
+// +kubebuilder:printcolumn:name="CAPPED_WATTS",type="integer",JSONPath=".status.totalLimitWatts"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PowerCapConfig assigns the power limit to the chassis of a set of hosts, and reports their power consumption
+type PowerCapConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PowerCapConfigSpec   `json:"spec,omitempty"`
+	Status PowerCapConfigStatus `json:"status,omitempty"`
+}
+
+type PowerCapConfigSpec struct {
+	// HostStatusNames lists the name of the HostStatus to cap
+	// +optional
+	HostStatusNames []string `json:"hostStatusNames,omitempty"`
+
+	// HostSelector selects the HostStatus by the labels, such as topohub.infrastructure.io/cluster-name
+	// and topohub.infrastructure.io/subnet-name
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// ChassisIds are the id of the chassis to cap, such as System.Embedded.1. All the chassis which report
+	// the PowerControl are capped when it is empty
+	// +optional
+	ChassisIds []string `json:"chassisIds,omitempty"`
+
+	// LimitWatts is the power limit of each chassis
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	LimitWatts int32 `json:"limitWatts"`
+
+	// LimitException is what the BMC does when the limit could not be maintained, it is decided by the BMC when it is empty
+	// +kubebuilder:validation:Enum=NoAction;HardPowerOff;LogEventOnly;Oem
+	// +optional
+	LimitException string `json:"limitException,omitempty"`
+
+	// CorrectionInMs is the time for the BMC to bring the consumption under the limit, it is decided by the BMC when it is empty
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CorrectionInMs *int64 `json:"correctionInMs,omitempty"`
+
+	// Mode decides what to do for the drift. Enforce: set the power limit. Monitor: only report the drift
+	// +kubebuilder:validation:Enum=Enforce;Monitor
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+type PowerCapConfigStatus struct {
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	TotalHosts   int32 `json:"totalHosts"`
+	InSyncHosts  int32 `json:"inSyncHosts"`
+	DriftedHosts int32 `json:"driftedHosts"`
+	FailedHosts  int32 `json:"failedHosts"`
+
+	// TotalConsumedWatts is the sum of the current consumption of all the chassis
+	TotalConsumedWatts int32 `json:"totalConsumedWatts"`
+	// TotalLimitWatts is the sum of the current power limit of all the chassis, which is compared with the budget of the rack
+	TotalLimitWatts int32 `json:"totalLimitWatts"`
+
+	// +optional
+	Hosts []PowerCapHostStatus `json:"hosts,omitempty"`
+}
+
+type PowerCapHostStatus struct {
+	HostStatusName string `json:"hostStatusName"`
+	// ChassisId is the id of the chassis which is capped, a host may have several chassis
+	// +optional
+	ChassisId string `json:"chassisId,omitempty"`
+
+	// State is InSync when the power limit of the chassis is as declared, Drifted when it differs in the Monitor mode,
+	// and Failed when the power could not be read or limited
+	// +kubebuilder:validation:Enum=InSync;Drifted;Failed
+	State string `json:"state"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LimitWatts is the current power limit of the chassis, it is 0 when the power is not limited
+	// +optional
+	LimitWatts int32 `json:"limitWatts,omitempty"`
+	// ConsumedWatts is the current consumption of the chassis
+	// +optional
+	ConsumedWatts int32 `json:"consumedWatts,omitempty"`
+	// AverageConsumedWatts, MinConsumedWatts and MaxConsumedWatts are the history of the consumption in the last IntervalInMin minutes
+	// +optional
+	AverageConsumedWatts int32 `json:"averageConsumedWatts,omitempty"`
+	// +optional
+	MinConsumedWatts int32 `json:"minConsumedWatts,omitempty"`
+	// +optional
+	MaxConsumedWatts int32 `json:"maxConsumedWatts,omitempty"`
+	// +optional
+	IntervalInMin int32 `json:"intervalInMin,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PowerCapConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PowerCapConfig `json:"items"`
+}
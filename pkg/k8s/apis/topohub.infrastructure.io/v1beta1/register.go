@@ -35,6 +35,27 @@ const (
 
 	// KindBindingIp is the kind name for BindingIp resource
 	KindBindingIp = "BindingIp"
+
+	// KindHostInventory is the kind name for HostInventory resource
+	KindHostInventory = "HostInventory"
+
+	// KindBiosConfig is the kind name for BiosConfig resource
+	KindBiosConfig = "BiosConfig"
+
+	// KindBmcAccount is the kind name for BmcAccount resource
+	KindBmcAccount = "BmcAccount"
+
+	// KindStorageConfig is the kind name for StorageConfig resource
+	KindStorageConfig = "StorageConfig"
+
+	// KindPowerCapConfig is the kind name for PowerCapConfig resource
+	KindPowerCapConfig = "PowerCapConfig"
+
+	// KindBmcCertificate is the kind name for BmcCertificate resource
+	KindBmcCertificate = "BmcCertificate"
+
+	// KindBmcProfile is the kind name for BmcProfile resource
+	KindBmcProfile = "BmcProfile"
 )
 
 var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
@@ -65,4 +86,11 @@ func init() {
 	SchemeBuilder.Register(&HostStatus{}, &HostStatusList{})
 	SchemeBuilder.Register(&HostOperation{}, &HostOperationList{})
 	SchemeBuilder.Register(&BindingIp{}, &BindingIpList{})
+	SchemeBuilder.Register(&HostInventory{}, &HostInventoryList{})
+	SchemeBuilder.Register(&BiosConfig{}, &BiosConfigList{})
+	SchemeBuilder.Register(&BmcAccount{}, &BmcAccountList{})
+	SchemeBuilder.Register(&StorageConfig{}, &StorageConfigList{})
+	SchemeBuilder.Register(&PowerCapConfig{}, &PowerCapConfigList{})
+	SchemeBuilder.Register(&BmcCertificate{}, &BmcCertificateList{})
+	SchemeBuilder.Register(&BmcProfile{}, &BmcProfileList{})
 }
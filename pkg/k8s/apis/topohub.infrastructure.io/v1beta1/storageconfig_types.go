@@ -0,0 +1,161 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// StorageConfigModeEnforce creates and deletes the volumes of the hosts
+	StorageConfigModeEnforce = "Enforce"
+	// StorageConfigModeMonitor only reports the drift
+	StorageConfigModeMonitor = "Monitor"
+
+	StorageStateInSync  = "InSync"
+	StorageStatePending = "Pending"
+	StorageStateDrifted = "Drifted"
+	StorageStateFailed  = "Failed"
+
+	VolumeActionCreate = "Create"
+	VolumeActionDelete = "Delete"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.totalHosts"
+// +kubebuilder:printcolumn:name="INSYNC",type="integer",JSONPath=".status.inSyncHosts"
+// +kubebuilder:printcolumn:name="PENDING",type="integer",JSONPath=".status.pendingHosts"
+// +kubebuilder:printcolumn:name="DRIFTED",type="integer",JSONPath=".status.driftedHosts"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failedHosts"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// StorageConfig declares the desired RAID volumes on the redfish Storage of a set of hosts
+type StorageConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StorageConfigSpec   `json:"spec,omitempty"`
+	Status StorageConfigStatus `json:"status,omitempty"`
+}
+
+type StorageConfigSpec struct {
+	// HostStatusNames lists the name of the HostStatus to configure
+	// +optional
+	HostStatusNames []string `json:"hostStatusNames,omitempty"`
+
+	// HostSelector selects the HostStatus by the labels, such as topohub.infrastructure.io/cluster-name
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+
+	// Volumes are the desired volumes, a volume is identified by its name
+	// +optional
+	Volumes []VolumeSpec `json:"volumes,omitempty"`
+
+	// DeleteUndeclaredVolumes deletes the volumes which are not declared in spec.volumes, and the volumes whose
+	// raid type or drives differ from the declaration, so they could be created again. The data on them is lost
+	// +optional
+	DeleteUndeclaredVolumes bool `json:"deleteUndeclaredVolumes,omitempty"`
+
+	// ApplyTime is the @Redfish.OperationApplyTime when the BMC creates or deletes the volumes, it is decided by the BMC when it is empty
+	// +kubebuilder:validation:Enum=Immediate;OnReset;AtMaintenanceWindowStart;InMaintenanceWindowOnReset
+	// +optional
+	ApplyTime string `json:"applyTime,omitempty"`
+
+	// Mode decides what to do for the drift. Enforce: create and delete the volumes. Monitor: only report the drift
+	// +kubebuilder:validation:Enum=Enforce;Monitor
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+type VolumeSpec struct {
+	// Name is the name of the volume
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// StorageId is the id of the redfish Storage which creates the volume, such as RAID.Integrated.1-1.
+	// It is the Storage which has all the drives when it is empty
+	// +optional
+	StorageId string `json:"storageId,omitempty"`
+
+	// +kubebuilder:validation:Enum=RAID0;RAID1;RAID5;RAID6;RAID10;RAID50;RAID60;RAID1E;RAID00
+	// +kubebuilder:validation:Required
+	RAIDType string `json:"raidType"`
+
+	// Drives are the member drives of the volume, which are the id or the @odata.id of the redfish Drive,
+	// refer to status.systems[].storage[].drives of the HostInventory
+	// +kubebuilder:validation:MinItems=1
+	Drives []string `json:"drives"`
+
+	// CapacityBytes is the size of the volume, the volume uses all the capacity of the drives when it is empty
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CapacityBytes *int64 `json:"capacityBytes,omitempty"`
+
+	// InitializeMethod decides how the new volume is initialized. Skip: no initialization.
+	// Background: the volume is available immediately and erased in the background. Foreground: the volume is available after it is erased
+	// +kubebuilder:validation:Enum=Skip;Background;Foreground
+	// +optional
+	InitializeMethod string `json:"initializeMethod,omitempty"`
+}
+
+type StorageConfigStatus struct {
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	TotalHosts   int32 `json:"totalHosts"`
+	InSyncHosts  int32 `json:"inSyncHosts"`
+	PendingHosts int32 `json:"pendingHosts"`
+	DriftedHosts int32 `json:"driftedHosts"`
+	FailedHosts  int32 `json:"failedHosts"`
+
+	// +optional
+	Hosts []StorageHostStatus `json:"hosts,omitempty"`
+}
+
+type StorageHostStatus struct {
+	HostStatusName string `json:"hostStatusName"`
+	// SystemId is the id of the ComputerSystem, a host may expose several systems
+	// +optional
+	SystemId string `json:"systemId,omitempty"`
+
+	// State is InSync when the volumes are as declared, Pending when the BMC is creating or deleting the volumes,
+	// Drifted when the volumes differ, and Failed when the storage could not be read or configured
+	// +kubebuilder:validation:Enum=InSync;Pending;Drifted;Failed
+	State string `json:"state"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastApplyTime is the time when topohub requests the BMC to create or delete the volumes last time
+	// +optional
+	LastApplyTime string `json:"lastApplyTime,omitempty"`
+
+	// Drift lists the volumes which need to be created or deleted
+	// +optional
+	Drift []VolumeDrift `json:"drift,omitempty"`
+}
+
+type VolumeDrift struct {
+	Name string `json:"name"`
+	// Action is what to do with the volume
+	// +kubebuilder:validation:Enum=Create;Delete
+	Action string `json:"action"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// RequestTime is the time when topohub requests the action to the BMC. The action is not requested again
+	// until it times out, since the BMC may create the volume after the reboot
+	// +optional
+	RequestTime string `json:"requestTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type StorageConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []StorageConfig `json:"items"`
+}
@@ -136,6 +136,14 @@ type SubnetSpec struct {
 	// Feature configuration
 	// +optional
 	Feature *FeatureSpec `json:"feature,omitempty"`
+
+	// BmcTLS configures the verification of the certificate of the BMC of the dhcp clients in the subnet
+	// +optional
+	BmcTLS *BmcTLSSpec `json:"bmcTls,omitempty"`
+
+	// BmcProfileName is the BmcProfile which configures the network services of the BMC of the dhcp clients in the subnet
+	// +optional
+	BmcProfileName *string `json:"bmcProfileName,omitempty"`
 }
 
 // SubnetStatus defines the observed state of Subnet
@@ -27,6 +27,11 @@ func (in *BasicInfo) DeepCopyInto(out *BasicInfo) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(BmcTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicInfo.
@@ -129,81 +134,174 @@ func (in *BindingIpStatus) DeepCopy() *BindingIpStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DhcpStatusSpec) DeepCopyInto(out *DhcpStatusSpec) {
+func (in *BiosAttributeDrift) DeepCopyInto(out *BiosAttributeDrift) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DhcpStatusSpec.
-func (in *DhcpStatusSpec) DeepCopy() *DhcpStatusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BiosAttributeDrift.
+func (in *BiosAttributeDrift) DeepCopy() *BiosAttributeDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(DhcpStatusSpec)
+	out := new(BiosAttributeDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnableSyncEndpointSpec) DeepCopyInto(out *EnableSyncEndpointSpec) {
+func (in *BiosConfig) DeepCopyInto(out *BiosConfig) {
 	*out = *in
-	if in.DefaultClusterName != nil {
-		in, out := &in.DefaultClusterName, &out.DefaultClusterName
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BiosConfig.
+func (in *BiosConfig) DeepCopy() *BiosConfig {
+	if in == nil {
+		return nil
 	}
+	out := new(BiosConfig)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnableSyncEndpointSpec.
-func (in *EnableSyncEndpointSpec) DeepCopy() *EnableSyncEndpointSpec {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BiosConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BiosConfigList) DeepCopyInto(out *BiosConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BiosConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BiosConfigList.
+func (in *BiosConfigList) DeepCopy() *BiosConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(EnableSyncEndpointSpec)
+	out := new(BiosConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BiosConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
+func (in *BiosConfigSpec) DeepCopyInto(out *BiosConfigSpec) {
 	*out = *in
-	if in.EnableSyncEndpoint != nil {
-		in, out := &in.EnableSyncEndpoint, &out.EnableSyncEndpoint
-		*out = new(EnableSyncEndpointSpec)
+	if in.HostStatusNames != nil {
+		in, out := &in.HostStatusNames, &out.HostStatusNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpec.
-func (in *FeatureSpec) DeepCopy() *FeatureSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BiosConfigSpec.
+func (in *BiosConfigSpec) DeepCopy() *BiosConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureSpec)
+	out := new(BiosConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostOperation) DeepCopyInto(out *HostOperation) {
+func (in *BiosConfigStatus) DeepCopyInto(out *BiosConfigStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]BiosHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BiosConfigStatus.
+func (in *BiosConfigStatus) DeepCopy() *BiosConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BiosConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BiosHostStatus) DeepCopyInto(out *BiosHostStatus) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]BiosAttributeDrift, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BiosHostStatus.
+func (in *BiosHostStatus) DeepCopy() *BiosHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BiosHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcAccount) DeepCopyInto(out *BmcAccount) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperation.
-func (in *HostOperation) DeepCopy() *HostOperation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcAccount.
+func (in *BmcAccount) DeepCopy() *BmcAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(HostOperation)
+	out := new(BmcAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostOperation) DeepCopyObject() runtime.Object {
+func (in *BmcAccount) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -211,31 +309,46 @@ func (in *HostOperation) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostOperationList) DeepCopyInto(out *HostOperationList) {
+func (in *BmcAccountHostStatus) DeepCopyInto(out *BmcAccountHostStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcAccountHostStatus.
+func (in *BmcAccountHostStatus) DeepCopy() *BmcAccountHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcAccountHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcAccountList) DeepCopyInto(out *BmcAccountList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HostOperation, len(*in))
+		*out = make([]BmcAccount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationList.
-func (in *HostOperationList) DeepCopy() *HostOperationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcAccountList.
+func (in *BmcAccountList) DeepCopy() *BmcAccountList {
 	if in == nil {
 		return nil
 	}
-	out := new(HostOperationList)
+	out := new(BmcAccountList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostOperationList) DeepCopyObject() runtime.Object {
+func (in *BmcAccountList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -243,55 +356,83 @@ func (in *HostOperationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostOperationSpec) DeepCopyInto(out *HostOperationSpec) {
+func (in *BmcAccountSpec) DeepCopyInto(out *BmcAccountSpec) {
 	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AdminSecretName != nil {
+		in, out := &in.AdminSecretName, &out.AdminSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdminSecretNamespace != nil {
+		in, out := &in.AdminSecretNamespace, &out.AdminSecretNamespace
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationSpec.
-func (in *HostOperationSpec) DeepCopy() *HostOperationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcAccountSpec.
+func (in *BmcAccountSpec) DeepCopy() *BmcAccountSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HostOperationSpec)
+	out := new(BmcAccountSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostOperationStatus) DeepCopyInto(out *HostOperationStatus) {
+func (in *BmcAccountStatus) DeepCopyInto(out *BmcAccountStatus) {
 	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]BmcAccountHostStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationStatus.
-func (in *HostOperationStatus) DeepCopy() *HostOperationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcAccountStatus.
+func (in *BmcAccountStatus) DeepCopy() *BmcAccountStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HostOperationStatus)
+	out := new(BmcAccountStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostStatus) DeepCopyInto(out *HostStatus) {
+func (in *BmcCertificate) DeepCopyInto(out *BmcCertificate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatus.
-func (in *HostStatus) DeepCopy() *HostStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcCertificate.
+func (in *BmcCertificate) DeepCopy() *BmcCertificate {
 	if in == nil {
 		return nil
 	}
-	out := new(HostStatus)
+	out := new(BmcCertificate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostStatus) DeepCopyObject() runtime.Object {
+func (in *BmcCertificate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -299,31 +440,46 @@ func (in *HostStatus) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostStatusList) DeepCopyInto(out *HostStatusList) {
+func (in *BmcCertificateHostStatus) DeepCopyInto(out *BmcCertificateHostStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcCertificateHostStatus.
+func (in *BmcCertificateHostStatus) DeepCopy() *BmcCertificateHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcCertificateHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcCertificateList) DeepCopyInto(out *BmcCertificateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HostStatus, len(*in))
+		*out = make([]BmcCertificate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatusList.
-func (in *HostStatusList) DeepCopy() *HostStatusList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcCertificateList.
+func (in *BmcCertificateList) DeepCopy() *BmcCertificateList {
 	if in == nil {
 		return nil
 	}
-	out := new(HostStatusList)
+	out := new(BmcCertificateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HostStatusList) DeepCopyObject() runtime.Object {
+func (in *BmcCertificateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -331,116 +487,122 @@ func (in *HostStatusList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostStatusStatus) DeepCopyInto(out *HostStatusStatus) {
+func (in *BmcCertificateSpec) DeepCopyInto(out *BmcCertificateSpec) {
 	*out = *in
-	in.Basic.DeepCopyInto(&out.Basic)
-	if in.Info != nil {
-		in, out := &in.Info, &out.Info
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.HostStatusNames != nil {
+		in, out := &in.HostStatusNames, &out.HostStatusNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.Log.DeepCopyInto(&out.Log)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatusStatus.
-func (in *HostStatusStatus) DeepCopy() *HostStatusStatus {
-	if in == nil {
-		return nil
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(HostStatusStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPv4SubnetSpec) DeepCopyInto(out *IPv4SubnetSpec) {
-	*out = *in
-	if in.Gateway != nil {
-		in, out := &in.Gateway, &out.Gateway
-		*out = new(string)
+	if in.AlternativeNames != nil {
+		in, out := &in.AlternativeNames, &out.AlternativeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyBitLength != nil {
+		in, out := &in.KeyBitLength, &out.KeyBitLength
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Dns != nil {
-		in, out := &in.Dns, &out.Dns
-		*out = new(string)
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPv4SubnetSpec.
-func (in *IPv4SubnetSpec) DeepCopy() *IPv4SubnetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcCertificateSpec.
+func (in *BmcCertificateSpec) DeepCopy() *BmcCertificateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IPv4SubnetSpec)
+	out := new(BmcCertificateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterfaceSpec) DeepCopyInto(out *InterfaceSpec) {
+func (in *BmcCertificateStatus) DeepCopyInto(out *BmcCertificateStatus) {
 	*out = *in
-	if in.VlanID != nil {
-		in, out := &in.VlanID, &out.VlanID
-		*out = new(int32)
-		**out = **in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]BmcCertificateHostStatus, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSpec.
-func (in *InterfaceSpec) DeepCopy() *InterfaceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcCertificateStatus.
+func (in *BmcCertificateStatus) DeepCopy() *BmcCertificateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InterfaceSpec)
+	out := new(BmcCertificateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogEntry) DeepCopyInto(out *LogEntry) {
+func (in *BmcNTPSpec) DeepCopyInto(out *BmcNTPSpec) {
 	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogEntry.
-func (in *LogEntry) DeepCopy() *LogEntry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcNTPSpec.
+func (in *BmcNTPSpec) DeepCopy() *BmcNTPSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LogEntry)
+	out := new(BmcNTPSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogStruct) DeepCopyInto(out *LogStruct) {
+func (in *BmcNetworkInterfaceSpec) DeepCopyInto(out *BmcNetworkInterfaceSpec) {
 	*out = *in
-	if in.LastestLog != nil {
-		in, out := &in.LastestLog, &out.LastestLog
-		*out = new(LogEntry)
+	if in.NameServers != nil {
+		in, out := &in.NameServers, &out.NameServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DhcpUseNTPServers != nil {
+		in, out := &in.DhcpUseNTPServers, &out.DhcpUseNTPServers
+		*out = new(bool)
 		**out = **in
 	}
-	if in.LastestWarningLog != nil {
-		in, out := &in.LastestWarningLog, &out.LastestWarningLog
-		*out = new(LogEntry)
+	if in.DhcpUseDNSServers != nil {
+		in, out := &in.DhcpUseDNSServers, &out.DhcpUseDNSServers
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStruct.
-func (in *LogStruct) DeepCopy() *LogStruct {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcNetworkInterfaceSpec.
+func (in *BmcNetworkInterfaceSpec) DeepCopy() *BmcNetworkInterfaceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LogStruct)
+	out := new(BmcNetworkInterfaceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Subnet) DeepCopyInto(out *Subnet) {
+func (in *BmcProfile) DeepCopyInto(out *BmcProfile) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -448,18 +610,18 @@ func (in *Subnet) DeepCopyInto(out *Subnet) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subnet.
-func (in *Subnet) DeepCopy() *Subnet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcProfile.
+func (in *BmcProfile) DeepCopy() *BmcProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(Subnet)
+	out := new(BmcProfile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Subnet) DeepCopyObject() runtime.Object {
+func (in *BmcProfile) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -467,31 +629,56 @@ func (in *Subnet) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubnetList) DeepCopyInto(out *SubnetList) {
+func (in *BmcProfileHostStatus) DeepCopyInto(out *BmcProfileHostStatus) {
+	*out = *in
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CorrectedDrifts != nil {
+		in, out := &in.CorrectedDrifts, &out.CorrectedDrifts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcProfileHostStatus.
+func (in *BmcProfileHostStatus) DeepCopy() *BmcProfileHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcProfileHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcProfileList) DeepCopyInto(out *BmcProfileList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Subnet, len(*in))
+		*out = make([]BmcProfile, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetList.
-func (in *SubnetList) DeepCopy() *SubnetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcProfileList.
+func (in *BmcProfileList) DeepCopy() *BmcProfileList {
 	if in == nil {
 		return nil
 	}
-	out := new(SubnetList)
+	out := new(BmcProfileList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SubnetList) DeepCopyObject() runtime.Object {
+func (in *BmcProfileList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -499,17 +686,1443 @@ func (in *SubnetList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
+func (in *BmcProfileSpec) DeepCopyInto(out *BmcProfileSpec) {
 	*out = *in
-	in.IPv4Subnet.DeepCopyInto(&out.IPv4Subnet)
-	in.Interface.DeepCopyInto(&out.Interface)
-	if in.Feature != nil {
-		in, out := &in.Feature, &out.Feature
-		*out = new(FeatureSpec)
+	if in.NTP != nil {
+		in, out := &in.NTP, &out.NTP
+		*out = new(BmcNTPSpec)
 		(*in).DeepCopyInto(*out)
 	}
-}
-
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(BmcSyslogSpec)
+		**out = **in
+	}
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = new(BmcProtocolsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkInterface != nil {
+		in, out := &in.NetworkInterface, &out.NetworkInterface
+		*out = new(BmcNetworkInterfaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcProfileSpec.
+func (in *BmcProfileSpec) DeepCopy() *BmcProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcProfileStatus) DeepCopyInto(out *BmcProfileStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]BmcProfileHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcProfileStatus.
+func (in *BmcProfileStatus) DeepCopy() *BmcProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcProtocolsSpec) DeepCopyInto(out *BmcProtocolsSpec) {
+	*out = *in
+	if in.IPMI != nil {
+		in, out := &in.IPMI, &out.IPMI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SNMP != nil {
+		in, out := &in.SNMP, &out.SNMP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Telnet != nil {
+		in, out := &in.Telnet, &out.Telnet
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcProtocolsSpec.
+func (in *BmcProtocolsSpec) DeepCopy() *BmcProtocolsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcProtocolsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcResetSpec) DeepCopyInto(out *BmcResetSpec) {
+	*out = *in
+	if in.TimeoutMinutes != nil {
+		in, out := &in.TimeoutMinutes, &out.TimeoutMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcResetSpec.
+func (in *BmcResetSpec) DeepCopy() *BmcResetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcResetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcResetStatus) DeepCopyInto(out *BmcResetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcResetStatus.
+func (in *BmcResetStatus) DeepCopy() *BmcResetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcResetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcSyslogSpec) DeepCopyInto(out *BmcSyslogSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcSyslogSpec.
+func (in *BmcSyslogSpec) DeepCopy() *BmcSyslogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcSyslogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BmcTLSSpec) DeepCopyInto(out *BmcTLSSpec) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(CABundleSource)
+		**out = **in
+	}
+	if in.ServerName != nil {
+		in, out := &in.ServerName, &out.ServerName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BmcTLSSpec.
+func (in *BmcTLSSpec) DeepCopy() *BmcTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BmcTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootInfo) DeepCopyInto(out *BootInfo) {
+	*out = *in
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootInfo.
+func (in *BootInfo) DeepCopy() *BootInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(BootInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootSpec) DeepCopyInto(out *BootSpec) {
+	*out = *in
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootSpec.
+func (in *BootSpec) DeepCopy() *BootSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleSource) DeepCopyInto(out *CABundleSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleSource.
+func (in *CABundleSource) DeepCopy() *CABundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateInfo) DeepCopyInto(out *CertificateInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateInfo.
+func (in *CertificateInfo) DeepCopy() *CertificateInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionSpec) DeepCopyInto(out *DecommissionSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionSpec.
+func (in *DecommissionSpec) DeepCopy() *DecommissionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionStepStatus) DeepCopyInto(out *DecommissionStepStatus) {
+	*out = *in
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]TaskInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionStepStatus.
+func (in *DecommissionStepStatus) DeepCopy() *DecommissionStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DhcpStatusSpec) DeepCopyInto(out *DhcpStatusSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DhcpStatusSpec.
+func (in *DhcpStatusSpec) DeepCopy() *DhcpStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DhcpStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriveInventory) DeepCopyInto(out *DriveInventory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriveInventory.
+func (in *DriveInventory) DeepCopy() *DriveInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(DriveInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnableSyncEndpointSpec) DeepCopyInto(out *EnableSyncEndpointSpec) {
+	*out = *in
+	if in.DefaultClusterName != nil {
+		in, out := &in.DefaultClusterName, &out.DefaultClusterName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnableSyncEndpointSpec.
+func (in *EnableSyncEndpointSpec) DeepCopy() *EnableSyncEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnableSyncEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EthernetInterfaceInventory) DeepCopyInto(out *EthernetInterfaceInventory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EthernetInterfaceInventory.
+func (in *EthernetInterfaceInventory) DeepCopy() *EthernetInterfaceInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(EthernetInterfaceInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventSubscriptionInfo) DeepCopyInto(out *EventSubscriptionInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventSubscriptionInfo.
+func (in *EventSubscriptionInfo) DeepCopy() *EventSubscriptionInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(EventSubscriptionInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
+	*out = *in
+	if in.EnableSyncEndpoint != nil {
+		in, out := &in.EnableSyncEndpoint, &out.EnableSyncEndpoint
+		*out = new(EnableSyncEndpointSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpec.
+func (in *FeatureSpec) DeepCopy() *FeatureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareInventory) DeepCopyInto(out *FirmwareInventory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareInventory.
+func (in *FirmwareInventory) DeepCopy() *FirmwareInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateRecord) DeepCopyInto(out *FirmwareUpdateRecord) {
+	*out = *in
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = make([]FirmwareInventory, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateRecord.
+func (in *FirmwareUpdateRecord) DeepCopy() *FirmwareUpdateRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirmwareUpdateSpec) DeepCopyInto(out *FirmwareUpdateSpec) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirmwareUpdateSpec.
+func (in *FirmwareUpdateSpec) DeepCopy() *FirmwareUpdateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FirmwareUpdateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostInventory) DeepCopyInto(out *HostInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostInventory.
+func (in *HostInventory) DeepCopy() *HostInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(HostInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostInventoryList) DeepCopyInto(out *HostInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostInventoryList.
+func (in *HostInventoryList) DeepCopy() *HostInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostInventoryStatus) DeepCopyInto(out *HostInventoryStatus) {
+	*out = *in
+	if in.Systems != nil {
+		in, out := &in.Systems, &out.Systems
+		*out = make([]SystemInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = make([]FirmwareInventory, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostInventoryStatus.
+func (in *HostInventoryStatus) DeepCopy() *HostInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostOperation) DeepCopyInto(out *HostOperation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperation.
+func (in *HostOperation) DeepCopy() *HostOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(HostOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostOperationList) DeepCopyInto(out *HostOperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationList.
+func (in *HostOperationList) DeepCopy() *HostOperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostOperationSpec) DeepCopyInto(out *HostOperationSpec) {
+	*out = *in
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(FirmwareUpdateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VirtualMedia != nil {
+		in, out := &in.VirtualMedia, &out.VirtualMedia
+		*out = new(VirtualMediaBootSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Boot != nil {
+		in, out := &in.Boot, &out.Boot
+		*out = new(BootSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(DecommissionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndicatorLED != nil {
+		in, out := &in.IndicatorLED, &out.IndicatorLED
+		*out = new(IndicatorLEDSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BmcReset != nil {
+		in, out := &in.BmcReset, &out.BmcReset
+		*out = new(BmcResetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationSpec.
+func (in *HostOperationSpec) DeepCopy() *HostOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostOperationStatus) DeepCopyInto(out *HostOperationStatus) {
+	*out = *in
+	if in.Task != nil {
+		in, out := &in.Task, &out.Task
+		*out = new(TaskInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FirmwareVersions != nil {
+		in, out := &in.FirmwareVersions, &out.FirmwareVersions
+		*out = make([]FirmwareInventory, len(*in))
+		copy(*out, *in)
+	}
+	if in.VirtualMedia != nil {
+		in, out := &in.VirtualMedia, &out.VirtualMedia
+		*out = new(VirtualMediaStatus)
+		**out = **in
+	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = make([]DecommissionStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IndicatorLED != nil {
+		in, out := &in.IndicatorLED, &out.IndicatorLED
+		*out = new(IndicatorLEDStatus)
+		**out = **in
+	}
+	if in.BmcReset != nil {
+		in, out := &in.BmcReset, &out.BmcReset
+		*out = new(BmcResetStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostOperationStatus.
+func (in *HostOperationStatus) DeepCopy() *HostOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostStatus) DeepCopyInto(out *HostStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatus.
+func (in *HostStatus) DeepCopy() *HostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostStatusList) DeepCopyInto(out *HostStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatusList.
+func (in *HostStatusList) DeepCopy() *HostStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(HostStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HostStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostStatusStatus) DeepCopyInto(out *HostStatusStatus) {
+	*out = *in
+	in.Basic.DeepCopyInto(&out.Basic)
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Systems != nil {
+		in, out := &in.Systems, &out.Systems
+		*out = make([]SystemInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]ManagerInfo, len(*in))
+		copy(*out, *in)
+	}
+	in.Log.DeepCopyInto(&out.Log)
+	if in.LastFirmwareUpdate != nil {
+		in, out := &in.LastFirmwareUpdate, &out.LastFirmwareUpdate
+		*out = new(FirmwareUpdateRecord)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventSubscription != nil {
+		in, out := &in.EventSubscription, &out.EventSubscription
+		*out = new(EventSubscriptionInfo)
+		**out = **in
+	}
+	if in.SnmpTrap != nil {
+		in, out := &in.SnmpTrap, &out.SnmpTrap
+		*out = new(SnmpTrapInfo)
+		**out = **in
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(CertificateInfo)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatusStatus.
+func (in *HostStatusStatus) DeepCopy() *HostStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPv4SubnetSpec) DeepCopyInto(out *IPv4SubnetSpec) {
+	*out = *in
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(string)
+		**out = **in
+	}
+	if in.Dns != nil {
+		in, out := &in.Dns, &out.Dns
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPv4SubnetSpec.
+func (in *IPv4SubnetSpec) DeepCopy() *IPv4SubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPv4SubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndicatorLEDSpec) DeepCopyInto(out *IndicatorLEDSpec) {
+	*out = *in
+	if in.RevertAfterMinutes != nil {
+		in, out := &in.RevertAfterMinutes, &out.RevertAfterMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndicatorLEDSpec.
+func (in *IndicatorLEDSpec) DeepCopy() *IndicatorLEDSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndicatorLEDSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndicatorLEDStatus) DeepCopyInto(out *IndicatorLEDStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndicatorLEDStatus.
+func (in *IndicatorLEDStatus) DeepCopy() *IndicatorLEDStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndicatorLEDStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceSpec) DeepCopyInto(out *InterfaceSpec) {
+	*out = *in
+	if in.VlanID != nil {
+		in, out := &in.VlanID, &out.VlanID
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSpec.
+func (in *InterfaceSpec) DeepCopy() *InterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LLDPNeighbor) DeepCopyInto(out *LLDPNeighbor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLDPNeighbor.
+func (in *LLDPNeighbor) DeepCopy() *LLDPNeighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(LLDPNeighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogEntry) DeepCopyInto(out *LogEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogEntry.
+func (in *LogEntry) DeepCopy() *LogEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(LogEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStruct) DeepCopyInto(out *LogStruct) {
+	*out = *in
+	if in.LastestLog != nil {
+		in, out := &in.LastestLog, &out.LastestLog
+		*out = new(LogEntry)
+		**out = **in
+	}
+	if in.LastestWarningLog != nil {
+		in, out := &in.LastestWarningLog, &out.LastestWarningLog
+		*out = new(LogEntry)
+		**out = **in
+	}
+	if in.LastestEvent != nil {
+		in, out := &in.LastestEvent, &out.LastestEvent
+		*out = new(LogEntry)
+		**out = **in
+	}
+	if in.LastestWarningEvent != nil {
+		in, out := &in.LastestWarningEvent, &out.LastestWarningEvent
+		*out = new(LogEntry)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStruct.
+func (in *LogStruct) DeepCopy() *LogStruct {
+	if in == nil {
+		return nil
+	}
+	out := new(LogStruct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerInfo) DeepCopyInto(out *ManagerInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerInfo.
+func (in *ManagerInfo) DeepCopy() *ManagerInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryInventory) DeepCopyInto(out *MemoryInventory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryInventory.
+func (in *MemoryInventory) DeepCopy() *MemoryInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAdapterInventory) DeepCopyInto(out *NetworkAdapterInventory) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]NetworkPortInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAdapterInventory.
+func (in *NetworkAdapterInventory) DeepCopy() *NetworkAdapterInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAdapterInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPortInventory) DeepCopyInto(out *NetworkPortInventory) {
+	*out = *in
+	if in.MACAddresses != nil {
+		in, out := &in.MACAddresses, &out.MACAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LLDP != nil {
+		in, out := &in.LLDP, &out.LLDP
+		*out = new(LLDPNeighbor)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPortInventory.
+func (in *NetworkPortInventory) DeepCopy() *NetworkPortInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPortInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PCIeDeviceInventory) DeepCopyInto(out *PCIeDeviceInventory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PCIeDeviceInventory.
+func (in *PCIeDeviceInventory) DeepCopy() *PCIeDeviceInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(PCIeDeviceInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerCapConfig) DeepCopyInto(out *PowerCapConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerCapConfig.
+func (in *PowerCapConfig) DeepCopy() *PowerCapConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerCapConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PowerCapConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerCapConfigList) DeepCopyInto(out *PowerCapConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PowerCapConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerCapConfigList.
+func (in *PowerCapConfigList) DeepCopy() *PowerCapConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerCapConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PowerCapConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerCapConfigSpec) DeepCopyInto(out *PowerCapConfigSpec) {
+	*out = *in
+	if in.HostStatusNames != nil {
+		in, out := &in.HostStatusNames, &out.HostStatusNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChassisIds != nil {
+		in, out := &in.ChassisIds, &out.ChassisIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CorrectionInMs != nil {
+		in, out := &in.CorrectionInMs, &out.CorrectionInMs
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerCapConfigSpec.
+func (in *PowerCapConfigSpec) DeepCopy() *PowerCapConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerCapConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerCapConfigStatus) DeepCopyInto(out *PowerCapConfigStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]PowerCapHostStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerCapConfigStatus.
+func (in *PowerCapConfigStatus) DeepCopy() *PowerCapConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerCapConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerCapHostStatus) DeepCopyInto(out *PowerCapHostStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerCapHostStatus.
+func (in *PowerCapHostStatus) DeepCopy() *PowerCapHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerCapHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorInventory) DeepCopyInto(out *ProcessorInventory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorInventory.
+func (in *ProcessorInventory) DeepCopy() *ProcessorInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnmpTrapInfo) DeepCopyInto(out *SnmpTrapInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnmpTrapInfo.
+func (in *SnmpTrapInfo) DeepCopy() *SnmpTrapInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(SnmpTrapInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
+func (in *StorageConfig) DeepCopy() *StorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfigList) DeepCopyInto(out *StorageConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StorageConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfigList.
+func (in *StorageConfigList) DeepCopy() *StorageConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfigSpec) DeepCopyInto(out *StorageConfigSpec) {
+	*out = *in
+	if in.HostStatusNames != nil {
+		in, out := &in.HostStatusNames, &out.HostStatusNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfigSpec.
+func (in *StorageConfigSpec) DeepCopy() *StorageConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfigStatus) DeepCopyInto(out *StorageConfigStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]StorageHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfigStatus.
+func (in *StorageConfigStatus) DeepCopy() *StorageConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageHostStatus) DeepCopyInto(out *StorageHostStatus) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]VolumeDrift, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageHostStatus.
+func (in *StorageHostStatus) DeepCopy() *StorageHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageInventory) DeepCopyInto(out *StorageInventory) {
+	*out = *in
+	if in.SupportedRAIDTypes != nil {
+		in, out := &in.SupportedRAIDTypes, &out.SupportedRAIDTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drives != nil {
+		in, out := &in.Drives, &out.Drives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VolumeInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageInventory.
+func (in *StorageInventory) DeepCopy() *StorageInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subnet) DeepCopyInto(out *Subnet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subnet.
+func (in *Subnet) DeepCopy() *Subnet {
+	if in == nil {
+		return nil
+	}
+	out := new(Subnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Subnet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetList) DeepCopyInto(out *SubnetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Subnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetList.
+func (in *SubnetList) DeepCopy() *SubnetList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubnetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
+	*out = *in
+	in.IPv4Subnet.DeepCopyInto(&out.IPv4Subnet)
+	in.Interface.DeepCopyInto(&out.Interface)
+	if in.Feature != nil {
+		in, out := &in.Feature, &out.Feature
+		*out = new(FeatureSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BmcTLS != nil {
+		in, out := &in.BmcTLS, &out.BmcTLS
+		*out = new(BmcTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BmcProfileName != nil {
+		in, out := &in.BmcProfileName, &out.BmcProfileName
+		*out = new(string)
+		**out = **in
+	}
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
 func (in *SubnetSpec) DeepCopy() *SubnetSpec {
 	if in == nil {
@@ -551,3 +2164,207 @@ func (in *SubnetStatus) DeepCopy() *SubnetStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemInfo) DeepCopyInto(out *SystemInfo) {
+	*out = *in
+	if in.SupportedResetTypes != nil {
+		in, out := &in.SupportedResetTypes, &out.SupportedResetTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedBy != nil {
+		in, out := &in.ManagedBy, &out.ManagedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Boot != nil {
+		in, out := &in.Boot, &out.Boot
+		*out = new(BootInfo)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemInfo.
+func (in *SystemInfo) DeepCopy() *SystemInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemInventory) DeepCopyInto(out *SystemInventory) {
+	*out = *in
+	if in.Processors != nil {
+		in, out := &in.Processors, &out.Processors
+		*out = make([]ProcessorInventory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = make([]MemoryInventory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drives != nil {
+		in, out := &in.Drives, &out.Drives
+		*out = make([]DriveInventory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = make([]StorageInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PCIeDevices != nil {
+		in, out := &in.PCIeDevices, &out.PCIeDevices
+		*out = make([]PCIeDeviceInventory, len(*in))
+		copy(*out, *in)
+	}
+	if in.EthernetInterfaces != nil {
+		in, out := &in.EthernetInterfaces, &out.EthernetInterfaces
+		*out = make([]EthernetInterfaceInventory, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkAdapters != nil {
+		in, out := &in.NetworkAdapters, &out.NetworkAdapters
+		*out = make([]NetworkAdapterInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemInventory.
+func (in *SystemInventory) DeepCopy() *SystemInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskInfo) DeepCopyInto(out *TaskInfo) {
+	*out = *in
+	if in.Messages != nil {
+		in, out := &in.Messages, &out.Messages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskInfo.
+func (in *TaskInfo) DeepCopy() *TaskInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMediaBootSpec) DeepCopyInto(out *VirtualMediaBootSpec) {
+	*out = *in
+	if in.EjectAfterMinutes != nil {
+		in, out := &in.EjectAfterMinutes, &out.EjectAfterMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMediaBootSpec.
+func (in *VirtualMediaBootSpec) DeepCopy() *VirtualMediaBootSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMediaBootSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMediaStatus) DeepCopyInto(out *VirtualMediaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMediaStatus.
+func (in *VirtualMediaStatus) DeepCopy() *VirtualMediaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMediaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeDrift) DeepCopyInto(out *VolumeDrift) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeDrift.
+func (in *VolumeDrift) DeepCopy() *VolumeDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeInventory) DeepCopyInto(out *VolumeInventory) {
+	*out = *in
+	if in.Drives != nil {
+		in, out := &in.Drives, &out.Drives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeInventory.
+func (in *VolumeInventory) DeepCopy() *VolumeInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
+	*out = *in
+	if in.Drives != nil {
+		in, out := &in.Drives, &out.Drives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CapacityBytes != nil {
+		in, out := &in.CapacityBytes, &out.CapacityBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
+func (in *VolumeSpec) DeepCopy() *VolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
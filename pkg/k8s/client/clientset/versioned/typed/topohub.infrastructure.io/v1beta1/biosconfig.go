@@ -0,0 +1,59 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	context "context"
+
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	scheme "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// BiosConfigsGetter has a method to return a BiosConfigInterface.
+// A group's client should implement this interface.
+type BiosConfigsGetter interface {
+	BiosConfigs() BiosConfigInterface
+}
+
+// BiosConfigInterface has methods to work with BiosConfig resources.
+type BiosConfigInterface interface {
+	Create(ctx context.Context, biosConfig *topohubinfrastructureiov1beta1.BiosConfig, opts v1.CreateOptions) (*topohubinfrastructureiov1beta1.BiosConfig, error)
+	Update(ctx context.Context, biosConfig *topohubinfrastructureiov1beta1.BiosConfig, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BiosConfig, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, biosConfig *topohubinfrastructureiov1beta1.BiosConfig, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BiosConfig, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*topohubinfrastructureiov1beta1.BiosConfig, error)
+	List(ctx context.Context, opts v1.ListOptions) (*topohubinfrastructureiov1beta1.BiosConfigList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *topohubinfrastructureiov1beta1.BiosConfig, err error)
+	BiosConfigExpansion
+}
+
+// biosConfigs implements BiosConfigInterface
+type biosConfigs struct {
+	*gentype.ClientWithList[*topohubinfrastructureiov1beta1.BiosConfig, *topohubinfrastructureiov1beta1.BiosConfigList]
+}
+
+// newBiosConfigs returns a BiosConfigs
+func newBiosConfigs(c *TopohubV1beta1Client) *biosConfigs {
+	return &biosConfigs{
+		gentype.NewClientWithList[*topohubinfrastructureiov1beta1.BiosConfig, *topohubinfrastructureiov1beta1.BiosConfigList](
+			"biosconfigs",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *topohubinfrastructureiov1beta1.BiosConfig { return &topohubinfrastructureiov1beta1.BiosConfig{} },
+			func() *topohubinfrastructureiov1beta1.BiosConfigList {
+				return &topohubinfrastructureiov1beta1.BiosConfigList{}
+			},
+		),
+	}
+}
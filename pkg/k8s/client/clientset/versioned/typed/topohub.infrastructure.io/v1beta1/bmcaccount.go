@@ -0,0 +1,59 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	context "context"
+
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	scheme "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// BmcAccountsGetter has a method to return a BmcAccountInterface.
+// A group's client should implement this interface.
+type BmcAccountsGetter interface {
+	BmcAccounts() BmcAccountInterface
+}
+
+// BmcAccountInterface has methods to work with BmcAccount resources.
+type BmcAccountInterface interface {
+	Create(ctx context.Context, bmcAccount *topohubinfrastructureiov1beta1.BmcAccount, opts v1.CreateOptions) (*topohubinfrastructureiov1beta1.BmcAccount, error)
+	Update(ctx context.Context, bmcAccount *topohubinfrastructureiov1beta1.BmcAccount, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BmcAccount, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, bmcAccount *topohubinfrastructureiov1beta1.BmcAccount, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BmcAccount, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*topohubinfrastructureiov1beta1.BmcAccount, error)
+	List(ctx context.Context, opts v1.ListOptions) (*topohubinfrastructureiov1beta1.BmcAccountList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *topohubinfrastructureiov1beta1.BmcAccount, err error)
+	BmcAccountExpansion
+}
+
+// bmcAccounts implements BmcAccountInterface
+type bmcAccounts struct {
+	*gentype.ClientWithList[*topohubinfrastructureiov1beta1.BmcAccount, *topohubinfrastructureiov1beta1.BmcAccountList]
+}
+
+// newBmcAccounts returns a BmcAccounts
+func newBmcAccounts(c *TopohubV1beta1Client) *bmcAccounts {
+	return &bmcAccounts{
+		gentype.NewClientWithList[*topohubinfrastructureiov1beta1.BmcAccount, *topohubinfrastructureiov1beta1.BmcAccountList](
+			"bmcaccounts",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *topohubinfrastructureiov1beta1.BmcAccount { return &topohubinfrastructureiov1beta1.BmcAccount{} },
+			func() *topohubinfrastructureiov1beta1.BmcAccountList {
+				return &topohubinfrastructureiov1beta1.BmcAccountList{}
+			},
+		),
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	context "context"
+
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	scheme "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// BmcCertificatesGetter has a method to return a BmcCertificateInterface.
+// A group's client should implement this interface.
+type BmcCertificatesGetter interface {
+	BmcCertificates() BmcCertificateInterface
+}
+
+// BmcCertificateInterface has methods to work with BmcCertificate resources.
+type BmcCertificateInterface interface {
+	Create(ctx context.Context, bmcCertificate *topohubinfrastructureiov1beta1.BmcCertificate, opts v1.CreateOptions) (*topohubinfrastructureiov1beta1.BmcCertificate, error)
+	Update(ctx context.Context, bmcCertificate *topohubinfrastructureiov1beta1.BmcCertificate, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BmcCertificate, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, bmcCertificate *topohubinfrastructureiov1beta1.BmcCertificate, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BmcCertificate, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*topohubinfrastructureiov1beta1.BmcCertificate, error)
+	List(ctx context.Context, opts v1.ListOptions) (*topohubinfrastructureiov1beta1.BmcCertificateList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *topohubinfrastructureiov1beta1.BmcCertificate, err error)
+	BmcCertificateExpansion
+}
+
+// bmcCertificates implements BmcCertificateInterface
+type bmcCertificates struct {
+	*gentype.ClientWithList[*topohubinfrastructureiov1beta1.BmcCertificate, *topohubinfrastructureiov1beta1.BmcCertificateList]
+}
+
+// newBmcCertificates returns a BmcCertificates
+func newBmcCertificates(c *TopohubV1beta1Client) *bmcCertificates {
+	return &bmcCertificates{
+		gentype.NewClientWithList[*topohubinfrastructureiov1beta1.BmcCertificate, *topohubinfrastructureiov1beta1.BmcCertificateList](
+			"bmccertificates",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *topohubinfrastructureiov1beta1.BmcCertificate {
+				return &topohubinfrastructureiov1beta1.BmcCertificate{}
+			},
+			func() *topohubinfrastructureiov1beta1.BmcCertificateList {
+				return &topohubinfrastructureiov1beta1.BmcCertificateList{}
+			},
+		),
+	}
+}
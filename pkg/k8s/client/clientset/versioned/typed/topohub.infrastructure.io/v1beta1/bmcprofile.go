@@ -0,0 +1,59 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	context "context"
+
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	scheme "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// BmcProfilesGetter has a method to return a BmcProfileInterface.
+// A group's client should implement this interface.
+type BmcProfilesGetter interface {
+	BmcProfiles() BmcProfileInterface
+}
+
+// BmcProfileInterface has methods to work with BmcProfile resources.
+type BmcProfileInterface interface {
+	Create(ctx context.Context, bmcProfile *topohubinfrastructureiov1beta1.BmcProfile, opts v1.CreateOptions) (*topohubinfrastructureiov1beta1.BmcProfile, error)
+	Update(ctx context.Context, bmcProfile *topohubinfrastructureiov1beta1.BmcProfile, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BmcProfile, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, bmcProfile *topohubinfrastructureiov1beta1.BmcProfile, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.BmcProfile, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*topohubinfrastructureiov1beta1.BmcProfile, error)
+	List(ctx context.Context, opts v1.ListOptions) (*topohubinfrastructureiov1beta1.BmcProfileList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *topohubinfrastructureiov1beta1.BmcProfile, err error)
+	BmcProfileExpansion
+}
+
+// bmcProfiles implements BmcProfileInterface
+type bmcProfiles struct {
+	*gentype.ClientWithList[*topohubinfrastructureiov1beta1.BmcProfile, *topohubinfrastructureiov1beta1.BmcProfileList]
+}
+
+// newBmcProfiles returns a BmcProfiles
+func newBmcProfiles(c *TopohubV1beta1Client) *bmcProfiles {
+	return &bmcProfiles{
+		gentype.NewClientWithList[*topohubinfrastructureiov1beta1.BmcProfile, *topohubinfrastructureiov1beta1.BmcProfileList](
+			"bmcprofiles",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *topohubinfrastructureiov1beta1.BmcProfile { return &topohubinfrastructureiov1beta1.BmcProfile{} },
+			func() *topohubinfrastructureiov1beta1.BmcProfileList {
+				return &topohubinfrastructureiov1beta1.BmcProfileList{}
+			},
+		),
+	}
+}
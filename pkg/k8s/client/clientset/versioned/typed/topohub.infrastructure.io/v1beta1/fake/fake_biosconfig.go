@@ -0,0 +1,37 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeBiosConfigs implements BiosConfigInterface
+type fakeBiosConfigs struct {
+	*gentype.FakeClientWithList[*v1beta1.BiosConfig, *v1beta1.BiosConfigList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakeBiosConfigs(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.BiosConfigInterface {
+	return &fakeBiosConfigs{
+		gentype.NewFakeClientWithList[*v1beta1.BiosConfig, *v1beta1.BiosConfigList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("biosconfigs"),
+			v1beta1.SchemeGroupVersion.WithKind("BiosConfig"),
+			func() *v1beta1.BiosConfig { return &v1beta1.BiosConfig{} },
+			func() *v1beta1.BiosConfigList { return &v1beta1.BiosConfigList{} },
+			func(dst, src *v1beta1.BiosConfigList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.BiosConfigList) []*v1beta1.BiosConfig { return gentype.ToPointerSlice(list.Items) },
+			func(list *v1beta1.BiosConfigList, items []*v1beta1.BiosConfig) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
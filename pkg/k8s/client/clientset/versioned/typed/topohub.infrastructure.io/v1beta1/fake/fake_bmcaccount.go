@@ -0,0 +1,37 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeBmcAccounts implements BmcAccountInterface
+type fakeBmcAccounts struct {
+	*gentype.FakeClientWithList[*v1beta1.BmcAccount, *v1beta1.BmcAccountList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakeBmcAccounts(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.BmcAccountInterface {
+	return &fakeBmcAccounts{
+		gentype.NewFakeClientWithList[*v1beta1.BmcAccount, *v1beta1.BmcAccountList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("bmcaccounts"),
+			v1beta1.SchemeGroupVersion.WithKind("BmcAccount"),
+			func() *v1beta1.BmcAccount { return &v1beta1.BmcAccount{} },
+			func() *v1beta1.BmcAccountList { return &v1beta1.BmcAccountList{} },
+			func(dst, src *v1beta1.BmcAccountList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.BmcAccountList) []*v1beta1.BmcAccount { return gentype.ToPointerSlice(list.Items) },
+			func(list *v1beta1.BmcAccountList, items []*v1beta1.BmcAccount) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
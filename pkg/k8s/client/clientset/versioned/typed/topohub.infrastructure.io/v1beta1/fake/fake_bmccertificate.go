@@ -0,0 +1,39 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeBmcCertificates implements BmcCertificateInterface
+type fakeBmcCertificates struct {
+	*gentype.FakeClientWithList[*v1beta1.BmcCertificate, *v1beta1.BmcCertificateList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakeBmcCertificates(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.BmcCertificateInterface {
+	return &fakeBmcCertificates{
+		gentype.NewFakeClientWithList[*v1beta1.BmcCertificate, *v1beta1.BmcCertificateList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("bmccertificates"),
+			v1beta1.SchemeGroupVersion.WithKind("BmcCertificate"),
+			func() *v1beta1.BmcCertificate { return &v1beta1.BmcCertificate{} },
+			func() *v1beta1.BmcCertificateList { return &v1beta1.BmcCertificateList{} },
+			func(dst, src *v1beta1.BmcCertificateList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.BmcCertificateList) []*v1beta1.BmcCertificate {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1beta1.BmcCertificateList, items []*v1beta1.BmcCertificate) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
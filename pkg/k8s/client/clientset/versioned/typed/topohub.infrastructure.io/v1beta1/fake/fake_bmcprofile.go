@@ -0,0 +1,37 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeBmcProfiles implements BmcProfileInterface
+type fakeBmcProfiles struct {
+	*gentype.FakeClientWithList[*v1beta1.BmcProfile, *v1beta1.BmcProfileList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakeBmcProfiles(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.BmcProfileInterface {
+	return &fakeBmcProfiles{
+		gentype.NewFakeClientWithList[*v1beta1.BmcProfile, *v1beta1.BmcProfileList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("bmcprofiles"),
+			v1beta1.SchemeGroupVersion.WithKind("BmcProfile"),
+			func() *v1beta1.BmcProfile { return &v1beta1.BmcProfile{} },
+			func() *v1beta1.BmcProfileList { return &v1beta1.BmcProfileList{} },
+			func(dst, src *v1beta1.BmcProfileList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.BmcProfileList) []*v1beta1.BmcProfile { return gentype.ToPointerSlice(list.Items) },
+			func(list *v1beta1.BmcProfileList, items []*v1beta1.BmcProfile) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
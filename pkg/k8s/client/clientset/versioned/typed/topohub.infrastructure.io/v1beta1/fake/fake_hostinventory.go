@@ -0,0 +1,39 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeHostInventories implements HostInventoryInterface
+type fakeHostInventories struct {
+	*gentype.FakeClientWithList[*v1beta1.HostInventory, *v1beta1.HostInventoryList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakeHostInventories(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.HostInventoryInterface {
+	return &fakeHostInventories{
+		gentype.NewFakeClientWithList[*v1beta1.HostInventory, *v1beta1.HostInventoryList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("hostinventories"),
+			v1beta1.SchemeGroupVersion.WithKind("HostInventory"),
+			func() *v1beta1.HostInventory { return &v1beta1.HostInventory{} },
+			func() *v1beta1.HostInventoryList { return &v1beta1.HostInventoryList{} },
+			func(dst, src *v1beta1.HostInventoryList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.HostInventoryList) []*v1beta1.HostInventory {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1beta1.HostInventoryList, items []*v1beta1.HostInventory) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakePowerCapConfigs implements PowerCapConfigInterface
+type fakePowerCapConfigs struct {
+	*gentype.FakeClientWithList[*v1beta1.PowerCapConfig, *v1beta1.PowerCapConfigList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakePowerCapConfigs(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.PowerCapConfigInterface {
+	return &fakePowerCapConfigs{
+		gentype.NewFakeClientWithList[*v1beta1.PowerCapConfig, *v1beta1.PowerCapConfigList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("powercapconfigs"),
+			v1beta1.SchemeGroupVersion.WithKind("PowerCapConfig"),
+			func() *v1beta1.PowerCapConfig { return &v1beta1.PowerCapConfig{} },
+			func() *v1beta1.PowerCapConfigList { return &v1beta1.PowerCapConfigList{} },
+			func(dst, src *v1beta1.PowerCapConfigList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.PowerCapConfigList) []*v1beta1.PowerCapConfig {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1beta1.PowerCapConfigList, items []*v1beta1.PowerCapConfig) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
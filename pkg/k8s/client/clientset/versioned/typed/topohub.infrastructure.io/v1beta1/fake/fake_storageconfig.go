@@ -0,0 +1,39 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/typed/topohub.infrastructure.io/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeStorageConfigs implements StorageConfigInterface
+type fakeStorageConfigs struct {
+	*gentype.FakeClientWithList[*v1beta1.StorageConfig, *v1beta1.StorageConfigList]
+	Fake *FakeTopohubV1beta1
+}
+
+func newFakeStorageConfigs(fake *FakeTopohubV1beta1) topohubinfrastructureiov1beta1.StorageConfigInterface {
+	return &fakeStorageConfigs{
+		gentype.NewFakeClientWithList[*v1beta1.StorageConfig, *v1beta1.StorageConfigList](
+			fake.Fake,
+			"",
+			v1beta1.SchemeGroupVersion.WithResource("storageconfigs"),
+			v1beta1.SchemeGroupVersion.WithKind("StorageConfig"),
+			func() *v1beta1.StorageConfig { return &v1beta1.StorageConfig{} },
+			func() *v1beta1.StorageConfigList { return &v1beta1.StorageConfigList{} },
+			func(dst, src *v1beta1.StorageConfigList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.StorageConfigList) []*v1beta1.StorageConfig {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1beta1.StorageConfigList, items []*v1beta1.StorageConfig) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
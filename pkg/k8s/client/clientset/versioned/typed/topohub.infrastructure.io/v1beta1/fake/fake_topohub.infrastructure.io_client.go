@@ -19,10 +19,30 @@ func (c *FakeTopohubV1beta1) BindingIps() v1beta1.BindingIpInterface {
 	return newFakeBindingIps(c)
 }
 
+func (c *FakeTopohubV1beta1) BiosConfigs() v1beta1.BiosConfigInterface {
+	return newFakeBiosConfigs(c)
+}
+
+func (c *FakeTopohubV1beta1) BmcAccounts() v1beta1.BmcAccountInterface {
+	return newFakeBmcAccounts(c)
+}
+
+func (c *FakeTopohubV1beta1) BmcCertificates() v1beta1.BmcCertificateInterface {
+	return newFakeBmcCertificates(c)
+}
+
+func (c *FakeTopohubV1beta1) BmcProfiles() v1beta1.BmcProfileInterface {
+	return newFakeBmcProfiles(c)
+}
+
 func (c *FakeTopohubV1beta1) HostEndpoints() v1beta1.HostEndpointInterface {
 	return newFakeHostEndpoints(c)
 }
 
+func (c *FakeTopohubV1beta1) HostInventories() v1beta1.HostInventoryInterface {
+	return newFakeHostInventories(c)
+}
+
 func (c *FakeTopohubV1beta1) HostOperations() v1beta1.HostOperationInterface {
 	return newFakeHostOperations(c)
 }
@@ -31,6 +51,14 @@ func (c *FakeTopohubV1beta1) HostStatuses() v1beta1.HostStatusInterface {
 	return newFakeHostStatuses(c)
 }
 
+func (c *FakeTopohubV1beta1) PowerCapConfigs() v1beta1.PowerCapConfigInterface {
+	return newFakePowerCapConfigs(c)
+}
+
+func (c *FakeTopohubV1beta1) StorageConfigs() v1beta1.StorageConfigInterface {
+	return newFakeStorageConfigs(c)
+}
+
 func (c *FakeTopohubV1beta1) Subnets() v1beta1.SubnetInterface {
 	return newFakeSubnets(c)
 }
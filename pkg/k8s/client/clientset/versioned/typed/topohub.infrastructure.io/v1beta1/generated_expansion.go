@@ -7,10 +7,24 @@ package v1beta1
 
 type BindingIpExpansion interface{}
 
+type BiosConfigExpansion interface{}
+
+type BmcAccountExpansion interface{}
+
+type BmcCertificateExpansion interface{}
+
+type BmcProfileExpansion interface{}
+
 type HostEndpointExpansion interface{}
 
+type HostInventoryExpansion interface{}
+
 type HostOperationExpansion interface{}
 
 type HostStatusExpansion interface{}
 
+type PowerCapConfigExpansion interface{}
+
+type StorageConfigExpansion interface{}
+
 type SubnetExpansion interface{}
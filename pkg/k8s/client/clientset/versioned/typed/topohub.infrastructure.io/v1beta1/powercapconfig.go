@@ -0,0 +1,61 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	context "context"
+
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	scheme "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// PowerCapConfigsGetter has a method to return a PowerCapConfigInterface.
+// A group's client should implement this interface.
+type PowerCapConfigsGetter interface {
+	PowerCapConfigs() PowerCapConfigInterface
+}
+
+// PowerCapConfigInterface has methods to work with PowerCapConfig resources.
+type PowerCapConfigInterface interface {
+	Create(ctx context.Context, powerCapConfig *topohubinfrastructureiov1beta1.PowerCapConfig, opts v1.CreateOptions) (*topohubinfrastructureiov1beta1.PowerCapConfig, error)
+	Update(ctx context.Context, powerCapConfig *topohubinfrastructureiov1beta1.PowerCapConfig, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.PowerCapConfig, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, powerCapConfig *topohubinfrastructureiov1beta1.PowerCapConfig, opts v1.UpdateOptions) (*topohubinfrastructureiov1beta1.PowerCapConfig, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*topohubinfrastructureiov1beta1.PowerCapConfig, error)
+	List(ctx context.Context, opts v1.ListOptions) (*topohubinfrastructureiov1beta1.PowerCapConfigList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *topohubinfrastructureiov1beta1.PowerCapConfig, err error)
+	PowerCapConfigExpansion
+}
+
+// powerCapConfigs implements PowerCapConfigInterface
+type powerCapConfigs struct {
+	*gentype.ClientWithList[*topohubinfrastructureiov1beta1.PowerCapConfig, *topohubinfrastructureiov1beta1.PowerCapConfigList]
+}
+
+// newPowerCapConfigs returns a PowerCapConfigs
+func newPowerCapConfigs(c *TopohubV1beta1Client) *powerCapConfigs {
+	return &powerCapConfigs{
+		gentype.NewClientWithList[*topohubinfrastructureiov1beta1.PowerCapConfig, *topohubinfrastructureiov1beta1.PowerCapConfigList](
+			"powercapconfigs",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *topohubinfrastructureiov1beta1.PowerCapConfig {
+				return &topohubinfrastructureiov1beta1.PowerCapConfig{}
+			},
+			func() *topohubinfrastructureiov1beta1.PowerCapConfigList {
+				return &topohubinfrastructureiov1beta1.PowerCapConfigList{}
+			},
+		),
+	}
+}
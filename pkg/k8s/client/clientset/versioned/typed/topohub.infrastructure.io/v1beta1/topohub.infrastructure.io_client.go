@@ -16,9 +16,16 @@ import (
 type TopohubV1beta1Interface interface {
 	RESTClient() rest.Interface
 	BindingIpsGetter
+	BiosConfigsGetter
+	BmcAccountsGetter
+	BmcCertificatesGetter
+	BmcProfilesGetter
 	HostEndpointsGetter
+	HostInventoriesGetter
 	HostOperationsGetter
 	HostStatusesGetter
+	PowerCapConfigsGetter
+	StorageConfigsGetter
 	SubnetsGetter
 }
 
@@ -31,10 +38,30 @@ func (c *TopohubV1beta1Client) BindingIps() BindingIpInterface {
 	return newBindingIps(c)
 }
 
+func (c *TopohubV1beta1Client) BiosConfigs() BiosConfigInterface {
+	return newBiosConfigs(c)
+}
+
+func (c *TopohubV1beta1Client) BmcAccounts() BmcAccountInterface {
+	return newBmcAccounts(c)
+}
+
+func (c *TopohubV1beta1Client) BmcCertificates() BmcCertificateInterface {
+	return newBmcCertificates(c)
+}
+
+func (c *TopohubV1beta1Client) BmcProfiles() BmcProfileInterface {
+	return newBmcProfiles(c)
+}
+
 func (c *TopohubV1beta1Client) HostEndpoints() HostEndpointInterface {
 	return newHostEndpoints(c)
 }
 
+func (c *TopohubV1beta1Client) HostInventories() HostInventoryInterface {
+	return newHostInventories(c)
+}
+
 func (c *TopohubV1beta1Client) HostOperations() HostOperationInterface {
 	return newHostOperations(c)
 }
@@ -43,6 +70,14 @@ func (c *TopohubV1beta1Client) HostStatuses() HostStatusInterface {
 	return newHostStatuses(c)
 }
 
+func (c *TopohubV1beta1Client) PowerCapConfigs() PowerCapConfigInterface {
+	return newPowerCapConfigs(c)
+}
+
+func (c *TopohubV1beta1Client) StorageConfigs() StorageConfigInterface {
+	return newStorageConfigs(c)
+}
+
 func (c *TopohubV1beta1Client) Subnets() SubnetInterface {
 	return newSubnets(c)
 }
@@ -42,12 +42,26 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=topohub.infrastructure.io, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("bindingips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().BindingIps().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("biosconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().BiosConfigs().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("bmcaccounts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().BmcAccounts().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("bmccertificates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().BmcCertificates().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("bmcprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().BmcProfiles().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("hostendpoints"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().HostEndpoints().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("hostinventories"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().HostInventories().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("hostoperations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().HostOperations().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("hoststatuses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().HostStatuses().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("powercapconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().PowerCapConfigs().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("storageconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().StorageConfigs().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("subnets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Topohub().V1beta1().Subnets().Informer()}, nil
 
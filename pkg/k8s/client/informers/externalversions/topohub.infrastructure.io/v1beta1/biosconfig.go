@@ -0,0 +1,76 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	context "context"
+	time "time"
+
+	apistopohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	versioned "github.com/infrastructure-io/topohub/pkg/k8s/client/clientset/versioned"
+	internalinterfaces "github.com/infrastructure-io/topohub/pkg/k8s/client/informers/externalversions/internalinterfaces"
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/client/listers/topohub.infrastructure.io/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// BiosConfigInformer provides access to a shared informer and lister for
+// BiosConfigs.
+type BiosConfigInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() topohubinfrastructureiov1beta1.BiosConfigLister
+}
+
+type biosConfigInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewBiosConfigInformer constructs a new informer for BiosConfig type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewBiosConfigInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredBiosConfigInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredBiosConfigInformer constructs a new informer for BiosConfig type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredBiosConfigInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.TopohubV1beta1().BiosConfigs().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.TopohubV1beta1().BiosConfigs().Watch(context.TODO(), options)
+			},
+		},
+		&apistopohubinfrastructureiov1beta1.BiosConfig{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *biosConfigInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredBiosConfigInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *biosConfigInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&apistopohubinfrastructureiov1beta1.BiosConfig{}, f.defaultInformer)
+}
+
+func (f *biosConfigInformer) Lister() topohubinfrastructureiov1beta1.BiosConfigLister {
+	return topohubinfrastructureiov1beta1.NewBiosConfigLister(f.Informer().GetIndexer())
+}
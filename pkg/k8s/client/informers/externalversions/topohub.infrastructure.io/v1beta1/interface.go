@@ -13,12 +13,26 @@ import (
 type Interface interface {
 	// BindingIps returns a BindingIpInformer.
 	BindingIps() BindingIpInformer
+	// BiosConfigs returns a BiosConfigInformer.
+	BiosConfigs() BiosConfigInformer
+	// BmcAccounts returns a BmcAccountInformer.
+	BmcAccounts() BmcAccountInformer
+	// BmcCertificates returns a BmcCertificateInformer.
+	BmcCertificates() BmcCertificateInformer
+	// BmcProfiles returns a BmcProfileInformer.
+	BmcProfiles() BmcProfileInformer
 	// HostEndpoints returns a HostEndpointInformer.
 	HostEndpoints() HostEndpointInformer
+	// HostInventories returns a HostInventoryInformer.
+	HostInventories() HostInventoryInformer
 	// HostOperations returns a HostOperationInformer.
 	HostOperations() HostOperationInformer
 	// HostStatuses returns a HostStatusInformer.
 	HostStatuses() HostStatusInformer
+	// PowerCapConfigs returns a PowerCapConfigInformer.
+	PowerCapConfigs() PowerCapConfigInformer
+	// StorageConfigs returns a StorageConfigInformer.
+	StorageConfigs() StorageConfigInformer
 	// Subnets returns a SubnetInformer.
 	Subnets() SubnetInformer
 }
@@ -39,11 +53,36 @@ func (v *version) BindingIps() BindingIpInformer {
 	return &bindingIpInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// BiosConfigs returns a BiosConfigInformer.
+func (v *version) BiosConfigs() BiosConfigInformer {
+	return &biosConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// BmcAccounts returns a BmcAccountInformer.
+func (v *version) BmcAccounts() BmcAccountInformer {
+	return &bmcAccountInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// BmcCertificates returns a BmcCertificateInformer.
+func (v *version) BmcCertificates() BmcCertificateInformer {
+	return &bmcCertificateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// BmcProfiles returns a BmcProfileInformer.
+func (v *version) BmcProfiles() BmcProfileInformer {
+	return &bmcProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // HostEndpoints returns a HostEndpointInformer.
 func (v *version) HostEndpoints() HostEndpointInformer {
 	return &hostEndpointInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// HostInventories returns a HostInventoryInformer.
+func (v *version) HostInventories() HostInventoryInformer {
+	return &hostInventoryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // HostOperations returns a HostOperationInformer.
 func (v *version) HostOperations() HostOperationInformer {
 	return &hostOperationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -54,6 +93,16 @@ func (v *version) HostStatuses() HostStatusInformer {
 	return &hostStatusInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// PowerCapConfigs returns a PowerCapConfigInformer.
+func (v *version) PowerCapConfigs() PowerCapConfigInformer {
+	return &powerCapConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// StorageConfigs returns a StorageConfigInformer.
+func (v *version) StorageConfigs() StorageConfigInformer {
+	return &storageConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Subnets returns a SubnetInformer.
 func (v *version) Subnets() SubnetInformer {
 	return &subnetInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
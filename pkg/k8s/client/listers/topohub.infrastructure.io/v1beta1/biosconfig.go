@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// BiosConfigLister helps list BiosConfigs.
+// All objects returned here must be treated as read-only.
+type BiosConfigLister interface {
+	// List lists all BiosConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.BiosConfig, err error)
+	// Get retrieves the BiosConfig from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.BiosConfig, error)
+	BiosConfigListerExpansion
+}
+
+// biosConfigLister implements the BiosConfigLister interface.
+type biosConfigLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.BiosConfig]
+}
+
+// NewBiosConfigLister returns a new BiosConfigLister.
+func NewBiosConfigLister(indexer cache.Indexer) BiosConfigLister {
+	return &biosConfigLister{listers.New[*topohubinfrastructureiov1beta1.BiosConfig](indexer, topohubinfrastructureiov1beta1.Resource("biosconfig"))}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// BmcAccountLister helps list BmcAccounts.
+// All objects returned here must be treated as read-only.
+type BmcAccountLister interface {
+	// List lists all BmcAccounts in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.BmcAccount, err error)
+	// Get retrieves the BmcAccount from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.BmcAccount, error)
+	BmcAccountListerExpansion
+}
+
+// bmcAccountLister implements the BmcAccountLister interface.
+type bmcAccountLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.BmcAccount]
+}
+
+// NewBmcAccountLister returns a new BmcAccountLister.
+func NewBmcAccountLister(indexer cache.Indexer) BmcAccountLister {
+	return &bmcAccountLister{listers.New[*topohubinfrastructureiov1beta1.BmcAccount](indexer, topohubinfrastructureiov1beta1.Resource("bmcaccount"))}
+}
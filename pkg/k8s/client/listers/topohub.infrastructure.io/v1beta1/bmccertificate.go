@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// BmcCertificateLister helps list BmcCertificates.
+// All objects returned here must be treated as read-only.
+type BmcCertificateLister interface {
+	// List lists all BmcCertificates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.BmcCertificate, err error)
+	// Get retrieves the BmcCertificate from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.BmcCertificate, error)
+	BmcCertificateListerExpansion
+}
+
+// bmcCertificateLister implements the BmcCertificateLister interface.
+type bmcCertificateLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.BmcCertificate]
+}
+
+// NewBmcCertificateLister returns a new BmcCertificateLister.
+func NewBmcCertificateLister(indexer cache.Indexer) BmcCertificateLister {
+	return &bmcCertificateLister{listers.New[*topohubinfrastructureiov1beta1.BmcCertificate](indexer, topohubinfrastructureiov1beta1.Resource("bmccertificate"))}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// BmcProfileLister helps list BmcProfiles.
+// All objects returned here must be treated as read-only.
+type BmcProfileLister interface {
+	// List lists all BmcProfiles in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.BmcProfile, err error)
+	// Get retrieves the BmcProfile from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.BmcProfile, error)
+	BmcProfileListerExpansion
+}
+
+// bmcProfileLister implements the BmcProfileLister interface.
+type bmcProfileLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.BmcProfile]
+}
+
+// NewBmcProfileLister returns a new BmcProfileLister.
+func NewBmcProfileLister(indexer cache.Indexer) BmcProfileLister {
+	return &bmcProfileLister{listers.New[*topohubinfrastructureiov1beta1.BmcProfile](indexer, topohubinfrastructureiov1beta1.Resource("bmcprofile"))}
+}
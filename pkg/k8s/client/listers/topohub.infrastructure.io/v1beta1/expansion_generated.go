@@ -9,10 +9,30 @@ package v1beta1
 // BindingIpLister.
 type BindingIpListerExpansion interface{}
 
+// BiosConfigListerExpansion allows custom methods to be added to
+// BiosConfigLister.
+type BiosConfigListerExpansion interface{}
+
+// BmcAccountListerExpansion allows custom methods to be added to
+// BmcAccountLister.
+type BmcAccountListerExpansion interface{}
+
+// BmcCertificateListerExpansion allows custom methods to be added to
+// BmcCertificateLister.
+type BmcCertificateListerExpansion interface{}
+
+// BmcProfileListerExpansion allows custom methods to be added to
+// BmcProfileLister.
+type BmcProfileListerExpansion interface{}
+
 // HostEndpointListerExpansion allows custom methods to be added to
 // HostEndpointLister.
 type HostEndpointListerExpansion interface{}
 
+// HostInventoryListerExpansion allows custom methods to be added to
+// HostInventoryLister.
+type HostInventoryListerExpansion interface{}
+
 // HostOperationListerExpansion allows custom methods to be added to
 // HostOperationLister.
 type HostOperationListerExpansion interface{}
@@ -21,6 +41,14 @@ type HostOperationListerExpansion interface{}
 // HostStatusLister.
 type HostStatusListerExpansion interface{}
 
+// PowerCapConfigListerExpansion allows custom methods to be added to
+// PowerCapConfigLister.
+type PowerCapConfigListerExpansion interface{}
+
+// StorageConfigListerExpansion allows custom methods to be added to
+// StorageConfigLister.
+type StorageConfigListerExpansion interface{}
+
 // SubnetListerExpansion allows custom methods to be added to
 // SubnetLister.
 type SubnetListerExpansion interface{}
@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// HostInventoryLister helps list HostInventories.
+// All objects returned here must be treated as read-only.
+type HostInventoryLister interface {
+	// List lists all HostInventories in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.HostInventory, err error)
+	// Get retrieves the HostInventory from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.HostInventory, error)
+	HostInventoryListerExpansion
+}
+
+// hostInventoryLister implements the HostInventoryLister interface.
+type hostInventoryLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.HostInventory]
+}
+
+// NewHostInventoryLister returns a new HostInventoryLister.
+func NewHostInventoryLister(indexer cache.Indexer) HostInventoryLister {
+	return &hostInventoryLister{listers.New[*topohubinfrastructureiov1beta1.HostInventory](indexer, topohubinfrastructureiov1beta1.Resource("hostinventory"))}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// PowerCapConfigLister helps list PowerCapConfigs.
+// All objects returned here must be treated as read-only.
+type PowerCapConfigLister interface {
+	// List lists all PowerCapConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.PowerCapConfig, err error)
+	// Get retrieves the PowerCapConfig from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.PowerCapConfig, error)
+	PowerCapConfigListerExpansion
+}
+
+// powerCapConfigLister implements the PowerCapConfigLister interface.
+type powerCapConfigLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.PowerCapConfig]
+}
+
+// NewPowerCapConfigLister returns a new PowerCapConfigLister.
+func NewPowerCapConfigLister(indexer cache.Indexer) PowerCapConfigLister {
+	return &powerCapConfigLister{listers.New[*topohubinfrastructureiov1beta1.PowerCapConfig](indexer, topohubinfrastructureiov1beta1.Resource("powercapconfig"))}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Authors of infrastructure-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	topohubinfrastructureiov1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// StorageConfigLister helps list StorageConfigs.
+// All objects returned here must be treated as read-only.
+type StorageConfigLister interface {
+	// List lists all StorageConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*topohubinfrastructureiov1beta1.StorageConfig, err error)
+	// Get retrieves the StorageConfig from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*topohubinfrastructureiov1beta1.StorageConfig, error)
+	StorageConfigListerExpansion
+}
+
+// storageConfigLister implements the StorageConfigLister interface.
+type storageConfigLister struct {
+	listers.ResourceIndexer[*topohubinfrastructureiov1beta1.StorageConfig]
+}
+
+// NewStorageConfigLister returns a new StorageConfigLister.
+func NewStorageConfigLister(indexer cache.Indexer) StorageConfigLister {
+	return &storageConfigLister{listers.New[*topohubinfrastructureiov1beta1.StorageConfig](indexer, topohubinfrastructureiov1beta1.Resource("storageconfig"))}
+}
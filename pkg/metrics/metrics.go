@@ -0,0 +1,241 @@
+// prometheus metrics of topohub, which are exposed on the metrics endpoint of the controller-runtime manager
+
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "topohub"
+
+// the labels to identify the host
+var hostLabels = []string{"host", "cluster_name", "subnet"}
+
+var (
+	HostTemperatureCelsius = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "host_temperature_celsius",
+		Help:      "Temperature reading of the sensor in the chassis of the host",
+	}, append(hostLabels, "chassis", "sensor"))
+
+	HostFanSpeed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "host_fan_speed",
+		Help:      "Speed reading of the fan in the chassis of the host, the unit is RPM or Percent",
+	}, append(hostLabels, "chassis", "sensor", "unit"))
+
+	HostPowerSupplyInputWatts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "host_power_supply_input_watts",
+		Help:      "Input power of the power supply in the chassis of the host",
+	}, append(hostLabels, "chassis", "sensor"))
+
+	HostPowerConsumedWatts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "host_power_consumed_watts",
+		Help:      "Power consumed by the chassis of the host",
+	}, append(hostLabels, "chassis", "sensor"))
+
+	HostVoltageVolts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "host_voltage_volts",
+		Help:      "Voltage reading of the sensor in the chassis of the host",
+	}, append(hostLabels, "chassis", "sensor"))
+)
+
+var (
+	SubnetIpTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "subnet_ip_total",
+		Help:      "Number of ip addresses in the ip range of the dhcp server of the subnet",
+	}, []string{"subnet"})
+
+	SubnetIpAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "subnet_ip_available",
+		Help:      "Number of ip addresses which are not assigned or bound in the subnet",
+	}, []string{"subnet"})
+
+	SubnetIpActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "subnet_ip_active",
+		Help:      "Number of ip addresses which are in use in the lease file of the subnet",
+	}, []string{"subnet"})
+
+	SubnetIpBound = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "subnet_ip_bound",
+		Help:      "Number of ip addresses which are bound to the mac address in the subnet",
+	}, []string{"subnet"})
+
+	DhcpServerRestartsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dhcp_server_restarts_total",
+		Help:      "Number of restarts of the dnsmasq process of the subnet",
+	}, []string{"subnet"})
+
+	RedfishRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redfish_request_duration_seconds",
+		Help:      "Latency of the redfish requests to the bmc",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"ip"})
+
+	RedfishRequestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redfish_request_errors_total",
+		Help:      "Number of the redfish requests to the bmc which fail or get the error status code",
+	}, []string{"ip"})
+
+	HostOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hostoperations_total",
+		Help:      "Number of the finished HostOperation by the action and the status",
+	}, []string{"action", "status"})
+
+	HostStatusCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "hoststatus_count",
+		Help:      "Number of the HostStatus by the cluster name and the healthy state",
+	}, []string{"cluster_name", "healthy"})
+
+	HostStatusUpdatesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hoststatus_updates_total",
+		Help:      "Number of the periodic updates of the HostStatus by the result, which is success, failure, timeout or skipped",
+	}, []string{"result"})
+
+	HostStatusUpdateCycleDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "hoststatus_update_cycle_duration_seconds",
+		Help:      "Time to update all the HostStatus in a periodic cycle",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600},
+	})
+)
+
+// sensorCollectors hold the readings of the sensors of each host
+var sensorCollectors = []*prometheus.GaugeVec{
+	HostTemperatureCelsius,
+	HostFanSpeed,
+	HostPowerSupplyInputWatts,
+	HostPowerConsumedWatts,
+	HostVoltageVolts,
+}
+
+func init() {
+	for _, c := range sensorCollectors {
+		metrics.Registry.MustRegister(c)
+	}
+	metrics.Registry.MustRegister(
+		SubnetIpTotal,
+		SubnetIpAvailable,
+		SubnetIpActive,
+		SubnetIpBound,
+		DhcpServerRestartsTotal,
+		RedfishRequestDuration,
+		RedfishRequestErrorsTotal,
+		HostOperationsTotal,
+		HostStatusCount,
+		HostStatusUpdatesTotal,
+		HostStatusUpdateCycleDuration,
+	)
+}
+
+// sensorSeries identifies the series of a sensor reading
+type sensorSeries struct {
+	collector *prometheus.GaugeVec
+	labels    string
+}
+
+var (
+	seriesLock sync.Mutex
+	// hostSensors holds the label values of the sensor readings exported for each host
+	hostSensors = map[string]map[sensorSeries][]string{}
+	// hostStatusClusters holds the clusters whose HostStatus are counted
+	hostStatusClusters = map[string]struct{}{}
+)
+
+// HostSensors collects the sensor readings of a host in an update, which replace the former readings of the host
+// when they are committed
+type HostSensors struct {
+	host   string
+	series map[sensorSeries][]string
+}
+
+// NewHostSensors starts an update of the sensor readings of the host
+func NewHostSensors(host string) *HostSensors {
+	return &HostSensors{host: host, series: map[sensorSeries][]string{}}
+}
+
+// Set sets the reading of the sensor, the first label value must be the host
+func (h *HostSensors) Set(c *prometheus.GaugeVec, value float64, labelValues ...string) {
+	c.WithLabelValues(labelValues...).Set(value)
+	h.series[sensorSeries{collector: c, labels: strings.Join(labelValues, "\xff")}] = labelValues
+}
+
+// Commit removes the former readings of the host which are not reported in this update, for example, the power
+// supply is removed. The current readings are set before, so a scrape always sees the readings of the host
+func (h *HostSensors) Commit() {
+	seriesLock.Lock()
+	defer seriesLock.Unlock()
+	for series, labelValues := range hostSensors[h.host] {
+		if _, ok := h.series[series]; !ok {
+			series.collector.DeleteLabelValues(labelValues...)
+		}
+	}
+	hostSensors[h.host] = h.series
+}
+
+// DeleteHostSensors removes the sensor readings of the host, when the host is unreachable or deleted
+func DeleteHostSensors(host string) {
+	seriesLock.Lock()
+	defer seriesLock.Unlock()
+	for _, c := range sensorCollectors {
+		c.DeletePartialMatch(prometheus.Labels{"host": host})
+	}
+	delete(hostSensors, host)
+}
+
+// SetHostStatusCount sets the number of the healthy and the unhealthy HostStatus of each cluster. Both counts of a
+// cluster are set even if one is zero, and only the clusters which no longer have any HostStatus are removed, so a
+// scrape never sees a partial count
+func SetHostStatusCount(healthy, unhealthy map[string]int) {
+	seriesLock.Lock()
+	defer seriesLock.Unlock()
+	clusters := map[string]struct{}{}
+	for _, counts := range []map[string]int{healthy, unhealthy} {
+		for clusterName := range counts {
+			clusters[clusterName] = struct{}{}
+		}
+	}
+	for clusterName := range clusters {
+		HostStatusCount.WithLabelValues(clusterName, strconv.FormatBool(true)).Set(float64(healthy[clusterName]))
+		HostStatusCount.WithLabelValues(clusterName, strconv.FormatBool(false)).Set(float64(unhealthy[clusterName]))
+	}
+	for clusterName := range hostStatusClusters {
+		if _, ok := clusters[clusterName]; !ok {
+			HostStatusCount.DeleteLabelValues(clusterName, strconv.FormatBool(true))
+			HostStatusCount.DeleteLabelValues(clusterName, strconv.FormatBool(false))
+		}
+	}
+	hostStatusClusters = clusters
+}
+
+// DeleteSubnet removes the metrics of the subnet, when its dhcp server is stopped
+func DeleteSubnet(subnet string) {
+	SubnetIpTotal.DeleteLabelValues(subnet)
+	SubnetIpAvailable.DeleteLabelValues(subnet)
+	SubnetIpActive.DeleteLabelValues(subnet)
+	SubnetIpBound.DeleteLabelValues(subnet)
+}
+
+// DeleteRedfishHost removes the request metrics of the bmc, when the host is deleted
+func DeleteRedfishHost(ip string) {
+	RedfishRequestDuration.DeleteLabelValues(ip)
+	RedfishRequestErrorsTotal.DeleteLabelValues(ip)
+}
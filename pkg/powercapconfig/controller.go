@@ -0,0 +1,267 @@
+package powercapconfig
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// PowerCapConfigController sets the power limit of the chassis of the hosts, and reports their consumption
+type PowerCapConfigController struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	agentConfig *config.AgentConfig
+	log         *zap.SugaredLogger
+}
+
+func NewPowerCapConfigController(mgr ctrl.Manager, agentConfig *config.AgentConfig) (*PowerCapConfigController, error) {
+	return &PowerCapConfigController{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		agentConfig: agentConfig,
+		log:         log.Logger.Named("PowerCapConfigController"),
+	}, nil
+}
+
+// 只有 leader 才会执行 Reconcile
+// Reconcile checks the power limit of every selected host, and it is requeued at the interval of updating the HostStatus
+// to refresh the consumption
+func (r *PowerCapConfigController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.With("powercapconfig", req.Name)
+	logger.Debugf("Starting reconcile for PowerCapConfig %s", req.Name)
+
+	powerCapConfig := &topohubv1beta1.PowerCapConfig{}
+	if err := r.Get(ctx, req.NamespacedName, powerCapConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := time.Duration(r.agentConfig.RedfishHostStatusUpdateInterval) * time.Second
+
+	hosts, missing, err := r.selectHosts(ctx, powerCapConfig)
+	if err != nil {
+		logger.Errorf("Failed to select hosts: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	status := topohubv1beta1.PowerCapConfigStatus{}
+	for _, name := range missing {
+		status.Hosts = append(status.Hosts, topohubv1beta1.PowerCapHostStatus{
+			HostStatusName: name,
+			State:          topohubv1beta1.PowerCapStateFailed,
+			Message:        fmt.Sprintf("hostStatus %s is not found", name),
+		})
+	}
+	for i := range hosts {
+		status.Hosts = append(status.Hosts, r.syncHost(logger, powerCapConfig, &hosts[i])...)
+	}
+
+	for _, item := range status.Hosts {
+		status.TotalHosts++
+		status.TotalConsumedWatts += item.ConsumedWatts
+		status.TotalLimitWatts += item.LimitWatts
+		switch item.State {
+		case topohubv1beta1.PowerCapStateInSync:
+			status.InSyncHosts++
+		case topohubv1beta1.PowerCapStateDrifted:
+			status.DriftedHosts++
+		default:
+			status.FailedHosts++
+		}
+	}
+
+	// ignore the update time when comparing
+	status.LastUpdateTime = powerCapConfig.Status.LastUpdateTime
+	if reflect.DeepEqual(status, powerCapConfig.Status) {
+		logger.Debugf("no need to update PowerCapConfig %s", powerCapConfig.Name)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+	status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	powerCapConfig.Status = status
+	if err := r.Status().Update(ctx, powerCapConfig); err != nil {
+		logger.Errorf("Failed to update PowerCapConfig status: %v", err)
+		return ctrl.Result{}, err
+	}
+	logger.Debugf("Successfully updated PowerCapConfig %s status, total %d, inSync %d, drifted %d, failed %d, consumed %dW, capped %dW",
+		powerCapConfig.Name, status.TotalHosts, status.InSyncHosts, status.DriftedHosts, status.FailedHosts, status.TotalConsumedWatts, status.TotalLimitWatts)
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// selectHosts returns the HostStatus selected by the names and the label selector, and the names which are not found
+func (r *PowerCapConfigController) selectHosts(ctx context.Context, powerCapConfig *topohubv1beta1.PowerCapConfig) ([]topohubv1beta1.HostStatus, []string, error) {
+	selected := map[string]topohubv1beta1.HostStatus{}
+	missing := []string{}
+
+	for _, name := range powerCapConfig.Spec.HostStatusNames {
+		hostStatus := topohubv1beta1.HostStatus{}
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &hostStatus); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return nil, nil, err
+		}
+		selected[name] = hostStatus
+	}
+
+	if powerCapConfig.Spec.HostSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(powerCapConfig.Spec.HostSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid hostSelector: %v", err)
+		}
+		hostStatusList := &topohubv1beta1.HostStatusList{}
+		if err := r.List(ctx, hostStatusList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range hostStatusList.Items {
+			selected[item.Name] = item
+		}
+	}
+
+	result := []topohubv1beta1.HostStatus{}
+	for _, item := range selected {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	sort.Strings(missing)
+	return result, missing, nil
+}
+
+// syncHost reads the power of every chassis of the host, and sets the power limit in the Enforce mode
+func (r *PowerCapConfigController) syncHost(logger *zap.SugaredLogger, powerCapConfig *topohubv1beta1.PowerCapConfig, hostStatus *topohubv1beta1.HostStatus) []topohubv1beta1.PowerCapHostStatus {
+	failed := func(message string) []topohubv1beta1.PowerCapHostStatus {
+		return []topohubv1beta1.PowerCapHostStatus{{
+			HostStatusName: hostStatus.Name,
+			State:          topohubv1beta1.PowerCapStateFailed,
+			Message:        message,
+		}}
+	}
+
+	if !hostStatus.Status.Healthy {
+		return failed(fmt.Sprintf("hostStatus %s is not healthy", hostStatus.Name))
+	}
+	d := hoststatusData.HostCacheDatabase.Get(hostStatus.Name)
+	if d == nil {
+		return failed(fmt.Sprintf("failed to get connect config %s from cache", hostStatus.Name))
+	}
+	c, err := redfish.NewClient(*d, logger)
+	if err != nil {
+		return failed(err.Error())
+	}
+	controls, err := c.GetPowerControls()
+	if err != nil {
+		logger.Warnf("Failed to get power of %s: %v", hostStatus.Name, err)
+		return failed(err.Error())
+	}
+
+	spec := &powerCapConfig.Spec
+	result := []topohubv1beta1.PowerCapHostStatus{}
+	for _, chassisId := range spec.ChassisIds {
+		if !slices.ContainsFunc(controls, func(pc redfish.PowerControl) bool { return pc.ChassisId == chassisId }) {
+			result = append(result, topohubv1beta1.PowerCapHostStatus{
+				HostStatusName: hostStatus.Name,
+				ChassisId:      chassisId,
+				State:          topohubv1beta1.PowerCapStateFailed,
+				Message:        fmt.Sprintf("chassis %s does not report the PowerControl", chassisId),
+			})
+		}
+	}
+
+	for _, pc := range controls {
+		if len(spec.ChassisIds) > 0 && !slices.Contains(spec.ChassisIds, pc.ChassisId) {
+			continue
+		}
+		item := topohubv1beta1.PowerCapHostStatus{
+			HostStatusName:       hostStatus.Name,
+			ChassisId:            pc.ChassisId,
+			State:                topohubv1beta1.PowerCapStateInSync,
+			LimitWatts:           round(pc.LimitWatts),
+			ConsumedWatts:        round(pc.ConsumedWatts),
+			AverageConsumedWatts: round(pc.AverageConsumedWatts),
+			MinConsumedWatts:     round(pc.MinConsumedWatts),
+			MaxConsumedWatts:     round(pc.MaxConsumedWatts),
+			IntervalInMin:        round(pc.IntervalInMin),
+		}
+
+		drift := limitDrift(spec, &pc)
+		switch {
+		case len(drift) == 0:
+		case spec.Mode == topohubv1beta1.PowerCapConfigModeMonitor:
+			item.State = topohubv1beta1.PowerCapStateDrifted
+			item.Message = drift
+		default:
+			limit := redfish.PowerLimit{
+				LimitWatts:     spec.LimitWatts,
+				LimitException: spec.LimitException,
+			}
+			if spec.CorrectionInMs != nil {
+				limit.CorrectionInMs = *spec.CorrectionInMs
+			}
+			if err := c.SetPowerLimit(pc.Uri, limit); err != nil {
+				logger.Errorf("Failed to set power limit of %s chassis %s: %v", hostStatus.Name, pc.ChassisId, err)
+				item.State = topohubv1beta1.PowerCapStateFailed
+				item.Message = err.Error()
+				break
+			}
+			logger.Infof("Set power limit of %s chassis %s to %dW, %s", hostStatus.Name, pc.ChassisId, spec.LimitWatts, drift)
+			item.LimitWatts = spec.LimitWatts
+		}
+
+		if item.State == topohubv1beta1.PowerCapStateInSync && item.ConsumedWatts > item.LimitWatts {
+			item.Message = fmt.Sprintf("the consumption %dW exceeds the limit %dW", item.ConsumedWatts, item.LimitWatts)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// limitDrift describes how the power limit of the chassis differs from the spec, it is empty when they are the same
+func limitDrift(spec *topohubv1beta1.PowerCapConfigSpec, pc *redfish.PowerControl) string {
+	if round(pc.LimitWatts) != spec.LimitWatts {
+		if pc.LimitWatts == 0 {
+			return "the power is not limited"
+		}
+		return fmt.Sprintf("the power limit is %dW rather than %dW", round(pc.LimitWatts), spec.LimitWatts)
+	}
+	if len(spec.LimitException) > 0 && pc.LimitException != spec.LimitException {
+		return fmt.Sprintf("the limit exception is %s rather than %s", pc.LimitException, spec.LimitException)
+	}
+	if spec.CorrectionInMs != nil && pc.CorrectionInMs != *spec.CorrectionInMs {
+		return fmt.Sprintf("the correction time is %dms rather than %dms", pc.CorrectionInMs, *spec.CorrectionInMs)
+	}
+	return ""
+}
+
+// round converts the reading of the bmc to the integer
+func round(value float32) int32 {
+	return int32(math.Round(float64(value)))
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *PowerCapConfigController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topohubv1beta1.PowerCapConfig{}).
+		// the status is updated by itself, and the hosts are checked periodically
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
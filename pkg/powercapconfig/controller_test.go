@@ -0,0 +1,135 @@
+package powercapconfig
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const testPowerCapConfigName = "rack1"
+
+var _ = Describe("PowerCapConfigController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *PowerCapConfigController
+
+	// newController creates the controller with the HostStatus of the emulator, which is selected by the cluster label,
+	// and the PowerCapConfig which also names a missing HostStatus
+	newController := func(spec topohubv1beta1.PowerCapConfigSpec) {
+		hostStatus := bmc.HostStatus()
+		hostStatus.Labels = map[string]string{topohubv1beta1.LabelClusterName: "cluster1"}
+		spec.HostStatusNames = []string{"missing"}
+		spec.HostSelector = &metav1.LabelSelector{MatchLabels: map[string]string{topohubv1beta1.LabelClusterName: "cluster1"}}
+		c := bmc.Build(hostStatus, &topohubv1beta1.PowerCapConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: testPowerCapConfigName},
+			Spec:       spec,
+		})
+		r = &PowerCapConfigController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{RedfishHostStatusUpdateInterval: 60},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	// reconcile returns the status of the PowerCapConfig, whose first host is the missing one
+	reconcile := func() topohubv1beta1.PowerCapConfigStatus {
+		testhost.Reconcile(r, testPowerCapConfigName)
+		powerCapConfig := testhost.Get(r, testPowerCapConfigName, &topohubv1beta1.PowerCapConfig{})
+		Expect(powerCapConfig.Status.Hosts).To(HaveLen(int(powerCapConfig.Status.TotalHosts)))
+		Expect(powerCapConfig.Status.Hosts[0].HostStatusName).To(Equal("missing"))
+		Expect(powerCapConfig.Status.Hosts[0].State).To(Equal(topohubv1beta1.PowerCapStateFailed))
+		return powerCapConfig.Status
+	}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(false)
+		bmc.SetPowerControl(emulator.PowerControl{
+			ConsumedWatts:        320,
+			AverageConsumedWatts: 300.4,
+			MinConsumedWatts:     210,
+			MaxConsumedWatts:     450,
+		})
+	})
+
+	It("sets the power limit in the Enforce mode", func() {
+		newController(topohubv1beta1.PowerCapConfigSpec{
+			LimitWatts:     500,
+			LimitException: "LogEventOnly",
+			Mode:           topohubv1beta1.PowerCapConfigModeEnforce,
+		})
+
+		status := reconcile()
+		Expect(status.TotalHosts).To(Equal(int32(2)))
+		Expect(status.InSyncHosts).To(Equal(int32(1)))
+		Expect(status.FailedHosts).To(Equal(int32(1)))
+		Expect(status.TotalConsumedWatts).To(Equal(int32(320)))
+		Expect(status.TotalLimitWatts).To(Equal(int32(500)))
+		host := status.Hosts[1]
+		Expect(host.HostStatusName).To(Equal(testhost.HostStatusName))
+		Expect(host.ChassisId).To(Equal("1"))
+		Expect(host.State).To(Equal(topohubv1beta1.PowerCapStateInSync))
+		Expect(host.Message).To(BeEmpty())
+		Expect(host.AverageConsumedWatts).To(Equal(int32(300)))
+		Expect(host.MaxConsumedWatts).To(Equal(int32(450)))
+
+		pc, ok := bmc.PowerControl()
+		Expect(ok).To(BeTrue())
+		Expect(pc.LimitWatts).To(Equal(float32(500)))
+		Expect(pc.LimitException).To(Equal("LogEventOnly"))
+
+		// the limit is read back from the bmc
+		status = reconcile()
+		Expect(status.Hosts[1].State).To(Equal(topohubv1beta1.PowerCapStateInSync))
+		Expect(status.Hosts[1].LimitWatts).To(Equal(int32(500)))
+	})
+
+	It("reports the drift in the Monitor mode", func() {
+		newController(topohubv1beta1.PowerCapConfigSpec{
+			LimitWatts: 500,
+			Mode:       topohubv1beta1.PowerCapConfigModeMonitor,
+		})
+
+		status := reconcile()
+		Expect(status.DriftedHosts).To(Equal(int32(1)))
+		Expect(status.TotalLimitWatts).To(Equal(int32(0)))
+		Expect(status.Hosts[1].State).To(Equal(topohubv1beta1.PowerCapStateDrifted))
+		Expect(status.Hosts[1].Message).To(Equal("the power is not limited"))
+		pc, _ := bmc.PowerControl()
+		Expect(pc.LimitWatts).To(BeZero())
+	})
+
+	It("reports the consumption which exceeds the limit", func() {
+		bmc.SetPowerControl(emulator.PowerControl{ConsumedWatts: 320, LimitWatts: 300})
+		newController(topohubv1beta1.PowerCapConfigSpec{
+			LimitWatts: 300,
+			Mode:       topohubv1beta1.PowerCapConfigModeMonitor,
+		})
+
+		status := reconcile()
+		Expect(status.InSyncHosts).To(Equal(int32(1)))
+		Expect(status.Hosts[1].State).To(Equal(topohubv1beta1.PowerCapStateInSync))
+		Expect(status.Hosts[1].Message).To(Equal("the consumption 320W exceeds the limit 300W"))
+	})
+
+	It("fails when the chassis does not report the PowerControl", func() {
+		newController(topohubv1beta1.PowerCapConfigSpec{
+			LimitWatts: 500,
+			ChassisIds: []string{"1", "2"},
+		})
+
+		// every chassis of the host is reported
+		status := reconcile()
+		Expect(status.TotalHosts).To(Equal(int32(3)))
+		Expect(status.FailedHosts).To(Equal(int32(2)))
+		Expect(status.Hosts[1].ChassisId).To(Equal("2"))
+		Expect(status.Hosts[1].State).To(Equal(topohubv1beta1.PowerCapStateFailed))
+		Expect(status.Hosts[2].ChassisId).To(Equal("1"))
+		Expect(status.Hosts[2].State).To(Equal(topohubv1beta1.PowerCapStateInSync))
+	})
+})
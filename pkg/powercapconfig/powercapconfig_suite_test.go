@@ -0,0 +1,13 @@
+package powercapconfig
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPowerCapConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PowerCapConfig Suite")
+}
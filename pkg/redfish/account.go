@@ -0,0 +1,100 @@
+package redfish
+
+import (
+	"fmt"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// EnsureAccount creates the account on the bmc, or changes the password of the existing one
+func (c *redfishClient) EnsureAccount(username, password, roleId string) (string, bool, error) {
+	accountService, err := c.client.Service.AccountService()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get account service: %+v", err)
+	}
+	accounts, err := accountService.Accounts()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get accounts: %+v", err)
+	}
+
+	for _, account := range accounts {
+		if account.UserName != username {
+			continue
+		}
+		account.Password = password
+		account.Enabled = true
+		if len(roleId) > 0 {
+			account.RoleID = roleId
+		}
+		if err := account.Update(); err != nil {
+			return "", false, fmt.Errorf("failed to update account %s: %+v", account.ODataID, err)
+		}
+		c.logger.Infof("changed the password of account %s: %s", username, account.ODataID)
+		return account.ODataID, false, nil
+	}
+
+	if _, err = accountService.CreateAccount(username, password, roleId); err == nil {
+		// some bmc responds without the body, so look up the uri of the new account
+		uri, err := c.findAccount(accountService, username)
+		if err != nil {
+			return "", false, err
+		}
+		c.logger.Infof("created account %s: %s", username, uri)
+		return uri, true, nil
+	}
+	c.logger.Debugf("failed to create account %s, try the empty account slot: %+v", username, err)
+
+	// the bmc like iDRAC has fixed account slots, which could not be created by POST
+	for _, account := range accounts {
+		// the first slot is reserved for the anonymous user
+		if len(account.UserName) > 0 || account.ID == "1" {
+			continue
+		}
+		account.UserName = username
+		account.Password = password
+		account.RoleID = roleId
+		account.Enabled = true
+		if err := account.Update(); err != nil {
+			return "", false, fmt.Errorf("failed to set account slot %s: %+v", account.ODataID, err)
+		}
+		c.logger.Infof("created account %s in the slot %s", username, account.ODataID)
+		return account.ODataID, true, nil
+	}
+	return "", false, fmt.Errorf("failed to create account %s, there is no empty account slot", username)
+}
+
+func (c *redfishClient) findAccount(accountService *redfish.AccountService, username string) (string, error) {
+	accounts, err := accountService.Accounts()
+	if err != nil {
+		return "", fmt.Errorf("failed to get accounts: %+v", err)
+	}
+	for _, account := range accounts {
+		if account.UserName == username {
+			return account.ODataID, nil
+		}
+	}
+	return "", fmt.Errorf("account %s is not found after it is created", username)
+}
+
+// DeleteAccount deletes the account, or empties the account slot when the bmc does not support to delete it
+func (c *redfishClient) DeleteAccount(accountUri string) error {
+	resp, err := c.client.Delete(accountUri)
+	if err == nil {
+		resp.Body.Close()
+		c.logger.Infof("deleted account %s", accountUri)
+		return nil
+	}
+	c.logger.Debugf("failed to delete account %s, try to empty the slot: %+v", accountUri, err)
+
+	account, err := redfish.GetManagerAccount(c.client, accountUri)
+	if err != nil {
+		return fmt.Errorf("failed to get account %s: %+v", accountUri, err)
+	}
+	account.Enabled = false
+	account.UserName = ""
+	if err := account.Update(); err != nil {
+		return fmt.Errorf("failed to empty account %s: %+v", accountUri, err)
+	}
+	c.logger.Infof("emptied account slot %s", accountUri)
+	return nil
+}
@@ -0,0 +1,177 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// BiosAttributes is the bios attributes of a system
+type BiosAttributes struct {
+	// Current are the attributes which take effect now
+	Current map[string]string
+	// Pending are the attributes in the Bios/Settings, which take effect after the reboot
+	Pending map[string]string
+}
+
+// GetBiosAttributes returns the current and pending bios attributes of the system
+func (c *redfishClient) GetBiosAttributes(systemId string) (*BiosAttributes, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return nil, err
+	}
+	bios, err := system.Bios()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bios of system %s: %+v", system.ID, err)
+	}
+
+	result := &BiosAttributes{
+		Current: attributesToString(bios.Attributes),
+		Pending: map[string]string{},
+	}
+
+	// the Bios/Settings only holds the attributes which are going to change
+	settingsUri, err := c.biosSettingsUri(bios.ODataID)
+	if err != nil {
+		c.logger.Debugf("failed to get the settings object of bios %s: %+v", bios.ODataID, err)
+		return result, nil
+	}
+	if len(settingsUri) == 0 || settingsUri == bios.ODataID {
+		return result, nil
+	}
+	var settings struct {
+		Attributes redfish.SettingsAttributes
+	}
+	if err := c.getJson(settingsUri, &settings); err != nil {
+		c.logger.Debugf("failed to get the bios settings %s: %+v", settingsUri, err)
+		return result, nil
+	}
+	for k, v := range attributesToString(settings.Attributes) {
+		if result.Current[k] != v {
+			result.Pending[k] = v
+		}
+	}
+	return result, nil
+}
+
+// SetBiosAttributes writes the attributes to the Bios/Settings of the system, and they take effect at the applyTime
+func (c *redfishClient) SetBiosAttributes(systemId string, attributes map[string]string, applyTime string) error {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return err
+	}
+	bios, err := system.Bios()
+	if err != nil {
+		return fmt.Errorf("failed to get bios of system %s: %+v", system.ID, err)
+	}
+
+	// check whether the bmc supports the apply time
+	supported := false
+	for _, t := range bios.AllowedAttributeUpdateApplyTimes() {
+		if string(t) == applyTime {
+			supported = true
+			break
+		}
+	}
+	if len(applyTime) > 0 && !supported {
+		return fmt.Errorf("the bios of system %s does not support the apply time %s, supported: %v", system.ID, applyTime, bios.AllowedAttributeUpdateApplyTimes())
+	}
+
+	attrs := redfish.SettingsAttributes{}
+	for k, v := range attributes {
+		current, ok := bios.Attributes[k]
+		if !ok {
+			return fmt.Errorf("the bios of system %s does not have the attribute %s", system.ID, k)
+		}
+		value, err := convertAttribute(v, current)
+		if err != nil {
+			return fmt.Errorf("invalid value %s for the bios attribute %s: %+v", v, k, err)
+		}
+		attrs[k] = value
+	}
+
+	c.logger.Infof("set bios attributes of system %s at %s: %v", system.ID, applyTime, attributes)
+	if err := bios.UpdateBiosAttributesApplyAt(attrs, common.ApplyTime(applyTime)); err != nil {
+		return fmt.Errorf("failed to set bios attributes of system %s: %+v", system.ID, err)
+	}
+	return nil
+}
+
+// biosSettingsUri returns the uri of the @Redfish.Settings object of the bios
+func (c *redfishClient) biosSettingsUri(biosUri string) (string, error) {
+	var t struct {
+		Settings common.Settings `json:"@Redfish.Settings"`
+	}
+	if err := c.getJson(biosUri, &t); err != nil {
+		return "", err
+	}
+	return t.Settings.SettingsObject.String(), nil
+}
+
+// getJson gets the resource and decodes it into the result
+func (c *redfishClient) getJson(uri string, result interface{}) error {
+	resp, err := c.client.Get(uri)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func attributesToString(attrs redfish.SettingsAttributes) map[string]string {
+	result := map[string]string{}
+	for k, v := range attrs {
+		if v == nil {
+			result[k] = ""
+			continue
+		}
+		result[k] = fmt.Sprintf("%v", v)
+	}
+	return result
+}
+
+// convertAttribute converts the value to the type of the current value of the attribute
+func convertAttribute(value string, current interface{}) (interface{}, error) {
+	switch current.(type) {
+	case bool:
+		return strconv.ParseBool(value)
+	case float64:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+// ResetBios resets the bios attributes of the system to the defaults with Bios.ResetBios, which takes effect after the reboot.
+// It returns the uri of the redfish task, which is empty when the BMC finishes the action synchronously
+func (c *redfishClient) ResetBios(systemId string) (string, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return "", err
+	}
+	bios, err := system.Bios()
+	if err != nil {
+		return "", fmt.Errorf("failed to get bios of system %s: %+v", system.ID, err)
+	}
+	target, err := c.getActionTarget(bios.ODataID, "#Bios.ResetBios")
+	if err != nil {
+		return "", fmt.Errorf("failed to get bios %s: %+v", bios.ODataID, err)
+	}
+	if len(target) == 0 {
+		return "", fmt.Errorf("%w: bios %s does not support ResetBios", ErrNotSupported, bios.ODataID)
+	}
+	c.logger.Infof("reset bios %s", bios.ODataID)
+	taskUri, err := c.postAction(target, map[string]interface{}{})
+	if err != nil {
+		return "", fmt.Errorf("failed to reset bios %s: %+v", bios.ODataID, err)
+	}
+	return taskUri, nil
+}
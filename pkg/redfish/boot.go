@@ -0,0 +1,60 @@
+package redfish
+
+import (
+	"fmt"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// SetBoot sets the boot override and the persistent boot order of the system, and resets the system when required.
+// It returns the uri of the redfish task when the bmc resets the system asynchronously
+func (c *redfishClient) SetBoot(systemId string, boot topohubv1beta1.BootSpec) (string, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		c.logger.Errorf("failed to select the system: %+v", err)
+		return "", err
+	}
+	c.logger.Debugf("system %s, boot : %+v", system.Name, system.Boot)
+
+	if len(boot.Target) > 0 || len(boot.OverrideEnabled) > 0 || len(boot.BootMode) > 0 {
+		bootOverride := redfish.Boot{
+			BootSourceOverrideTarget:     redfish.BootSourceOverrideTarget(boot.Target),
+			BootSourceOverrideEnabled:    redfish.BootSourceOverrideEnabled(boot.OverrideEnabled),
+			BootSourceOverrideMode:       redfish.BootSourceOverrideMode(boot.BootMode),
+			UefiTargetBootSourceOverride: boot.UefiTarget,
+			HTTPBootURI:                  boot.HttpBootUri,
+		}
+		if len(boot.Target) > 0 && len(boot.OverrideEnabled) == 0 {
+			bootOverride.BootSourceOverrideEnabled = redfish.OnceBootSourceOverrideEnabled
+		}
+		c.logger.Infof("set boot override of system %s: target %s, enabled %s, mode %s", system.ID,
+			bootOverride.BootSourceOverrideTarget, bootOverride.BootSourceOverrideEnabled, bootOverride.BootSourceOverrideMode)
+		if err := c.vendor().SetBootOverride(c, system, bootOverride); err != nil {
+			return "", fmt.Errorf("failed to set boot override of system %s: %+v", system.ID, err)
+		}
+	}
+
+	// some bmc refuse to patch the boot order together with the boot override, so patch it separately
+	if len(boot.BootOrder) > 0 {
+		c.logger.Infof("set boot order of system %s: %v", system.ID, boot.BootOrder)
+		if err := c.vendor().SetBootOverride(c, system, redfish.Boot{BootOrder: boot.BootOrder}); err != nil {
+			return "", fmt.Errorf("failed to set boot order of system %s: %+v", system.ID, err)
+		}
+	}
+
+	if len(boot.ResetType) > 0 {
+		resetType := c.vendor().ResetType(system, boot.ResetType)
+		// the powered off system could not be restarted
+		if system.PowerState == redfish.OffPowerState && (resetType == redfish.ForceRestartResetType || resetType == redfish.GracefulRestartResetType) {
+			resetType = redfish.OnResetType
+		}
+		c.logger.Infof("reset system %s with %s", system.ID, resetType)
+		taskUri, err := c.resetSystem(system, resetType)
+		if err != nil {
+			return "", fmt.Errorf("failed to operate system %s: %+v", system.ID, err)
+		}
+		return taskUri, nil
+	}
+	return "", nil
+}
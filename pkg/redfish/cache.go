@@ -0,0 +1,149 @@
+package redfish
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/infrastructure-io/topohub/pkg/lock"
+)
+
+// clientCache caches the connected clients of the bmc by the ip address. The clients are shared by the controllers
+// which run concurrently, so the entry of a bmc is locked while its client is validated or created, which avoids
+// creating duplicated sessions on the bmc
+type clientCache struct {
+	lock    lock.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	// the lock is only held while the client is validated or created, which may take a login request to the bmc, so it
+	// uses sync.Mutex instead of lock.Mutex. It is released before the client is returned, so it does not serialize the
+	// requests of the callers to the bmc
+	lock    sync.Mutex
+	redfish *redfishClient
+	ipmi    *ipmiClient
+}
+
+// CacheClient holds the redfish and ipmi clients of the hosts
+var CacheClient = &clientCache{
+	entries: make(map[string]*cacheEntry),
+}
+
+// acquire returns the locked entry of the bmc, the caller must unlock it
+func (c *clientCache) acquire(ip string) *cacheEntry {
+	c.lock.Lock()
+	e, ok := c.entries[ip]
+	if !ok {
+		e = &cacheEntry{}
+		c.entries[ip] = e
+	}
+	c.lock.Unlock()
+
+	e.lock.Lock()
+	return e
+}
+
+// Delete logs out the cached clients of the bmc, it is called after the host is removed. The redfish client is logged
+// out after the requests of its callers finish
+func (c *clientCache) Delete(ip string) {
+	c.lock.Lock()
+	e, ok := c.entries[ip]
+	delete(c.entries, ip)
+	c.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.redfish != nil {
+		e.redfish.retire()
+		e.redfish = nil
+	}
+	if e.ipmi != nil {
+		e.ipmi.client.Close()
+		e.ipmi = nil
+	}
+}
+
+// Invalidate drops the cached clients of the bmc without logging out the redfish session, it is called after the bmc
+// is reset, since the bmc has dropped the sessions and may not respond the logout
+func (c *clientCache) Invalidate(ip string) {
+	c.lock.Lock()
+	e, ok := c.entries[ip]
+	delete(c.entries, ip)
+	c.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	// the socket of the ipmi client is released in the background, the close session request may time out
+	go func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		e.redfish = nil
+		if e.ipmi != nil {
+			e.ipmi.client.Close()
+			e.ipmi = nil
+		}
+	}()
+}
+
+// retireIdle is how long the redfish client dropped from the cache should be unused before it is logged out, since
+// the callers which got it from the cache may send several requests in turn
+var retireIdle = time.Minute
+
+// usageTransport records the requests in flight and the time when the last request finishes
+type usageTransport struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	inflight int
+	lastUsed time.Time
+}
+
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.inflight++
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.inflight--
+		t.lastUsed = time.Now()
+		t.mu.Unlock()
+	}()
+	return t.next.RoundTrip(req)
+}
+
+func (t *usageTransport) CloseIdleConnections() {
+	if c, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// idle returns how long no request is in flight
+func (t *usageTransport) idle() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inflight > 0 {
+		return 0
+	}
+	return time.Since(t.lastUsed)
+}
+
+// retire logs out the client dropped from the cache in the background, once it has been idle for retireIdle. Logging
+// it out at once would break the requests of the other callers sharing it
+func (c *redfishClient) retire() {
+	wait := retireIdle
+	go func() {
+		for {
+			idle := c.usage.idle()
+			if idle >= wait {
+				c.client.Logout()
+				return
+			}
+			time.Sleep(wait - idle)
+		}
+	}()
+}
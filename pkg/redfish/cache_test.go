@@ -0,0 +1,59 @@
+package redfish
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+)
+
+var _ = Describe("clientCache", Label("unitest"), func() {
+	It("logs out the replaced client after the requests of its callers finish", func() {
+		DeferCleanup(func(d time.Duration) { retireIdle = d }, retireIdle)
+		retireIdle = 200 * time.Millisecond
+
+		bmc := emulator.NewHTTP()
+		bmc.SetCredential("admin", "password")
+		DeferCleanup(func() {
+			CacheClient.Delete(bmc.Host())
+			bmc.Close()
+		})
+		con := hoststatusData.HostConnectCon{
+			Info: &topohubv1beta1.BasicInfo{
+				Type:   topohubv1beta1.HostTypeEndpoint,
+				IpAddr: bmc.Host(),
+				Port:   bmc.Port(),
+			},
+			Username: "admin",
+			Password: "password",
+		}
+		log := zap.NewNop().Sugar()
+		old, err := NewClient(con, log)
+		Expect(err).NotTo(HaveOccurred())
+
+		// another caller is in the middle of a slow request when the cached client is replaced
+		bmc.SetLatency("/redfish/v1/Managers", 500*time.Millisecond)
+		done := make(chan error, 1)
+		go func() {
+			_, e := old.GetManagers()
+			done <- e
+		}()
+		Eventually(func() int { return bmc.CountRequests(http.MethodGet, "/redfish/v1/Managers") }).Should(BeNumerically(">", 0))
+		bmc.Fail(http.MethodGet, "/redfish/v1/Systems", http.StatusUnauthorized, 1)
+		c, err := NewClient(con, log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).NotTo(BeIdenticalTo(old))
+
+		Eventually(done, 2*time.Second).Should(Receive(BeNil()))
+		Expect(bmc.Sessions()).To(Equal(2))
+		Eventually(bmc.Sessions, 2*time.Second).Should(Equal(1))
+		_, err = c.GetSystems()
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
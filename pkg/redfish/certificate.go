@@ -0,0 +1,167 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// HttpsCertificate is a certificate of the https service of the bmc, listed in the NetworkProtocol of the manager
+type HttpsCertificate struct {
+	// Uri is the @odata.id of the Certificate, which is replaced by ReplaceCertificate
+	Uri string
+	// CertificateString is the certificate in PEM
+	CertificateString string
+	ValidNotBefore    string
+	ValidNotAfter     string
+}
+
+// CertificateSigningRequest is the parameter of the CertificateService.GenerateCSR action
+type CertificateSigningRequest struct {
+	CommonName         string
+	AlternativeNames   []string
+	Organization       string
+	OrganizationalUnit string
+	City               string
+	State              string
+	Country            string
+	// KeyPairAlgorithm and KeyBitLength are decided by the bmc when they are empty
+	KeyPairAlgorithm string
+	KeyBitLength     int32
+}
+
+// httpsCertificatesUri returns the uri of the https certificate collection of the manager
+func (c *redfishClient) httpsCertificatesUri() (string, error) {
+	_, manager, err := c.primarySystem()
+	if err != nil {
+		return "", err
+	}
+	protocol, err := manager.NetworkProtocol()
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to get the network protocol of manager %s: %+v", ErrNotSupported, manager.ID, err)
+	}
+	// gofish does not expose the certificates of the https protocol
+	var t struct {
+		HTTPS struct {
+			Certificates common.Link
+		}
+	}
+	if err := c.getJson(protocol.ODataID, &t); err != nil {
+		return "", fmt.Errorf("failed to get network protocol %s: %+v", protocol.ODataID, err)
+	}
+	if len(t.HTTPS.Certificates) == 0 {
+		return "", fmt.Errorf("%w: manager %s does not report the https certificates", ErrNotSupported, manager.ID)
+	}
+	return t.HTTPS.Certificates.String(), nil
+}
+
+// GetHttpsCertificates returns the uri of the https certificate collection of the bmc, and the certificates in it
+func (c *redfishClient) GetHttpsCertificates() (string, []HttpsCertificate, error) {
+	collectionUri, err := c.httpsCertificatesUri()
+	if err != nil {
+		return "", nil, err
+	}
+	certificates, err := redfish.ListReferencedCertificates(c.client, collectionUri)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get certificates %s: %+v", collectionUri, err)
+	}
+	result := []HttpsCertificate{}
+	for _, item := range certificates {
+		result = append(result, HttpsCertificate{
+			Uri:               item.ODataID,
+			CertificateString: item.CertificateString,
+			ValidNotBefore:    item.ValidNotBefore,
+			ValidNotAfter:     item.ValidNotAfter,
+		})
+	}
+	return collectionUri, result, nil
+}
+
+// certificateServiceTarget returns the target of the action of the CertificateService
+func (c *redfishClient) certificateServiceTarget(action string) (string, error) {
+	service, err := c.client.Service.CertificateService()
+	if err != nil {
+		return "", fmt.Errorf("failed to get certificate service: %+v", err)
+	}
+	if service == nil {
+		return "", fmt.Errorf("%w: the CertificateService is not found", ErrNotSupported)
+	}
+	target, err := c.getActionTarget(service.ODataID, action)
+	if err != nil {
+		return "", fmt.Errorf("failed to get certificate service %s: %+v", service.ODataID, err)
+	}
+	if len(target) == 0 {
+		return "", fmt.Errorf("%w: the CertificateService does not support the %s action", ErrNotSupported, action)
+	}
+	return target, nil
+}
+
+// GenerateCSR makes the bmc generate a new key pair and returns the certificate signing request in PEM.
+// The key is kept by the bmc, and it is used when the signed certificate is installed to the collection
+func (c *redfishClient) GenerateCSR(collectionUri string, request CertificateSigningRequest) (string, error) {
+	target, err := c.certificateServiceTarget("#CertificateService.GenerateCSR")
+	if err != nil {
+		return "", err
+	}
+
+	// gofish posts the CertificateCollection as a string rather than a link, so the body is built here
+	body := map[string]interface{}{
+		"CertificateCollection": map[string]string{"@odata.id": collectionUri},
+		"CommonName":            request.CommonName,
+		"Organization":          request.Organization,
+		"OrganizationalUnit":    request.OrganizationalUnit,
+		"City":                  request.City,
+		"State":                 request.State,
+		"Country":               request.Country,
+	}
+	if len(request.AlternativeNames) > 0 {
+		body["AlternativeNames"] = request.AlternativeNames
+	}
+	if len(request.KeyPairAlgorithm) > 0 {
+		body["KeyPairAlgorithm"] = request.KeyPairAlgorithm
+	}
+	if request.KeyBitLength > 0 {
+		body["KeyBitLength"] = request.KeyBitLength
+	}
+
+	c.logger.Infof("generate the certificate signing request for %s: %s", collectionUri, request.CommonName)
+	resp, err := c.client.Post(target, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate the certificate signing request: %+v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the certificate signing request: %+v", err)
+	}
+	var result struct {
+		CSRString string
+	}
+	if err := json.Unmarshal(data, &result); err != nil || len(result.CSRString) == 0 {
+		return "", fmt.Errorf("the bmc does not respond the certificate signing request")
+	}
+	return result.CSRString, nil
+}
+
+// ReplaceCertificate replaces the certificate of the bmc with the signed certificate in PEM
+func (c *redfishClient) ReplaceCertificate(certificateUri string, certificate string) error {
+	target, err := c.certificateServiceTarget("#CertificateService.ReplaceCertificate")
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"CertificateUri":    map[string]string{"@odata.id": certificateUri},
+		"CertificateString": certificate,
+		"CertificateType":   redfish.PEMCertificateType,
+	}
+	c.logger.Infof("replace the certificate %s", certificateUri)
+	resp, err := c.client.Post(target, body)
+	if err != nil {
+		return fmt.Errorf("failed to replace the certificate %s: %+v", certificateUri, err)
+	}
+	resp.Body.Close()
+	return nil
+}
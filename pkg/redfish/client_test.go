@@ -0,0 +1,503 @@
+package redfish_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+)
+
+const (
+	username = "admin"
+	password = "password"
+)
+
+func hostConnectCon(bmc *emulator.Server, https bool) hoststatusData.HostConnectCon {
+	return hoststatusData.HostConnectCon{
+		Info: &topohubv1beta1.BasicInfo{
+			Type:   topohubv1beta1.HostTypeEndpoint,
+			IpAddr: bmc.Host(),
+			Port:   bmc.Port(),
+			Https:  https,
+		},
+		Username: username,
+		Password: password,
+	}
+}
+
+var _ = Describe("RedfishClient", Label("unitest"), func() {
+	var bmc *emulator.Server
+	log := zap.NewNop().Sugar()
+
+	BeforeEach(func() {
+		bmc = emulator.NewHTTP()
+		bmc.SetCredential(username, password)
+	})
+
+	AfterEach(func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		bmc.Close()
+	})
+
+	It("gets the info of the host", func() {
+		bmc.SetVendor("Emulator")
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		info, err := c.GetInfo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(HaveKeyWithValue("Manufacturer", "Emulator"))
+		Expect(info).To(HaveKeyWithValue("PowerState", emulator.PowerOn))
+		Expect(info).To(HaveKeyWithValue("SystemId", "1"))
+		Expect(info).To(HaveKeyWithValue("CpuPhysicalCore", "2"))
+		Expect(info).To(HaveKeyWithValue("BmcFirmwareVersion", "1.0.0"))
+
+		systems, err := c.GetSystems()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(systems).To(HaveLen(1))
+		Expect(systems[0].ManagedBy).To(Equal([]string{"bmc"}))
+		Expect(systems[0].IndicatorLED).To(Equal("Off"))
+
+		managers, err := c.GetManagers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managers).To(HaveLen(1))
+		Expect(managers[0].Id).To(Equal("bmc"))
+	})
+
+	It("fails with the wrong password", func() {
+		con := hostConnectCon(bmc, false)
+		con.Password = "wrong"
+		_, err := redfish.NewClient(con, log)
+		Expect(err).To(HaveOccurred())
+		Expect(bmc.Sessions()).To(Equal(0))
+	})
+
+	It("reuses the cached client", func() {
+		for i := 0; i < 3; i++ {
+			_, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(bmc.CountRequests(http.MethodPost, "/redfish/v1/SessionService/Sessions")).To(Equal(1))
+
+		// the cached client is replaced after its session expires
+		bmc.Fail(http.MethodGet, "/redfish/v1/Systems", http.StatusUnauthorized, 1)
+		_, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.CountRequests(http.MethodPost, "/redfish/v1/SessionService/Sessions")).To(Equal(2))
+		// the replaced client is logged out after it becomes idle
+		Expect(bmc.Sessions()).To(Equal(2))
+	})
+
+	It("operates the power of the designated system", func() {
+		bmc.SetSystems(
+			emulator.System{Id: "node1", PowerState: emulator.PowerOn, Health: "OK"},
+			emulator.System{Id: "node2", PowerState: emulator.PowerOn, Health: "OK"},
+		)
+		bmc.SetPowerTransitionDelay(200 * time.Millisecond)
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the system must be specified for the multi-node host
+		Expect(c.Power(topohubv1beta1.BootCmdForceOff, "")).Error().To(HaveOccurred())
+
+		Expect(c.Power(topohubv1beta1.BootCmdForceOff, "node2")).To(BeEmpty())
+		system, _ := bmc.System("node2")
+		Expect(system.PowerState).To(Equal(emulator.PowerPoweringOff))
+		Eventually(func() string {
+			system, _ := bmc.System("node2")
+			return system.PowerState
+		}).WithTimeout(2 * time.Second).Should(Equal(emulator.PowerOff))
+
+		system, _ = bmc.System("node1")
+		Expect(system.PowerState).To(Equal(emulator.PowerOn))
+	})
+
+	It("reports the failure of the reset action", func() {
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		bmc.Fail(http.MethodPost, "/redfish/v1/Systems/1/Actions", http.StatusInternalServerError, 1)
+		Expect(c.Power(topohubv1beta1.BootCmdForceRestart, "")).Error().To(HaveOccurred())
+		Expect(c.Power(topohubv1beta1.BootCmdForceRestart, "")).To(BeEmpty())
+	})
+
+	It("returns the task of the asynchronous reset", func() {
+		bmc.SetSystems(emulator.System{Id: "1", PowerState: emulator.PowerOn, Health: "OK", AsyncReset: true})
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		taskUri, err := c.Power(topohubv1beta1.BootCmdGracefulRestart, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskUri).To(Equal(bmc.Tasks()[0]))
+
+		task, err := c.GetTask(taskUri)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(task.State).To(Equal("Running"))
+		finished, _ := redfish.TaskFinished(task)
+		Expect(finished).To(BeFalse())
+	})
+
+	It("boots the system from the virtual media", func() {
+		bmc.SetSystems(emulator.System{
+			Id:                  "1",
+			PowerState:          emulator.PowerOff,
+			Health:              "OK",
+			SupportedResetTypes: []string{"On", "ForceOff", "ForceRestart"},
+			MediaImage:          "http://10.0.0.1/old.iso",
+		})
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the previous image is ejected, and the system which is off is powered on with the reset type it supports
+		mediaUri, taskUri, err := c.VirtualMediaBoot("", "http://10.0.0.1/os.iso")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mediaUri).To(Equal("/redfish/v1/Systems/1/VirtualMedia/Cd"))
+		Expect(taskUri).To(BeEmpty())
+		system, _ := bmc.System("1")
+		Expect(system.MediaImage).To(Equal("http://10.0.0.1/os.iso"))
+		Expect(system.Boot.OverrideTarget).To(Equal("Cd"))
+		Expect(system.Boot.OverrideEnabled).To(Equal("Once"))
+		Expect(system.PowerState).To(Equal(emulator.PowerOn))
+
+		Expect(c.EjectVirtualMedia(mediaUri)).To(Succeed())
+		system, _ = bmc.System("1")
+		Expect(system.MediaImage).To(BeEmpty())
+	})
+
+	It("collects the network adapters with the link state and LLDP neighbor", func() {
+		bmc.SetNetworkAdapters(
+			emulator.NetworkAdapter{
+				Id: "NIC.2", Manufacturer: "Mellanox", Model: "ConnectX-6", FirmwareVersion: "22.31.1014",
+				Ports: []emulator.Port{
+					{Id: "2", LinkStatus: "LinkDown", MACAddresses: []string{"b8:3f:d2:00:00:02"}},
+					{Id: "1", LinkStatus: "LinkUp", SpeedGbps: 25, MACAddresses: []string{"b8:3f:d2:00:00:01"},
+						LLDP: &emulator.LLDPNeighbor{ChassisId: "00:1c:73:00:00:99", PortId: "Ethernet12", SystemName: "leaf-1", ManagementAddressIPv4: "10.0.0.254"}},
+				},
+			},
+			emulator.NetworkAdapter{
+				Id: "NIC.1", Manufacturer: "Intel", Model: "X710", LegacyPorts: true,
+				Ports: []emulator.Port{{Id: "1", LinkStatus: "LinkUp", SpeedGbps: 10, MACAddresses: []string{"3c:fd:fe:00:00:01"}}},
+			},
+		)
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		inventory, err := c.GetInventory()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventory.Systems).To(HaveLen(1))
+		adapters := inventory.Systems[0].NetworkAdapters
+		Expect(adapters).To(HaveLen(2))
+
+		Expect(adapters[0].Model).To(Equal("X710"))
+		Expect(adapters[0].Ports).To(ConsistOf(topohubv1beta1.NetworkPortInventory{
+			ODataId:      "/redfish/v1/Chassis/1/NetworkAdapters/NIC.1/NetworkPorts/1",
+			PortId:       "1",
+			LinkStatus:   "LinkUp",
+			SpeedMbps:    10000,
+			MACAddresses: []string{"3c:fd:fe:00:00:01"},
+			Health:       "OK",
+			State:        "Enabled",
+		}))
+
+		Expect(adapters[1].FirmwareVersion).To(Equal("22.31.1014"))
+		Expect(adapters[1].Ports).To(HaveLen(2))
+		port := adapters[1].Ports[0]
+		Expect(port.PortId).To(Equal("1"))
+		Expect(port.LinkStatus).To(Equal("LinkUp"))
+		Expect(port.SpeedMbps).To(Equal(int32(25000)))
+		Expect(port.LLDP).NotTo(BeNil())
+		Expect(port.LLDP.SystemName).To(Equal("leaf-1"))
+		Expect(port.LLDP.PortId).To(Equal("Ethernet12"))
+		Expect(port.LLDP.ManagementAddressIPv4).To(Equal("10.0.0.254"))
+		Expect(adapters[1].Ports[1].LinkStatus).To(Equal("LinkDown"))
+		Expect(adapters[1].Ports[1].LLDP).To(BeNil())
+	})
+
+	It("sets the indicator led of the chassis", func() {
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.SetIndicatorLED("", "Blinking")).To(Equal("Off"))
+		Expect(bmc.IndicatorLED()).To(Equal("Blinking"))
+		systems, err := c.GetSystems()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(systems[0].IndicatorLED).To(Equal("Blinking"))
+
+		// the new bmc only reports the LocationIndicatorActive
+		bmc.SetIndicatorLED("Lit", true)
+		Expect(c.SetIndicatorLED("", "Off")).To(Equal("Lit"))
+		Expect(bmc.IndicatorLED()).To(Equal("Off"))
+	})
+
+	It("resets the manager and drops the sessions", func() {
+		bmc.SetManagerResetDelay(time.Hour)
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.ResetManager("", "PowerCycle")).NotTo(Succeed())
+		Expect(c.ResetManager("", "ForceRestart")).To(Succeed())
+		manager, _ := bmc.Manager("bmc")
+		Expect(manager.ResetType).To(Equal("ForceRestart"))
+		Expect(bmc.Sessions()).To(BeZero())
+
+		// the bmc does not respond while it is restarting
+		redfish.CacheClient.Invalidate(bmc.Host())
+		_, err = redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("sets the network services of the manager and the syslog destination", func() {
+		bmc.SetNetworkProtocol(emulator.NetworkProtocol{
+			Protocols:  map[string]bool{"IPMI": true, "SSH": true},
+			NTPServers: []string{"10.0.0.1", ""},
+		})
+		bmc.AddSubscription(emulator.Subscription{Destination: "syslog://10.0.0.9:514", SubscriptionType: "Syslog", Protocol: "SyslogUDP"})
+		bmc.AddSubscription(emulator.Subscription{Destination: "https://10.0.0.2/events", SubscriptionType: "RedfishEvent"})
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		network, err := c.GetManagerNetwork()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(network.ProtocolUri).To(Equal("/redfish/v1/Managers/bmc/NetworkProtocol"))
+		Expect(network.Protocols).To(Equal(map[string]bool{"IPMI": true, "SSH": true}))
+		Expect(network.NTPEnabled).To(BeFalse())
+		Expect(network.NTPServers).To(Equal([]string{"10.0.0.1"}))
+		Expect(network.InterfaceUri).To(Equal("/redfish/v1/Managers/bmc/EthernetInterfaces/eth0"))
+
+		Expect(c.SetManagerNetworkProtocol(network.ProtocolUri, redfish.NetworkProtocolSetting{
+			Protocols:  map[string]bool{"IPMI": false},
+			NTPServers: []string{"10.0.0.3", "10.0.0.4"},
+		})).To(Succeed())
+		useDhcp := false
+		Expect(c.SetManagerEthernetInterface(network.InterfaceUri, redfish.EthernetInterfaceSetting{
+			StaticNameServers: []string{"10.0.0.53"},
+			DHCPUseNTPServers: &useDhcp,
+		})).To(Succeed())
+		protocol := bmc.NetworkProtocol()
+		Expect(protocol.Protocols).To(Equal(map[string]bool{"IPMI": false, "SSH": true}))
+		Expect(protocol.NTPEnabled).To(BeTrue())
+		Expect(protocol.NTPServers).To(Equal([]string{"10.0.0.3", "10.0.0.4"}))
+		Expect(protocol.StaticNameServers).To(Equal([]string{"10.0.0.53"}))
+
+		// the stale syslog destination is replaced, and the redfish event subscription is kept
+		destination := redfish.SyslogDestination{Address: "10.0.0.8", Port: 514, Protocol: "SyslogTCP"}
+		Expect(c.EnsureSyslogDestination(destination)).To(BeTrue())
+		Expect(c.EnsureSyslogDestination(destination)).To(BeFalse())
+		subscriptions, err := c.GetSyslogSubscriptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subscriptions).To(HaveLen(1))
+		Expect(subscriptions[0].Destination).To(Equal("syslog://10.0.0.8:514"))
+		Expect(subscriptions[0].Protocol).To(Equal("SyslogTCP"))
+		Expect(bmc.Subscriptions()).To(HaveLen(2))
+	})
+
+	It("reads the power consumption and sets the power limit", func() {
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.GetPowerControls()).Error().To(MatchError(redfish.ErrNotSupported))
+
+		bmc.SetPowerControl(emulator.PowerControl{ConsumedWatts: 320, AverageConsumedWatts: 300, MinConsumedWatts: 210, MaxConsumedWatts: 450})
+		controls, err := c.GetPowerControls()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controls).To(HaveLen(1))
+		Expect(controls[0].ChassisId).To(Equal("1"))
+		Expect(controls[0].Uri).To(Equal("/redfish/v1/Chassis/1/Power"))
+		Expect(controls[0].ConsumedWatts).To(Equal(float32(320)))
+		Expect(controls[0].MaxConsumedWatts).To(Equal(float32(450)))
+		Expect(controls[0].LimitWatts).To(BeZero())
+
+		Expect(c.SetPowerLimit(controls[0].Uri, redfish.PowerLimit{LimitWatts: 400, CorrectionInMs: 1000})).To(Succeed())
+		pc, _ := bmc.PowerControl()
+		Expect(pc.LimitWatts).To(Equal(float32(400)))
+		Expect(pc.CorrectionInMs).To(Equal(int64(1000)))
+	})
+
+	It("gets the log entries of all the systems", func() {
+		bmc.AddLogEntry("1", emulator.LogEntry{Severity: "Critical", Message: "the fan is failed"})
+		bmc.AddLogEntry("1", emulator.LogEntry{Severity: "OK", Message: "the fan is recovered"})
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		entries, err := c.GetLog()
+		Expect(err).NotTo(HaveOccurred())
+		messages := []string{}
+		for _, e := range entries {
+			messages = append(messages, e.Message)
+		}
+		Expect(messages).To(ConsistOf("the fan is failed", "the fan is recovered"))
+	})
+
+	It("tracks the task of the firmware update", func() {
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		taskUri, err := c.SimpleUpdate("http://127.0.0.1/bios.bin", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.Tasks()).To(ConsistOf(taskUri))
+
+		task, err := c.GetTask(taskUri)
+		Expect(err).NotTo(HaveOccurred())
+		finished, _ := redfish.TaskFinished(task)
+		Expect(finished).To(BeFalse())
+
+		bmc.SetTask(taskUri, emulator.Task{State: "Exception", Status: "Critical", Messages: []string{"the image is invalid"}})
+		task, err = c.GetTask(taskUri)
+		Expect(err).NotTo(HaveOccurred())
+		finished, succeeded := redfish.TaskFinished(task)
+		Expect(finished).To(BeTrue())
+		Expect(succeeded).To(BeFalse())
+		Expect(task.Messages).To(ConsistOf("the image is invalid"))
+	})
+
+	It("creates and deletes the volume on the storage", func() {
+		bmc.SetStorage("1", emulator.Storage{
+			Id:                 "RAID.1",
+			SupportedRAIDTypes: []string{"RAID1", "RAID0"},
+			Drives:             []emulator.Drive{{Id: "Disk.0"}, {Id: "Disk.1"}},
+		})
+		c, err := redfish.NewClient(hostConnectCon(bmc, false), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		storages, err := c.GetStorage("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storages).To(HaveLen(1))
+		Expect(storages[0].SupportedRAIDTypes).To(Equal([]string{"RAID0", "RAID1"}))
+		Expect(storages[0].Drives).To(HaveLen(2))
+		Expect(storages[0].Volumes).To(BeEmpty())
+
+		taskUri, err := c.CreateVolume(storages[0].ODataId, redfish.VolumeRequest{
+			Name:     "os",
+			RAIDType: "RAID1",
+			Drives:   storages[0].Drives,
+		}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskUri).To(BeEmpty())
+
+		storages, err = c.GetStorage("1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storages[0].Volumes).To(HaveLen(1))
+		Expect(storages[0].Volumes[0].Name).To(Equal("os"))
+		Expect(storages[0].Volumes[0].RAIDType).To(Equal("RAID1"))
+		Expect(storages[0].Volumes[0].Drives).To(Equal(storages[0].Drives))
+
+		_, err = c.DeleteVolume(storages[0].Volumes[0].ODataId, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.Volumes("1", "RAID.1")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("RedfishClient with https", Label("unitest"), func() {
+	var bmc *emulator.Server
+	log := zap.NewNop().Sugar()
+
+	BeforeEach(func() {
+		bmc = emulator.New()
+		bmc.SetCredential(username, password)
+	})
+
+	AfterEach(func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		bmc.Close()
+	})
+
+	It("records the certificate on first use", func() {
+		con := hostConnectCon(bmc, true)
+		con.TLS = &hoststatusData.TLSTrust{}
+		c, err := redfish.NewClient(con, log)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert := c.GetCertificate()
+		Expect(cert).NotTo(BeNil())
+		Expect(cert.Fingerprint).To(Equal(redfish.Fingerprint(bmc.Certificate())))
+	})
+
+	It("refuses the certificate which is changed", func() {
+		con := hostConnectCon(bmc, true)
+		con.TLS = &hoststatusData.TLSTrust{KnownFingerprint: "00:11:22"}
+		_, err := redfish.NewClient(con, log)
+		var certErr *redfish.CertificateError
+		Expect(errors.As(err, &certErr)).To(BeTrue())
+		Expect(certErr.Reason).To(Equal(redfish.CertificateChanged))
+		// the credential is not sent to the untrusted bmc
+		Expect(bmc.Sessions()).To(Equal(0))
+	})
+
+	It("verifies the pinned fingerprints", func() {
+		con := hostConnectCon(bmc, true)
+		con.TLS = &hoststatusData.TLSTrust{Fingerprints: []string{"00:11:22"}}
+		_, err := redfish.NewClient(con, log)
+		var certErr *redfish.CertificateError
+		Expect(errors.As(err, &certErr)).To(BeTrue())
+
+		con.TLS = &hoststatusData.TLSTrust{Fingerprints: []string{redfish.Fingerprint(bmc.Certificate())}}
+		_, err = redfish.NewClient(con, log)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("replaces the https certificate with the one signed for the certificate signing request", func() {
+		c, err := redfish.NewClient(hostConnectCon(bmc, true), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		collectionUri, certificates, err := c.GetHttpsCertificates()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(collectionUri).To(Equal("/redfish/v1/Managers/bmc/NetworkProtocol/HTTPS/Certificates"))
+		Expect(certificates).To(HaveLen(1))
+		Expect(certificates[0].Uri).To(Equal(collectionUri + "/1"))
+		Expect(certificates[0].CertificateString).To(ContainSubstring("BEGIN CERTIFICATE"))
+
+		csrPem, err := c.GenerateCSR(collectionUri, redfish.CertificateSigningRequest{
+			CommonName:       bmc.Host(),
+			AlternativeNames: []string{bmc.Host(), "bmc.example.com"},
+			Organization:     "topohub",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		block, _ := pem.Decode([]byte(csrPem))
+		Expect(block).NotTo(BeNil())
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(csr.Subject.CommonName).To(Equal(bmc.Host()))
+		Expect(csr.Subject.Organization).To(ConsistOf("topohub"))
+		Expect(csr.DNSNames).To(ConsistOf("bmc.example.com"))
+
+		// the certificate is self-signed for the key of the request
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      csr.Subject,
+			IPAddresses:  []net.IP{net.ParseIP(bmc.Host())},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, csr.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		signed := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		Expect(c.ReplaceCertificate(certificates[0].Uri, signed)).To(Succeed())
+		Expect(bmc.HttpsCertificate()).To(Equal(signed))
+
+		// the certificate which does not match the key generated by the bmc is refused
+		der, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.ReplaceCertificate(certificates[0].Uri, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))).NotTo(Succeed())
+		Expect(bmc.HttpsCertificate()).To(Equal(signed))
+
+		_, certificates, err = c.GetHttpsCertificates()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certificates[0].ValidNotAfter).To(Equal(template.NotAfter.UTC().Format(time.RFC3339)))
+	})
+})
@@ -0,0 +1,187 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	accountServicePath = rootPath + "/AccountService"
+	accountsPath       = accountServicePath + "/Accounts"
+)
+
+// Account is the ManagerAccount of the account service, the enabled accounts could log in to the emulator besides the
+// credential set by SetCredential
+type Account struct {
+	Id       string
+	UserName string
+	Password string
+	RoleId   string
+	Enabled  bool
+}
+
+// AddAccount adds the ManagerAccount to the account service
+func (s *Server) AddAccount(account Account) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.addAccount(account)
+}
+
+// Accounts returns the ManagerAccount of the account service
+func (s *Server) Accounts() []Account {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := []Account{}
+	for _, account := range s.accounts {
+		result = append(result, *account)
+	}
+	return result
+}
+
+func (s *Server) addAccount(account Account) string {
+	s.accountId++
+	if len(account.Id) == 0 {
+		account.Id = strconv.Itoa(s.accountId)
+	}
+	s.accounts = append(s.accounts, &account)
+	return accountsPath + "/" + account.Id
+}
+
+// validCredential checks the credential with the one set by SetCredential and the enabled accounts
+func (s *Server) validCredential(username, password string) bool {
+	if username == s.username && password == s.password {
+		return true
+	}
+	for _, account := range s.accounts {
+		if account.Enabled && account.UserName == username && account.Password == password {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) findAccount(path string) *Account {
+	for _, account := range s.accounts {
+		if path == accountsPath+"/"+account.Id {
+			return account
+		}
+	}
+	return nil
+}
+
+// getAccountService responds the account service and its accounts, the password is not reported
+func (s *Server) getAccountService(w http.ResponseWriter, path string) bool {
+	switch path {
+	case accountServicePath:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":      accountServicePath,
+			"Id":             "AccountService",
+			"ServiceEnabled": true,
+			"Accounts":       link(accountsPath),
+		})
+		return true
+	case accountsPath:
+		members := []string{}
+		for _, account := range s.accounts {
+			members = append(members, accountsPath+"/"+account.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(accountsPath, members))
+		return true
+	}
+	account := s.findAccount(path)
+	if account == nil {
+		return false
+	}
+	writeJSON(w, http.StatusOK, accountResource(account))
+	return true
+}
+
+func accountResource(account *Account) map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id": accountsPath + "/" + account.Id,
+		"Id":        account.Id,
+		"UserName":  account.UserName,
+		"Password":  nil,
+		"RoleId":    account.RoleId,
+		"Enabled":   account.Enabled,
+	}
+}
+
+// createAccount creates the ManagerAccount, the user name must be unique
+func (s *Server) createAccount(w http.ResponseWriter, body []byte) {
+	var param struct {
+		UserName string
+		Password string
+		RoleId   string
+		Enabled  *bool
+	}
+	if err := json.Unmarshal(body, &param); err != nil || len(param.UserName) == 0 || len(param.Password) == 0 {
+		writeError(w, http.StatusBadRequest, "UserName and Password are required")
+		return
+	}
+	for _, account := range s.accounts {
+		if account.UserName == param.UserName {
+			writeError(w, http.StatusConflict, fmt.Sprintf("the account %s exists", param.UserName))
+			return
+		}
+	}
+	account := Account{UserName: param.UserName, Password: param.Password, RoleId: param.RoleId, Enabled: true}
+	if param.Enabled != nil {
+		account.Enabled = *param.Enabled
+	}
+	uri := s.addAccount(account)
+	w.Header().Set("Location", uri)
+	writeJSON(w, http.StatusCreated, accountResource(s.findAccount(uri)))
+}
+
+// patchAccount changes the user name, password, role and the enabled state of the ManagerAccount
+func (s *Server) patchAccount(w http.ResponseWriter, path string, body []byte) bool {
+	account := s.findAccount(path)
+	if account == nil {
+		return false
+	}
+	var param struct {
+		UserName *string
+		Password *string
+		RoleId   *string
+		Enabled  *bool
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return true
+	}
+	if param.UserName != nil {
+		account.UserName = *param.UserName
+	}
+	if param.Password != nil {
+		account.Password = *param.Password
+	}
+	if param.RoleId != nil {
+		account.RoleId = *param.RoleId
+	}
+	if param.Enabled != nil {
+		account.Enabled = *param.Enabled
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// deleteAccount removes the ManagerAccount
+func (s *Server) deleteAccount(w http.ResponseWriter, path string) bool {
+	for i, account := range s.accounts {
+		if path == accountsPath+"/"+account.Id {
+			s.accounts = append(s.accounts[:i], s.accounts[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return true
+		}
+	}
+	return false
+}
+
+// isAccountPath returns true for the path under the account service
+func isAccountPath(path string) bool {
+	return path == accountServicePath || strings.HasPrefix(path, accountServicePath+"/")
+}
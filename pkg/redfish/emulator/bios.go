@@ -0,0 +1,135 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// immediateApplyTime makes the PATCH of the Bios/Settings take effect at once, other apply times leave the attributes pending
+const immediateApplyTime = "Immediate"
+
+// Bios is the bios attributes of the system
+type Bios struct {
+	// Attributes take effect now, the values are string, bool or float64 like the json of the bmc
+	Attributes map[string]interface{}
+	// Pending are the attributes in the Bios/Settings, which take effect after the reboot
+	Pending map[string]interface{}
+	// SupportedApplyTimes is reported in the @Redfish.Settings, it is not reported when it is empty
+	SupportedApplyTimes []string
+	// ApplyTime is the apply time of the last PATCH of the Bios/Settings
+	ApplyTime string
+}
+
+// SetBios replaces the bios attributes of the system
+func (s *Server) SetBios(systemId string, bios Bios) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.bios == nil {
+		s.bios = map[string]*Bios{}
+	}
+	bios.Attributes = copyAttributes(bios.Attributes)
+	bios.Pending = copyAttributes(bios.Pending)
+	s.bios[systemId] = &bios
+}
+
+// Bios returns the current bios attributes of the system, which are changed by the client
+func (s *Server) Bios(systemId string) (Bios, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	bios, ok := s.bios[systemId]
+	if !ok {
+		return Bios{}, false
+	}
+	result := *bios
+	result.Attributes = copyAttributes(bios.Attributes)
+	result.Pending = copyAttributes(bios.Pending)
+	return result, true
+}
+
+func copyAttributes(attrs map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range attrs {
+		result[k] = v
+	}
+	return result
+}
+
+// getBios responds the Bios of the system and its Bios/Settings, the segments follow Bios. The system without the
+// attributes set by SetBios reports no attribute and no settings object
+func (s *Server) getBios(w http.ResponseWriter, system *System, segments []string) bool {
+	uri := systemsPath + "/" + system.Id + "/Bios"
+	bios := s.bios[system.Id]
+	switch {
+	case len(segments) == 0:
+		resource := map[string]interface{}{
+			"@odata.id":  uri,
+			"Id":         "Bios",
+			"Attributes": map[string]interface{}{},
+			"Actions": map[string]interface{}{
+				"#" + resetBios: map[string]string{"target": uri + "/Actions/" + resetBios},
+			},
+		}
+		if bios != nil {
+			resource["Attributes"] = bios.Attributes
+			settings := map[string]interface{}{
+				"SettingsObject": link(uri + "/Settings"),
+			}
+			if len(bios.SupportedApplyTimes) > 0 {
+				settings["SupportedApplyTimes"] = bios.SupportedApplyTimes
+			}
+			resource["@Redfish.Settings"] = settings
+		}
+		writeJSON(w, http.StatusOK, resource)
+		return true
+	case len(segments) == 1 && segments[0] == "Settings" && bios != nil:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":  uri + "/Settings",
+			"Id":         "Settings",
+			"Attributes": bios.Pending,
+		})
+		return true
+	}
+	return false
+}
+
+// patchBios writes the attributes to the Bios/Settings, they are pending until the system is reset unless the apply
+// time is Immediate. The bmc refuses the attribute which the bios does not have
+func (s *Server) patchBios(w http.ResponseWriter, path string, body []byte) bool {
+	segments := strings.Split(strings.TrimPrefix(path, systemsPath+"/"), "/")
+	if len(segments) != 3 || segments[1] != "Bios" || segments[2] != "Settings" {
+		return false
+	}
+	bios := s.bios[segments[0]]
+	if bios == nil {
+		return false
+	}
+	var param struct {
+		Attributes        map[string]interface{}
+		SettingsApplyTime struct {
+			ApplyTime string
+		} `json:"@Redfish.SettingsApplyTime"`
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return true
+	}
+	for name := range param.Attributes {
+		if _, ok := bios.Attributes[name]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("the attribute %s is not found", name))
+			return true
+		}
+	}
+	bios.ApplyTime = param.SettingsApplyTime.ApplyTime
+	for name, value := range param.Attributes {
+		if bios.ApplyTime == immediateApplyTime {
+			bios.Attributes[name] = value
+			delete(bios.Pending, name)
+			continue
+		}
+		bios.Pending[name] = value
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
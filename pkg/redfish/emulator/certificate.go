@@ -0,0 +1,237 @@
+package emulator
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	certificateServicePath = rootPath + "/CertificateService"
+	generateCSR            = "CertificateService.GenerateCSR"
+	replaceCertificate     = "CertificateService.ReplaceCertificate"
+)
+
+// SetHttpsCertificate replaces the https certificate in PEM which the manager reports. The certificate is only reported by
+// the redfish resource since its key is unknown, use ServeCertificate to serve the certificate too
+func (s *Server) SetHttpsCertificate(certificate string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.httpsCertificate = certificate
+}
+
+// ServeCertificate installs the certificate on the https emulator, which is served to the new connections and reported by
+// the manager, as if the certificate of the bmc is replaced. The connections are closed like the bmc restarts its web server
+func (s *Server) ServeCertificate(certificate tls.Certificate) {
+	s.lock.Lock()
+	s.serveCertificate(certificate)
+	s.lock.Unlock()
+	s.server.CloseClientConnections()
+}
+
+func (s *Server) serveCertificate(certificate tls.Certificate) {
+	s.servingCertificate = &certificate
+	s.httpsCertificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]}))
+}
+
+// configForClient serves the installed certificate, the config of httptest is used before any certificate is installed
+func (s *Server) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.servingCertificate == nil {
+		return nil, nil
+	}
+	config := s.server.TLS.Clone()
+	config.GetConfigForClient = nil
+	config.Certificates = []tls.Certificate{*s.servingCertificate}
+	return config, nil
+}
+
+// HttpsCertificate returns the https certificate in PEM which the manager reports, it is replaced by the ReplaceCertificate action
+func (s *Server) HttpsCertificate() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.currentHttpsCertificate()
+}
+
+// currentHttpsCertificate returns the certificate of httptest before the certificate is set, it is empty for http
+func (s *Server) currentHttpsCertificate() string {
+	if len(s.httpsCertificate) == 0 && s.server.Certificate() != nil {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw}))
+	}
+	return s.httpsCertificate
+}
+
+func parseCertificate(certificate string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil {
+		return nil, fmt.Errorf("the certificate is not in PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// getManagerResource responds the network protocol of the manager, its https certificates and ethernet interfaces, the segments
+// follow the uri of the manager
+func (s *Server) getManagerResource(w http.ResponseWriter, uri string, segments []string) bool {
+	protocolUri := uri + "/NetworkProtocol"
+	certificatesUri := protocolUri + "/HTTPS/Certificates"
+	switch {
+	case len(segments) == 1 && segments[0] == "NetworkProtocol":
+		writeJSON(w, http.StatusOK, s.networkProtocolResource(protocolUri, certificatesUri))
+		return true
+	case segments[0] == "EthernetInterfaces":
+		return s.getEthernetInterface(w, uri, segments[1:])
+	case len(segments) == 3 && segments[0] == "NetworkProtocol" && segments[1] == "HTTPS" && segments[2] == "Certificates":
+		members := []string{}
+		if len(s.currentHttpsCertificate()) > 0 {
+			members = append(members, certificatesUri+"/1")
+		}
+		writeJSON(w, http.StatusOK, collection(certificatesUri, members))
+		return true
+	case len(segments) == 4 && segments[0] == "NetworkProtocol" && segments[1] == "HTTPS" && segments[2] == "Certificates" && segments[3] == "1":
+		certificate := s.currentHttpsCertificate()
+		if len(certificate) == 0 {
+			return false
+		}
+		resource := map[string]interface{}{
+			"@odata.id":         certificatesUri + "/1",
+			"Id":                "1",
+			"CertificateString": certificate,
+			"CertificateType":   "PEM",
+		}
+		if cert, err := parseCertificate(certificate); err == nil {
+			resource["ValidNotBefore"] = cert.NotBefore.UTC().Format(time.RFC3339)
+			resource["ValidNotAfter"] = cert.NotAfter.UTC().Format(time.RFC3339)
+			resource["Subject"] = map[string]string{"CommonName": cert.Subject.CommonName}
+			resource["Issuer"] = map[string]string{"CommonName": cert.Issuer.CommonName}
+		}
+		writeJSON(w, http.StatusOK, resource)
+		return true
+	}
+	return false
+}
+
+// certificateService responds the CertificateService with its actions
+func certificateService() map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id": certificateServicePath,
+		"Id":        "CertificateService",
+		"Name":      "Certificate Service",
+		"Actions": map[string]interface{}{
+			"#" + generateCSR:        map[string]string{"target": certificateServicePath + "/Actions/" + generateCSR},
+			"#" + replaceCertificate: map[string]string{"target": certificateServicePath + "/Actions/" + replaceCertificate},
+		},
+	}
+}
+
+// generateCSR creates a new key pair for the https certificate, and responds the certificate signing request of it
+func (s *Server) generateCSR(w http.ResponseWriter, body []byte) {
+	var param struct {
+		CertificateCollection struct {
+			ODataID string `json:"@odata.id"`
+		}
+		CommonName         string
+		AlternativeNames   []string
+		Organization       string
+		OrganizationalUnit string
+		City               string
+		State              string
+		Country            string
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if len(param.CertificateCollection.ODataID) == 0 || len(param.CommonName) == 0 {
+		writeError(w, http.StatusBadRequest, "CertificateCollection and CommonName are required")
+		return
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         param.CommonName,
+			Organization:       nameField(param.Organization),
+			OrganizationalUnit: nameField(param.OrganizationalUnit),
+			Locality:           nameField(param.City),
+			Province:           nameField(param.State),
+			Country:            nameField(param.Country),
+		},
+	}
+	for _, name := range param.AlternativeNames {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.csrKey = key
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"CSRString":             string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})),
+		"CertificateCollection": link(param.CertificateCollection.ODataID),
+	})
+}
+
+func nameField(value string) []string {
+	if len(value) == 0 {
+		return nil
+	}
+	return []string{value}
+}
+
+// replaceCertificate installs the certificate, which must be issued for the key of the last certificate signing request.
+// The new certificate is served to the new connections
+func (s *Server) replaceCertificate(w http.ResponseWriter, body []byte) {
+	var param struct {
+		CertificateUri struct {
+			ODataID string `json:"@odata.id"`
+		}
+		CertificateString string
+		CertificateType   string
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if len(param.CertificateUri.ODataID) == 0 || param.CertificateType != "PEM" {
+		writeError(w, http.StatusBadRequest, "CertificateUri and the PEM CertificateType are required")
+		return
+	}
+	cert, err := parseCertificate(param.CertificateString)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid certificate: %v", err))
+		return
+	}
+	if s.csrKey == nil {
+		writeError(w, http.StatusBadRequest, "no certificate signing request is generated")
+		return
+	}
+	expected, _ := x509.MarshalPKIXPublicKey(&s.csrKey.PublicKey)
+	actual, _ := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if !bytes.Equal(expected, actual) {
+		writeError(w, http.StatusBadRequest, "the certificate does not match the key of the certificate signing request")
+		return
+	}
+	s.serveCertificate(tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: s.csrKey, Leaf: cert})
+	s.csrKey = nil
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,322 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	chassisPath = rootPath + "/Chassis"
+	chassisId   = "1"
+)
+
+// NetworkAdapter is a network adapter in the chassis
+type NetworkAdapter struct {
+	Id              string
+	Manufacturer    string
+	Model           string
+	SerialNumber    string
+	FirmwareVersion string
+	Ports           []Port
+	// LegacyPorts reports the ports as the deprecated NetworkPorts, which do not carry the LLDP neighbor
+	LegacyPorts bool
+}
+
+type Port struct {
+	Id string
+	// LinkStatus is LinkUp or LinkDown
+	LinkStatus   string
+	SpeedGbps    float32
+	MACAddresses []string
+	LLDP         *LLDPNeighbor
+}
+
+type LLDPNeighbor struct {
+	ChassisId             string
+	PortId                string
+	SystemName            string
+	ManagementAddressIPv4 string
+}
+
+// PowerControl is the power consumption and the power limit of the chassis
+type PowerControl struct {
+	ConsumedWatts        float32
+	AverageConsumedWatts float32
+	MinConsumedWatts     float32
+	MaxConsumedWatts     float32
+	// LimitWatts is 0 when the power is not limited
+	LimitWatts     float32
+	LimitException string
+	CorrectionInMs int64
+}
+
+// SetPowerControl makes the chassis report the Power resource with the PowerControl
+func (s *Server) SetPowerControl(pc PowerControl) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.powerControl = &pc
+}
+
+// PowerControl returns the current PowerControl of the chassis, the limit is changed by the client
+func (s *Server) PowerControl() (PowerControl, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.powerControl == nil {
+		return PowerControl{}, false
+	}
+	return *s.powerControl, true
+}
+
+// SetIndicatorLED sets the state of the indicator led of the chassis, which is Lit, Blinking or Off.
+// The chassis reports only the LocationIndicatorActive rather than the deprecated IndicatorLED when locationOnly is true
+func (s *Server) SetIndicatorLED(state string, locationOnly bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.indicatorLED = state
+	s.locationIndicatorOnly = locationOnly
+}
+
+// IndicatorLED returns the current state of the indicator led of the chassis
+func (s *Server) IndicatorLED() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.indicatorLEDState()
+}
+
+func (s *Server) indicatorLEDState() string {
+	if len(s.indicatorLED) == 0 {
+		return "Off"
+	}
+	return s.indicatorLED
+}
+
+// SetNetworkAdapters replaces the network adapters of the chassis, which contains all the systems
+func (s *Server) SetNetworkAdapters(adapters ...NetworkAdapter) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.networkAdapters = adapters
+}
+
+// getChassisResource responds the chassis, its network adapters and ports. The segments follow /redfish/v1/Chassis
+func (s *Server) getChassisResource(w http.ResponseWriter, segments []string) bool {
+	if len(segments) == 0 {
+		writeJSON(w, http.StatusOK, collection(chassisPath, []string{chassisPath + "/" + chassisId}))
+		return true
+	}
+	if segments[0] != chassisId {
+		return false
+	}
+	uri := chassisPath + "/" + chassisId
+
+	switch {
+	case len(segments) == 1:
+		systems := []map[string]string{}
+		for _, system := range s.systems {
+			systems = append(systems, link(systemsPath+"/"+system.Id))
+		}
+		chassis := map[string]interface{}{
+			"@odata.id":       uri,
+			"Id":              chassisId,
+			"Name":            "Chassis",
+			"ChassisType":     "RackMount",
+			"Status":          map[string]string{"State": "Enabled", "Health": "OK"},
+			"NetworkAdapters": link(uri + "/NetworkAdapters"),
+			"Links":           map[string]interface{}{"ComputerSystems": systems},
+		}
+		if s.powerControl != nil {
+			chassis["Power"] = link(uri + "/Power")
+		}
+		chassis["LocationIndicatorActive"] = s.indicatorLEDState() != "Off"
+		if !s.locationIndicatorOnly {
+			chassis["IndicatorLED"] = s.indicatorLEDState()
+		}
+		writeJSON(w, http.StatusOK, chassis)
+		return true
+	case len(segments) == 2 && segments[1] == "Power" && s.powerControl != nil:
+		pc := s.powerControl
+		powerLimit := map[string]interface{}{
+			"LimitInWatts":   nil,
+			"LimitException": pc.LimitException,
+			"CorrectionInMs": pc.CorrectionInMs,
+		}
+		if pc.LimitWatts > 0 {
+			powerLimit["LimitInWatts"] = pc.LimitWatts
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id": uri + "/Power",
+			"Id":        "Power",
+			"PowerControl": []map[string]interface{}{{
+				"@odata.id":          uri + "/Power#/PowerControl/0",
+				"MemberId":           "0",
+				"PowerConsumedWatts": pc.ConsumedWatts,
+				"PowerMetrics": map[string]interface{}{
+					"IntervalInMin":        1,
+					"AverageConsumedWatts": pc.AverageConsumedWatts,
+					"MinConsumedWatts":     pc.MinConsumedWatts,
+					"MaxConsumedWatts":     pc.MaxConsumedWatts,
+				},
+				"PowerLimit": powerLimit,
+			}},
+		})
+		return true
+	case len(segments) == 2 && segments[1] == "NetworkAdapters":
+		members := []string{}
+		for _, adapter := range s.networkAdapters {
+			members = append(members, uri+"/NetworkAdapters/"+adapter.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(uri+"/NetworkAdapters", members))
+		return true
+	case len(segments) >= 3 && segments[1] == "NetworkAdapters":
+		for _, adapter := range s.networkAdapters {
+			if adapter.Id == segments[2] {
+				return s.getNetworkAdapterResource(w, uri+"/NetworkAdapters/"+adapter.Id, &adapter, segments[3:])
+			}
+		}
+	}
+	return false
+}
+
+// patchChassis sets the indicator led of the chassis, or the power limit of the chassis
+func (s *Server) patchChassis(w http.ResponseWriter, urlPath string, body []byte) bool {
+	uri := chassisPath + "/" + chassisId
+	switch {
+	case urlPath == uri:
+		var param struct {
+			IndicatorLED            string
+			LocationIndicatorActive *bool
+		}
+		if err := json.Unmarshal(body, &param); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+			return true
+		}
+		switch {
+		case len(param.IndicatorLED) > 0 && s.locationIndicatorOnly:
+			writeError(w, http.StatusBadRequest, "the property IndicatorLED is not supported")
+			return true
+		case len(param.IndicatorLED) > 0:
+			s.indicatorLED = param.IndicatorLED
+		case param.LocationIndicatorActive != nil && *param.LocationIndicatorActive:
+			s.indicatorLED = "Lit"
+		case param.LocationIndicatorActive != nil:
+			s.indicatorLED = "Off"
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	case urlPath == uri+"/Power" && s.powerControl != nil:
+		s.patchPower(w, body)
+		return true
+	}
+	return false
+}
+
+// patchPower sets the power limit of the first PowerControl
+func (s *Server) patchPower(w http.ResponseWriter, body []byte) {
+	var param struct {
+		PowerControl []struct {
+			PowerLimit *struct {
+				LimitInWatts   *float32
+				LimitException string
+				CorrectionInMs int64
+			}
+		}
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if len(param.PowerControl) == 0 || param.PowerControl[0].PowerLimit == nil {
+		writeError(w, http.StatusBadRequest, "PowerControl[0].PowerLimit is required")
+		return
+	}
+	limit := param.PowerControl[0].PowerLimit
+	if limit.LimitInWatts == nil {
+		s.powerControl.LimitWatts = 0
+	} else {
+		s.powerControl.LimitWatts = *limit.LimitInWatts
+	}
+	if len(limit.LimitException) > 0 {
+		s.powerControl.LimitException = limit.LimitException
+	}
+	if limit.CorrectionInMs > 0 {
+		s.powerControl.CorrectionInMs = limit.CorrectionInMs
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getNetworkAdapterResource responds the adapter and its ports, the segments follow the uri of the adapter
+func (s *Server) getNetworkAdapterResource(w http.ResponseWriter, uri string, adapter *NetworkAdapter, segments []string) bool {
+	portsName := "Ports"
+	if adapter.LegacyPorts {
+		portsName = "NetworkPorts"
+	}
+
+	switch {
+	case len(segments) == 0:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":    uri,
+			"Id":           adapter.Id,
+			"Name":         adapter.Model,
+			"Manufacturer": adapter.Manufacturer,
+			"Model":        adapter.Model,
+			"SerialNumber": adapter.SerialNumber,
+			"Status":       map[string]string{"State": "Enabled", "Health": "OK"},
+			"Controllers":  []map[string]interface{}{{"FirmwarePackageVersion": adapter.FirmwareVersion}},
+			portsName:      link(uri + "/" + portsName),
+		})
+		return true
+	case segments[0] != portsName:
+		return false
+	case len(segments) == 1:
+		members := []string{}
+		for _, port := range adapter.Ports {
+			members = append(members, uri+"/"+portsName+"/"+port.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(uri+"/"+portsName, members))
+		return true
+	case len(segments) == 2:
+		for _, port := range adapter.Ports {
+			if port.Id != segments[1] {
+				continue
+			}
+			if adapter.LegacyPorts {
+				writeJSON(w, http.StatusOK, map[string]interface{}{
+					"@odata.id":                  uri + "/" + portsName + "/" + port.Id,
+					"Id":                         port.Id,
+					"PhysicalPortNumber":         port.Id,
+					"LinkStatus":                 strings.TrimPrefix(port.LinkStatus, "Link"),
+					"CurrentLinkSpeedMbps":       int(port.SpeedGbps * 1000),
+					"AssociatedNetworkAddresses": port.MACAddresses,
+					"Status":                     map[string]string{"State": "Enabled", "Health": "OK"},
+				})
+				return true
+			}
+			ethernet := map[string]interface{}{
+				"AssociatedMACAddresses": port.MACAddresses,
+				"LLDPEnabled":            port.LLDP != nil,
+			}
+			if port.LLDP != nil {
+				ethernet["LLDPReceive"] = map[string]interface{}{
+					"ChassisId":             port.LLDP.ChassisId,
+					"ChassisIdSubtype":      "MacAddr",
+					"PortId":                port.LLDP.PortId,
+					"PortIdSubtype":         "IfName",
+					"SystemName":            port.LLDP.SystemName,
+					"ManagementAddressIPv4": port.LLDP.ManagementAddressIPv4,
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"@odata.id":        uri + "/" + portsName + "/" + port.Id,
+				"Id":               port.Id,
+				"PortId":           port.Id,
+				"LinkStatus":       port.LinkStatus,
+				"CurrentSpeedGbps": port.SpeedGbps,
+				"Ethernet":         ethernet,
+				"Status":           map[string]string{"State": "Enabled", "Health": "OK"},
+			})
+			return true
+		}
+	}
+	return false
+}
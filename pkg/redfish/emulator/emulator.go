@@ -0,0 +1,436 @@
+// Package emulator serves a scriptable Redfish service with httptest, so the redfish client and the controllers
+// could be tested without the real bmc or the redfish mockup server. The systems, managers, storage, log entries and tasks are
+// set by the test, and the failures and the latency of the requests could be injected.
+package emulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the power states of the system
+const (
+	PowerOn          = "On"
+	PowerOff         = "Off"
+	PowerPoweringOn  = "PoweringOn"
+	PowerPoweringOff = "PoweringOff"
+)
+
+// System is a ComputerSystem of the emulator
+type System struct {
+	Id           string
+	Name         string
+	Manufacturer string
+	Model        string
+	SerialNumber string
+	HostName     string
+	BiosVersion  string
+	// PowerState is On or Off
+	PowerState string
+	Health     string
+	// SupportedResetTypes is reported as the allowable values of the reset action, all reset types are accepted when it is empty
+	SupportedResetTypes   []string
+	ProcessorCount        int
+	LogicalProcessorCount int
+	ProcessorModel        string
+	MemoryGiB             float64
+	Boot                  Boot
+	// BiosReset is set when the Bios.ResetBios action is received
+	BiosReset bool
+	// AsyncReset makes the reset action respond 202 with a running task, like the bmc which resets the system in the background
+	AsyncReset bool
+	// MediaImage is the image inserted to the virtual cd of the system, it is empty when the cd is ejected
+	MediaImage string
+}
+
+type Boot struct {
+	OverrideTarget  string
+	OverrideEnabled string
+	OverrideMode    string
+	UefiTarget      string
+	BootOrder       []string
+}
+
+// Manager is a bmc of the emulator, it manages all the systems
+type Manager struct {
+	Id              string
+	Name            string
+	ManagerType     string
+	Model           string
+	FirmwareVersion string
+	Health          string
+	// ResetType and ResetToDefaultsType record the latest Reset and ResetToDefaults action
+	ResetType           string
+	ResetToDefaultsType string
+}
+
+// LogEntry is an entry of the log service of the system
+type LogEntry struct {
+	Id string
+	// Created is the time in RFC3339
+	Created    string
+	Severity   string
+	Message    string
+	SensorType string
+}
+
+// Firmware is an item of the firmware inventory
+type Firmware struct {
+	Id         string
+	Name       string
+	Version    string
+	Updateable bool
+}
+
+// Task is the redfish task created by the asynchronous action, such as SimpleUpdate
+type Task struct {
+	// State is the TaskState, such as Running, Completed or Exception
+	State string
+	// Status is the TaskStatus, such as OK or Critical
+	Status          string
+	PercentComplete int
+	Messages        []string
+}
+
+// Request records a request received by the emulator
+type Request struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+type failure struct {
+	method     string
+	path       string
+	statusCode int
+	// times is the remaining times to fail, it fails forever when it is negative
+	times int
+}
+
+type latency struct {
+	path  string
+	delay time.Duration
+}
+
+// the power transition which is finished at the time
+type transition struct {
+	target string
+	at     time.Time
+}
+
+// Server is the Redfish service of the emulator
+type Server struct {
+	server *httptest.Server
+
+	lock     sync.Mutex
+	username string
+	password string
+	vendor   string
+
+	systems         []*System
+	managers        []*Manager
+	logs            map[string][]LogEntry
+	firmware        []Firmware
+	storages        map[string][]*Storage
+	bios            map[string]*Bios
+	networkAdapters []NetworkAdapter
+	powerControl    *PowerControl
+	tasks           map[string]*Task
+	transitions     map[string]*transition
+	powerDelay      time.Duration
+
+	// the indicator led of the chassis, it is Off when it is empty
+	indicatorLED          string
+	locationIndicatorOnly bool
+
+	// the https certificate reported by the manager, and the key of the last certificate signing request
+	httpsCertificate string
+	csrKey           *ecdsa.PrivateKey
+	// servingCertificate is the certificate installed on the emulator, the certificate of httptest is served when it is nil
+	servingCertificate *tls.Certificate
+
+	// the manager does not respond until the time after it is reset
+	managerResetDelay   time.Duration
+	managerRestartUntil time.Time
+
+	// the network services of the manager, and the subscriptions of the event service
+	networkProtocol NetworkProtocol
+	subscriptions   []Subscription
+	subscriptionId  int
+
+	// the ManagerAccount of the account service
+	accounts  []*Account
+	accountId int
+
+	sessions  map[string]string
+	sessionId int
+	taskId    int
+	volumeId  int
+
+	failures  []*failure
+	latencies []latency
+	requests  []Request
+}
+
+// New starts the emulator serving https, with one powered on system and one manager.
+// The credential is not checked until SetCredential is called
+func New() *Server {
+	s := newServer()
+	s.server.StartTLS()
+	return s
+}
+
+// NewHTTP starts the emulator serving http
+func NewHTTP() *Server {
+	s := newServer()
+	s.server.Start()
+	return s
+}
+
+func newServer() *Server {
+	s := &Server{
+		systems: []*System{{
+			Id:                    "1",
+			Name:                  "System",
+			Manufacturer:          "Emulator",
+			Model:                 "Emulated Server",
+			SerialNumber:          "SN0001",
+			BiosVersion:           "1.0.0",
+			PowerState:            PowerOn,
+			Health:                "OK",
+			ProcessorCount:        2,
+			LogicalProcessorCount: 64,
+			ProcessorModel:        "Emulated CPU",
+			MemoryGiB:             256,
+		}},
+		managers: []*Manager{{
+			Id:              "bmc",
+			Name:            "Manager",
+			ManagerType:     "BMC",
+			Model:           "Emulated BMC",
+			FirmwareVersion: "1.0.0",
+			Health:          "OK",
+		}},
+		networkProtocol: NetworkProtocol{
+			Protocols: map[string]bool{"IPMI": true, "SSH": true, "SNMP": false},
+		},
+		logs:        map[string][]LogEntry{},
+		storages:    map[string][]*Storage{},
+		tasks:       map[string]*Task{},
+		transitions: map[string]*transition{},
+		sessions:    map[string]string{},
+	}
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.server.TLS = &tls.Config{GetConfigForClient: s.configForClient}
+	// the handshake errors are expected when the client refuses the certificate
+	s.server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	return s
+}
+
+// Close shuts down the emulator
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the endpoint of the emulator, such as https://127.0.0.1:12345
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Host returns the ip address of the emulator
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	return host
+}
+
+// Port returns the port of the emulator
+func (s *Server) Port() int32 {
+	_, port, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return int32(p)
+}
+
+// Certificate returns the certificate which the https emulator starts with, it is nil for http
+func (s *Server) Certificate() *x509.Certificate {
+	return s.server.Certificate()
+}
+
+// SetCredential requires the username and the password to create the session or to access with the basic auth
+func (s *Server) SetCredential(username, password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.username = username
+	s.password = password
+}
+
+// SetVendor sets the Vendor of the service root, which selects the vendor adapter of the client
+func (s *Server) SetVendor(vendor string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.vendor = vendor
+}
+
+// SetSystems replaces the systems of the emulator
+func (s *Server) SetSystems(systems ...System) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.systems = nil
+	for i := range systems {
+		t := systems[i]
+		s.systems = append(s.systems, &t)
+	}
+	s.transitions = map[string]*transition{}
+}
+
+// System returns the current state of the system
+func (s *Server) System(id string) (System, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	system := s.findSystem(id)
+	if system == nil {
+		return System{}, false
+	}
+	return *system, true
+}
+
+// SetManagers replaces the managers of the emulator
+func (s *Server) SetManagers(managers ...Manager) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.managers = nil
+	for i := range managers {
+		t := managers[i]
+		s.managers = append(s.managers, &t)
+	}
+}
+
+// AddLogEntry adds the entry to the log service of the system, the latest entry is listed first
+func (s *Server) AddLogEntry(systemId string, entry LogEntry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(entry.Id) == 0 {
+		entry.Id = strconv.Itoa(len(s.logs[systemId]) + 1)
+	}
+	if len(entry.Created) == 0 {
+		entry.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.logs[systemId] = append([]LogEntry{entry}, s.logs[systemId]...)
+}
+
+// SetFirmware replaces the firmware inventory
+func (s *Server) SetFirmware(firmware ...Firmware) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.firmware = firmware
+}
+
+// SetPowerTransitionDelay makes the reset of the system pass the PoweringOn or PoweringOff state for the delay
+func (s *Server) SetPowerTransitionDelay(delay time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.powerDelay = delay
+}
+
+// SetTask sets the state of the task, the uri is returned by the asynchronous action
+func (s *Server) SetTask(uri string, task Task) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t := task
+	s.tasks[uri] = &t
+}
+
+// Tasks returns the uri of the tasks created by the asynchronous actions
+func (s *Server) Tasks() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := []string{}
+	for uri := range s.tasks {
+		result = append(result, uri)
+	}
+	return result
+}
+
+// Fail responds the status code to the requests with the method and the path prefix, for the given times.
+// The method matches all the methods when it is empty, and it fails forever when times is not positive
+func (s *Server) Fail(method, pathPrefix string, statusCode int, times int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if times <= 0 {
+		times = -1
+	}
+	s.failures = append(s.failures, &failure{method: method, path: pathPrefix, statusCode: statusCode, times: times})
+}
+
+// SetLatency delays the responses of the requests with the path prefix, the empty prefix matches all the requests
+func (s *Server) SetLatency(pathPrefix string, delay time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.latencies = append(s.latencies, latency{path: pathPrefix, delay: delay})
+}
+
+// Reset clears the injected failures and latency, and the recorded requests
+func (s *Server) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures = nil
+	s.latencies = nil
+	s.requests = nil
+}
+
+// Requests returns the requests received by the emulator
+func (s *Server) Requests() []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// CountRequests returns the number of the requests with the method and the path prefix
+func (s *Server) CountRequests(method, pathPrefix string) int {
+	count := 0
+	for _, r := range s.Requests() {
+		if (len(method) == 0 || r.Method == method) && strings.HasPrefix(r.Path, pathPrefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// Sessions returns the number of the sessions which are not logged out
+func (s *Server) Sessions() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.sessions)
+}
+
+func (s *Server) findSystem(id string) *System {
+	for _, system := range s.systems {
+		if system.Id == id {
+			s.settlePower(system)
+			return system
+		}
+	}
+	return nil
+}
+
+// settlePower finishes the power transition of the system when its delay passes
+func (s *Server) settlePower(system *System) {
+	t, ok := s.transitions[system.Id]
+	if !ok {
+		return
+	}
+	if time.Now().Before(t.at) {
+		return
+	}
+	system.PowerState = t.target
+	delete(s.transitions, system.Id)
+}
@@ -0,0 +1,639 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	rootPath     = "/redfish/v1"
+	sessionsPath = rootPath + "/SessionService/Sessions"
+	systemsPath  = rootPath + "/Systems"
+	managersPath = rootPath + "/Managers"
+	updatePath   = rootPath + "/UpdateService"
+	tasksPath    = rootPath + "/TaskService/Tasks"
+	resetAction  = "ComputerSystem.Reset"
+	simpleUpdate = "UpdateService.SimpleUpdate"
+	resetBios    = "Bios.ResetBios"
+	clearLog     = "LogService.ClearLog"
+	logService   = "Log"
+)
+
+func link(uri string) map[string]string {
+	return map[string]string{"@odata.id": uri}
+}
+
+func collection(uri string, members []string) map[string]interface{} {
+	links := []map[string]string{}
+	for _, m := range members {
+		links = append(links, link(m))
+	}
+	return map[string]interface{}{
+		"@odata.id":           uri,
+		"Members":             links,
+		"Members@odata.count": len(links),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// writeError responds the redfish error message
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    "Base.1.0.GeneralError",
+			"message": message,
+		},
+	})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := strings.TrimSuffix(r.URL.Path, "/")
+
+	s.lock.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Body: body})
+	var delay time.Duration
+	for _, l := range s.latencies {
+		if strings.HasPrefix(path, l.path) {
+			delay += l.delay
+		}
+	}
+	statusCode := 0
+	for _, f := range s.failures {
+		if f.times != 0 && (len(f.method) == 0 || f.method == r.Method) && strings.HasPrefix(path, f.path) {
+			statusCode = f.statusCode
+			if f.times > 0 {
+				f.times--
+			}
+			break
+		}
+	}
+	if statusCode == 0 && s.restarting() {
+		statusCode = http.StatusServiceUnavailable
+	}
+	s.lock.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if statusCode != 0 {
+		writeError(w, statusCode, "the failure is injected by the emulator")
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// the service root and the login are accessed without the authentication
+	if path == rootPath && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, s.serviceRoot())
+		return
+	}
+	if path == sessionsPath && r.Method == http.MethodPost {
+		s.createSession(w, body)
+		return
+	}
+	if !s.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, "the authentication is required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.get(w, path)
+	case http.MethodPost:
+		s.post(w, path, body)
+	case http.MethodPatch:
+		s.patch(w, path, body)
+	case http.MethodDelete:
+		s.delete(w, path)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("the method %s is not allowed", r.Method))
+	}
+}
+
+func (s *Server) serviceRoot() map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id":          rootPath + "/",
+		"Id":                 "RootService",
+		"Name":               "Root Service",
+		"RedfishVersion":     "1.11.0",
+		"Vendor":             s.vendor,
+		"Systems":            link(systemsPath),
+		"Managers":           link(managersPath),
+		"Chassis":            link(chassisPath),
+		"SessionService":     link(rootPath + "/SessionService"),
+		"UpdateService":      link(updatePath),
+		"CertificateService": link(certificateServicePath),
+		"EventService":       link(eventServicePath),
+		"AccountService":     link(accountServicePath),
+		"Links": map[string]interface{}{
+			"Sessions": link(sessionsPath),
+		},
+	}
+}
+
+func (s *Server) authenticated(r *http.Request) bool {
+	if len(s.username) == 0 {
+		return true
+	}
+	if token := r.Header.Get("X-Auth-Token"); len(token) > 0 {
+		_, ok := s.sessions[token]
+		return ok
+	}
+	username, password, ok := r.BasicAuth()
+	return ok && s.validCredential(username, password)
+}
+
+func (s *Server) createSession(w http.ResponseWriter, body []byte) {
+	var login struct {
+		UserName string
+		Password string
+	}
+	if err := json.Unmarshal(body, &login); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if len(s.username) > 0 && !s.validCredential(login.UserName, login.Password) {
+		writeError(w, http.StatusUnauthorized, "wrong username or password")
+		return
+	}
+	s.sessionId++
+	token := fmt.Sprintf("token-%d", s.sessionId)
+	uri := fmt.Sprintf("%s/%d", sessionsPath, s.sessionId)
+	s.sessions[token] = uri
+	w.Header().Set("X-Auth-Token", token)
+	w.Header().Set("Location", uri)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"@odata.id": uri,
+		"Id":        strconv.Itoa(s.sessionId),
+		"UserName":  login.UserName,
+	})
+}
+
+func (s *Server) get(w http.ResponseWriter, path string) {
+	switch {
+	case path == systemsPath:
+		members := []string{}
+		for _, system := range s.systems {
+			members = append(members, systemsPath+"/"+system.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(path, members))
+		return
+	case path == managersPath:
+		members := []string{}
+		for _, manager := range s.managers {
+			members = append(members, managersPath+"/"+manager.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(path, members))
+		return
+	case path == updatePath:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":         path,
+			"Id":                "UpdateService",
+			"ServiceEnabled":    true,
+			"FirmwareInventory": link(updatePath + "/FirmwareInventory"),
+			"Actions": map[string]interface{}{
+				"#" + simpleUpdate: map[string]string{"target": updatePath + "/Actions/" + simpleUpdate},
+			},
+		})
+		return
+	case path == updatePath+"/FirmwareInventory":
+		members := []string{}
+		for _, f := range s.firmware {
+			members = append(members, path+"/"+f.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(path, members))
+		return
+	case strings.HasPrefix(path, updatePath+"/FirmwareInventory/"):
+		id := strings.TrimPrefix(path, updatePath+"/FirmwareInventory/")
+		for _, f := range s.firmware {
+			if f.Id == id {
+				writeJSON(w, http.StatusOK, map[string]interface{}{
+					"@odata.id":  path,
+					"Id":         f.Id,
+					"Name":       f.Name,
+					"Version":    f.Version,
+					"Updateable": f.Updateable,
+				})
+				return
+			}
+		}
+	case strings.HasPrefix(path, tasksPath+"/"):
+		if task, ok := s.tasks[path]; ok {
+			messages := []map[string]string{}
+			for _, m := range task.Messages {
+				messages = append(messages, map[string]string{"Message": m})
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"@odata.id":       path,
+				"Id":              strings.TrimPrefix(path, tasksPath+"/"),
+				"TaskState":       task.State,
+				"TaskStatus":      task.Status,
+				"PercentComplete": task.PercentComplete,
+				"Messages":        messages,
+			})
+			return
+		}
+	case path == certificateServicePath:
+		writeJSON(w, http.StatusOK, certificateService())
+		return
+	case strings.HasPrefix(path, eventServicePath):
+		if s.getEventService(w, path) {
+			return
+		}
+	case isAccountPath(path):
+		if s.getAccountService(w, path) {
+			return
+		}
+	case strings.HasPrefix(path, managersPath+"/"):
+		segments := strings.Split(strings.TrimPrefix(path, managersPath+"/"), "/")
+		for _, manager := range s.managers {
+			if manager.Id != segments[0] {
+				continue
+			}
+			if len(segments) == 1 {
+				writeJSON(w, http.StatusOK, managerResource(manager))
+				return
+			}
+			if s.getManagerResource(w, managersPath+"/"+manager.Id, segments[1:]) {
+				return
+			}
+		}
+	case strings.HasPrefix(path, systemsPath+"/"):
+		if s.getSystemResource(w, strings.Split(strings.TrimPrefix(path, systemsPath+"/"), "/")) {
+			return
+		}
+	case path == chassisPath:
+		if s.getChassisResource(w, nil) {
+			return
+		}
+	case strings.HasPrefix(path, chassisPath+"/"):
+		if s.getChassisResource(w, strings.Split(strings.TrimPrefix(path, chassisPath+"/"), "/")) {
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("the resource %s is not found", path))
+}
+
+// getSystemResource responds the system, its log service and log entries. The segments follow /redfish/v1/Systems
+func (s *Server) getSystemResource(w http.ResponseWriter, segments []string) bool {
+	system := s.findSystem(segments[0])
+	if system == nil {
+		return false
+	}
+	uri := systemsPath + "/" + system.Id
+	if len(segments) > 1 && segments[1] == "Storage" {
+		return s.getStorageResource(w, system, segments[2:])
+	}
+	if len(segments) > 1 && segments[1] == "VirtualMedia" {
+		return s.getVirtualMedia(w, system, segments[2:])
+	}
+	if len(segments) > 1 && segments[1] == "Bios" {
+		return s.getBios(w, system, segments[2:])
+	}
+	switch len(segments) {
+	case 1:
+		writeJSON(w, http.StatusOK, s.systemResource(system))
+		return true
+	case 2:
+		if segments[1] == "LogServices" {
+			writeJSON(w, http.StatusOK, collection(uri+"/LogServices", []string{uri + "/LogServices/" + logService}))
+			return true
+		}
+	case 3:
+		if segments[1] == "LogServices" && segments[2] == logService {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"@odata.id": uri + "/LogServices/" + logService,
+				"Id":        logService,
+				"Name":      "System Event Log",
+				"Status":    map[string]string{"State": "Enabled", "Health": "OK"},
+				"Entries":   link(uri + "/LogServices/" + logService + "/Entries"),
+				"Actions": map[string]interface{}{
+					"#" + clearLog: map[string]string{"target": uri + "/LogServices/" + logService + "/Actions/" + clearLog},
+				},
+			})
+			return true
+		}
+	case 4, 5:
+		if segments[1] != "LogServices" || segments[2] != logService || segments[3] != "Entries" {
+			return false
+		}
+		entriesUri := uri + "/LogServices/" + logService + "/Entries"
+		if len(segments) == 4 {
+			members := []string{}
+			for _, entry := range s.logs[system.Id] {
+				members = append(members, entriesUri+"/"+entry.Id)
+			}
+			writeJSON(w, http.StatusOK, collection(entriesUri, members))
+			return true
+		}
+		for _, entry := range s.logs[system.Id] {
+			if entry.Id == segments[4] {
+				writeJSON(w, http.StatusOK, map[string]interface{}{
+					"@odata.id":     entriesUri + "/" + entry.Id,
+					"Id":            entry.Id,
+					"Created":       entry.Created,
+					"Severity":      entry.Severity,
+					"Message":       entry.Message,
+					"EntryType":     "Event",
+					"OemSensorType": entry.SensorType,
+				})
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) systemResource(system *System) map[string]interface{} {
+	uri := systemsPath + "/" + system.Id
+	managedBy := []map[string]string{}
+	for _, manager := range s.managers {
+		managedBy = append(managedBy, link(managersPath+"/"+manager.Id))
+	}
+	reset := map[string]interface{}{"target": uri + "/Actions/" + resetAction}
+	if len(system.SupportedResetTypes) > 0 {
+		reset["ResetType@Redfish.AllowableValues"] = system.SupportedResetTypes
+	}
+	return map[string]interface{}{
+		"@odata.id":    uri,
+		"Id":           system.Id,
+		"Name":         system.Name,
+		"Manufacturer": system.Manufacturer,
+		"Model":        system.Model,
+		"SerialNumber": system.SerialNumber,
+		"HostName":     system.HostName,
+		"BiosVersion":  system.BiosVersion,
+		"PowerState":   system.PowerState,
+		"Status":       map[string]string{"State": "Enabled", "Health": system.Health},
+		"ProcessorSummary": map[string]interface{}{
+			"Count":                 system.ProcessorCount,
+			"LogicalProcessorCount": system.LogicalProcessorCount,
+			"Model":                 system.ProcessorModel,
+			"Status":                map[string]string{"Health": "OK"},
+		},
+		"MemorySummary": map[string]interface{}{
+			"TotalSystemMemoryGiB": system.MemoryGiB,
+			"Status":               map[string]string{"Health": "OK"},
+		},
+		"Boot": map[string]interface{}{
+			"BootSourceOverrideTarget":     system.Boot.OverrideTarget,
+			"BootSourceOverrideEnabled":    system.Boot.OverrideEnabled,
+			"BootSourceOverrideMode":       system.Boot.OverrideMode,
+			"UefiTargetBootSourceOverride": system.Boot.UefiTarget,
+			"BootOrder":                    system.Boot.BootOrder,
+		},
+		"LogServices":  link(uri + "/LogServices"),
+		"Storage":      link(uri + "/Storage"),
+		"Bios":         link(uri + "/Bios"),
+		"VirtualMedia": link(uri + "/VirtualMedia"),
+		"Links": map[string]interface{}{
+			"ManagedBy": managedBy,
+		},
+		"Actions": map[string]interface{}{
+			"#" + resetAction: reset,
+		},
+	}
+}
+
+func managerResource(manager *Manager) map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id":          managersPath + "/" + manager.Id,
+		"Id":                 manager.Id,
+		"Name":               manager.Name,
+		"ManagerType":        manager.ManagerType,
+		"Model":              manager.Model,
+		"FirmwareVersion":    manager.FirmwareVersion,
+		"Status":             map[string]string{"State": "Enabled", "Health": manager.Health},
+		"NetworkProtocol":    link(managersPath + "/" + manager.Id + "/NetworkProtocol"),
+		"EthernetInterfaces": link(managersPath + "/" + manager.Id + "/EthernetInterfaces"),
+		"Actions":            managerActions(manager),
+	}
+}
+
+func (s *Server) post(w http.ResponseWriter, path string, body []byte) {
+	switch {
+	case path == certificateServicePath+"/Actions/"+generateCSR:
+		s.generateCSR(w, body)
+		return
+	case path == certificateServicePath+"/Actions/"+replaceCertificate:
+		s.replaceCertificate(w, body)
+		return
+	case strings.HasPrefix(path, managersPath+"/"):
+		if s.managerAction(w, path, body) {
+			return
+		}
+	case path == subscriptionsPath:
+		s.createSubscription(w, body)
+		return
+	case path == accountsPath:
+		s.createAccount(w, body)
+		return
+	case path == updatePath+"/Actions/"+simpleUpdate:
+		var param struct {
+			ImageURI string
+		}
+		if err := json.Unmarshal(body, &param); err != nil || len(param.ImageURI) == 0 {
+			writeError(w, http.StatusBadRequest, "ImageURI is required")
+			return
+		}
+		s.createTask(w)
+		return
+	case strings.HasPrefix(path, systemsPath+"/") && strings.Contains(path, "/Storage/") && strings.Contains(path, "/Actions/"):
+		if s.storageAction(w, path) {
+			return
+		}
+	case strings.HasPrefix(path, systemsPath+"/") && strings.Contains(path, "/VirtualMedia/"):
+		if s.virtualMediaAction(w, path, body) {
+			return
+		}
+	case strings.HasPrefix(path, systemsPath+"/") && strings.HasSuffix(path, "/Actions/"+resetBios):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, systemsPath+"/"), "/Bios/Actions/"+resetBios)
+		if system := s.findSystem(id); system != nil {
+			system.BiosReset = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	case strings.HasPrefix(path, systemsPath+"/") && strings.HasSuffix(path, "/Actions/"+clearLog):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, systemsPath+"/"), "/LogServices/"+logService+"/Actions/"+clearLog)
+		if system := s.findSystem(id); system != nil {
+			delete(s.logs, system.Id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	case strings.HasPrefix(path, systemsPath+"/") && strings.HasSuffix(path, "/Volumes"):
+		if s.createVolume(w, path, body) {
+			return
+		}
+	case strings.HasPrefix(path, systemsPath+"/") && strings.HasSuffix(path, "/Actions/"+resetAction):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, systemsPath+"/"), "/Actions/"+resetAction)
+		system := s.findSystem(id)
+		if system == nil {
+			break
+		}
+		var param struct {
+			ResetType string
+		}
+		if err := json.Unmarshal(body, &param); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+			return
+		}
+		if code, err := s.reset(system, param.ResetType); err != nil {
+			writeError(w, code, err.Error())
+			return
+		}
+		if system.AsyncReset {
+			s.createTask(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("the action %s is not found", path))
+}
+
+// createTask responds 202 with a running task
+func (s *Server) createTask(w http.ResponseWriter) {
+	s.taskId++
+	uri := fmt.Sprintf("%s/%d", tasksPath, s.taskId)
+	s.tasks[uri] = &Task{State: "Running", Status: "OK"}
+	w.Header().Set("Location", uri)
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"@odata.id": uri,
+		"Id":        strconv.Itoa(s.taskId),
+		"TaskState": "Running",
+	})
+}
+
+// reset changes the power state of the system, the state passes PoweringOn or PoweringOff when the delay is set
+func (s *Server) reset(system *System, resetType string) (int, error) {
+	if len(system.SupportedResetTypes) > 0 {
+		supported := false
+		for _, t := range system.SupportedResetTypes {
+			if t == resetType {
+				supported = true
+			}
+		}
+		if !supported {
+			return http.StatusBadRequest, fmt.Errorf("the reset type %s is not supported", resetType)
+		}
+	}
+	if _, ok := s.transitions[system.Id]; ok {
+		return http.StatusConflict, fmt.Errorf("the system %s is in the power transition", system.Id)
+	}
+
+	var target, transient string
+	switch resetType {
+	case "On", "ForceOn":
+		target, transient = PowerOn, PowerPoweringOn
+	case "ForceOff", "GracefulShutdown", "PushPowerButton":
+		target, transient = PowerOff, PowerPoweringOff
+	case "ForceRestart", "GracefulRestart", "PowerCycle":
+		if system.PowerState == PowerOff {
+			return http.StatusConflict, fmt.Errorf("the system %s is powered off", system.Id)
+		}
+		target, transient = PowerOn, PowerPoweringOn
+	case "Nmi":
+		return 0, nil
+	default:
+		return http.StatusBadRequest, fmt.Errorf("unknown reset type %q", resetType)
+	}
+
+	if s.powerDelay > 0 {
+		system.PowerState = transient
+		s.transitions[system.Id] = &transition{target: target, at: time.Now().Add(s.powerDelay)}
+	} else {
+		system.PowerState = target
+	}
+	return 0, nil
+}
+
+func (s *Server) patch(w http.ResponseWriter, path string, body []byte) {
+	if strings.HasPrefix(path, chassisPath+"/") && s.patchChassis(w, path, body) {
+		return
+	}
+	if strings.HasPrefix(path, managersPath+"/") && s.patchManager(w, path, body) {
+		return
+	}
+	if isAccountPath(path) && s.patchAccount(w, path, body) {
+		return
+	}
+	if strings.HasPrefix(path, systemsPath+"/") && s.patchBios(w, path, body) {
+		return
+	}
+	if strings.HasPrefix(path, systemsPath+"/") {
+		if system := s.findSystem(strings.TrimPrefix(path, systemsPath+"/")); system != nil {
+			var param struct {
+				Boot struct {
+					BootSourceOverrideTarget     string
+					BootSourceOverrideEnabled    string
+					BootSourceOverrideMode       string
+					UefiTargetBootSourceOverride string
+					BootOrder                    []string
+				}
+			}
+			if err := json.Unmarshal(body, &param); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+				return
+			}
+			boot := param.Boot
+			if len(boot.BootSourceOverrideTarget) > 0 {
+				system.Boot.OverrideTarget = boot.BootSourceOverrideTarget
+			}
+			if len(boot.BootSourceOverrideEnabled) > 0 {
+				system.Boot.OverrideEnabled = boot.BootSourceOverrideEnabled
+			}
+			if len(boot.BootSourceOverrideMode) > 0 {
+				system.Boot.OverrideMode = boot.BootSourceOverrideMode
+			}
+			if len(boot.UefiTargetBootSourceOverride) > 0 {
+				system.Boot.UefiTarget = boot.UefiTargetBootSourceOverride
+			}
+			if len(boot.BootOrder) > 0 {
+				system.Boot.BootOrder = boot.BootOrder
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("the resource %s is not found", path))
+}
+
+func (s *Server) delete(w http.ResponseWriter, path string) {
+	if strings.HasPrefix(path, systemsPath+"/") && s.deleteVolume(w, path) {
+		return
+	}
+	if strings.HasPrefix(path, subscriptionsPath+"/") && s.deleteSubscription(w, path) {
+		return
+	}
+	if isAccountPath(path) && s.deleteAccount(w, path) {
+		return
+	}
+	if strings.HasPrefix(path, sessionsPath+"/") {
+		for token, uri := range s.sessions {
+			if uri == path {
+				delete(s.sessions, token)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("the resource %s is not found", path))
+}
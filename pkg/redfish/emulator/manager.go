@@ -0,0 +1,96 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	managerReset           = "Manager.Reset"
+	managerResetToDefaults = "Manager.ResetToDefaults"
+)
+
+// SetManagerResetDelay sets the time for the manager to restart after it is reset, the emulator responds 503 to all the
+// requests during the time
+func (s *Server) SetManagerResetDelay(delay time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.managerResetDelay = delay
+}
+
+// Manager returns the current state of the manager
+func (s *Server) Manager(id string) (Manager, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, manager := range s.managers {
+		if manager.Id == id {
+			return *manager, true
+		}
+	}
+	return Manager{}, false
+}
+
+// restarting checks whether the manager is restarting after it is reset
+func (s *Server) restarting() bool {
+	return time.Now().Before(s.managerRestartUntil)
+}
+
+func managerActions(manager *Manager) map[string]interface{} {
+	uri := managersPath + "/" + manager.Id + "/Actions/"
+	return map[string]interface{}{
+		"#" + managerReset: map[string]interface{}{
+			"target":                            uri + managerReset,
+			"ResetType@Redfish.AllowableValues": []string{"GracefulRestart", "ForceRestart"},
+		},
+		"#" + managerResetToDefaults: map[string]interface{}{
+			"target":                            uri + managerResetToDefaults,
+			"ResetType@Redfish.AllowableValues": []string{"ResetAll", "PreserveNetworkAndUsers", "PreserveNetwork"},
+		},
+	}
+}
+
+// managerAction resets the manager or restores its factory settings. The manager drops all the sessions, and restarts
+// for the delay set by SetManagerResetDelay
+func (s *Server) managerAction(w http.ResponseWriter, path string, body []byte) bool {
+	segments := strings.Split(strings.TrimPrefix(path, managersPath+"/"), "/")
+	if len(segments) != 3 || segments[1] != "Actions" {
+		return false
+	}
+	var manager *Manager
+	for _, m := range s.managers {
+		if m.Id == segments[0] {
+			manager = m
+		}
+	}
+	if manager == nil || (segments[2] != managerReset && segments[2] != managerResetToDefaults) {
+		return false
+	}
+
+	var param struct {
+		ResetType string
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return true
+	}
+	if segments[2] == managerReset {
+		if param.ResetType != "GracefulRestart" && param.ResetType != "ForceRestart" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported reset type %s", param.ResetType))
+			return true
+		}
+		manager.ResetType = param.ResetType
+	} else {
+		if param.ResetType != "ResetAll" && param.ResetType != "PreserveNetworkAndUsers" && param.ResetType != "PreserveNetwork" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported reset type %s", param.ResetType))
+			return true
+		}
+		manager.ResetToDefaultsType = param.ResetType
+	}
+	s.sessions = map[string]string{}
+	s.managerRestartUntil = time.Now().Add(s.managerResetDelay)
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
@@ -0,0 +1,269 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	eventServicePath  = rootPath + "/EventService"
+	subscriptionsPath = eventServicePath + "/Subscriptions"
+	bmcInterface      = "eth0"
+)
+
+// NetworkProtocol is the network services of the manager
+type NetworkProtocol struct {
+	// Protocols is the state of the protocols such as IPMI, SSH, SNMP and Telnet, the protocol which is absent is not reported
+	Protocols  map[string]bool
+	NTPEnabled bool
+	NTPServers []string
+	// the settings of the ethernet interface of the manager, which holds the ip address of the emulator
+	StaticNameServers []string
+	DHCPUseNTPServers bool
+	DHCPUseDNSServers bool
+}
+
+// Subscription is the EventDestination of the event service
+type Subscription struct {
+	Id               string
+	Destination      string
+	SubscriptionType string
+	Protocol         string
+}
+
+// SetNetworkProtocol replaces the network services of the manager
+func (s *Server) SetNetworkProtocol(protocol NetworkProtocol) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.networkProtocol = protocol
+	s.networkProtocol.Protocols = map[string]bool{}
+	for name, enabled := range protocol.Protocols {
+		s.networkProtocol.Protocols[name] = enabled
+	}
+}
+
+// NetworkProtocol returns the current network services of the manager
+func (s *Server) NetworkProtocol() NetworkProtocol {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := s.networkProtocol
+	result.Protocols = map[string]bool{}
+	for name, enabled := range s.networkProtocol.Protocols {
+		result.Protocols[name] = enabled
+	}
+	return result
+}
+
+// AddSubscription adds the EventDestination to the event service
+func (s *Server) AddSubscription(subscription Subscription) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.addSubscription(subscription)
+}
+
+// Subscriptions returns the EventDestination of the event service
+func (s *Server) Subscriptions() []Subscription {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Subscription{}, s.subscriptions...)
+}
+
+func (s *Server) addSubscription(subscription Subscription) string {
+	s.subscriptionId++
+	if len(subscription.Id) == 0 {
+		subscription.Id = strconv.Itoa(s.subscriptionId)
+	}
+	s.subscriptions = append(s.subscriptions, subscription)
+	return subscriptionsPath + "/" + subscription.Id
+}
+
+// networkProtocolResource responds the ManagerNetworkProtocol with the https certificates
+func (s *Server) networkProtocolResource(protocolUri, certificatesUri string) map[string]interface{} {
+	resource := map[string]interface{}{
+		"@odata.id": protocolUri,
+		"Id":        "NetworkProtocol",
+		"HTTPS": map[string]interface{}{
+			"ProtocolEnabled": true,
+			"Port":            443,
+			"Certificates":    link(certificatesUri),
+		},
+		"NTP": map[string]interface{}{
+			"ProtocolEnabled": s.networkProtocol.NTPEnabled,
+			"NTPServers":      append([]string{}, s.networkProtocol.NTPServers...),
+		},
+	}
+	for name, enabled := range s.networkProtocol.Protocols {
+		resource[name] = map[string]interface{}{"ProtocolEnabled": enabled}
+	}
+	return resource
+}
+
+// getEthernetInterface responds the ethernet interfaces of the manager, the segments follow EthernetInterfaces
+func (s *Server) getEthernetInterface(w http.ResponseWriter, uri string, segments []string) bool {
+	interfacesUri := uri + "/EthernetInterfaces"
+	switch {
+	case len(segments) == 0:
+		writeJSON(w, http.StatusOK, collection(interfacesUri, []string{interfacesUri + "/" + bmcInterface}))
+		return true
+	case len(segments) == 1 && segments[0] == bmcInterface:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":         interfacesUri + "/" + bmcInterface,
+			"Id":                bmcInterface,
+			"IPv4Addresses":     []map[string]string{{"Address": s.Host()}},
+			"StaticNameServers": append([]string{}, s.networkProtocol.StaticNameServers...),
+			"DHCPv4": map[string]interface{}{
+				"DHCPEnabled":   true,
+				"UseNTPServers": s.networkProtocol.DHCPUseNTPServers,
+				"UseDNSServers": s.networkProtocol.DHCPUseDNSServers,
+			},
+		})
+		return true
+	}
+	return false
+}
+
+// patchManager sets the network protocols and the ethernet interface of the manager
+func (s *Server) patchManager(w http.ResponseWriter, path string, body []byte) bool {
+	segments := strings.Split(strings.TrimPrefix(path, managersPath+"/"), "/")
+	found := false
+	for _, manager := range s.managers {
+		if manager.Id == segments[0] {
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+	switch {
+	case len(segments) == 2 && segments[1] == "NetworkProtocol":
+		param := map[string]json.RawMessage{}
+		if err := json.Unmarshal(body, &param); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+			return true
+		}
+		for name, data := range param {
+			var setting struct {
+				ProtocolEnabled *bool
+				NTPServers      []string
+			}
+			if err := json.Unmarshal(data, &setting); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %v", name, err))
+				return true
+			}
+			if name == "NTP" {
+				if setting.ProtocolEnabled != nil {
+					s.networkProtocol.NTPEnabled = *setting.ProtocolEnabled
+				}
+				if setting.NTPServers != nil {
+					s.networkProtocol.NTPServers = setting.NTPServers
+				}
+				continue
+			}
+			if _, ok := s.networkProtocol.Protocols[name]; !ok {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("the protocol %s is not supported", name))
+				return true
+			}
+			if setting.ProtocolEnabled != nil {
+				s.networkProtocol.Protocols[name] = *setting.ProtocolEnabled
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	case len(segments) == 3 && segments[1] == "EthernetInterfaces" && segments[2] == bmcInterface:
+		var param struct {
+			StaticNameServers []string
+			DHCPv4            *struct {
+				UseNTPServers *bool
+				UseDNSServers *bool
+			}
+		}
+		if err := json.Unmarshal(body, &param); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+			return true
+		}
+		if param.StaticNameServers != nil {
+			s.networkProtocol.StaticNameServers = param.StaticNameServers
+		}
+		if param.DHCPv4 != nil && param.DHCPv4.UseNTPServers != nil {
+			s.networkProtocol.DHCPUseNTPServers = *param.DHCPv4.UseNTPServers
+		}
+		if param.DHCPv4 != nil && param.DHCPv4.UseDNSServers != nil {
+			s.networkProtocol.DHCPUseDNSServers = *param.DHCPv4.UseDNSServers
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
+
+// getEventService responds the event service and its subscriptions
+func (s *Server) getEventService(w http.ResponseWriter, path string) bool {
+	switch {
+	case path == eventServicePath:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":      eventServicePath,
+			"Id":             "EventService",
+			"ServiceEnabled": true,
+			"Subscriptions":  link(subscriptionsPath),
+		})
+		return true
+	case path == subscriptionsPath:
+		members := []string{}
+		for _, item := range s.subscriptions {
+			members = append(members, subscriptionsPath+"/"+item.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(subscriptionsPath, members))
+		return true
+	}
+	for _, item := range s.subscriptions {
+		if path == subscriptionsPath+"/"+item.Id {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"@odata.id":        path,
+				"Id":               item.Id,
+				"Destination":      item.Destination,
+				"SubscriptionType": item.SubscriptionType,
+				"Protocol":         item.Protocol,
+			})
+			return true
+		}
+	}
+	return false
+}
+
+// createSubscription creates the EventDestination, the subscription type is RedfishEvent when it is not specified
+func (s *Server) createSubscription(w http.ResponseWriter, body []byte) {
+	var param struct {
+		Destination      string
+		SubscriptionType string
+		Protocol         string
+	}
+	if err := json.Unmarshal(body, &param); err != nil || len(param.Destination) == 0 {
+		writeError(w, http.StatusBadRequest, "Destination is required")
+		return
+	}
+	if len(param.SubscriptionType) == 0 {
+		param.SubscriptionType = "RedfishEvent"
+	}
+	uri := s.addSubscription(Subscription{
+		Destination:      param.Destination,
+		SubscriptionType: param.SubscriptionType,
+		Protocol:         param.Protocol,
+	})
+	w.Header().Set("Location", uri)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// deleteSubscription removes the EventDestination
+func (s *Server) deleteSubscription(w http.ResponseWriter, path string) bool {
+	for i, item := range s.subscriptions {
+		if path == subscriptionsPath+"/"+item.Id {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	storageReset = "Storage.ResetToDefaults"
+	driveErase   = "Drive.SecureErase"
+)
+
+// Storage is a redfish Storage of the system, such as a RAID controller
+type Storage struct {
+	Id                 string
+	Name               string
+	SupportedRAIDTypes []string
+	Drives             []Drive
+	Volumes            []Volume
+}
+
+type Drive struct {
+	Id            string
+	Name          string
+	CapacityBytes int64
+	// SecureEraseUnsupported hides the SecureErase action of the drive
+	SecureEraseUnsupported bool
+}
+
+type Volume struct {
+	Id            string
+	Name          string
+	RAIDType      string
+	CapacityBytes int64
+	// Drives are the id of the member drives
+	Drives []string
+}
+
+// SetStorage replaces the storage of the system
+func (s *Server) SetStorage(systemId string, storages ...Storage) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.storages == nil {
+		s.storages = map[string][]*Storage{}
+	}
+	s.storages[systemId] = nil
+	for i := range storages {
+		t := storages[i]
+		t.Drives = append([]Drive{}, t.Drives...)
+		t.Volumes = append([]Volume{}, t.Volumes...)
+		s.storages[systemId] = append(s.storages[systemId], &t)
+	}
+}
+
+// Volumes returns the current volumes of the storage
+func (s *Server) Volumes(systemId, storageId string) []Volume {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if st := s.findStorage(systemId, storageId); st != nil {
+		return append([]Volume{}, st.Volumes...)
+	}
+	return nil
+}
+
+func (s *Server) findStorage(systemId, storageId string) *Storage {
+	for _, st := range s.storages[systemId] {
+		if st.Id == storageId {
+			return st
+		}
+	}
+	return nil
+}
+
+// getStorageResource responds the storage, its drives and volumes. The segments follow /redfish/v1/Systems/{id}/Storage
+func (s *Server) getStorageResource(w http.ResponseWriter, system *System, segments []string) bool {
+	uri := systemsPath + "/" + system.Id + "/Storage"
+	if len(segments) == 0 {
+		members := []string{}
+		for _, st := range s.storages[system.Id] {
+			members = append(members, uri+"/"+st.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(uri, members))
+		return true
+	}
+	st := s.findStorage(system.Id, segments[0])
+	if st == nil {
+		return false
+	}
+	uri += "/" + st.Id
+
+	switch {
+	case len(segments) == 1:
+		drives := []map[string]string{}
+		for _, d := range st.Drives {
+			drives = append(drives, link(uri+"/Drives/"+d.Id))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id": uri,
+			"Id":        st.Id,
+			"Name":      st.Name,
+			"Status":    map[string]string{"State": "Enabled", "Health": "OK"},
+			"StorageControllers": []map[string]interface{}{{
+				"@odata.id":          uri + "#/StorageControllers/0",
+				"MemberId":           "0",
+				"SupportedRAIDTypes": st.SupportedRAIDTypes,
+			}},
+			"Drives":  drives,
+			"Volumes": link(uri + "/Volumes"),
+			"Actions": map[string]interface{}{
+				"#" + storageReset: map[string]string{"target": uri + "/Actions/" + storageReset},
+			},
+		})
+		return true
+	case len(segments) == 3 && segments[1] == "Drives":
+		for _, d := range st.Drives {
+			if d.Id == segments[2] {
+				drive := map[string]interface{}{
+					"@odata.id":     uri + "/Drives/" + d.Id,
+					"Id":            d.Id,
+					"Name":          d.Name,
+					"CapacityBytes": d.CapacityBytes,
+					"Status":        map[string]string{"State": "Enabled", "Health": "OK"},
+				}
+				if !d.SecureEraseUnsupported {
+					drive["Actions"] = map[string]interface{}{
+						"#" + driveErase: map[string]string{"target": uri + "/Drives/" + d.Id + "/Actions/" + driveErase},
+					}
+				}
+				writeJSON(w, http.StatusOK, drive)
+				return true
+			}
+		}
+	case len(segments) == 2 && segments[1] == "Volumes":
+		members := []string{}
+		for _, v := range st.Volumes {
+			members = append(members, uri+"/Volumes/"+v.Id)
+		}
+		writeJSON(w, http.StatusOK, collection(uri+"/Volumes", members))
+		return true
+	case len(segments) == 3 && segments[1] == "Volumes":
+		for _, v := range st.Volumes {
+			if v.Id == segments[2] {
+				drives := []map[string]string{}
+				for _, d := range v.Drives {
+					drives = append(drives, link(uri+"/Drives/"+d))
+				}
+				writeJSON(w, http.StatusOK, map[string]interface{}{
+					"@odata.id":     uri + "/Volumes/" + v.Id,
+					"Id":            v.Id,
+					"Name":          v.Name,
+					"RAIDType":      v.RAIDType,
+					"CapacityBytes": v.CapacityBytes,
+					"Status":        map[string]string{"State": "Enabled", "Health": "OK"},
+					"Links":         map[string]interface{}{"Drives": drives},
+				})
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// createVolume creates the volume synchronously, the path is /redfish/v1/Systems/{id}/Storage/{id}/Volumes
+func (s *Server) createVolume(w http.ResponseWriter, urlPath string, body []byte) bool {
+	segments := strings.Split(strings.TrimPrefix(urlPath, systemsPath+"/"), "/")
+	if len(segments) != 4 || segments[1] != "Storage" || segments[3] != "Volumes" {
+		return false
+	}
+	st := s.findStorage(segments[0], segments[2])
+	if st == nil {
+		return false
+	}
+
+	var param struct {
+		Name          string
+		RAIDType      string
+		CapacityBytes int64
+		Links         struct {
+			Drives []map[string]string
+		}
+	}
+	if err := json.Unmarshal(body, &param); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return true
+	}
+	if len(param.Links.Drives) == 0 {
+		writeError(w, http.StatusBadRequest, "Links.Drives is required")
+		return true
+	}
+	volume := Volume{Name: param.Name, RAIDType: param.RAIDType, CapacityBytes: param.CapacityBytes}
+	for _, d := range param.Links.Drives {
+		id := path.Base(d["@odata.id"])
+		for _, v := range st.Volumes {
+			for _, used := range v.Drives {
+				if used == id {
+					writeError(w, http.StatusConflict, fmt.Sprintf("the drive %s is used by volume %s", id, v.Id))
+					return true
+				}
+			}
+		}
+		volume.Drives = append(volume.Drives, id)
+	}
+
+	s.volumeId++
+	volume.Id = "Volume" + strconv.Itoa(s.volumeId)
+	st.Volumes = append(st.Volumes, volume)
+	w.Header().Set("Location", urlPath+"/"+volume.Id)
+	w.WriteHeader(http.StatusCreated)
+	return true
+}
+
+// deleteVolume deletes the volume, the path is /redfish/v1/Systems/{id}/Storage/{id}/Volumes/{id}
+func (s *Server) deleteVolume(w http.ResponseWriter, urlPath string) bool {
+	segments := strings.Split(strings.TrimPrefix(urlPath, systemsPath+"/"), "/")
+	if len(segments) != 5 || segments[1] != "Storage" || segments[3] != "Volumes" {
+		return false
+	}
+	st := s.findStorage(segments[0], segments[2])
+	if st == nil {
+		return false
+	}
+	for i, v := range st.Volumes {
+		if v.Id == segments[4] {
+			st.Volumes = append(st.Volumes[:i], st.Volumes[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return true
+		}
+	}
+	return false
+}
+
+// storageAction resets the storage or erases the drive. The storage is reset synchronously, and the drive is erased by a
+// running task, which is finished by the test with SetTask. The path follows /redfish/v1/Systems/{id}/Storage/{id}
+func (s *Server) storageAction(w http.ResponseWriter, urlPath string) bool {
+	segments := strings.Split(strings.TrimPrefix(urlPath, systemsPath+"/"), "/")
+	if len(segments) < 5 || segments[1] != "Storage" {
+		return false
+	}
+	st := s.findStorage(segments[0], segments[2])
+	if st == nil {
+		return false
+	}
+
+	switch {
+	case len(segments) == 5 && segments[3] == "Actions" && segments[4] == storageReset:
+		st.Volumes = nil
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	case len(segments) == 7 && segments[3] == "Drives" && segments[5] == "Actions" && segments[6] == driveErase:
+		for _, d := range st.Drives {
+			if d.Id == segments[4] && !d.SecureEraseUnsupported {
+				s.createTask(w)
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	virtualCd   = "Cd"
+	insertMedia = "VirtualMedia.InsertMedia"
+	ejectMedia  = "VirtualMedia.EjectMedia"
+)
+
+// getVirtualMedia responds the virtual cd of the system, the segments follow VirtualMedia
+func (s *Server) getVirtualMedia(w http.ResponseWriter, system *System, segments []string) bool {
+	mediaUri := systemsPath + "/" + system.Id + "/VirtualMedia"
+	switch {
+	case len(segments) == 0:
+		writeJSON(w, http.StatusOK, collection(mediaUri, []string{mediaUri + "/" + virtualCd}))
+		return true
+	case len(segments) == 1 && segments[0] == virtualCd:
+		uri := mediaUri + "/" + virtualCd
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"@odata.id":      uri,
+			"Id":             virtualCd,
+			"MediaTypes":     []string{"CD", "DVD"},
+			"Image":          system.MediaImage,
+			"Inserted":       len(system.MediaImage) > 0,
+			"WriteProtected": true,
+			"Actions": map[string]interface{}{
+				"#" + insertMedia: map[string]string{"target": uri + "/Actions/" + insertMedia},
+				"#" + ejectMedia:  map[string]string{"target": uri + "/Actions/" + ejectMedia},
+			},
+		})
+		return true
+	}
+	return false
+}
+
+// virtualMediaAction inserts the image to the virtual cd or ejects it, the bmc refuses to insert when the cd is occupied
+func (s *Server) virtualMediaAction(w http.ResponseWriter, path string, body []byte) bool {
+	segments := strings.Split(strings.TrimPrefix(path, systemsPath+"/"), "/")
+	if len(segments) != 5 || segments[1] != "VirtualMedia" || segments[2] != virtualCd || segments[3] != "Actions" {
+		return false
+	}
+	system := s.findSystem(segments[0])
+	if system == nil {
+		return false
+	}
+	switch segments[4] {
+	case insertMedia:
+		var param struct {
+			Image string
+		}
+		if err := json.Unmarshal(body, &param); err != nil || len(param.Image) == 0 {
+			writeError(w, http.StatusBadRequest, "Image is required")
+			return true
+		}
+		if len(system.MediaImage) > 0 {
+			writeError(w, http.StatusConflict, fmt.Sprintf("the image %s has been inserted", system.MediaImage))
+			return true
+		}
+		system.MediaImage = param.Image
+	case ejectMedia:
+		system.MediaImage = ""
+	default:
+		return false
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
@@ -0,0 +1,86 @@
+package redfish
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// EnsureEventSubscription checks whether the subscription still exists on the bmc, and subscribes the events again
+// when it is lost, for example, the bmc is reset to the factory defaults. The other subscriptions whose destination
+// starts with destinationPrefix are stale, they are left by the former agent or carry a former token
+func (c *redfishClient) EnsureEventSubscription(subscriptionUri string, destination string, destinationPrefix string, context string) (string, bool, error) {
+	eventService, err := c.client.Service.EventService()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get event service: %+v", err)
+	}
+	if !eventService.ServiceEnabled {
+		return "", false, fmt.Errorf("the event service of the bmc is disabled")
+	}
+
+	subscriptions, err := eventService.GetEventSubscriptions()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get event subscriptions: %+v", err)
+	}
+	found := false
+	stale := []*redfish.EventDestination{}
+	for _, item := range subscriptions {
+		if item.Destination != destination && (len(destinationPrefix) == 0 || !strings.HasPrefix(item.Destination, destinationPrefix)) {
+			continue
+		}
+		if item.ODataID == subscriptionUri && item.Destination == destination {
+			found = true
+			continue
+		}
+		stale = append(stale, item)
+	}
+
+	// remove the stale subscriptions to avoid receiving duplicated events, or the events rejected for the former token
+	for _, item := range stale {
+		c.logger.Infof("delete the stale event subscription %s to %s", item.ODataID, item.Destination)
+		if err := eventService.DeleteEventSubscription(item.ODataID); err != nil {
+			c.logger.Warnf("failed to delete the stale event subscription %s: %+v", item.ODataID, err)
+		}
+	}
+	if found {
+		return subscriptionUri, false, nil
+	}
+
+	// subscribe all message registries and resource types
+	uri, err := eventService.CreateEventSubscriptionInstance(destination, nil, nil, nil, redfish.RedfishEventDestinationProtocol, context, "", nil)
+	if err != nil && len(eventService.EventTypesForSubscription) > 0 {
+		// the bmc before Redfish v1.6 only supports the subscription based on the event types
+		c.logger.Debugf("failed to subscribe events with the registry prefixes, try the event types %v: %+v", eventService.EventTypesForSubscription, err)
+		eventTypes := []redfish.EventType{}
+		for _, t := range eventService.EventTypesForSubscription {
+			// the MetricReport is too noisy, and it is not accepted by some bmc along with other types
+			if t != redfish.MetricReportEventType {
+				eventTypes = append(eventTypes, t)
+			}
+		}
+		uri, err = eventService.CreateEventSubscription(destination, eventTypes, nil, redfish.RedfishEventDestinationProtocol, context, nil)
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create event subscription to %s: %+v", destination, err)
+	}
+	// the Location header may be an absolute url
+	if u, err := url.Parse(uri); err == nil && len(u.Host) > 0 {
+		uri = u.Path
+	}
+	c.logger.Infof("created event subscription %s to %s", uri, destination)
+	return uri, true, nil
+}
+
+// DeleteEventSubscription deletes the subscription from the bmc
+func (c *redfishClient) DeleteEventSubscription(subscriptionUri string) error {
+	eventService, err := c.client.Service.EventService()
+	if err != nil {
+		return fmt.Errorf("failed to get event service: %+v", err)
+	}
+	if err := eventService.DeleteEventSubscription(subscriptionUri); err != nil {
+		return fmt.Errorf("failed to delete event subscription %s: %+v", subscriptionUri, err)
+	}
+	return nil
+}
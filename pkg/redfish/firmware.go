@@ -0,0 +1,129 @@
+package redfish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// the default target of the SimpleUpdate action defined by the DMTF
+const defaultSimpleUpdateTarget = "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate"
+
+// SimpleUpdate asks the BMC to download the image from the imageUri and update the firmware.
+// It returns the uri of the redfish task, which is empty when the BMC finishes the update synchronously
+func (c *redfishClient) SimpleUpdate(imageUri string, targets []string) (string, error) {
+	updateService, err := c.client.Service.UpdateService()
+	if err != nil {
+		return "", fmt.Errorf("failed to get update service: %+v", err)
+	}
+	if !updateService.ServiceEnabled {
+		c.logger.Warnf("the UpdateService of the BMC is not enabled, try to update anyway")
+	}
+
+	target := actionTarget(updateService.RawData, "#UpdateService.SimpleUpdate")
+	if len(target) == 0 {
+		target = defaultSimpleUpdateTarget
+	}
+
+	param := &redfish.SimpleUpdateParameters{
+		ImageURI:         imageUri,
+		Targets:          targets,
+		TransferProtocol: redfish.HTTPTransferProtocolType,
+	}
+	c.logger.Infof("simple update firmware with image %s, targets %v", imageUri, targets)
+	resp, err := c.client.Post(target, param)
+	if err != nil {
+		return "", fmt.Errorf("failed to post SimpleUpdate: %+v", err)
+	}
+	defer resp.Body.Close()
+
+	return taskUriFromResponse(resp.Header.Get("Location"), resp.Body), nil
+}
+
+// MultipartUpdate uploads the image file to the MultipartHttpPushUri of the BMC to update the firmware.
+// It returns the uri of the redfish task, which is empty when the BMC finishes the update synchronously
+func (c *redfishClient) MultipartUpdate(imageFile string, targets []string) (string, error) {
+	updateService, err := c.client.Service.UpdateService()
+	if err != nil {
+		return "", fmt.Errorf("failed to get update service: %+v", err)
+	}
+	if len(updateService.MultipartHTTPPushURI) == 0 {
+		return "", fmt.Errorf("the BMC does not support the multipart http push")
+	}
+
+	file, err := os.Open(imageFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image %s: %+v", imageFile, err)
+	}
+	defer file.Close()
+
+	if targets == nil {
+		targets = []string{}
+	}
+	parameters, err := json.Marshal(map[string]interface{}{"Targets": targets})
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Infof("push firmware image %s to %s, targets %v", imageFile, updateService.MultipartHTTPPushURI, targets)
+	resp, err := c.client.PostMultipart(updateService.MultipartHTTPPushURI, map[string]io.Reader{
+		"UpdateParameters": bytes.NewReader(parameters),
+		"UpdateFile":       file,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to push firmware image: %+v", err)
+	}
+	defer resp.Body.Close()
+
+	return taskUriFromResponse(resp.Header.Get("Location"), resp.Body), nil
+}
+
+// GetFirmwareVersions returns the firmware inventory with the odata id in targets, it returns all firmware when targets is empty
+func (c *redfishClient) GetFirmwareVersions(targets []string) ([]topohubv1beta1.FirmwareInventory, error) {
+	all := c.getFirmwareInventory()
+	if len(targets) == 0 {
+		return all, nil
+	}
+
+	result := []topohubv1beta1.FirmwareInventory{}
+	for _, item := range all {
+		for _, t := range targets {
+			if item.ODataId == t {
+				result = append(result, item)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// actionTarget reads the target uri of the action from the raw data of the resource
+func actionTarget(rawData []byte, action string) string {
+	var t struct {
+		Actions map[string]struct {
+			Target string `json:"target"`
+		}
+	}
+	if err := json.Unmarshal(rawData, &t); err != nil {
+		return ""
+	}
+	return t.Actions[action].Target
+}
+
+// getActionTarget reads the target uri of the action from the resource, for the gofish objects which do not keep the raw data
+func (c *redfishClient) getActionTarget(uri string, action string) (string, error) {
+	var t struct {
+		Actions map[string]struct {
+			Target string `json:"target"`
+		}
+	}
+	if err := c.getJson(uri, &t); err != nil {
+		return "", err
+	}
+	return t.Actions[action].Target, nil
+}
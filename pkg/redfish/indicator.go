@@ -0,0 +1,73 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// indicatorChassis returns the first chassis of the system which reports the IndicatorLED or the LocationIndicatorActive
+func (c *redfishClient) indicatorChassis(system *redfish.ComputerSystem) (*redfish.Chassis, error) {
+	cs, err := c.systemChassis(system, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, chassis := range cs {
+		if len(chassis.IndicatorLED) > 0 || hasLocationIndicator(chassis.RawData) {
+			return chassis, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no chassis of system %s reports the indicator led", ErrNotSupported, system.ID)
+}
+
+// hasLocationIndicator checks whether the resource reports the LocationIndicatorActive, which is false when it is absent
+func hasLocationIndicator(raw []byte) bool {
+	t := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return false
+	}
+	_, ok := t["LocationIndicatorActive"]
+	return ok
+}
+
+// indicatorLEDState returns the state of the indicator led. The LocationIndicatorActive replaces the deprecated IndicatorLED
+// in the new BMC, it is reported as Lit when it is active
+func indicatorLEDState(chassis *redfish.Chassis) string {
+	if len(chassis.IndicatorLED) > 0 {
+		return string(chassis.IndicatorLED)
+	}
+	if chassis.LocationIndicatorActive {
+		return topohubv1beta1.IndicatorLEDLit
+	}
+	return topohubv1beta1.IndicatorLEDOff
+}
+
+// SetIndicatorLED sets the indicator led of the chassis which contains the system, and returns its previous state.
+// The IndicatorLED is patched when the chassis reports it, since the LocationIndicatorActive could not make the led blink
+func (c *redfishClient) SetIndicatorLED(systemId string, state string) (string, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return "", err
+	}
+	chassis, err := c.indicatorChassis(system)
+	if err != nil {
+		return "", err
+	}
+	previous := indicatorLEDState(chassis)
+
+	body := map[string]interface{}{}
+	if len(chassis.IndicatorLED) > 0 {
+		body["IndicatorLED"] = state
+	} else {
+		body["LocationIndicatorActive"] = state != topohubv1beta1.IndicatorLEDOff
+	}
+	c.logger.Infof("set indicator led of chassis %s from %s to %s", chassis.ID, previous, state)
+	resp, err := c.client.Patch(chassis.ODataID, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to set indicator led of chassis %s: %+v", chassis.ID, err)
+	}
+	resp.Body.Close()
+	return previous, nil
+}
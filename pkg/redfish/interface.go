@@ -1,73 +1,201 @@
 package redfish
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stmcginnis/gofish/redfish"
 	"reflect"
+	"sync"
+	"time"
 
 	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
 	"github.com/stmcginnis/gofish"
 	"go.uber.org/zap"
 )
 
 // Client 定义了 Redfish 客户端接口
 type RefishClient interface {
-	Power(string) error
+	// Power operates the system with the id, the id could be empty when the host exposes only one system.
+	// It returns the uri of the redfish task when the bmc resets the system asynchronously
+	Power(bootCmd string, systemId string) (string, error)
 	GetInfo() (map[string]string, error)
+	// GetInventory returns the structured hardware inventory for the HostInventory
+	GetInventory() (*topohubv1beta1.HostInventoryStatus, error)
 	GetLog() ([]*redfish.LogEntry, error)
+	// GetSensors returns the readings of the temperatures, fans, power and voltages
+	GetSensors() ([]SensorReading, error)
+	// 列出 bmc 暴露的所有 ComputerSystem 和 Manager
+	GetSystems() ([]topohubv1beta1.SystemInfo, error)
+	GetManagers() ([]topohubv1beta1.ManagerInfo, error)
+	// 升级固件，返回 bmc 创建的 redfish task 的 uri，同步完成升级时为空
+	SimpleUpdate(imageUri string, targets []string) (string, error)
+	MultipartUpdate(imageFile string, targets []string) (string, error)
+	GetFirmwareVersions(targets []string) ([]topohubv1beta1.FirmwareInventory, error)
+	// 插入 iso 到虚拟光驱并从光驱启动一次，返回虚拟光驱的 uri，以及 bmc 异步重启主机时创建的 redfish task 的 uri
+	VirtualMediaBoot(systemId string, imageUri string) (string, string, error)
+	EjectVirtualMedia(mediaUri string) error
+	// SetBoot sets the boot override and the persistent boot order of the system, and returns the uri of the redfish task
+	// when the bmc resets the system asynchronously
+	SetBoot(systemId string, boot topohubv1beta1.BootSpec) (string, error)
+	// SetIndicatorLED sets the indicator led of the chassis which contains the system to Lit, Blinking or Off, and returns its previous state
+	SetIndicatorLED(systemId string, state string) (string, error)
+	// bios 属性，系统重启后生效的属性在 Pending 中
+	GetBiosAttributes(systemId string) (*BiosAttributes, error)
+	SetBiosAttributes(systemId string, attributes map[string]string, applyTime string) error
+	// 列出 system 的 Storage 及其 drive 和 volume
+	GetStorage(systemId string) ([]topohubv1beta1.StorageInventory, error)
+	// 在 Storage 上创建或删除 volume，返回 bmc 创建的 redfish task 的 uri，同步完成时为空
+	CreateVolume(storageUri string, volume VolumeRequest, applyTime string) (string, error)
+	DeleteVolume(volumeUri string, applyTime string) (string, error)
+	// 下线主机时，清除 Storage 的配置、擦除硬盘、恢复 BIOS 的默认值、清空日志，返回 bmc 创建的 redfish task 的 uri
+	ResetStorage(systemId string) ([]string, error)
+	SecureEraseDrives(systemId string) ([]string, error)
+	ResetBios(systemId string) (string, error)
+	ClearLogs(systemId string) error
+	// 读取机箱的功耗及其历史、功率上限，设置机箱的功率上限
+	GetPowerControls() ([]PowerControl, error)
+	SetPowerLimit(powerUri string, limit PowerLimit) error
+	// GetTask returns the state of the asynchronous redfish task
+	GetTask(taskUri string) (*topohubv1beta1.TaskInfo, error)
+	// 订阅 bmc 的事件，推送到 destination。订阅不存在时（例如 bmc 被重置后）重新创建，返回订阅的 uri 以及是否新建。
+	// 目的地址以 destinationPrefix 开头的其它订阅是过期的，会被删除
+	EnsureEventSubscription(subscriptionUri string, destination string, destinationPrefix string, context string) (string, bool, error)
+	DeleteEventSubscription(subscriptionUri string) error
+	// 创建 bmc 的账户，账户已存在时修改其密码，返回账户的 uri 以及是否新建
+	EnsureAccount(username, password, roleId string) (string, bool, error)
+	DeleteAccount(accountUri string) error
+	// SetSnmp enables or disables the snmp agent of the bmc
+	SetSnmp(enabled bool) error
+	// 配置 bmc 的 snmp trap 目的地址，目的地址丢失时（例如 bmc 被重置后）重新配置，返回目的地址的 uri 以及是否新建
+	EnsureSnmpTrapDestination(destinationUri string, destination SnmpTrapDestination) (string, bool, error)
+	DeleteSnmpTrapDestination(destinationUri string) error
+	// 读取 bmc 的 https 证书，由 bmc 生成 CSR，使用 CA 签发的证书替换 https 证书
+	GetHttpsCertificates() (string, []HttpsCertificate, error)
+	GenerateCSR(collectionUri string, request CertificateSigningRequest) (string, error)
+	ReplaceCertificate(certificateUri string, certificate string) error
+	// 重启管理 system 的 bmc，或者恢复 bmc 的出厂设置，bmc 在重启期间无法访问
+	ResetManager(systemId string, resetType string) error
+	ResetManagerToDefaults(systemId string, resetType string) error
+	// 读取和设置 bmc 的网络服务，包括 ManagerNetworkProtocol 中的协议和 NTP、bmc 网口的 DNS 以及 syslog 转发
+	GetManagerNetwork() (*ManagerNetwork, error)
+	SetManagerNetworkProtocol(protocolUri string, setting NetworkProtocolSetting) error
+	SetManagerEthernetInterface(interfaceUri string, setting EthernetInterfaceSetting) error
+	GetSyslogSubscriptions() ([]SyslogSubscription, error)
+	EnsureSyslogDestination(destination SyslogDestination) (bool, error)
+	// GetCertificate returns the certificate presented by the bmc, it is nil when https is not used
+	GetCertificate() *topohubv1beta1.CertificateInfo
 }
 
 // redfishClient 实现了 Client 接口
 type redfishClient struct {
 	config gofish.ClientConfig
+	// tls is the trust of the certificate which the client is created with
+	tls  *hoststatusData.TLSTrust
+	peer *peerCertificate
+	// usage records the requests of the client, it is logged out after it is dropped from the cache and becomes idle
+	usage  *usageTransport
 	logger *zap.SugaredLogger
 	client *gofish.APIClient
+
+	// adapter overrides the calls for the vendor of the bmc, it is selected at the first use
+	adapterOnce sync.Once
+	adapter     vendorAdapter
 }
 
 var _ RefishClient = (*redfishClient)(nil)
 
-var CacheClient = make(map[string]*redfishClient)
+// responseTimeout is the max time to wait for the response of the bmc
+const responseTimeout = 2 * time.Minute
+
+// ErrNotSupported is returned when the bmc could not do the operation with its protocol, such as updating the firmware over ipmi
+var ErrNotSupported = errors.New("not supported by the protocol of the bmc")
 
-// NewClient 创建一个新的 Redfish 客户端
+// NewClient 创建一个新的 Redfish 客户端，客户端按照 bmc 地址缓存，可以被多个协程并发使用
 func NewClient(hostCon hoststatusData.HostConnectCon, log *zap.SugaredLogger) (RefishClient, error) {
+	if hostCon.Info.Protocol == topohubv1beta1.ProtocolIPMI {
+		return newIpmiClient(hostCon, log)
+	}
 
 	url := buildEndpoint(hostCon)
 	config := gofish.ClientConfig{
 		Endpoint:         url,
 		Username:         hostCon.Username,
 		Password:         hostCon.Password,
-		Insecure:         true,
 		ReuseConnections: true,
 	}
 
-	if c, ok := CacheClient[hostCon.Info.IpAddr]; ok {
-		if reflect.DeepEqual(config, c.config) {
+	e := CacheClient.acquire(hostCon.Info.IpAddr)
+	defer e.lock.Unlock()
+	if c := e.redfish; c != nil {
+		if reflect.DeepEqual(config, c.config) && reflect.DeepEqual(hostCon.TLS, c.tls) {
 			_, err := c.client.Service.Systems()
 			if err == nil {
 				log.Debugf("use cached redfish client for %s", hostCon.Info.IpAddr)
 				return c, nil
 			}
 		}
-		log.Debugf("logout invalid cached redfish client for %s", hostCon.Info.IpAddr)
-		c.client.Logout()
-		delete(CacheClient, hostCon.Info.IpAddr)
+		// the other callers may still be using the cached client, so it is logged out after their requests finish
+		log.Debugf("retire invalid cached redfish client for %s", hostCon.Info.IpAddr)
+		c.retire()
+		e.redfish = nil
 	}
 
 	log.Debugf("create new redfish client for %s", hostCon.Info.IpAddr)
-	client, err := gofish.Connect(config)
+	c, err := connect(hostCon, config, log)
+	if err != nil {
+		return nil, err
+	}
+
+	e.redfish = c
+	return c, nil
+}
+
+// ConnectOnce connects the bmc without the cache, and logs out after fn returns. It is used for the credential which
+// is not in the secret yet, so the cached client of the host is not replaced
+func ConnectOnce(hostCon hoststatusData.HostConnectCon, log *zap.SugaredLogger, fn func(RefishClient) error) error {
+	if hostCon.Info.Protocol == topohubv1beta1.ProtocolIPMI {
+		c, err := connectIpmi(hostCon, log)
+		if err != nil {
+			return err
+		}
+		defer c.client.Close()
+		return fn(c)
+	}
+	config := gofish.ClientConfig{
+		Endpoint: buildEndpoint(hostCon),
+		Username: hostCon.Username,
+		Password: hostCon.Password,
+	}
+	c, err := connect(hostCon, config, log)
+	if err != nil {
+		return err
+	}
+	defer c.client.Logout()
+	return fn(c)
+}
+
+func connect(hostCon hoststatusData.HostConnectCon, config gofish.ClientConfig, log *zap.SugaredLogger) (*redfishClient, error) {
+	// the certificate is verified before the credential is sent to create the session
+	peer := &peerCertificate{}
+	withClient := config
+	withClient.HTTPClient = newHTTPClient(hostCon, config.ReuseConnections, peer)
+	usage := &usageTransport{next: withClient.HTTPClient.Transport}
+	withClient.HTTPClient.Transport = usage
+	client, err := gofish.Connect(withClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %+v", err)
+		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
-	c := &redfishClient{
+	return &redfishClient{
 		config: config,
+		tls:    hostCon.TLS,
+		peer:   peer,
+		usage:  usage,
 		logger: log.Named("redfish").With(
-			zap.String("endpoint", url),
+			zap.String("endpoint", config.Endpoint),
 		),
 		client: client,
-	}
-
-	CacheClient[hostCon.Info.IpAddr] = c
-	return c, nil
+	}, nil
 }
 
 // buildEndpoint 根据 HostConnectCon 构建 Redfish 服务的端点 URL
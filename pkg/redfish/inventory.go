@@ -0,0 +1,366 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	DeviceType_Unknown = "Unknown"
+	DeviceType_GPU     = "GPU"
+	DeviceType_Storage = "STORAGE"
+	DeviceType_NIC     = "NIC"
+)
+
+// GetInventory collects the hardware inventory of all systems.
+// Every item is identified by its @odata.id, and the lists are sorted by it,
+// so that the inventory does not churn when the BMC returns the arrays in different order
+func (c *redfishClient) GetInventory() (*topohubv1beta1.HostInventoryStatus, error) {
+
+	// Attached the client to service root
+	service := c.client.Service
+
+	ss, err := service.Systems()
+	if err != nil {
+		c.logger.Errorf("failed to Query the computer systems: %+v", err)
+		return nil, err
+	} else if len(ss) == 0 {
+		c.logger.Errorf("failed to get system")
+		return nil, fmt.Errorf("failed to get system")
+	}
+	c.logger.Debugf("system amount: %d", len(ss))
+
+	result := &topohubv1beta1.HostInventoryStatus{}
+	for _, system := range ss {
+		item, err := c.getSystemInventory(system, len(ss) == 1)
+		if err != nil {
+			return nil, err
+		}
+		result.Systems = append(result.Systems, *item)
+	}
+	sort.Slice(result.Systems, func(i, j int) bool { return result.Systems[i].ODataId < result.Systems[j].ODataId })
+
+	// firmware inventory is optional for the BMC
+	result.Firmware = c.getFirmwareInventory()
+
+	return result, nil
+}
+
+func (c *redfishClient) getSystemInventory(system *redfish.ComputerSystem, onlySystem bool) (*topohubv1beta1.SystemInventory, error) {
+	result := &topohubv1beta1.SystemInventory{
+		Id:                    system.ID,
+		ODataId:               system.ODataID,
+		SerialNumber:          system.SerialNumber,
+		UUID:                  system.UUID,
+		ProcessorCount:        int32(system.ProcessorSummary.Count),
+		LogicalProcessorCount: int32(system.ProcessorSummary.LogicalProcessorCount),
+		TotalMemoryGiB:        int32(system.MemorySummary.TotalSystemMemoryGiB),
+	}
+
+	// cpu info
+	cpus, err := system.Processors()
+	if err != nil {
+		c.logger.Errorf("failed to get processors: %+v", err)
+		return nil, err
+	}
+	c.logger.Debugf("cpus amount: %d", len(cpus))
+	for _, cpu := range cpus {
+		result.Processors = append(result.Processors, topohubv1beta1.ProcessorInventory{
+			ODataId:       cpu.ODataID,
+			Socket:        cpu.Socket,
+			Manufacturer:  cpu.Manufacturer,
+			Model:         cpu.Model,
+			ProcessorType: string(cpu.ProcessorType),
+			Architecture:  string(cpu.ProcessorArchitecture),
+			TotalCores:    int32(cpu.TotalCores),
+			TotalThreads:  int32(cpu.TotalThreads),
+			MaxSpeedMHz:   int32(cpu.MaxSpeedMHz),
+			SerialNumber:  cpu.SerialNumber,
+			Health:        string(cpu.Status.Health),
+			State:         string(cpu.Status.State),
+		})
+	}
+	sort.Slice(result.Processors, func(i, j int) bool { return result.Processors[i].ODataId < result.Processors[j].ODataId })
+
+	// memory info
+	mms, err := system.Memory()
+	if err != nil {
+		c.logger.Errorf("failed to get memory: %+v", err)
+		return nil, err
+	}
+	c.logger.Debugf("memory amount: %d", len(mms))
+	for _, mm := range mms {
+		result.Memory = append(result.Memory, topohubv1beta1.MemoryInventory{
+			ODataId:           mm.ODataID,
+			DeviceLocator:     mm.DeviceLocator,
+			Manufacturer:      mm.Manufacturer,
+			PartNumber:        mm.PartNumber,
+			SerialNumber:      mm.SerialNumber,
+			MemoryDeviceType:  string(mm.MemoryDeviceType),
+			CapacityMiB:       int32(mm.CapacityMiB),
+			OperatingSpeedMhz: int32(mm.OperatingSpeedMhz),
+			Health:            string(mm.Status.Health),
+			State:             string(mm.Status.State),
+		})
+	}
+	sort.Slice(result.Memory, func(i, j int) bool { return result.Memory[i].ODataId < result.Memory[j].ODataId })
+
+	// storage info
+	storages, err := system.Storage()
+	if err != nil {
+		c.logger.Debugf("failed to get storage of system %s: %+v", system.ID, err)
+	}
+	result.Drives = c.getDriveInventory(system, storages)
+	result.Storage = c.storageInventory(storages)
+	sort.Slice(result.Drives, func(i, j int) bool { return result.Drives[i].ODataId < result.Drives[j].ODataId })
+
+	// pcie and network info
+	cs, err := c.systemChassis(system, onlySystem)
+	if err != nil {
+		return nil, err
+	}
+	pcieList, err := c.systemPCIeDevices(system, cs)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debugf("system %s pcie devices amount: %d", system.ID, len(pcieList))
+	interfaces := map[string]topohubv1beta1.EthernetInterfaceInventory{}
+	for _, item := range pcieList {
+		device := topohubv1beta1.PCIeDeviceInventory{
+			ODataId:         item.ODataID,
+			DeviceType:      pcieDeviceType(item.Description),
+			Name:            item.Name,
+			Description:     item.Description,
+			Manufacturer:    item.Manufacturer,
+			Model:           item.Model,
+			SerialNumber:    item.SerialNumber,
+			FirmwareVersion: item.FirmwareVersion,
+			PCIeType:        string(item.PCIeInterface.PCIeType),
+			MaxPCIeType:     string(item.PCIeInterface.MaxPCIeType),
+			LanesInUse:      int32(item.PCIeInterface.LanesInUse),
+			MaxLanes:        int32(item.PCIeInterface.MaxLanes),
+			Health:          string(item.Status.Health),
+			State:           string(item.Status.State),
+		}
+
+		pfcs, err := item.PCIeFunctions()
+		if err == nil {
+			for _, pfc := range pfcs {
+				// for network device function
+				ints, err := pfc.EthernetInterfaces()
+				if err == nil && len(ints) > 0 {
+					device.DeviceType = DeviceType_NIC
+					for _, netint := range ints {
+						t := ethernetInterfaceInventory(netint)
+						t.PCIeDevice = item.ODataID
+						interfaces[t.ODataId] = t
+					}
+				}
+				// for storage device function
+				stors, err := pfc.StorageControllers()
+				if err == nil && len(stors) > 0 && device.DeviceType == DeviceType_Unknown {
+					device.DeviceType = DeviceType_Storage
+				}
+			}
+		}
+		result.PCIeDevices = append(result.PCIeDevices, device)
+	}
+	sort.Slice(result.PCIeDevices, func(i, j int) bool { return result.PCIeDevices[i].ODataId < result.PCIeDevices[j].ODataId })
+
+	// the ethernet interfaces of the system, which may not hang under the pcie functions
+	ints, err := system.EthernetInterfaces()
+	if err != nil {
+		c.logger.Warnf("failed to get ethernet interfaces of system %s: %+v", system.ID, err)
+	}
+	for _, netint := range ints {
+		if _, ok := interfaces[netint.ODataID]; !ok {
+			interfaces[netint.ODataID] = ethernetInterfaceInventory(netint)
+		}
+	}
+	for _, t := range interfaces {
+		result.EthernetInterfaces = append(result.EthernetInterfaces, t)
+	}
+	sort.Slice(result.EthernetInterfaces, func(i, j int) bool {
+		return result.EthernetInterfaces[i].ODataId < result.EthernetInterfaces[j].ODataId
+	})
+
+	// the network adapters and ports, the LLDP neighbor maps the nic of the host to the switch port
+	result.NetworkAdapters = c.getNetworkAdapterInventory(cs)
+
+	return result, nil
+}
+
+// getDriveInventory collects the drives from the Storage resources, and falls back to the SimpleStorage for old BMC
+func (c *redfishClient) getDriveInventory(system *redfish.ComputerSystem, storages []*redfish.Storage) []topohubv1beta1.DriveInventory {
+	result := []topohubv1beta1.DriveInventory{}
+
+	for _, st := range storages {
+		drives, err := st.Drives()
+		if err != nil {
+			c.logger.Warnf("failed to get drives of storage %s: %+v", st.ODataID, err)
+			continue
+		}
+		for _, d := range drives {
+			result = append(result, topohubv1beta1.DriveInventory{
+				ODataId:         d.ODataID,
+				Name:            d.Name,
+				Manufacturer:    d.Manufacturer,
+				Model:           d.Model,
+				SerialNumber:    d.SerialNumber,
+				MediaType:       string(d.MediaType),
+				Protocol:        string(d.Protocol),
+				CapacityBytes:   d.CapacityBytes,
+				FirmwareVersion: d.Revision,
+				Health:          string(d.Status.Health),
+				State:           string(d.Status.State),
+			})
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+
+	simpleStorages, err := system.SimpleStorages()
+	if err != nil {
+		c.logger.Debugf("failed to get simple storage of system %s: %+v", system.ID, err)
+		return result
+	}
+	c.logger.Debugf("simple storage amount: %d", len(simpleStorages))
+	for _, st := range simpleStorages {
+		for _, item := range st.Devices {
+			result = append(result, topohubv1beta1.DriveInventory{
+				ODataId:       st.ODataID + "#" + item.Name,
+				Name:          item.Name,
+				Manufacturer:  item.Manufacturer,
+				Model:         item.Model,
+				CapacityBytes: item.CapacityBytes,
+				Health:        string(item.Status.Health),
+				State:         string(item.Status.State),
+			})
+		}
+	}
+	return result
+}
+
+// getFirmwareInventory collects the firmware inventory from the UpdateService
+func (c *redfishClient) getFirmwareInventory() []topohubv1beta1.FirmwareInventory {
+	result := []topohubv1beta1.FirmwareInventory{}
+
+	updateService, err := c.client.Service.UpdateService()
+	if err != nil {
+		c.logger.Debugf("failed to get update service: %+v", err)
+		return result
+	}
+	items, err := updateService.FirmwareInventories()
+	if err != nil {
+		c.logger.Debugf("failed to get firmware inventory: %+v", err)
+		return result
+	}
+	for _, item := range items {
+		result = append(result, topohubv1beta1.FirmwareInventory{
+			ODataId:    item.ODataID,
+			Name:       item.Name,
+			Version:    item.Version,
+			SoftwareId: item.SoftwareID,
+			Updateable: item.Updateable,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ODataId < result[j].ODataId })
+	return result
+}
+
+// systemChassis returns the chassis which contain the system.
+// When the chassis do not link to any system and the host only has one system, all chassis are returned
+func (c *redfishClient) systemChassis(system *redfish.ComputerSystem, onlySystem bool) ([]*redfish.Chassis, error) {
+	cs, err := c.client.Service.Chassis()
+	if err != nil {
+		c.logger.Errorf("failed to get chassis: %+v", err)
+		return nil, err
+	}
+	c.logger.Debugf("chassis amount: %d", len(cs))
+
+	result := []*redfish.Chassis{}
+	for _, chassis := range cs {
+		t := struct {
+			Links struct {
+				ComputerSystems []struct {
+					ODataID string `json:"@odata.id"`
+				}
+			}
+		}{}
+		if err := json.Unmarshal(chassis.RawData, &t); err != nil {
+			continue
+		}
+		for _, s := range t.Links.ComputerSystems {
+			if s.ODataID == system.ODataID {
+				result = append(result, chassis)
+				break
+			}
+		}
+	}
+	if len(result) == 0 && onlySystem {
+		return cs, nil
+	}
+	return result, nil
+}
+
+// systemPCIeDevices returns the pcie devices of the system, which are linked by the system or by its chassis
+func (c *redfishClient) systemPCIeDevices(system *redfish.ComputerSystem, cs []*redfish.Chassis) ([]*redfish.PCIeDevice, error) {
+	result := []*redfish.PCIeDevice{}
+	existed := map[string]bool{}
+
+	pcieList, err := system.PCIeDevices()
+	if err != nil {
+		c.logger.Debugf("failed to get pcie devices of system %s: %+v", system.ID, err)
+	}
+	for _, item := range pcieList {
+		existed[item.ODataID] = true
+		result = append(result, item)
+	}
+
+	for _, chassis := range cs {
+		pcieList, err := chassis.PCIeDevices()
+		if err != nil {
+			c.logger.Errorf("failed to get pcie devices of chassis %s: %+v", chassis.ID, err)
+			return nil, err
+		}
+		for _, item := range pcieList {
+			if !existed[item.ODataID] {
+				existed[item.ODataID] = true
+				result = append(result, item)
+			}
+		}
+	}
+	return result, nil
+}
+
+func pcieDeviceType(description string) string {
+	switch strings.ToLower(description) {
+	case "gpu device":
+		return DeviceType_GPU
+	case "nvmessd device":
+		return DeviceType_Storage
+	case "nic device":
+		return DeviceType_NIC
+	default:
+		return DeviceType_Unknown
+	}
+}
+
+func ethernetInterfaceInventory(netint *redfish.EthernetInterface) topohubv1beta1.EthernetInterfaceInventory {
+	return topohubv1beta1.EthernetInterfaceInventory{
+		ODataId:             netint.ODataID,
+		Name:                netint.Name,
+		MACAddress:          netint.MACAddress,
+		PermanentMACAddress: netint.PermanentMACAddress,
+		SpeedMbps:           int32(netint.SpeedMbps),
+		LinkStatus:          string(netint.LinkStatus),
+		Health:              string(netint.Status.Health),
+		State:               string(netint.Status.State),
+	}
+}
@@ -0,0 +1,581 @@
+package redfish
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+	"go.uber.org/zap"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	"github.com/infrastructure-io/topohub/pkg/ipmi"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+)
+
+// ipmiSystemId is the id of the only system managed by the ipmi bmc
+const ipmiSystemId = "1"
+
+// ipmiClient implements the RefishClient with IPMI v2.0 over lan for the old bmc without usable redfish.
+// It supports the power control, chassis status, FRU inventory and SEL, other operations return ErrNotSupported
+type ipmiClient struct {
+	addr     string
+	username string
+	password string
+	logger   *zap.SugaredLogger
+	client   *ipmi.Client
+
+	// mu protects the cached FRU and SEL, since the client is shared by the controllers
+	mu sync.Mutex
+	// the FRU is read once for the session, since reading it takes dozens of requests
+	fru *ipmi.FRU
+	// the SEL is read again only when the bmc adds or erases the entries
+	selInfo    *ipmi.SELInfo
+	selEntries []*redfish.LogEntry
+}
+
+var _ RefishClient = (*ipmiClient)(nil)
+
+func newIpmiClient(hostCon hoststatusData.HostConnectCon, log *zap.SugaredLogger) (RefishClient, error) {
+	addr := ipmiAddr(hostCon)
+	e := CacheClient.acquire(hostCon.Info.IpAddr)
+	defer e.lock.Unlock()
+	if c := e.ipmi; c != nil {
+		if c.addr == addr && c.username == hostCon.Username && c.password == hostCon.Password {
+			if _, err := c.client.GetDeviceID(); err == nil {
+				log.Debugf("use cached ipmi client for %s", hostCon.Info.IpAddr)
+				return c, nil
+			}
+		}
+		log.Debugf("close invalid cached ipmi client for %s", hostCon.Info.IpAddr)
+		c.client.Close()
+		e.ipmi = nil
+	}
+
+	log.Debugf("create new ipmi client for %s", hostCon.Info.IpAddr)
+	c, err := connectIpmi(hostCon, log)
+	if err != nil {
+		return nil, err
+	}
+	e.ipmi = c
+	return c, nil
+}
+
+func connectIpmi(hostCon hoststatusData.HostConnectCon, log *zap.SugaredLogger) (*ipmiClient, error) {
+	addr := ipmiAddr(hostCon)
+	client := ipmi.NewClient(addr, hostCon.Username, hostCon.Password, ipmi.DefaultTimeout)
+	if err := client.Open(); err != nil {
+		return nil, fmt.Errorf("failed to connect: %+v", err)
+	}
+	return &ipmiClient{
+		addr:     addr,
+		username: hostCon.Username,
+		password: hostCon.Password,
+		logger:   log.Named("ipmi").With(zap.String("endpoint", addr)),
+		client:   client,
+	}, nil
+}
+
+func ipmiAddr(hostCon hoststatusData.HostConnectCon) string {
+	port := int(hostCon.Info.Port)
+	if port == 0 {
+		port = ipmi.DefaultPort
+	}
+	return net.JoinHostPort(hostCon.Info.IpAddr, strconv.Itoa(port))
+}
+
+func (c *ipmiClient) checkSystem(systemId string) error {
+	if len(systemId) > 0 && systemId != ipmiSystemId {
+		return fmt.Errorf("system %s is not found, the ipmi bmc only manages the system %s", systemId, ipmiSystemId)
+	}
+	return nil
+}
+
+func (c *ipmiClient) getFRU() *ipmi.FRU {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fru != nil {
+		return c.fru
+	}
+	fru, err := c.client.ReadFRU(ipmi.FRUDeviceBuiltin)
+	if err != nil {
+		// some bmc has no FRU of the system board, the other information is still available
+		c.logger.Warnf("failed to read fru: %+v", err)
+		return &ipmi.FRU{}
+	}
+	c.fru = fru
+	return fru
+}
+
+func fruManufacturer(fru *ipmi.FRU) string {
+	return firstNonEmpty(fru.ProductManufacturer, fru.BoardManufacturer)
+}
+
+func fruModel(fru *ipmi.FRU) string {
+	return firstNonEmpty(fru.ProductName, fru.BoardProductName)
+}
+
+func fruSerialNumber(fru *ipmi.FRU) string {
+	return firstNonEmpty(fru.ProductSerialNumber, fru.ChassisSerialNumber, fru.BoardSerialNumber)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
+
+func powerState(status *ipmi.ChassisStatus) string {
+	if status.PowerOn {
+		return string(redfish.OnPowerState)
+	}
+	return string(redfish.OffPowerState)
+}
+
+func chassisHealth(status *ipmi.ChassisStatus) string {
+	if status.Faulted() {
+		return string(common.WarningHealth)
+	}
+	return string(common.OKHealth)
+}
+
+// the reset types which are mapped to the chassis control
+var ipmiResetTypes = []string{
+	topohubv1beta1.BootCmdOn,
+	topohubv1beta1.BootCmdForceOn,
+	topohubv1beta1.BootCmdForceOff,
+	topohubv1beta1.BootCmdGracefulShutdown,
+	topohubv1beta1.BootCmdForceRestart,
+}
+
+func (c *ipmiClient) GetInfo() (map[string]string, error) {
+	result := map[string]string{}
+
+	device, err := c.client.GetDeviceID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device id: %+v", err)
+	}
+	status, err := c.client.GetChassisStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chassis status: %+v", err)
+	}
+	fru := c.getFRU()
+
+	setData(result, "BmcFirmwareVersion", device.FirmwareVersion)
+	setData(result, "IpmiVersion", device.IpmiVersion)
+	setData(result, "Manufacturer", fruManufacturer(fru))
+	setData(result, "Model", fruModel(fru))
+	setData(result, "SerialNumber", fruSerialNumber(fru))
+	setData(result, "PowerState", powerState(status))
+	setData(result, "SyatemStatus", chassisHealth(status))
+	setData(result, "PowerRestorePolicy", status.PowerRestorePolicy)
+	setData(result, "SupportedReset", strings.Join(ipmiResetTypes, ","))
+	setData(result, "SystemId", ipmiSystemId)
+	return result, nil
+}
+
+func (c *ipmiClient) GetSystems() ([]topohubv1beta1.SystemInfo, error) {
+	status, err := c.client.GetChassisStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chassis status: %+v", err)
+	}
+	fru := c.getFRU()
+	return []topohubv1beta1.SystemInfo{
+		{
+			Id:                  ipmiSystemId,
+			Manufacturer:        fruManufacturer(fru),
+			Model:               fruModel(fru),
+			SerialNumber:        fruSerialNumber(fru),
+			PowerState:          powerState(status),
+			Health:              chassisHealth(status),
+			SupportedResetTypes: ipmiResetTypes,
+			IndicatorLED:        ipmiIndicatorLED(status),
+		},
+	}, nil
+}
+
+func (c *ipmiClient) GetManagers() ([]topohubv1beta1.ManagerInfo, error) {
+	device, err := c.client.GetDeviceID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device id: %+v", err)
+	}
+	return []topohubv1beta1.ManagerInfo{
+		{
+			Id:              "BMC",
+			Name:            fmt.Sprintf("IPMI %s BMC", device.IpmiVersion),
+			ManagerType:     string(redfish.BMCManagerType),
+			Model:           fmt.Sprintf("manufacturer %d product %d", device.ManufacturerID, device.ProductID),
+			FirmwareVersion: device.FirmwareVersion,
+		},
+	}, nil
+}
+
+// GetInventory returns the serial number in the FRU and the firmware version of the bmc,
+// the processors, memory and drives could not be listed over ipmi
+func (c *ipmiClient) GetInventory() (*topohubv1beta1.HostInventoryStatus, error) {
+	device, err := c.client.GetDeviceID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device id: %+v", err)
+	}
+	fru := c.getFRU()
+	return &topohubv1beta1.HostInventoryStatus{
+		Systems: []topohubv1beta1.SystemInventory{
+			{
+				Id:           ipmiSystemId,
+				SerialNumber: fruSerialNumber(fru),
+			},
+		},
+		Firmware: []topohubv1beta1.FirmwareInventory{
+			{
+				Name:    "BMC",
+				Version: device.FirmwareVersion,
+			},
+		},
+	}, nil
+}
+
+// GetLog converts the SEL to the log entries, the latest entry is the first one
+func (c *ipmiClient) GetLog() ([]*redfish.LogEntry, error) {
+	info, err := c.client.GetSELInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sel info: %+v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.selInfo != nil && *c.selInfo == *info {
+		return c.selEntries, nil
+	}
+
+	records, err := c.client.GetSELEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sel entries: %+v", err)
+	}
+	c.logger.Debugf("sel entries amount: %d", len(records))
+
+	result := make([]*redfish.LogEntry, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		entry := &redfish.LogEntry{
+			EntryType:    redfish.SELLogEntryType,
+			Severity:     redfish.EventSeverity(record.Severity()),
+			Message:      record.Message(),
+			SensorNumber: int(record.SensorNumber),
+		}
+		entry.ID = strconv.Itoa(int(record.RecordID))
+		if !record.Timestamp.IsZero() {
+			entry.Created = record.Timestamp.Format(time.RFC3339)
+		}
+		result = append(result, entry)
+	}
+	c.selInfo = info
+	c.selEntries = result
+	return result, nil
+}
+
+// Power operates the chassis synchronously, so it never returns the task
+func (c *ipmiClient) Power(bootCmd string, systemId string) (string, error) {
+	return "", c.power(bootCmd, systemId)
+}
+
+func (c *ipmiClient) power(bootCmd string, systemId string) error {
+	if err := c.checkSystem(systemId); err != nil {
+		return err
+	}
+
+	var action byte
+	switch bootCmd {
+	case topohubv1beta1.BootCmdOn, topohubv1beta1.BootCmdForceOn:
+		action = ipmi.ChassisPowerUp
+	case topohubv1beta1.BootCmdForceOff:
+		action = ipmi.ChassisPowerDown
+	case topohubv1beta1.BootCmdGracefulShutdown:
+		action = ipmi.ChassisSoftShutdown
+	case topohubv1beta1.BootCmdForceRestart:
+		return c.reset()
+	case topohubv1beta1.BootCmdResetPxeOnce:
+		c.logger.Infof("pxe reboot %s", c.addr)
+		if err := c.client.SetBootDevice(ipmi.BootDevicePxe, false, false); err != nil {
+			return fmt.Errorf("failed to set boot option error:%+v", err)
+		}
+		return c.reset()
+	case topohubv1beta1.BootCmdGracefulRestart:
+		return fmt.Errorf("%w: the graceful restart over ipmi", ErrNotSupported)
+	default:
+		c.logger.Errorf("unknown boot cmd: %+v", bootCmd)
+		return fmt.Errorf("unknown boot cmd: %+v", bootCmd)
+	}
+
+	c.logger.Infof("operation %s on %s", bootCmd, c.addr)
+	if err := c.client.ChassisControl(action); err != nil {
+		return fmt.Errorf("failed to operate system %s: %+v", ipmiSystemId, err)
+	}
+	return nil
+}
+
+// reset restarts the system, or powers on the system which is off
+func (c *ipmiClient) reset() error {
+	status, err := c.client.GetChassisStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get chassis status: %+v", err)
+	}
+	action := byte(ipmi.ChassisHardReset)
+	if !status.PowerOn {
+		action = ipmi.ChassisPowerUp
+	}
+	c.logger.Infof("reset %s with chassis control %d", c.addr, action)
+	if err := c.client.ChassisControl(action); err != nil {
+		return fmt.Errorf("failed to operate system %s: %+v", ipmiSystemId, err)
+	}
+	return nil
+}
+
+// ipmiBootDevices maps the BootSourceOverrideTarget to the boot device of ipmi
+var ipmiBootDevices = map[string]byte{
+	string(redfish.NoneBootSourceOverrideTarget):      ipmi.BootDeviceNone,
+	string(redfish.PxeBootSourceOverrideTarget):       ipmi.BootDevicePxe,
+	string(redfish.HddBootSourceOverrideTarget):       ipmi.BootDeviceDisk,
+	string(redfish.CdBootSourceOverrideTarget):        ipmi.BootDeviceCdrom,
+	string(redfish.BiosSetupBootSourceOverrideTarget): ipmi.BootDeviceBiosSetup,
+}
+
+func (c *ipmiClient) SetBoot(systemId string, boot topohubv1beta1.BootSpec) (string, error) {
+	return "", c.setBoot(systemId, boot)
+}
+
+func (c *ipmiClient) setBoot(systemId string, boot topohubv1beta1.BootSpec) error {
+	if err := c.checkSystem(systemId); err != nil {
+		return err
+	}
+	if len(boot.BootOrder) > 0 {
+		return fmt.Errorf("%w: the boot order over ipmi", ErrNotSupported)
+	}
+
+	if len(boot.Target) > 0 || len(boot.OverrideEnabled) > 0 {
+		device := byte(ipmi.BootDeviceNone)
+		if boot.OverrideEnabled != string(redfish.DisabledBootSourceOverrideEnabled) && len(boot.Target) > 0 {
+			d, ok := ipmiBootDevices[boot.Target]
+			if !ok {
+				return fmt.Errorf("%w: the boot target %s over ipmi", ErrNotSupported, boot.Target)
+			}
+			device = d
+		}
+		persistent := boot.OverrideEnabled == string(redfish.ContinuousBootSourceOverrideEnabled)
+		efi := boot.BootMode == string(redfish.UEFIBootSourceOverrideMode)
+		c.logger.Infof("set boot device of %s: target %s, persistent %v, efi %v", c.addr, boot.Target, persistent, efi)
+		if err := c.client.SetBootDevice(device, persistent, efi); err != nil {
+			return fmt.Errorf("failed to set boot device: %+v", err)
+		}
+	}
+
+	if len(boot.ResetType) > 0 {
+		if err := c.power(boot.ResetType, systemId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ipmiIndicatorLED maps the chassis identify state to the state of the indicator led, it is empty when the bmc does not report it
+func ipmiIndicatorLED(status *ipmi.ChassisStatus) string {
+	switch status.IdentifyState {
+	case "Off":
+		return topohubv1beta1.IndicatorLEDOff
+	case "TemporaryOn", "IndefiniteOn":
+		return topohubv1beta1.IndicatorLEDLit
+	}
+	return ""
+}
+
+// SetIndicatorLED turns on the chassis identify until it is turned off, the identify led of ipmi does not distinguish Lit from Blinking
+func (c *ipmiClient) SetIndicatorLED(systemId string, state string) (string, error) {
+	if err := c.checkSystem(systemId); err != nil {
+		return "", err
+	}
+	status, err := c.client.GetChassisStatus()
+	if err != nil {
+		return "", fmt.Errorf("failed to get chassis status: %+v", err)
+	}
+	previous := ipmiIndicatorLED(status)
+
+	c.logger.Infof("set chassis identify of %s from %s to %s", c.addr, previous, state)
+	if err := c.client.ChassisIdentify(0, state != topohubv1beta1.IndicatorLEDOff); err != nil {
+		return "", fmt.Errorf("failed to set chassis identify: %+v", err)
+	}
+	return previous, nil
+}
+
+func (c *ipmiClient) GetSensors() ([]SensorReading, error) {
+	return nil, fmt.Errorf("%w: the sensor readings over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SimpleUpdate(imageUri string, targets []string) (string, error) {
+	return "", fmt.Errorf("%w: the firmware update over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) MultipartUpdate(imageFile string, targets []string) (string, error) {
+	return "", fmt.Errorf("%w: the firmware update over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetFirmwareVersions(targets []string) ([]topohubv1beta1.FirmwareInventory, error) {
+	return nil, fmt.Errorf("%w: the firmware inventory over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) VirtualMediaBoot(systemId string, imageUri string) (string, string, error) {
+	return "", "", fmt.Errorf("%w: the virtual media over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) EjectVirtualMedia(mediaUri string) error {
+	return fmt.Errorf("%w: the virtual media over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetBiosAttributes(systemId string) (*BiosAttributes, error) {
+	return nil, fmt.Errorf("%w: the bios attributes over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SetBiosAttributes(systemId string, attributes map[string]string, applyTime string) error {
+	return fmt.Errorf("%w: the bios attributes over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetStorage(systemId string) ([]topohubv1beta1.StorageInventory, error) {
+	return nil, fmt.Errorf("%w: the storage over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) CreateVolume(storageUri string, volume VolumeRequest, applyTime string) (string, error) {
+	return "", fmt.Errorf("%w: the volume configuration over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) DeleteVolume(volumeUri string, applyTime string) (string, error) {
+	return "", fmt.Errorf("%w: the volume configuration over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) ResetStorage(systemId string) ([]string, error) {
+	return nil, fmt.Errorf("%w: the storage reset over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SecureEraseDrives(systemId string) ([]string, error) {
+	return nil, fmt.Errorf("%w: the drive erase over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) ResetBios(systemId string) (string, error) {
+	return "", fmt.Errorf("%w: the bios reset over ipmi", ErrNotSupported)
+}
+
+// ClearLogs erases the SEL
+func (c *ipmiClient) ClearLogs(systemId string) error {
+	if err := c.checkSystem(systemId); err != nil {
+		return err
+	}
+	c.logger.Infof("clear the sel")
+	if err := c.client.ClearSEL(); err != nil {
+		return fmt.Errorf("failed to clear sel: %+v", err)
+	}
+	return nil
+}
+
+func (c *ipmiClient) GetPowerControls() ([]PowerControl, error) {
+	return nil, fmt.Errorf("%w: the power capping over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SetPowerLimit(powerUri string, limit PowerLimit) error {
+	return fmt.Errorf("%w: the power capping over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetTask(taskUri string) (*topohubv1beta1.TaskInfo, error) {
+	return nil, fmt.Errorf("%w: the task over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) EnsureEventSubscription(subscriptionUri string, destination string, destinationPrefix string, context string) (string, bool, error) {
+	return "", false, fmt.Errorf("%w: the event subscription over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) DeleteEventSubscription(subscriptionUri string) error {
+	return fmt.Errorf("%w: the event subscription over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) EnsureAccount(username, password, roleId string) (string, bool, error) {
+	return "", false, fmt.Errorf("%w: the account management over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) DeleteAccount(accountUri string) error {
+	return fmt.Errorf("%w: the account management over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SetSnmp(enabled bool) error {
+	return fmt.Errorf("%w: the snmp setting over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) EnsureSnmpTrapDestination(destinationUri string, destination SnmpTrapDestination) (string, bool, error) {
+	return "", false, fmt.Errorf("%w: the snmp trap destination over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) DeleteSnmpTrapDestination(destinationUri string) error {
+	return fmt.Errorf("%w: the snmp trap destination over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetHttpsCertificates() (string, []HttpsCertificate, error) {
+	return "", nil, fmt.Errorf("%w: the certificate management over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GenerateCSR(collectionUri string, request CertificateSigningRequest) (string, error) {
+	return "", fmt.Errorf("%w: the certificate management over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) ReplaceCertificate(certificateUri string, certificate string) error {
+	return fmt.Errorf("%w: the certificate management over ipmi", ErrNotSupported)
+}
+
+// ResetManager resets the bmc with the warm reset for GracefulRestart, and the cold reset for ForceRestart
+func (c *ipmiClient) ResetManager(systemId string, resetType string) error {
+	if err := c.checkSystem(systemId); err != nil {
+		return err
+	}
+	var cold bool
+	switch resetType {
+	case topohubv1beta1.BootCmdGracefulRestart:
+	case topohubv1beta1.BootCmdForceRestart:
+		cold = true
+	default:
+		return fmt.Errorf("%w: the reset type %s of the bmc over ipmi", ErrNotSupported, resetType)
+	}
+	c.logger.Infof("reset the bmc %s, cold: %v", c.addr, cold)
+	if err := c.client.ResetBMC(cold); err != nil {
+		return fmt.Errorf("failed to reset the bmc: %+v", err)
+	}
+	return nil
+}
+
+func (c *ipmiClient) ResetManagerToDefaults(systemId string, resetType string) error {
+	return fmt.Errorf("%w: resetting the bmc to defaults over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetManagerNetwork() (*ManagerNetwork, error) {
+	return nil, fmt.Errorf("%w: the network services of the bmc over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SetManagerNetworkProtocol(protocolUri string, setting NetworkProtocolSetting) error {
+	return fmt.Errorf("%w: the network services of the bmc over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) SetManagerEthernetInterface(interfaceUri string, setting EthernetInterfaceSetting) error {
+	return fmt.Errorf("%w: the network services of the bmc over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) GetSyslogSubscriptions() ([]SyslogSubscription, error) {
+	return nil, fmt.Errorf("%w: the syslog destination over ipmi", ErrNotSupported)
+}
+
+func (c *ipmiClient) EnsureSyslogDestination(destination SyslogDestination) (bool, error) {
+	return false, fmt.Errorf("%w: the syslog destination over ipmi", ErrNotSupported)
+}
+
+// GetCertificate returns nil, since ipmi does not use tls
+func (c *ipmiClient) GetCertificate() *topohubv1beta1.CertificateInfo {
+	return nil
+}
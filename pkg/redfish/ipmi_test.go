@@ -0,0 +1,239 @@
+package redfish_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	ipmiEmulator "github.com/infrastructure-io/topohub/pkg/ipmi/emulator"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// selRecord builds the system event record at 2024-03-01 08:30:00 UTC, the record id is set by the emulator
+func selRecord(sensorType, sensorNumber, eventType, offset byte) [16]byte {
+	return [16]byte{0, 0, 0x02, 0x08, 0x92, 0xe1, 0x65, 0x20, 0x00, 0x04, sensorType, sensorNumber, eventType, offset, 0xff, 0xff}
+}
+
+// fruData builds the FRU with the product area of the manufacturer, the name and the serial number
+func fruData(manufacturer, name, serialNumber string) []byte {
+	area := []byte{0x01, 0, 0x00}
+	for _, field := range []string{manufacturer, name, "", "", serialNumber} {
+		area = append(area, 0xc0|byte(len(field)))
+		area = append(area, field...)
+	}
+	area = append(area, 0xc1)
+	for (len(area)+1)%8 != 0 {
+		area = append(area, 0)
+	}
+	area[1] = byte((len(area) + 1) / 8)
+	area = append(area, sum(area))
+	header := []byte{0x01, 0, 0, 0, 1, 0, 0}
+	return append(append(header, sum(header)), area...)
+}
+
+// sum is the checksum which makes the sum of the bytes zero
+func sum(data []byte) byte {
+	var s byte
+	for _, b := range data {
+		s += b
+	}
+	return -s
+}
+
+var _ = Describe("IpmiClient", Label("unitest"), func() {
+	var bmc *ipmiEmulator.Server
+	var c redfish.RefishClient
+	log := zap.NewNop().Sugar()
+
+	hostConnectCon := func() hoststatusData.HostConnectCon {
+		return hoststatusData.HostConnectCon{
+			Info: &topohubv1beta1.BasicInfo{
+				Type:     topohubv1beta1.HostTypeEndpoint,
+				IpAddr:   bmc.Host(),
+				Port:     bmc.Port(),
+				Protocol: topohubv1beta1.ProtocolIPMI,
+			},
+			Username: username,
+			Password: password,
+		}
+	}
+
+	BeforeEach(func() {
+		bmc = ipmiEmulator.New()
+		bmc.SetCredential(username, password)
+		bmc.SetFRU(fruData("Acme", "Server 2U", "SN0001"))
+		DeferCleanup(func() {
+			redfish.CacheClient.Delete(bmc.Host())
+			bmc.Close()
+		})
+		var err error
+		c, err = redfish.NewClient(hostConnectCon(), log)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("gets the info of the host from the device id, the chassis status and the FRU", func() {
+		info, err := c.GetInfo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(HaveKeyWithValue("BmcFirmwareVersion", "2.15"))
+		Expect(info).To(HaveKeyWithValue("IpmiVersion", "2.0"))
+		Expect(info).To(HaveKeyWithValue("Manufacturer", "Acme"))
+		Expect(info).To(HaveKeyWithValue("Model", "Server 2U"))
+		Expect(info).To(HaveKeyWithValue("SerialNumber", "SN0001"))
+		Expect(info).To(HaveKeyWithValue("PowerState", "On"))
+		Expect(info).To(HaveKeyWithValue("PowerRestorePolicy", "LastState"))
+
+		systems, err := c.GetSystems()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(systems).To(HaveLen(1))
+		Expect(systems[0].Id).To(Equal("1"))
+		Expect(systems[0].Health).To(Equal("OK"))
+		Expect(systems[0].IndicatorLED).To(Equal(topohubv1beta1.IndicatorLEDOff))
+
+		// the FRU is read once for the session
+		reads := bmc.CountCommands(ipmiEmulator.NetFnStorage, ipmiEmulator.CmdReadFRUData)
+		_, err = c.GetInventory()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.CountCommands(ipmiEmulator.NetFnStorage, ipmiEmulator.CmdReadFRUData)).To(Equal(reads))
+	})
+
+	It("reports the other information when the bmc has no FRU", func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		bmc.SetFRU(nil)
+		bmc.SetChassis(ipmiEmulator.Chassis{CoolingFault: true})
+		c, err := redfish.NewClient(hostConnectCon(), log)
+		Expect(err).NotTo(HaveOccurred())
+
+		systems, err := c.GetSystems()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(systems[0].SerialNumber).To(BeEmpty())
+		Expect(systems[0].PowerState).To(Equal("Off"))
+		Expect(systems[0].Health).To(Equal("Warning"))
+		Expect(systems[0].IndicatorLED).To(BeEmpty())
+	})
+
+	It("reuses the session of the cached client", func() {
+		_, err := redfish.NewClient(hostConnectCon(), log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.Sessions()).To(Equal(1))
+
+		redfish.CacheClient.Delete(bmc.Host())
+		Expect(bmc.Sessions()).To(BeZero())
+	})
+
+	It("fails to connect with the wrong password", func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		hostCon := hostConnectCon()
+		hostCon.Password = "wrong"
+		_, err := redfish.NewClient(hostCon, log)
+		Expect(err).To(MatchError(ContainSubstring("the password is wrong")))
+	})
+
+	It("converts the SEL to the log entries from the latest", func() {
+		bmc.AddSEL(selRecord(0x12, 0x01, 0x6f, 0x05), selRecord(0x0c, 0x31, 0x6f, 0x01))
+		entries, err := c.GetLog()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].ID).To(Equal("2"))
+		Expect(string(entries[0].Severity)).To(Equal("Critical"))
+		Expect(entries[0].Message).To(Equal("Memory #0x31 | Uncorrectable ECC | Asserted"))
+		Expect(entries[0].SensorNumber).To(Equal(0x31))
+		Expect(entries[0].Created).To(Equal("2024-03-01T08:30:00Z"))
+		Expect(entries[1].ID).To(Equal("1"))
+		Expect(string(entries[1].Severity)).To(Equal("OK"))
+
+		// the SEL is read again only after it is changed
+		reads := bmc.CountCommands(ipmiEmulator.NetFnStorage, ipmiEmulator.CmdGetSELEntry)
+		_, err = c.GetLog()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.CountCommands(ipmiEmulator.NetFnStorage, ipmiEmulator.CmdGetSELEntry)).To(Equal(reads))
+
+		Expect(c.ClearLogs("")).To(Succeed())
+		entries, err = c.GetLog()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	DescribeTable("operates the power of the system",
+		func(powerOn bool, bootCmd string, expectedPowerOn bool, bootFlags []byte) {
+			bmc.SetChassis(ipmiEmulator.Chassis{PowerOn: powerOn})
+			taskUri, err := c.Power(bootCmd, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskUri).To(BeEmpty())
+			Expect(bmc.Chassis().PowerOn).To(Equal(expectedPowerOn))
+			Expect(bmc.BootFlags()).To(Equal(bootFlags))
+		},
+		Entry("on", false, topohubv1beta1.BootCmdOn, true, []byte{}),
+		Entry("force off", true, topohubv1beta1.BootCmdForceOff, false, []byte{}),
+		Entry("graceful shutdown", true, topohubv1beta1.BootCmdGracefulShutdown, false, []byte{}),
+		Entry("force restart", true, topohubv1beta1.BootCmdForceRestart, true, []byte{}),
+		Entry("force restart powers on the system which is off", false, topohubv1beta1.BootCmdForceRestart, true, []byte{}),
+		Entry("pxe reboot", true, topohubv1beta1.BootCmdResetPxeOnce, true, []byte{0x80, 0x04, 0, 0, 0}),
+	)
+
+	It("refuses the operations which ipmi does not support", func() {
+		_, err := c.Power(topohubv1beta1.BootCmdGracefulRestart, "")
+		Expect(err).To(MatchError(redfish.ErrNotSupported))
+		_, err = c.SetBoot("", topohubv1beta1.BootSpec{BootOrder: []string{"Pxe"}})
+		Expect(err).To(MatchError(redfish.ErrNotSupported))
+		_, err = c.SetBoot("", topohubv1beta1.BootSpec{Target: "UefiHttp"})
+		Expect(err).To(MatchError(redfish.ErrNotSupported))
+		_, _, err = c.VirtualMediaBoot("", "http://example.com/boot.iso")
+		Expect(err).To(MatchError(redfish.ErrNotSupported))
+		_, err = c.GetBiosAttributes("")
+		Expect(err).To(MatchError(redfish.ErrNotSupported))
+		Expect(c.ResetManager("", topohubv1beta1.BootCmdForceOff)).To(MatchError(redfish.ErrNotSupported))
+		Expect(c.GetCertificate()).To(BeNil())
+
+		_, err = c.Power(topohubv1beta1.BootCmdOn, "2")
+		Expect(err).To(MatchError(ContainSubstring("system 2 is not found")))
+	})
+
+	It("sets the boot device and resets the system", func() {
+		_, err := c.SetBoot("", topohubv1beta1.BootSpec{
+			Target:          "Cd",
+			OverrideEnabled: "Continuous",
+			BootMode:        "UEFI",
+			ResetType:       topohubv1beta1.BootCmdForceRestart,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.BootFlags()).To(Equal([]byte{0xe0, 0x14, 0, 0, 0}))
+		Expect(bmc.CountCommands(ipmiEmulator.NetFnChassis, ipmiEmulator.CmdChassisControl)).To(Equal(1))
+
+		// the override is disabled with the boot device none
+		_, err = c.SetBoot("", topohubv1beta1.BootSpec{Target: "Cd", OverrideEnabled: "Disabled"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.BootFlags()).To(Equal([]byte{0x80, 0x00, 0, 0, 0}))
+	})
+
+	It("sets the indicator led with the chassis identify", func() {
+		previous, err := c.SetIndicatorLED("", topohubv1beta1.IndicatorLEDLit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(previous).To(Equal(topohubv1beta1.IndicatorLEDOff))
+		Expect(bmc.Chassis().Identify).To(Equal(ipmiEmulator.IdentifyIndefiniteOn))
+
+		previous, err = c.SetIndicatorLED("", topohubv1beta1.IndicatorLEDOff)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(previous).To(Equal(topohubv1beta1.IndicatorLEDLit))
+		Expect(bmc.Chassis().Identify).To(Equal(ipmiEmulator.IdentifyOff))
+	})
+
+	It("resets the bmc with the warm or the cold reset", func() {
+		Expect(c.ResetManager("", topohubv1beta1.BootCmdGracefulRestart)).To(Succeed())
+		Expect(c.ResetManager("", topohubv1beta1.BootCmdForceRestart)).To(Succeed())
+		Expect(bmc.CountCommands(ipmiEmulator.NetFnApp, ipmiEmulator.CmdWarmReset)).To(Equal(1))
+		Expect(bmc.CountCommands(ipmiEmulator.NetFnApp, ipmiEmulator.CmdColdReset)).To(Equal(1))
+	})
+
+	It("runs the function with a session which is not cached", func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		err := redfish.ConnectOnce(hostConnectCon(), log, func(c redfish.RefishClient) error {
+			Expect(bmc.Sessions()).To(Equal(1))
+			_, err := c.GetInfo()
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bmc.Sessions()).To(BeZero())
+	})
+})
@@ -2,6 +2,7 @@ package redfish
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/stmcginnis/gofish/redfish"
 )
@@ -24,22 +25,40 @@ func (c *redfishClient) GetLog() ([]*redfish.LogEntry, error) {
 		return nil, fmt.Errorf("failed to get system")
 	}
 	c.logger.Debugf("system amount: %d", len(ss))
-	// for n, t := range ss {
-	// 	c.logger.Debugf("systems[%d]: %+v", n, *t)
-	// }
 
-	// for barel metal case,
-	system := ss[0]
+	// collect the logs of every system of a multi-node host
+	for _, system := range ss {
+		entries, err := c.getSystemLog(system)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+	}
 
-	ls, err := system.LogServices()
+	if len(ss) > 1 {
+		// the latest log entry should be the first one
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Created > result[j].Created
+		})
+	}
+
+	return result, nil
+
+}
+
+func (c *redfishClient) getSystemLog(system *redfish.ComputerSystem) ([]*redfish.LogEntry, error) {
+
+	result := []*redfish.LogEntry{}
+
+	ls, err := c.vendor().LogServices(c, system)
 	if err != nil {
-		c.logger.Errorf("failed to Query the log services: %+v", err)
+		c.logger.Errorf("failed to Query the log services of system %s: %+v", system.ID, err)
 		return nil, err
 	} else if len(ls) == 0 {
-		c.logger.Errorf("failed to get log service")
+		c.logger.Errorf("failed to get log service of system %s", system.ID)
 		return nil, nil
 	}
-	c.logger.Debugf("log service amount: %d", len(ls))
+	c.logger.Debugf("log service amount of system %s: %d", system.ID, len(ls))
 	for _, t := range ls {
 		if t.Status.State != "Enabled" {
 			c.logger.Debugf("log service %s is disabled", t.Name)
@@ -57,5 +76,49 @@ func (c *redfishClient) GetLog() ([]*redfish.LogEntry, error) {
 	}
 
 	return result, nil
+}
 
+// ClearLogs clears the log services of the system and its managers with LogService.ClearLog
+func (c *redfishClient) ClearLogs(systemId string) error {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return err
+	}
+	ls, err := system.LogServices()
+	if err != nil {
+		return fmt.Errorf("failed to get log services of system %s: %+v", system.ID, err)
+	}
+	managers, err := system.ManagedBy()
+	if err != nil {
+		return fmt.Errorf("failed to get managers of system %s: %+v", system.ID, err)
+	}
+	for _, m := range managers {
+		mls, err := m.LogServices()
+		if err != nil {
+			c.logger.Debugf("failed to get log services of manager %s: %+v", m.ID, err)
+			continue
+		}
+		ls = append(ls, mls...)
+	}
+
+	cleared := 0
+	for _, t := range ls {
+		target, err := c.getActionTarget(t.ODataID, "#LogService.ClearLog")
+		if err != nil {
+			return fmt.Errorf("failed to get log service %s: %+v", t.ODataID, err)
+		}
+		if len(target) == 0 {
+			c.logger.Debugf("log service %s does not support ClearLog", t.ODataID)
+			continue
+		}
+		c.logger.Infof("clear log service %s", t.ODataID)
+		if err := t.ClearLog(); err != nil {
+			return fmt.Errorf("failed to clear log service %s: %+v", t.ODataID, err)
+		}
+		cleared++
+	}
+	if cleared == 0 {
+		return fmt.Errorf("%w: no log service of system %s supports ClearLog", ErrNotSupported, system.ID)
+	}
+	return nil
 }
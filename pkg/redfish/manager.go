@@ -0,0 +1,61 @@
+package redfish
+
+import (
+	"fmt"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// systemManager returns the manager which manages the system, or the first manager when the system does not report it
+func (c *redfishClient) systemManager(systemId string) (*redfish.Manager, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return nil, err
+	}
+	managers, err := system.ManagedBy()
+	if err == nil && len(managers) > 0 {
+		return managers[0], nil
+	}
+	managers, err = c.client.Service.Managers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managers: %+v", err)
+	}
+	if len(managers) == 0 {
+		return nil, fmt.Errorf("no manager found")
+	}
+	return managers[0], nil
+}
+
+// ResetManager restarts the bmc which manages the system. The bmc drops the sessions and stops responding until it
+// boots again, so the cached client is invalidated by the caller
+func (c *redfishClient) ResetManager(systemId string, resetType string) error {
+	manager, err := c.systemManager(systemId)
+	if err != nil {
+		return err
+	}
+	if len(actionTarget(manager.RawData, "#Manager.Reset")) == 0 {
+		return fmt.Errorf("%w: manager %s does not support the Reset action", ErrNotSupported, manager.ID)
+	}
+	c.logger.Infof("reset manager %s with %s", manager.ID, resetType)
+	if err := manager.Reset(redfish.ResetType(resetType)); err != nil {
+		return fmt.Errorf("failed to reset manager %s: %+v", manager.ID, err)
+	}
+	return nil
+}
+
+// ResetManagerToDefaults restores the factory settings of the bmc which manages the system, the bmc restarts after that.
+// The settings which are preserved depend on the reset type, such as PreserveNetworkAndUsers
+func (c *redfishClient) ResetManagerToDefaults(systemId string, resetType string) error {
+	manager, err := c.systemManager(systemId)
+	if err != nil {
+		return err
+	}
+	if len(actionTarget(manager.RawData, "#Manager.ResetToDefaults")) == 0 {
+		return fmt.Errorf("%w: manager %s does not support the ResetToDefaults action", ErrNotSupported, manager.ID)
+	}
+	c.logger.Infof("reset manager %s to defaults with %s", manager.ID, resetType)
+	if err := manager.ResetToDefaults(redfish.ResetToDefaultsType(resetType)); err != nil {
+		return fmt.Errorf("failed to reset manager %s to defaults: %+v", manager.ID, err)
+	}
+	return nil
+}
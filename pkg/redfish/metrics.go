@@ -0,0 +1,31 @@
+package redfish
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/infrastructure-io/topohub/pkg/metrics"
+)
+
+// metricsTransport records the latency and the errors of the redfish requests to the bmc
+type metricsTransport struct {
+	ip   string
+	next http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	metrics.RedfishRequestDuration.WithLabelValues(t.ip).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		metrics.RedfishRequestErrorsTotal.WithLabelValues(t.ip).Inc()
+	}
+	return resp, err
+}
+
+// CloseIdleConnections is called by the http client when the client logs out
+func (t *metricsTransport) CloseIdleConnections() {
+	if c, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
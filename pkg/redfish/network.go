@@ -0,0 +1,107 @@
+package redfish
+
+import (
+	"sort"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// getNetworkAdapterInventory collects the network adapters of the chassis with their ports.
+// The Ports of the adapter report the LLDP neighbor, and the NetworkPorts are read for the old BMC which does not implement the Ports
+func (c *redfishClient) getNetworkAdapterInventory(cs []*redfish.Chassis) []topohubv1beta1.NetworkAdapterInventory {
+	result := []topohubv1beta1.NetworkAdapterInventory{}
+	for _, chassis := range cs {
+		adapters, err := chassis.NetworkAdapters()
+		if err != nil {
+			c.logger.Warnf("failed to get network adapters of chassis %s: %+v", chassis.ID, err)
+			continue
+		}
+		for _, adapter := range adapters {
+			item := topohubv1beta1.NetworkAdapterInventory{
+				ODataId:      adapter.ODataID,
+				Name:         adapter.Name,
+				Manufacturer: adapter.Manufacturer,
+				Model:        adapter.Model,
+				PartNumber:   adapter.PartNumber,
+				SerialNumber: adapter.SerialNumber,
+				Health:       string(adapter.Status.Health),
+				State:        string(adapter.Status.State),
+			}
+			for _, controller := range adapter.Controllers {
+				if len(controller.FirmwarePackageVersion) > 0 {
+					item.FirmwareVersion = controller.FirmwarePackageVersion
+					break
+				}
+			}
+
+			ports, err := adapter.Ports()
+			if err != nil {
+				c.logger.Debugf("failed to get ports of network adapter %s: %+v", adapter.ODataID, err)
+			}
+			for _, port := range ports {
+				item.Ports = append(item.Ports, portInventory(port))
+			}
+			if len(ports) == 0 {
+				networkPorts, err := adapter.NetworkPorts()
+				if err != nil {
+					c.logger.Debugf("failed to get network ports of network adapter %s: %+v", adapter.ODataID, err)
+				}
+				for _, port := range networkPorts {
+					item.Ports = append(item.Ports, networkPortInventory(port))
+				}
+			}
+			sort.Slice(item.Ports, func(i, j int) bool { return item.Ports[i].ODataId < item.Ports[j].ODataId })
+			result = append(result, item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ODataId < result[j].ODataId })
+	return result
+}
+
+func portInventory(port *redfish.Port) topohubv1beta1.NetworkPortInventory {
+	result := topohubv1beta1.NetworkPortInventory{
+		ODataId:      port.ODataID,
+		PortId:       port.PortID,
+		LinkStatus:   string(port.LinkStatus),
+		SpeedMbps:    int32(port.CurrentSpeedGbps * 1000),
+		MACAddresses: port.Ethernet.AssociatedMACAddresses,
+		Health:       string(port.Status.Health),
+		State:        string(port.Status.State),
+	}
+	lldp := port.Ethernet.LLDPReceive
+	if len(lldp.ChassisID) > 0 || len(lldp.PortID) > 0 || len(lldp.SystemName) > 0 {
+		result.LLDP = &topohubv1beta1.LLDPNeighbor{
+			ChassisId:             lldp.ChassisID,
+			ChassisIdSubtype:      string(lldp.ChassisIDSubtype),
+			PortId:                lldp.PortID,
+			PortIdSubtype:         string(lldp.PortIDSubtype),
+			SystemName:            lldp.SystemName,
+			SystemDescription:     lldp.SystemDescription,
+			ManagementAddressIPv4: lldp.ManagementAddressIPv4,
+			ManagementAddressIPv6: lldp.ManagementAddressIPv6,
+			ManagementVlanId:      int32(lldp.ManagementVlanID),
+		}
+	}
+	return result
+}
+
+// networkPortInventory converts the deprecated NetworkPort, whose link status is Up or Down
+func networkPortInventory(port *redfish.NetworkPort) topohubv1beta1.NetworkPortInventory {
+	linkStatus := string(port.LinkStatus)
+	switch port.LinkStatus {
+	case redfish.UpPortLinkStatus:
+		linkStatus = string(redfish.LinkUpPortLinkStatus)
+	case redfish.DownPortLinkStatus:
+		linkStatus = string(redfish.LinkDownPortLinkStatus)
+	}
+	return topohubv1beta1.NetworkPortInventory{
+		ODataId:      port.ODataID,
+		PortId:       port.PhysicalPortNumber,
+		LinkStatus:   linkStatus,
+		SpeedMbps:    int32(port.CurrentLinkSpeedMbps),
+		MACAddresses: port.AssociatedNetworkAddresses,
+		Health:       string(port.Status.Health),
+		State:        string(port.Status.State),
+	}
+}
@@ -0,0 +1,266 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// the protocols of the ManagerNetworkProtocol which could be enabled or disabled
+const (
+	NetworkProtocolIPMI   = "IPMI"
+	NetworkProtocolSSH    = "SSH"
+	NetworkProtocolSNMP   = "SNMP"
+	NetworkProtocolTelnet = "Telnet"
+)
+
+var networkProtocols = []string{NetworkProtocolIPMI, NetworkProtocolSSH, NetworkProtocolSNMP, NetworkProtocolTelnet}
+
+// ManagerNetwork is the network services of the bmc
+type ManagerNetwork struct {
+	ProtocolUri string
+	// Protocols is the state of the protocols reported by the bmc, such as IPMI and SSH
+	Protocols  map[string]bool
+	NTPEnabled bool
+	NTPServers []string
+
+	// InterfaceUri is the ethernet interface of the manager which holds the ip address of the bmc, it is empty when the
+	// manager does not report the ethernet interfaces
+	InterfaceUri      string
+	StaticNameServers []string
+	DHCPUseNTPServers bool
+	DHCPUseDNSServers bool
+}
+
+// NetworkProtocolSetting is the change of the ManagerNetworkProtocol, the NTP is left unchanged when NTPServers is nil
+type NetworkProtocolSetting struct {
+	Protocols  map[string]bool
+	NTPServers []string
+}
+
+// EthernetInterfaceSetting is the change of the ethernet interface of the manager, the setting which is nil is left unchanged
+type EthernetInterfaceSetting struct {
+	StaticNameServers []string
+	DHCPUseNTPServers *bool
+	DHCPUseDNSServers *bool
+}
+
+// SyslogDestination is the syslog server which the bmc forwards the logs to
+type SyslogDestination struct {
+	Address string
+	Port    int
+	// Protocol is SyslogUDP, SyslogTCP or SyslogTLS
+	Protocol string
+}
+
+// Url returns the destination in the form of the EventDestination
+func (d SyslogDestination) Url() string {
+	return "syslog://" + net.JoinHostPort(d.Address, strconv.Itoa(d.Port))
+}
+
+// SyslogSubscription is the EventDestination with the Syslog subscription type
+type SyslogSubscription struct {
+	Uri         string
+	Destination string
+	Protocol    string
+}
+
+// GetManagerNetwork reads the network protocols of the manager and its ethernet interface
+func (c *redfishClient) GetManagerNetwork() (*ManagerNetwork, error) {
+	_, manager, err := c.primarySystem()
+	if err != nil {
+		return nil, err
+	}
+	protocol, err := manager.NetworkProtocol()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network protocol of manager %s: %+v", manager.ID, err)
+	}
+	result := &ManagerNetwork{
+		ProtocolUri: protocol.ODataID,
+		Protocols:   map[string]bool{},
+		NTPEnabled:  protocol.NTP.ProtocolEnabled,
+		NTPServers:  nonEmpty(protocol.NTP.NTPServers),
+	}
+
+	// the protocol which the bmc does not support is absent, which could not be told by gofish
+	raw := map[string]json.RawMessage{}
+	if err := c.getJson(protocol.ODataID, &raw); err != nil {
+		return nil, fmt.Errorf("failed to get network protocol of manager %s: %+v", manager.ID, err)
+	}
+	for _, name := range networkProtocols {
+		var state struct {
+			ProtocolEnabled *bool
+		}
+		if data, ok := raw[name]; ok && json.Unmarshal(data, &state) == nil && state.ProtocolEnabled != nil {
+			result.Protocols[name] = *state.ProtocolEnabled
+		}
+	}
+
+	interfaces, err := manager.EthernetInterfaces()
+	if err != nil {
+		c.logger.Debugf("failed to get ethernet interfaces of manager %s: %+v", manager.ID, err)
+		return result, nil
+	}
+	if item := c.bmcInterface(interfaces); item != nil {
+		result.InterfaceUri = item.ODataID
+		result.StaticNameServers = nonEmpty(item.StaticNameServers)
+		result.DHCPUseNTPServers = item.DHCPv4.UseNTPServers
+		result.DHCPUseDNSServers = item.DHCPv4.UseDNSServers
+	}
+	return result, nil
+}
+
+// bmcInterface returns the ethernet interface which holds the ip address of the bmc, or the only interface of the manager
+func (c *redfishClient) bmcInterface(interfaces []*redfish.EthernetInterface) *redfish.EthernetInterface {
+	if u, err := url.Parse(c.config.Endpoint); err == nil {
+		for _, item := range interfaces {
+			for _, address := range item.IPv4Addresses {
+				if address.Address == u.Hostname() {
+					return item
+				}
+			}
+		}
+	}
+	if len(interfaces) == 1 {
+		return interfaces[0]
+	}
+	return nil
+}
+
+// SetManagerNetworkProtocol enables or disables the protocols, and sets the NTP servers
+func (c *redfishClient) SetManagerNetworkProtocol(protocolUri string, setting NetworkProtocolSetting) error {
+	body := map[string]interface{}{}
+	for name, enabled := range setting.Protocols {
+		body[name] = map[string]interface{}{
+			"ProtocolEnabled": enabled,
+		}
+	}
+	if setting.NTPServers != nil {
+		body["NTP"] = map[string]interface{}{
+			"ProtocolEnabled": true,
+			"NTPServers":      setting.NTPServers,
+		}
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	resp, err := c.client.Patch(protocolUri, body)
+	if err != nil {
+		return fmt.Errorf("failed to set network protocol %s: %+v", protocolUri, err)
+	}
+	resp.Body.Close()
+	c.logger.Infof("set the network protocol %s: %+v", protocolUri, setting)
+	return nil
+}
+
+// SetManagerEthernetInterface sets the static DNS servers of the ethernet interface of the manager, and whether to use
+// the NTP and DNS servers from the dhcp server
+func (c *redfishClient) SetManagerEthernetInterface(interfaceUri string, setting EthernetInterfaceSetting) error {
+	body := map[string]interface{}{}
+	if setting.StaticNameServers != nil {
+		body["StaticNameServers"] = setting.StaticNameServers
+	}
+	dhcp := map[string]interface{}{}
+	if setting.DHCPUseNTPServers != nil {
+		dhcp["UseNTPServers"] = *setting.DHCPUseNTPServers
+	}
+	if setting.DHCPUseDNSServers != nil {
+		dhcp["UseDNSServers"] = *setting.DHCPUseDNSServers
+	}
+	if len(dhcp) > 0 {
+		body["DHCPv4"] = dhcp
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	resp, err := c.client.Patch(interfaceUri, body)
+	if err != nil {
+		return fmt.Errorf("failed to set ethernet interface %s: %+v", interfaceUri, err)
+	}
+	resp.Body.Close()
+	c.logger.Infof("set the ethernet interface %s: %+v", interfaceUri, body)
+	return nil
+}
+
+// GetSyslogSubscriptions lists the EventDestination with the Syslog subscription type, which is introduced in Redfish 2023.1
+func (c *redfishClient) GetSyslogSubscriptions() ([]SyslogSubscription, error) {
+	eventService, err := c.client.Service.EventService()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event service: %+v", err)
+	}
+	subscriptions, err := eventService.GetEventSubscriptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event subscriptions: %+v", err)
+	}
+	result := []SyslogSubscription{}
+	for _, item := range subscriptions {
+		if item.SubscriptionType != redfish.SyslogSubscriptionType {
+			continue
+		}
+		result = append(result, SyslogSubscription{
+			Uri:         item.ODataID,
+			Destination: item.Destination,
+			Protocol:    string(item.Protocol),
+		})
+	}
+	return result, nil
+}
+
+// EnsureSyslogDestination configures the bmc to forward the logs to the destination only, the other syslog subscriptions
+// are removed. It returns true when the subscription is created
+func (c *redfishClient) EnsureSyslogDestination(destination SyslogDestination) (bool, error) {
+	subscriptions, err := c.GetSyslogSubscriptions()
+	if err != nil {
+		return false, err
+	}
+	target := destination.Url()
+	found := false
+	for _, item := range subscriptions {
+		if !found && item.Destination == target && item.Protocol == destination.Protocol {
+			found = true
+			continue
+		}
+		c.logger.Infof("delete the syslog subscription %s to %s", item.Uri, item.Destination)
+		resp, err := c.client.Delete(item.Uri)
+		if err != nil {
+			return false, fmt.Errorf("failed to delete syslog subscription %s: %+v", item.Uri, err)
+		}
+		resp.Body.Close()
+	}
+	if found {
+		return false, nil
+	}
+
+	eventService, err := c.client.Service.EventService()
+	if err != nil {
+		return false, fmt.Errorf("failed to get event service: %+v", err)
+	}
+	// gofish does not support the Syslog subscription type, so post it directly
+	body := map[string]interface{}{
+		"Destination":      target,
+		"SubscriptionType": redfish.SyslogSubscriptionType,
+		"Protocol":         destination.Protocol,
+	}
+	resp, err := c.client.Post(eventService.Subscriptions, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to create syslog subscription to %s: %+v", target, err)
+	}
+	resp.Body.Close()
+	c.logger.Infof("configured the syslog destination %s with %s", target, destination.Protocol)
+	return true, nil
+}
+
+// nonEmpty drops the empty items, the bmc pads the list of the servers with the empty strings
+func nonEmpty(items []string) []string {
+	result := []string{}
+	for _, item := range items {
+		if len(item) > 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
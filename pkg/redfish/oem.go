@@ -0,0 +1,148 @@
+package redfish
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// vendorAdapter overrides the calls which differ between the bmc vendors, such as the OEM sections of iDRAC, iLO and OpenBMC.
+// The dmtfAdapter implements the standard behavior, and a vendor adapter embeds it to override part of the calls.
+// The other vendors, such as the XClarity of Lenovo, Supermicro and the iBMC of Huawei, use the dmtfAdapter, since the
+// calls work with their standard resources. To support a new vendor, add an oem_<vendor>.go which registers the adapter in init()
+type vendorAdapter interface {
+	Name() string
+	// GetInfo adds the vendor specific information of the system to the result of GetInfo
+	GetInfo(c *redfishClient, system *redfish.ComputerSystem, result map[string]string)
+	// ResetType converts the power command to the reset type which the system supports
+	ResetType(system *redfish.ComputerSystem, bootCmd string) redfish.ResetType
+	// SetBootOverride patches the boot property of the system
+	SetBootOverride(c *redfishClient, system *redfish.ComputerSystem, boot redfish.Boot) error
+	// LogServices returns the log services to read the logs of the system
+	LogServices(c *redfishClient, system *redfish.ComputerSystem) ([]*redfish.LogService, error)
+	// SetSnmp enables or disables the snmp agent of the bmc
+	SetSnmp(c *redfishClient, manager *redfish.Manager, enabled bool) error
+	// EnsureSnmpTrap configures the snmp trap destination of the bmc, it returns the uri of the destination and whether it is created
+	EnsureSnmpTrap(c *redfishClient, manager *redfish.Manager, destinationUri string, destination SnmpTrapDestination) (string, bool, error)
+	DeleteSnmpTrap(c *redfishClient, manager *redfish.Manager, destinationUri string) error
+}
+
+type adapterEntry struct {
+	// keywords are matched with the whole words of the Vendor of the service root or the Manufacturer of the system, so
+	// the keyword "hp" matches "HP" but not "Chips"
+	keywords []string
+	adapter  vendorAdapter
+}
+
+var adapters []adapterEntry
+
+// registerAdapter is called in init(), so the registry is not modified after the start
+func registerAdapter(adapter vendorAdapter, keywords ...string) {
+	for i := range keywords {
+		keywords[i] = words(keywords[i])
+	}
+	adapters = append(adapters, adapterEntry{keywords: keywords, adapter: adapter})
+}
+
+// selectAdapter returns the adapter of the vendor, or the dmtfAdapter for the unknown vendor
+func selectAdapter(vendor, manufacturer string) vendorAdapter {
+	for _, name := range []string{vendor, manufacturer} {
+		name = words(name)
+		if len(name) == 0 {
+			continue
+		}
+		for _, entry := range adapters {
+			for _, keyword := range entry.keywords {
+				if strings.Contains(" "+name+" ", " "+keyword+" ") {
+					return entry.adapter
+				}
+			}
+		}
+	}
+	return dmtfAdapter{}
+}
+
+// words returns the lower case words of the name separated by a space, such as "hewlett packard enterprise" for
+// "Hewlett-Packard Enterprise"
+func words(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// vendor returns the adapter of the bmc, it is decided once for the client
+func (c *redfishClient) vendor() vendorAdapter {
+	c.adapterOnce.Do(func() {
+		vendor := c.client.Service.Vendor
+		manufacturer := ""
+		// the Vendor of the service root is introduced in Redfish 1.5, so check the manufacturer of the system for the old bmc
+		if ss, err := c.client.Service.Systems(); err == nil && len(ss) > 0 {
+			manufacturer = ss[0].Manufacturer
+		}
+		c.adapter = selectAdapter(vendor, manufacturer)
+		c.logger.Debugf("use the %s adapter for the bmc, vendor %q, manufacturer %q", c.adapter.Name(), vendor, manufacturer)
+	})
+	return c.adapter
+}
+
+// dmtfAdapter implements the calls according to the DMTF Redfish specification
+type dmtfAdapter struct{}
+
+var _ vendorAdapter = dmtfAdapter{}
+
+func (dmtfAdapter) Name() string {
+	return "dmtf"
+}
+
+func (dmtfAdapter) GetInfo(c *redfishClient, system *redfish.ComputerSystem, result map[string]string) {
+}
+
+func (dmtfAdapter) ResetType(system *redfish.ComputerSystem, bootCmd string) redfish.ResetType {
+	resetType := redfish.ResetType(bootCmd)
+	// some bmc only implements On to power on the system
+	if resetType == redfish.ForceOnResetType && !supportResetType(system, resetType) && supportResetType(system, redfish.OnResetType) {
+		return redfish.OnResetType
+	}
+	return resetType
+}
+
+func (dmtfAdapter) SetBootOverride(c *redfishClient, system *redfish.ComputerSystem, boot redfish.Boot) error {
+	return system.SetBoot(boot)
+}
+
+func (dmtfAdapter) LogServices(c *redfishClient, system *redfish.ComputerSystem) ([]*redfish.LogService, error) {
+	return system.LogServices()
+}
+
+func (dmtfAdapter) SetSnmp(c *redfishClient, manager *redfish.Manager, enabled bool) error {
+	protocol, err := manager.NetworkProtocol()
+	if err != nil {
+		return fmt.Errorf("failed to get network protocol of manager %s: %+v", manager.ID, err)
+	}
+	body := map[string]interface{}{
+		"SNMP": map[string]interface{}{
+			"ProtocolEnabled": enabled,
+		},
+	}
+	resp, err := c.client.Patch(protocol.ODataID, body)
+	if err != nil {
+		return fmt.Errorf("failed to set snmp of manager %s: %+v", manager.ID, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// supportResetType returns true when the system supports the reset type, or it does not report the supported types
+func supportResetType(system *redfish.ComputerSystem, resetType redfish.ResetType) bool {
+	if len(system.SupportedResetTypes) == 0 {
+		return true
+	}
+	for _, t := range system.SupportedResetTypes {
+		if t == resetType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// dellAdapter is for the iDRAC of Dell
+type dellAdapter struct {
+	dmtfAdapter
+}
+
+func init() {
+	registerAdapter(dellAdapter{}, "dell")
+}
+
+func (dellAdapter) Name() string {
+	return "dell"
+}
+
+func (dellAdapter) GetInfo(c *redfishClient, system *redfish.ComputerSystem, result map[string]string) {
+	// the SKU of the Dell system is the service tag
+	setData(result, "ServiceTag", system.SKU)
+
+	var t struct {
+		Oem struct {
+			Dell struct {
+				DellSystem struct {
+					SystemGeneration string
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(system.RawData, &t); err != nil {
+		c.logger.Debugf("failed to decode the oem of system %s: %+v", system.ID, err)
+		return
+	}
+	if len(t.Oem.Dell.DellSystem.SystemGeneration) > 0 {
+		setData(result, "SystemGeneration", t.Oem.Dell.DellSystem.SystemGeneration)
+	}
+}
+
+// LogServices reads the Sel of the iDRAC, when the system does not have an enabled log service, such as the iDRAC 8
+func (a dellAdapter) LogServices(c *redfishClient, system *redfish.ComputerSystem) ([]*redfish.LogService, error) {
+	ls, err := a.dmtfAdapter.LogServices(c, system)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range ls {
+		if t.Status.State == "Enabled" {
+			return ls, nil
+		}
+	}
+
+	managers, err := system.ManagedBy()
+	if err != nil {
+		return nil, err
+	}
+	result := []*redfish.LogService{}
+	for _, m := range managers {
+		mls, err := m.LogServices()
+		if err != nil {
+			c.logger.Debugf("failed to get log services of manager %s: %+v", m.ID, err)
+			continue
+		}
+		// the Lclog records the lifecycle of the configuration jobs, which is too noisy
+		for _, t := range mls {
+			if t.ID == "Sel" {
+				result = append(result, t)
+			}
+		}
+	}
+	return result, nil
+}
+
+// SetSnmp sets the snmp agent with the attributes of the iDRAC
+func (dellAdapter) SetSnmp(c *redfishClient, manager *redfish.Manager, enabled bool) error {
+	value := "Disabled"
+	if enabled {
+		value = "Enabled"
+	}
+	body := map[string]interface{}{
+		"Attributes": map[string]interface{}{
+			"SNMP.1.AgentEnable": value,
+		},
+	}
+	resp, err := c.client.Patch(manager.ODataID+"/Attributes", body)
+	if err != nil {
+		return fmt.Errorf("failed to set snmp attributes of iDRAC %s: %+v", manager.ID, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+const (
+	// the iDRAC has 8 snmp alert destinations, which are the attributes SNMPAlert.1 to SNMPAlert.8
+	idracSnmpAlertDestinations = 8
+)
+
+// EnsureSnmpTrap configures an SNMPAlert destination of the iDRAC, the uri is the name of the destination such as SNMPAlert.1
+func (dellAdapter) EnsureSnmpTrap(c *redfishClient, manager *redfish.Manager, destinationUri string, destination SnmpTrapDestination) (string, bool, error) {
+	var t struct {
+		Attributes map[string]interface{}
+	}
+	if err := c.getJson(manager.ODataID+"/Attributes", &t); err != nil {
+		return "", false, fmt.Errorf("failed to get attributes of iDRAC %s: %+v", manager.ID, err)
+	}
+	attr := func(name string) string {
+		if v, ok := t.Attributes[name]; ok && v != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return ""
+	}
+	settings := map[string]interface{}{
+		"SNMP.1.TrapFormat":     "SNMPv2",
+		"SNMP.1.AlertPort":      destination.Port,
+		"SNMP.1.AgentCommunity": destination.Community,
+		// the global switch of the alerts
+		"IPMILan.1.AlertEnable": "Enabled",
+	}
+
+	if len(destinationUri) > 0 && attr(destinationUri+".Destination") == destination.Address && attr(destinationUri+".State") == "Enabled" {
+		changed := false
+		for k, v := range settings {
+			// the community may be masked by the iDRAC, it is only set when the destination is configured
+			if k == "SNMP.1.AgentCommunity" {
+				continue
+			}
+			if current, ok := t.Attributes[k]; ok && fmt.Sprintf("%v", current) != fmt.Sprintf("%v", v) {
+				changed = true
+			}
+		}
+		if !changed {
+			return destinationUri, false, nil
+		}
+	}
+
+	// reuse the destination with the same address, or the first free one
+	slot := ""
+	for i := 1; i <= idracSnmpAlertDestinations; i++ {
+		name := fmt.Sprintf("SNMPAlert.%d", i)
+		if attr(name+".Destination") == destination.Address {
+			slot = name
+			break
+		}
+	}
+	for i := 1; i <= idracSnmpAlertDestinations && len(slot) == 0; i++ {
+		name := fmt.Sprintf("SNMPAlert.%d", i)
+		if _, ok := t.Attributes[name+".Destination"]; !ok {
+			continue
+		}
+		if address := attr(name + ".Destination"); address == "" || address == "::" || address == "0.0.0.0" {
+			slot = name
+		}
+	}
+	if len(slot) == 0 {
+		return "", false, fmt.Errorf("no free snmp alert destination on iDRAC %s", manager.ID)
+	}
+
+	settings[slot+".Destination"] = destination.Address
+	settings[slot+".State"] = "Enabled"
+	resp, err := c.client.Patch(manager.ODataID+"/Attributes", map[string]interface{}{"Attributes": settings})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to set snmp alert attributes of iDRAC %s: %+v", manager.ID, err)
+	}
+	resp.Body.Close()
+	return slot, true, nil
+}
+
+func (dellAdapter) DeleteSnmpTrap(c *redfishClient, manager *redfish.Manager, destinationUri string) error {
+	body := map[string]interface{}{
+		"Attributes": map[string]interface{}{
+			destinationUri + ".State":       "Disabled",
+			destinationUri + ".Destination": "",
+		},
+	}
+	resp, err := c.client.Patch(manager.ODataID+"/Attributes", body)
+	if err != nil {
+		return fmt.Errorf("failed to delete snmp alert destination %s of iDRAC %s: %+v", destinationUri, manager.ID, err)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,44 @@
+package redfish
+
+import (
+	"encoding/json"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// hpeAdapter is for the iLO of HPE, and the iLO 4 reports the vendor as HP
+type hpeAdapter struct {
+	dmtfAdapter
+}
+
+func init() {
+	registerAdapter(hpeAdapter{}, "hpe", "hp")
+}
+
+func (hpeAdapter) Name() string {
+	return "hpe"
+}
+
+// GetInfo reports the PostState, which shows whether the system finishes the POST
+func (hpeAdapter) GetInfo(c *redfishClient, system *redfish.ComputerSystem, result map[string]string) {
+	type hpeSystem struct {
+		PostState string
+	}
+	var t struct {
+		Oem struct {
+			Hpe hpeSystem
+			Hp  hpeSystem
+		}
+	}
+	if err := json.Unmarshal(system.RawData, &t); err != nil {
+		c.logger.Debugf("failed to decode the oem of system %s: %+v", system.ID, err)
+		return
+	}
+	postState := t.Oem.Hpe.PostState
+	if len(postState) == 0 {
+		postState = t.Oem.Hp.PostState
+	}
+	if len(postState) > 0 {
+		setData(result, "PostState", postState)
+	}
+}
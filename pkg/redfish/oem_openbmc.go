@@ -0,0 +1,33 @@
+package redfish
+
+import (
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// openbmcAdapter is for the bmcweb of OpenBMC
+type openbmcAdapter struct {
+	dmtfAdapter
+}
+
+func init() {
+	registerAdapter(openbmcAdapter{}, "openbmc")
+}
+
+func (openbmcAdapter) Name() string {
+	return "openbmc"
+}
+
+// LogServices skips the PostCodes, which records every POST code of each boot as a log entry
+func (a openbmcAdapter) LogServices(c *redfishClient, system *redfish.ComputerSystem) ([]*redfish.LogService, error) {
+	ls, err := a.dmtfAdapter.LogServices(c, system)
+	if err != nil {
+		return nil, err
+	}
+	result := []*redfish.LogService{}
+	for _, t := range ls {
+		if t.ID != "PostCodes" {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,22 @@
+package redfish
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("selectAdapter", Label("unitest"), func() {
+	DescribeTable("selects the adapter by the whole words of the vendor or the manufacturer",
+		func(vendor, manufacturer, expected string) {
+			Expect(selectAdapter(vendor, manufacturer).Name()).To(Equal(expected))
+		},
+		Entry("iDRAC", "Dell", "Dell Inc.", "dell"),
+		Entry("iLO 5", "HPE", "HPE", "hpe"),
+		Entry("iLO 4 without the vendor", "", "HP", "hpe"),
+		Entry("OpenBMC", "OpenBMC", "", "openbmc"),
+		Entry("XClarity", "Lenovo", "Lenovo", "dmtf"),
+		Entry("Supermicro", "Supermicro", "Supermicro", "dmtf"),
+		Entry("iBMC", "Huawei", "Huawei", "dmtf"),
+		Entry("the vendor containing hp in a word", "ChipsHPC", "Whpx", "dmtf"),
+	)
+})
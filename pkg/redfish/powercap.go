@@ -0,0 +1,92 @@
+package redfish
+
+import (
+	"fmt"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// PowerControl is the power consumption and the power limit of the chassis, read from the first PowerControl of its Power resource
+type PowerControl struct {
+	ChassisId string
+	// Uri is the @odata.id of the Power resource, which is patched to set the limit
+	Uri           string
+	ConsumedWatts float32
+	// the history of the consumption in the last IntervalInMin minutes
+	AverageConsumedWatts float32
+	MinConsumedWatts     float32
+	MaxConsumedWatts     float32
+	IntervalInMin        float32
+	// LimitWatts is 0 when the power is not limited
+	LimitWatts     float32
+	LimitException string
+	CorrectionInMs int64
+}
+
+// PowerLimit is the power limit to set, the LimitException and the CorrectionInMs are not changed when they are empty
+type PowerLimit struct {
+	LimitWatts     int32
+	LimitException string
+	CorrectionInMs int64
+}
+
+// GetPowerControls returns the power consumption and the power limit of all the chassis which report the PowerControl
+func (c *redfishClient) GetPowerControls() ([]PowerControl, error) {
+	cs, err := c.client.Service.Chassis()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chassis: %+v", err)
+	}
+
+	result := []PowerControl{}
+	for _, chassis := range cs {
+		power, err := chassis.Power()
+		if err != nil {
+			c.logger.Debugf("failed to get power of chassis %s: %+v", chassis.ID, err)
+			continue
+		}
+		if power == nil || len(power.PowerControl) == 0 {
+			continue
+		}
+		pc := power.PowerControl[0]
+		result = append(result, PowerControl{
+			ChassisId:            chassis.ID,
+			Uri:                  power.ODataID,
+			ConsumedWatts:        pc.PowerConsumedWatts,
+			AverageConsumedWatts: pc.PowerMetrics.AverageConsumedWatts,
+			MinConsumedWatts:     pc.PowerMetrics.MinConsumedWatts,
+			MaxConsumedWatts:     pc.PowerMetrics.MaxConsumedWatts,
+			IntervalInMin:        pc.PowerMetrics.IntervalInMin,
+			LimitWatts:           pc.PowerLimit.LimitInWatts,
+			LimitException:       string(pc.PowerLimit.LimitException),
+			CorrectionInMs:       pc.PowerLimit.CorrectionInMs,
+		})
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: no chassis reports the PowerControl", ErrNotSupported)
+	}
+	return result, nil
+}
+
+// SetPowerLimit patches the power limit to the first PowerControl of the Power resource
+func (c *redfishClient) SetPowerLimit(powerUri string, limit PowerLimit) error {
+	powerLimit := map[string]interface{}{
+		"LimitInWatts": limit.LimitWatts,
+	}
+	if len(limit.LimitException) > 0 {
+		powerLimit["LimitException"] = redfish.PowerLimitException(limit.LimitException)
+	}
+	if limit.CorrectionInMs > 0 {
+		powerLimit["CorrectionInMs"] = limit.CorrectionInMs
+	}
+	body := map[string]interface{}{
+		"PowerControl": []map[string]interface{}{{"PowerLimit": powerLimit}},
+	}
+
+	c.logger.Infof("set power limit of %s: %+v", powerUri, limit)
+	resp, err := c.client.Patch(powerUri, body)
+	if err != nil {
+		return fmt.Errorf("failed to set power limit of %s: %+v", powerUri, err)
+	}
+	resp.Body.Close()
+	return nil
+}
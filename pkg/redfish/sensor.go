@@ -0,0 +1,188 @@
+package redfish
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	SensorTypeTemperature   = "Temperature"
+	SensorTypeFan           = "Fan"
+	SensorTypePowerInput    = "PowerInput"
+	SensorTypePowerConsumed = "PowerConsumed"
+	SensorTypeVoltage       = "Voltage"
+)
+
+// SensorReading is a reading of the sensor in the chassis
+type SensorReading struct {
+	// Type is one of the SensorType*
+	Type    string
+	Chassis string
+	Name    string
+	// Unit is only set for the fan, which could be RPM or Percent
+	Unit  string
+	Value float64
+}
+
+// GetSensors collects the temperatures, fan speeds, power and voltages of all chassis.
+// It reads the deprecated Thermal and Power resources, and falls back to the ThermalSubsystem and
+// EnvironmentMetrics for the bmc which only implements the new schema
+func (c *redfishClient) GetSensors() ([]SensorReading, error) {
+	cs, err := c.client.Service.Chassis()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chassis: %+v", err)
+	}
+
+	result := []SensorReading{}
+	for _, chassis := range cs {
+		thermal, err := chassis.Thermal()
+		if err != nil {
+			c.logger.Debugf("failed to get thermal of chassis %s: %+v", chassis.ID, err)
+		}
+		if thermal != nil {
+			result = append(result, thermalReadings(chassis.ID, thermal)...)
+		} else {
+			result = append(result, c.thermalSubsystemReadings(chassis)...)
+		}
+
+		power, err := chassis.Power()
+		if err != nil {
+			c.logger.Debugf("failed to get power of chassis %s: %+v", chassis.ID, err)
+		}
+		if power != nil {
+			result = append(result, powerReadings(chassis.ID, power)...)
+		} else {
+			result = append(result, c.environmentReadings(chassis)...)
+		}
+	}
+	return result, nil
+}
+
+func absent(status common.Status) bool {
+	return status.State == common.AbsentState
+}
+
+func thermalReadings(chassisId string, thermal *redfish.Thermal) []SensorReading {
+	result := []SensorReading{}
+	for _, t := range thermal.Temperatures {
+		if absent(t.Status) {
+			continue
+		}
+		result = append(result, SensorReading{
+			Type:    SensorTypeTemperature,
+			Chassis: chassisId,
+			Name:    sensorName(t.Name, t.MemberID),
+			Value:   float64(t.ReadingCelsius),
+		})
+	}
+	for _, f := range thermal.Fans {
+		if absent(f.Status) {
+			continue
+		}
+		result = append(result, SensorReading{
+			Type:    SensorTypeFan,
+			Chassis: chassisId,
+			Name:    sensorName(f.Name, f.MemberID),
+			Unit:    string(f.ReadingUnits),
+			Value:   float64(f.Reading),
+		})
+	}
+	return result
+}
+
+func powerReadings(chassisId string, power *redfish.Power) []SensorReading {
+	result := []SensorReading{}
+	for _, p := range power.PowerControl {
+		result = append(result, SensorReading{
+			Type:    SensorTypePowerConsumed,
+			Chassis: chassisId,
+			Name:    sensorName(p.Name, p.MemberID),
+			Value:   float64(p.PowerConsumedWatts),
+		})
+	}
+	for _, p := range power.PowerSupplies {
+		if absent(p.Status) {
+			continue
+		}
+		result = append(result, SensorReading{
+			Type:    SensorTypePowerInput,
+			Chassis: chassisId,
+			Name:    sensorName(p.Name, p.MemberID),
+			Value:   float64(p.PowerInputWatts),
+		})
+	}
+	for _, v := range power.Voltages {
+		if absent(v.Status) {
+			continue
+		}
+		result = append(result, SensorReading{
+			Type:    SensorTypeVoltage,
+			Chassis: chassisId,
+			Name:    sensorName(v.Name, v.MemberID),
+			Value:   float64(v.ReadingVolts),
+		})
+	}
+	return result
+}
+
+func (c *redfishClient) thermalSubsystemReadings(chassis *redfish.Chassis) []SensorReading {
+	result := []SensorReading{}
+	subsystem, err := chassis.ThermalSubsystem()
+	if err != nil || subsystem == nil {
+		return result
+	}
+	metrics, err := subsystem.ThermalMetrics()
+	if err != nil || metrics == nil {
+		c.logger.Debugf("failed to get thermal metrics of chassis %s: %+v", chassis.ID, err)
+		return result
+	}
+	for _, t := range metrics.TemperatureReadingsCelsius {
+		result = append(result, SensorReading{
+			Type:    SensorTypeTemperature,
+			Chassis: chassis.ID,
+			Name:    sensorName(t.DeviceName, t.DataSourceURI),
+			Value:   t.Reading,
+		})
+	}
+	return result
+}
+
+func (c *redfishClient) environmentReadings(chassis *redfish.Chassis) []SensorReading {
+	result := []SensorReading{}
+	metrics, err := chassis.EnvironmentMetrics()
+	if err != nil || metrics == nil {
+		return result
+	}
+	for _, f := range metrics.FanSpeedsPercent {
+		result = append(result, SensorReading{
+			Type:    SensorTypeFan,
+			Chassis: chassis.ID,
+			Name:    sensorName(f.DeviceName, f.DataSourceURI),
+			Unit:    string(redfish.PercentReadingUnits),
+			Value:   f.Reading,
+		})
+	}
+	if len(metrics.PowerWatts.DataSourceURI) > 0 || metrics.PowerWatts.Reading != 0 {
+		result = append(result, SensorReading{
+			Type:    SensorTypePowerConsumed,
+			Chassis: chassis.ID,
+			Name:    sensorName("", metrics.PowerWatts.DataSourceURI),
+			Value:   float64(metrics.PowerWatts.Reading),
+		})
+	}
+	return result
+}
+
+// sensorName returns the name of the sensor, or the last segment of the id when the name is empty
+func sensorName(name, id string) string {
+	if len(name) > 0 {
+		return name
+	}
+	if len(id) > 0 {
+		return path.Base(id)
+	}
+	return "unknown"
+}
@@ -1,8 +1,124 @@
 package redfish
 
-// func (c *redfishClient) Reboot(bootCmd BootCmd) error {
-// special for vendors
-func (c *redfishClient) SetSnmp() error {
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// SnmpTrapDestination is the receiver of the SNMPv2c traps of the bmc
+type SnmpTrapDestination struct {
+	Address   string
+	Port      int
+	Community string
+}
+
+// Url returns the destination in the form of the EventDestination
+func (d SnmpTrapDestination) Url() string {
+	return "snmp://" + net.JoinHostPort(d.Address, strconv.Itoa(d.Port))
+}
+
+// SetSnmp enables or disables the snmp agent of the bmc, which is vendor specific
+func (c *redfishClient) SetSnmp(enabled bool) error {
+	_, manager, err := c.primarySystem()
+	if err != nil {
+		return err
+	}
+	if err := c.vendor().SetSnmp(c, manager, enabled); err != nil {
+		return err
+	}
+	c.logger.Infof("set the snmp agent of manager %s: enabled=%v", manager.ID, enabled)
+	return nil
+}
+
+// EnsureSnmpTrapDestination configures the bmc to send the traps to the destination, and configures it again when it is lost
+func (c *redfishClient) EnsureSnmpTrapDestination(destinationUri string, destination SnmpTrapDestination) (string, bool, error) {
+	_, manager, err := c.primarySystem()
+	if err != nil {
+		return "", false, err
+	}
+	uri, created, err := c.vendor().EnsureSnmpTrap(c, manager, destinationUri, destination)
+	if err != nil {
+		return "", false, err
+	}
+	if created {
+		c.logger.Infof("configured the snmp trap destination %s of manager %s: %s", destination.Url(), manager.ID, uri)
+	}
+	return uri, created, nil
+}
+
+// DeleteSnmpTrapDestination removes the trap destination from the bmc
+func (c *redfishClient) DeleteSnmpTrapDestination(destinationUri string) error {
+	_, manager, err := c.primarySystem()
+	if err != nil {
+		return err
+	}
+	return c.vendor().DeleteSnmpTrap(c, manager, destinationUri)
+}
+
+// EnsureSnmpTrap creates the EventDestination with the SNMPTrap subscription type, which is introduced in Redfish 2019.4
+func (dmtfAdapter) EnsureSnmpTrap(c *redfishClient, manager *redfish.Manager, destinationUri string, destination SnmpTrapDestination) (string, bool, error) {
+	eventService, err := c.client.Service.EventService()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get event service: %+v", err)
+	}
+	subscriptions, err := eventService.GetEventSubscriptions()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get event subscriptions: %+v", err)
+	}
+	target := destination.Url()
+	stale := []string{}
+	for _, item := range subscriptions {
+		if item.Destination != target {
+			continue
+		}
+		if item.ODataID == destinationUri {
+			return destinationUri, false, nil
+		}
+		stale = append(stale, item.ODataID)
+	}
+	for _, uri := range stale {
+		c.logger.Infof("delete the stale snmp trap subscription %s to %s", uri, target)
+		if err := eventService.DeleteEventSubscription(uri); err != nil {
+			c.logger.Warnf("failed to delete the stale snmp trap subscription %s: %+v", uri, err)
+		}
+	}
+
+	// gofish does not support the SNMP settings of the subscription, so post it directly
+	body := map[string]interface{}{
+		"Destination":      target,
+		"SubscriptionType": redfish.SNMPTrapSubscriptionType,
+		"Protocol":         redfish.SNMPv2cEventDestinationProtocol,
+		"SNMP": map[string]interface{}{
+			"TrapCommunity": destination.Community,
+		},
+	}
+	resp, err := c.client.Post(eventService.Subscriptions, body)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create snmp trap subscription to %s: %+v", target, err)
+	}
+	defer resp.Body.Close()
+	uri := resp.Header.Get("Location")
+	if len(uri) == 0 {
+		return "", false, fmt.Errorf("no location of the snmp trap subscription to %s", target)
+	}
+	// the Location header may be an absolute url
+	if u, err := url.Parse(uri); err == nil && len(u.Host) > 0 {
+		uri = u.Path
+	}
+	return uri, true, nil
+}
+
+func (dmtfAdapter) DeleteSnmpTrap(c *redfishClient, manager *redfish.Manager, destinationUri string) error {
+	eventService, err := c.client.Service.EventService()
+	if err != nil {
+		return fmt.Errorf("failed to get event service: %+v", err)
+	}
+	if err := eventService.DeleteEventSubscription(destinationUri); err != nil {
+		return fmt.Errorf("failed to delete snmp trap subscription %s: %+v", destinationUri, err)
+	}
 	return nil
 }
@@ -0,0 +1,267 @@
+package redfish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// VolumeRequest is the volume to create on the redfish Storage
+type VolumeRequest struct {
+	Name     string
+	RAIDType string
+	// Drives are the @odata.id of the member drives
+	Drives []string
+	// CapacityBytes is the size of the volume, it uses all the capacity of the drives when it is 0
+	CapacityBytes    int64
+	InitializeMethod string
+}
+
+// redfish url: /redfish/v1/Systems/{id}/Storage
+// GetStorage returns the storage of the system with the drives and volumes, sorted by the @odata.id
+func (c *redfishClient) GetStorage(systemId string) ([]topohubv1beta1.StorageInventory, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return nil, err
+	}
+	storages, err := system.Storage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage of system %s: %+v", system.ID, err)
+	}
+	return c.storageInventory(storages), nil
+}
+
+func (c *redfishClient) storageInventory(storages []*redfish.Storage) []topohubv1beta1.StorageInventory {
+	result := []topohubv1beta1.StorageInventory{}
+	for _, st := range storages {
+		item := topohubv1beta1.StorageInventory{
+			Id:      st.ID,
+			ODataId: st.ODataID,
+			Name:    st.Name,
+			Health:  string(st.Status.Health),
+		}
+
+		// the controllers are embedded in the storage before Storage v1.15, and linked as a collection after it
+		raidTypes := map[string]bool{}
+		for _, ctrl := range st.StorageControllers {
+			for _, t := range ctrl.SupportedRAIDTypes {
+				raidTypes[string(t)] = true
+			}
+		}
+		if ctrls, err := st.Controllers(); err == nil {
+			for _, ctrl := range ctrls {
+				for _, t := range ctrl.SupportedRAIDTypes {
+					raidTypes[string(t)] = true
+				}
+			}
+		}
+		for t := range raidTypes {
+			item.SupportedRAIDTypes = append(item.SupportedRAIDTypes, t)
+		}
+		sort.Strings(item.SupportedRAIDTypes)
+
+		drives, err := st.Drives()
+		if err != nil {
+			c.logger.Warnf("failed to get drives of storage %s: %+v", st.ODataID, err)
+		}
+		for _, d := range drives {
+			item.Drives = append(item.Drives, d.ODataID)
+		}
+		sort.Strings(item.Drives)
+
+		volumes, err := st.Volumes()
+		if err != nil {
+			c.logger.Debugf("failed to get volumes of storage %s: %+v", st.ODataID, err)
+		}
+		for _, v := range volumes {
+			volume := topohubv1beta1.VolumeInventory{
+				ODataId:       v.ODataID,
+				Id:            v.ID,
+				Name:          v.Name,
+				RAIDType:      string(v.RAIDType),
+				CapacityBytes: int64(v.CapacityBytes),
+				Health:        string(v.Status.Health),
+				State:         string(v.Status.State),
+			}
+			if members, err := v.Drives(); err == nil {
+				for _, d := range members {
+					volume.Drives = append(volume.Drives, d.ODataID)
+				}
+				sort.Strings(volume.Drives)
+			}
+			for _, op := range v.Operations {
+				volume.Operations = append(volume.Operations, fmt.Sprintf("%s %d%%", op.OperationName, op.PercentageComplete))
+			}
+			item.Volumes = append(item.Volumes, volume)
+		}
+		sort.Slice(item.Volumes, func(i, j int) bool { return item.Volumes[i].ODataId < item.Volumes[j].ODataId })
+
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ODataId < result[j].ODataId })
+	return result
+}
+
+// CreateVolume creates the volume on the storage, it returns the uri of the redfish task, which is empty when the
+// BMC creates the volume synchronously
+func (c *redfishClient) CreateVolume(storageUri string, volume VolumeRequest, applyTime string) (string, error) {
+	var st struct {
+		Volumes common.Link
+	}
+	if err := c.getJson(storageUri, &st); err != nil {
+		return "", fmt.Errorf("failed to get storage %s: %+v", storageUri, err)
+	}
+	volumesUri := st.Volumes.String()
+	if len(volumesUri) == 0 {
+		volumesUri = storageUri + "/Volumes"
+	}
+
+	drives := []map[string]string{}
+	for _, d := range volume.Drives {
+		drives = append(drives, map[string]string{"@odata.id": d})
+	}
+	body := map[string]interface{}{
+		"Name":     volume.Name,
+		"RAIDType": volume.RAIDType,
+		"Links": map[string]interface{}{
+			"Drives": drives,
+		},
+	}
+	if volume.CapacityBytes > 0 {
+		body["CapacityBytes"] = volume.CapacityBytes
+	}
+	if len(volume.InitializeMethod) > 0 {
+		body["InitializeMethod"] = volume.InitializeMethod
+	}
+	if len(applyTime) > 0 {
+		body["@Redfish.OperationApplyTime"] = applyTime
+	}
+
+	c.logger.Infof("create volume %s on %s: %s, drives %v", volume.Name, volumesUri, volume.RAIDType, volume.Drives)
+	resp, err := c.client.Post(volumesUri, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create volume %s: %+v", volume.Name, err)
+	}
+	defer resp.Body.Close()
+
+	// the Location is the new volume when the BMC creates it synchronously
+	if resp.StatusCode != http.StatusAccepted {
+		return "", nil
+	}
+	return taskUriFromResponse(resp.Header.Get("Location"), resp.Body), nil
+}
+
+// DeleteVolume deletes the volume, it returns the uri of the redfish task, which is empty when the BMC deletes
+// the volume synchronously
+func (c *redfishClient) DeleteVolume(volumeUri string, applyTime string) (string, error) {
+	c.logger.Infof("delete volume %s", volumeUri)
+	var resp *http.Response
+	var err error
+	if len(applyTime) > 0 {
+		// the apply time is in the body of the DELETE request
+		body, _ := json.Marshal(map[string]string{"@Redfish.OperationApplyTime": applyTime})
+		resp, err = c.client.RunRawRequestWithHeaders(http.MethodDelete, volumeUri, bytes.NewReader(body), "application/json", nil)
+	} else {
+		resp, err = c.client.Delete(volumeUri)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to delete volume %s: %+v", volumeUri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", nil
+	}
+	return taskUriFromResponse(resp.Header.Get("Location"), resp.Body), nil
+}
+
+// ResetStorage resets every storage of the system to the factory defaults with Storage.ResetToDefaults, which deletes
+// all the volumes. It returns the uri of the redfish tasks, and ErrNotSupported when no storage supports the action
+func (c *redfishClient) ResetStorage(systemId string) ([]string, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return nil, err
+	}
+	storages, err := system.Storage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage of system %s: %+v", system.ID, err)
+	}
+
+	tasks := []string{}
+	supported := false
+	for _, st := range storages {
+		target, err := c.getActionTarget(st.ODataID, "#Storage.ResetToDefaults")
+		if err != nil {
+			return tasks, fmt.Errorf("failed to get storage %s: %+v", st.ODataID, err)
+		}
+		if len(target) == 0 {
+			c.logger.Debugf("storage %s does not support ResetToDefaults", st.ODataID)
+			continue
+		}
+		supported = true
+		c.logger.Infof("reset storage %s to defaults", st.ODataID)
+		taskUri, err := c.postAction(target, map[string]string{"ResetType": string(redfish.ResetAllStorageResetToDefaultsType)})
+		if err != nil {
+			return tasks, fmt.Errorf("failed to reset storage %s: %+v", st.ODataID, err)
+		}
+		if len(taskUri) > 0 {
+			tasks = append(tasks, taskUri)
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("%w: no storage of system %s supports ResetToDefaults", ErrNotSupported, system.ID)
+	}
+	return tasks, nil
+}
+
+// SecureEraseDrives sanitizes every drive of the system with Drive.SecureErase. It returns the uri of the redfish tasks.
+// No drive is erased when any of them does not support the action, so the host is not left partially erased
+func (c *redfishClient) SecureEraseDrives(systemId string) ([]string, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		return nil, err
+	}
+	storages, err := system.Storage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage of system %s: %+v", system.ID, err)
+	}
+
+	targets := map[string]string{}
+	for _, st := range storages {
+		drives, err := st.Drives()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get drives of storage %s: %+v", st.ODataID, err)
+		}
+		for _, d := range drives {
+			target := actionTarget(d.RawData, "#Drive.SecureErase")
+			if len(target) == 0 {
+				return nil, fmt.Errorf("drive %s does not support SecureErase", d.ODataID)
+			}
+			targets[d.ODataID] = target
+		}
+	}
+
+	drives := []string{}
+	for d := range targets {
+		drives = append(drives, d)
+	}
+	sort.Strings(drives)
+	tasks := []string{}
+	for _, d := range drives {
+		c.logger.Infof("secure erase drive %s", d)
+		taskUri, err := c.postAction(targets[d], map[string]interface{}{})
+		if err != nil {
+			return tasks, fmt.Errorf("failed to erase drive %s: %+v", d, err)
+		}
+		if len(taskUri) > 0 {
+			tasks = append(tasks, taskUri)
+		}
+	}
+	return tasks, nil
+}
@@ -0,0 +1,153 @@
+package redfish
+
+import (
+	"fmt"
+	"strings"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// redfish url: /redfish/v1/Systems
+func (c *redfishClient) GetSystems() ([]topohubv1beta1.SystemInfo, error) {
+	ss, err := c.client.Service.Systems()
+	if err != nil {
+		c.logger.Errorf("failed to Query the computer systems: %+v", err)
+		return nil, err
+	} else if len(ss) == 0 {
+		c.logger.Errorf("failed to get system")
+		return nil, fmt.Errorf("failed to get system")
+	}
+	c.logger.Debugf("system amount: %d", len(ss))
+
+	result := []topohubv1beta1.SystemInfo{}
+	for _, system := range ss {
+		item := topohubv1beta1.SystemInfo{
+			Id:           system.ID,
+			ODataId:      system.ODataID,
+			Name:         system.Name,
+			Manufacturer: system.Manufacturer,
+			Model:        system.Model,
+			SerialNumber: system.SerialNumber,
+			HostName:     system.HostName,
+			BiosVersion:  system.BIOSVersion,
+			PowerState:   string(system.PowerState),
+			Health:       string(system.Status.Health),
+		}
+		for _, t := range system.SupportedResetTypes {
+			item.SupportedResetTypes = append(item.SupportedResetTypes, string(t))
+		}
+		managers, err := system.ManagedBy()
+		if err != nil {
+			c.logger.Debugf("failed to get the managers of system %s: %+v", system.ID, err)
+		}
+		for _, m := range managers {
+			item.ManagedBy = append(item.ManagedBy, m.ID)
+		}
+		item.Boot = &topohubv1beta1.BootInfo{
+			OverrideTarget:  string(system.Boot.BootSourceOverrideTarget),
+			OverrideEnabled: string(system.Boot.BootSourceOverrideEnabled),
+			OverrideMode:    string(system.Boot.BootSourceOverrideMode),
+			UefiTarget:      system.Boot.UefiTargetBootSourceOverride,
+			BootOrder:       system.Boot.BootOrder,
+		}
+		if chassis, err := c.indicatorChassis(system); err != nil {
+			c.logger.Debugf("failed to get the indicator led of system %s: %+v", system.ID, err)
+		} else {
+			item.IndicatorLED = indicatorLEDState(chassis)
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// redfish url: /redfish/v1/Managers
+func (c *redfishClient) GetManagers() ([]topohubv1beta1.ManagerInfo, error) {
+	managers, err := c.client.Service.Managers()
+	if err != nil {
+		c.logger.Errorf("failed to Query the bmc : %+v", err)
+		return nil, err
+	} else if len(managers) == 0 {
+		c.logger.Errorf("failed to get bmc")
+		return nil, fmt.Errorf("failed to get bmc")
+	}
+	c.logger.Debugf("bmc amount: %d", len(managers))
+
+	result := []topohubv1beta1.ManagerInfo{}
+	for _, m := range managers {
+		result = append(result, topohubv1beta1.ManagerInfo{
+			Id:              m.ID,
+			ODataId:         m.ODataID,
+			Name:            m.Name,
+			ManagerType:     string(m.ManagerType),
+			Model:           m.Model,
+			FirmwareVersion: m.FirmwareVersion,
+			Health:          string(m.Status.Health),
+		})
+	}
+
+	return result, nil
+}
+
+// selectSystem returns the ComputerSystem with the id.
+// When the id is empty, the host must expose only one system, so that we never operate a wrong system of a multi-node host
+func (c *redfishClient) selectSystem(systemId string) (*redfish.ComputerSystem, error) {
+	ss, err := c.client.Service.Systems()
+	if err != nil {
+		c.logger.Errorf("failed to Query the computer systems: %+v", err)
+		return nil, err
+	}
+	if len(ss) == 0 {
+		c.logger.Errorf("no system found")
+		return nil, fmt.Errorf("no system found")
+	}
+
+	if len(systemId) == 0 {
+		if len(ss) == 1 {
+			return ss[0], nil
+		}
+		ids := []string{}
+		for _, system := range ss {
+			ids = append(ids, system.ID)
+		}
+		return nil, fmt.Errorf("the host exposes %d systems (%s), the system id must be specified", len(ss), strings.Join(ids, ","))
+	}
+
+	for _, system := range ss {
+		if system.ID == systemId {
+			return system, nil
+		}
+	}
+	return nil, fmt.Errorf("system %s is not found", systemId)
+}
+
+// primarySystem returns the system and its bmc which are reported in the flat info of the hoststatus
+func (c *redfishClient) primarySystem() (*redfish.ComputerSystem, *redfish.Manager, error) {
+	ss, err := c.client.Service.Systems()
+	if err != nil {
+		c.logger.Errorf("failed to Query the computer systems: %+v", err)
+		return nil, nil, err
+	} else if len(ss) == 0 {
+		c.logger.Errorf("failed to get system")
+		return nil, nil, fmt.Errorf("failed to get system")
+	}
+	c.logger.Debugf("system amount: %d", len(ss))
+	system := ss[0]
+
+	// prefer the manager which manages the system, rather than the first one of a multi-manager enclosure
+	managers, err := system.ManagedBy()
+	if err == nil && len(managers) > 0 {
+		return system, managers[0], nil
+	}
+	managers, err = c.client.Service.Managers()
+	if err != nil {
+		c.logger.Errorf("failed to Query the bmc : %+v", err)
+		return nil, nil, err
+	} else if len(managers) == 0 {
+		c.logger.Errorf("failed to get bmc")
+		return nil, nil, fmt.Errorf("failed to get bmc")
+	}
+	c.logger.Debugf("bmc amount: %d", len(managers))
+	return system, managers[0], nil
+}
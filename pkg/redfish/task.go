@@ -0,0 +1,95 @@
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// taskUriFromResponse returns the uri of the task created by the BMC for an asynchronous action.
+// The BMC responses the Task resource in the body, or the task monitor in the Location header
+func taskUriFromResponse(location string, body io.Reader) string {
+	data, _ := io.ReadAll(body)
+	var t struct {
+		ODataID   string `json:"@odata.id"`
+		TaskState string
+	}
+	if err := json.Unmarshal(data, &t); err == nil && len(t.TaskState) > 0 && len(t.ODataID) > 0 {
+		return t.ODataID
+	}
+	return location
+}
+
+// postAction posts the action, it returns the uri of the redfish task, which is empty when the BMC finishes the action synchronously
+func (c *redfishClient) postAction(target string, body interface{}) (string, error) {
+	resp, err := c.client.Post(target, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", nil
+	}
+	return taskUriFromResponse(resp.Header.Get("Location"), resp.Body), nil
+}
+
+// GetTask returns the state of the redfish task or the task monitor
+func (c *redfishClient) GetTask(taskUri string) (*topohubv1beta1.TaskInfo, error) {
+	resp, err := c.client.Get(taskUri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %+v", taskUri, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task %s: %+v", taskUri, err)
+	}
+
+	result := &topohubv1beta1.TaskInfo{
+		Uri: taskUri,
+	}
+
+	// the task monitor responses the result of the action rather than the task when the action finishes
+	var t redfish.Task
+	if resp.StatusCode == http.StatusNoContent || len(data) == 0 || json.Unmarshal(data, &t) != nil || len(t.TaskState) == 0 {
+		c.logger.Debugf("task %s returns the http code %d without task state, consider it completed", taskUri, resp.StatusCode)
+		result.State = string(redfish.CompletedTaskState)
+		result.PercentComplete = 100
+		return result, nil
+	}
+
+	result.State = string(t.TaskState)
+	result.Health = string(t.TaskStatus)
+	result.PercentComplete = int32(t.PercentComplete)
+	for _, m := range t.Messages {
+		msg := m.Message
+		if len(msg) == 0 {
+			msg = m.MessageID
+		}
+		if len(msg) > 0 {
+			result.Messages = append(result.Messages, msg)
+		}
+	}
+	if t.TaskState == redfish.CompletedTaskState && result.PercentComplete == 0 {
+		result.PercentComplete = 100
+	}
+	return result, nil
+}
+
+// TaskFinished returns whether the task has reached the final state and whether it succeeds
+func TaskFinished(task *topohubv1beta1.TaskInfo) (finished bool, succeeded bool) {
+	switch redfish.TaskState(task.State) {
+	case redfish.CompletedTaskState:
+		return true, task.Health != string(common.CriticalHealth)
+	case redfish.ExceptionTaskState, redfish.KilledTaskState, redfish.CancelledTaskState:
+		return true, false
+	}
+	return false, false
+}
@@ -0,0 +1,205 @@
+package redfish
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+)
+
+// the reasons of CertificateError
+const (
+	// CertificateChanged means the certificate does not match the pinned or the recorded fingerprint
+	CertificateChanged = "CertificateChanged"
+	// CertificateUntrusted means the certificate is not issued by the CA bundle
+	CertificateUntrusted = "CertificateUntrusted"
+)
+
+// CertificateError is returned when the certificate of the bmc is not trusted
+type CertificateError struct {
+	Reason string
+	// Certificate is the certificate presented by the bmc
+	Certificate *topohubv1beta1.CertificateInfo
+	Message     string
+}
+
+func (e *CertificateError) Error() string {
+	return fmt.Sprintf("untrusted certificate %s of the bmc: %s", e.Certificate.Fingerprint, e.Message)
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the certificate in the form of AB:CD:...
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+// normalizeFingerprint allows the fingerprint to be written in the lower case or without the colons
+func normalizeFingerprint(fingerprint string) string {
+	r := strings.NewReplacer(":", "", " ", "")
+	return strings.ToUpper(r.Replace(fingerprint))
+}
+
+// NewCertificateInfo describes the certificate of the bmc which is recorded in the HostStatus
+func NewCertificateInfo(cert *x509.Certificate) *topohubv1beta1.CertificateInfo {
+	return &topohubv1beta1.CertificateInfo{
+		Fingerprint: Fingerprint(cert),
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		NotBefore:   cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:    cert.NotAfter.UTC().Format(time.RFC3339),
+		RecordTime:  time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// peerCertificate holds the latest certificate presented by the bmc
+type peerCertificate struct {
+	lock sync.Mutex
+	info *topohubv1beta1.CertificateInfo
+}
+
+func (p *peerCertificate) get() *topohubv1beta1.CertificateInfo {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.info == nil {
+		return nil
+	}
+	t := *p.info
+	return &t
+}
+
+// verifyConnection returns the callback which verifies the certificate of the bmc with the trust, it is called for
+// each tls handshake, so the certificate replaced on a running bmc is verified too
+func verifyConnection(ip string, trust *hoststatusData.TLSTrust, peer *peerCertificate) func(tls.ConnectionState) error {
+	var roots *x509.CertPool
+	if len(trust.CAPem) > 0 {
+		roots = x509.NewCertPool()
+		roots.AppendCertsFromPEM(trust.CAPem)
+	}
+	serverName := trust.ServerName
+	if len(serverName) == 0 {
+		serverName = ip
+	}
+
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("the bmc presents no certificate")
+		}
+		leaf := cs.PeerCertificates[0]
+		info := NewCertificateInfo(leaf)
+		peer.lock.Lock()
+		peer.info = info
+		peer.lock.Unlock()
+
+		if trust.InsecureSkipVerify {
+			return nil
+		}
+
+		if roots != nil {
+			intermediates := x509.NewCertPool()
+			for _, cert := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err := leaf.Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				DNSName:       serverName,
+			})
+			if err != nil {
+				return &CertificateError{Reason: CertificateUntrusted, Certificate: info, Message: err.Error()}
+			}
+		}
+
+		if len(trust.Fingerprints) > 0 {
+			for _, f := range trust.Fingerprints {
+				if normalizeFingerprint(f) == normalizeFingerprint(info.Fingerprint) {
+					return nil
+				}
+			}
+			return &CertificateError{Reason: CertificateChanged, Certificate: info, Message: "it does not match the pinned fingerprints"}
+		}
+
+		// trust on first use
+		if roots == nil && len(trust.KnownFingerprint) > 0 &&
+			normalizeFingerprint(trust.KnownFingerprint) != normalizeFingerprint(info.Fingerprint) {
+			return &CertificateError{
+				Reason:      CertificateChanged,
+				Certificate: info,
+				Message:     fmt.Sprintf("it is changed from the certificate %s trusted on first use", trust.KnownFingerprint),
+			}
+		}
+		return nil
+	}
+}
+
+// keepAliveTransport keeps the connection to the bmc open. gofish closes the connection after each request when
+// the http client is provided by the caller
+type keepAliveTransport struct {
+	next http.RoundTripper
+}
+
+func (t *keepAliveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := *req
+	r.Close = false
+	return t.next.RoundTrip(&r)
+}
+
+func (t *keepAliveTransport) CloseIdleConnections() {
+	if c, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// newHTTPClient creates the http client to the bmc, which verifies the certificate of the bmc with hostCon.TLS
+func newHTTPClient(hostCon hoststatusData.HostConnectCon, reuseConnections bool, peer *peerCertificate) *http.Client {
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	trust := hostCon.TLS
+	if trust == nil {
+		trust = &hoststatusData.TLSTrust{InsecureSkipVerify: true}
+	}
+	transport := &http.Transport{
+		Proxy:                 defaultTransport.Proxy,
+		DialContext:           defaultTransport.DialContext,
+		MaxIdleConns:          defaultTransport.MaxIdleConns,
+		IdleConnTimeout:       defaultTransport.IdleConnTimeout,
+		ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		// bound the wait of each response, so a hung bmc does not block its updates forever. The timer starts after the
+		// request is sent, so the upload of the firmware is not limited
+		ResponseHeaderTimeout: responseTimeout,
+		TLSClientConfig: &tls.Config{
+			// the certificate is verified by VerifyConnection, since the bmc is usually accessed by the ip address and
+			// its certificate may be self-signed
+			InsecureSkipVerify: true,
+			VerifyConnection:   verifyConnection(hostCon.Info.IpAddr, trust, peer),
+		},
+	}
+
+	var rt http.RoundTripper = transport
+	if reuseConnections {
+		transport.IdleConnTimeout = 1 * time.Minute
+		rt = &keepAliveTransport{next: transport}
+	}
+	// record the latency and the errors of the requests
+	return &http.Client{
+		Transport: &metricsTransport{
+			ip:   hostCon.Info.IpAddr,
+			next: rt,
+		},
+	}
+}
+
+// GetCertificate returns the certificate presented by the bmc at the latest tls handshake
+func (c *redfishClient) GetCertificate() *topohubv1beta1.CertificateInfo {
+	return c.peer.get()
+}
@@ -0,0 +1,116 @@
+package redfish
+
+import (
+	"fmt"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// VirtualMediaBoot inserts the ISO to the virtual cd of the system, boots the system from the cd once, and returns the
+// @odata.id of the virtual media, which is used to eject the ISO later, and the uri of the redfish task when the bmc
+// resets the system asynchronously
+func (c *redfishClient) VirtualMediaBoot(systemId string, imageUri string) (string, string, error) {
+	system, err := c.selectSystem(systemId)
+	if err != nil {
+		c.logger.Errorf("failed to select the system: %+v", err)
+		return "", "", err
+	}
+
+	media, err := c.systemVirtualCd(system)
+	if err != nil {
+		return "", "", err
+	}
+
+	// eject the previous image, most bmc refuse to insert when the media is occupied
+	if media.Inserted {
+		c.logger.Infof("eject image %s from virtual media %s", media.Image, media.ODataID)
+		if err := media.EjectMedia(); err != nil {
+			return "", "", fmt.Errorf("failed to eject image %s from virtual media %s: %+v", media.Image, media.ODataID, err)
+		}
+	}
+
+	c.logger.Infof("insert image %s to virtual media %s", imageUri, media.ODataID)
+	err = media.InsertMediaConfig(redfish.VirtualMediaConfig{
+		Image:                imageUri,
+		Inserted:             true,
+		WriteProtected:       true,
+		TransferProtocolType: redfish.HTTPTransferProtocolType,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to insert image to virtual media %s: %+v", media.ODataID, err)
+	}
+
+	bootOverride := redfish.Boot{
+		BootSourceOverrideTarget:  redfish.CdBootSourceOverrideTarget,
+		BootSourceOverrideEnabled: redfish.OnceBootSourceOverrideEnabled,
+	}
+	c.logger.Infof("boot system %s from virtual media once", system.ID)
+	if err := c.vendor().SetBootOverride(c, system, bootOverride); err != nil {
+		return media.ODataID, "", fmt.Errorf("failed to set boot option error:%+v", err)
+	}
+
+	// power on the host which is off, otherwise restart it
+	bootCmd := topohubv1beta1.BootCmdForceRestart
+	if system.PowerState == redfish.OffPowerState {
+		bootCmd = topohubv1beta1.BootCmdForceOn
+	}
+	taskUri, err := c.resetSystem(system, c.vendor().ResetType(system, bootCmd))
+	if err != nil {
+		return media.ODataID, "", fmt.Errorf("failed to operate system %s: %+v", system.ID, err)
+	}
+	if len(taskUri) > 0 {
+		c.logger.Infof("the bmc resets system %s asynchronously with task %s", system.ID, taskUri)
+	}
+	return media.ODataID, taskUri, nil
+}
+
+// EjectVirtualMedia ejects the image from the virtual media
+func (c *redfishClient) EjectVirtualMedia(mediaUri string) error {
+	media, err := redfish.GetVirtualMedia(c.client, mediaUri)
+	if err != nil {
+		return fmt.Errorf("failed to get virtual media %s: %+v", mediaUri, err)
+	}
+	if !media.Inserted {
+		c.logger.Debugf("virtual media %s has been ejected", mediaUri)
+		return nil
+	}
+	c.logger.Infof("eject image %s from virtual media %s", media.Image, mediaUri)
+	if err := media.EjectMedia(); err != nil {
+		return fmt.Errorf("failed to eject virtual media %s: %+v", mediaUri, err)
+	}
+	return nil
+}
+
+// systemVirtualCd returns the virtual media which could be inserted with a cd image.
+// The virtual media is under the system since redfish 1.11, and it is under the manager for the earlier bmc
+func (c *redfishClient) systemVirtualCd(system *redfish.ComputerSystem) (*redfish.VirtualMedia, error) {
+	medias, err := system.VirtualMedia()
+	if err != nil || len(medias) == 0 {
+		c.logger.Debugf("no virtual media under system %s, try the managers: %+v", system.ID, err)
+		managers, err := system.ManagedBy()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the managers of system %s: %+v", system.ID, err)
+		}
+		for _, manager := range managers {
+			items, err := manager.VirtualMedia()
+			if err != nil {
+				c.logger.Debugf("failed to get virtual media of manager %s: %+v", manager.ID, err)
+				continue
+			}
+			medias = append(medias, items...)
+		}
+	}
+
+	for _, media := range medias {
+		if !media.SupportsMediaInsert {
+			continue
+		}
+		for _, t := range media.MediaTypes {
+			if t == redfish.CDMediaType || t == redfish.DVDMediaType {
+				return media, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no virtual media supports the cd image for system %s", system.ID)
+}
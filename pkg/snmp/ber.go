@@ -0,0 +1,236 @@
+package snmp
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// the BER tags used by SNMP
+const (
+	TagInteger        = 0x02
+	TagOctetString    = 0x04
+	TagNull           = 0x05
+	TagOID            = 0x06
+	TagSequence       = 0x30
+	TagIPAddress      = 0x40
+	TagCounter32      = 0x41
+	TagGauge32        = 0x42
+	TagTimeTicks      = 0x43
+	TagOpaque         = 0x44
+	TagCounter64      = 0x46
+	TagNoSuchObject   = 0x80
+	TagNoSuchInstance = 0x81
+	TagEndOfMibView   = 0x82
+
+	pduTrapV1  = 0xa4
+	pduInform  = 0xa6
+	pduTrapV2  = 0xa7
+	pduReport  = 0xa8
+	maxLenSize = 4
+)
+
+var errTruncated = errors.New("truncated ber data")
+
+// readTLV reads a tag-length-value from the data, the value and the rest are the slices of the data
+func readTLV(data []byte) (tag byte, value []byte, rest []byte, err error) {
+	if len(data) < 2 {
+		return 0, nil, nil, errTruncated
+	}
+	tag = data[0]
+	length := int(data[1])
+	pos := 2
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > maxLenSize || len(data) < pos+n {
+			return 0, nil, nil, fmt.Errorf("invalid ber length of tag 0x%02x", tag)
+		}
+		length = 0
+		for _, b := range data[pos : pos+n] {
+			length = length<<8 | int(b)
+		}
+		pos += n
+	}
+	if length < 0 || len(data)-pos < length {
+		return 0, nil, nil, errTruncated
+	}
+	return tag, data[pos : pos+length], data[pos+length:], nil
+}
+
+// expectTLV reads a tag-length-value, and fails when the tag is not the expected one
+func expectTLV(data []byte, expected byte) ([]byte, []byte, error) {
+	tag, value, rest, err := readTLV(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag != expected {
+		return nil, nil, fmt.Errorf("unexpected ber tag 0x%02x, expected 0x%02x", tag, expected)
+	}
+	return value, rest, nil
+}
+
+func readInt(data []byte) (int64, []byte, error) {
+	value, rest, err := expectTLV(data, TagInteger)
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := decodeInt(value)
+	return n, rest, err
+}
+
+func readOctetString(data []byte) ([]byte, []byte, error) {
+	return expectTLV(data, TagOctetString)
+}
+
+func readOID(data []byte) (string, []byte, error) {
+	value, rest, err := expectTLV(data, TagOID)
+	if err != nil {
+		return "", nil, err
+	}
+	oid, err := decodeOID(value)
+	return oid, rest, err
+}
+
+// decodeInt decodes the two's complement integer
+func decodeInt(value []byte) (int64, error) {
+	if len(value) == 0 || len(value) > 8 {
+		return 0, fmt.Errorf("invalid length %d of the integer", len(value))
+	}
+	n := int64(int8(value[0]))
+	for _, b := range value[1:] {
+		n = n<<8 | int64(b)
+	}
+	return n, nil
+}
+
+// decodeUint decodes the unsigned integer of Counter32, Gauge32, TimeTicks and Counter64, which may have a leading zero
+func decodeUint(value []byte) (uint64, error) {
+	if len(value) == 0 || len(value) > 9 {
+		return 0, fmt.Errorf("invalid length %d of the unsigned integer", len(value))
+	}
+	var n uint64
+	for _, b := range value {
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
+
+func decodeOID(value []byte) (string, error) {
+	if len(value) == 0 {
+		return "", fmt.Errorf("empty object identifier")
+	}
+	parts := []string{}
+	var n uint64
+	for i, b := range value {
+		n = n<<7 | uint64(b&0x7f)
+		if b&0x80 != 0 {
+			if i == len(value)-1 {
+				return "", fmt.Errorf("truncated object identifier")
+			}
+			continue
+		}
+		if len(parts) == 0 {
+			// the first sub-identifier encodes the first two arcs
+			first := n / 40
+			if first > 2 {
+				first = 2
+			}
+			parts = append(parts, strconv.FormatUint(first, 10), strconv.FormatUint(n-first*40, 10))
+		} else {
+			parts = append(parts, strconv.FormatUint(n, 10))
+		}
+		n = 0
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// Variable is a variable binding of the trap
+type Variable struct {
+	OID  string
+	Type byte
+	// Value is int64 for Integer, uint64 for Counter32, Gauge32, TimeTicks and Counter64, []byte for OctetString
+	// and Opaque, string for OID, net.IP for IpAddress, and nil for Null and the exceptions
+	Value interface{}
+}
+
+// String formats the value, the printable octet string is returned as the text
+func (v Variable) String() string {
+	switch value := v.Value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		if isPrintable(value) {
+			return strings.TrimRight(string(value), "\x00")
+		}
+		return hex.EncodeToString(value)
+	case net.IP:
+		return value.String()
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func isPrintable(data []byte) bool {
+	for i, b := range data {
+		// the string from some agents is terminated with NUL
+		if b == 0 && i == len(data)-1 {
+			continue
+		}
+		if (b < 0x20 || b > 0x7e) && b != '\n' && b != '\r' && b != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeVariables decodes the sequence of the variable bindings
+func decodeVariables(data []byte) ([]Variable, error) {
+	list, _, err := expectTLV(data, TagSequence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variable bindings: %v", err)
+	}
+	result := []Variable{}
+	for len(list) > 0 {
+		var item []byte
+		item, list, err = expectTLV(list, TagSequence)
+		if err != nil {
+			return nil, fmt.Errorf("invalid variable binding: %v", err)
+		}
+		oid, rest, err := readOID(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name of the variable binding: %v", err)
+		}
+		tag, value, _, err := readTLV(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %v", oid, err)
+		}
+		v := Variable{OID: oid, Type: tag}
+		switch tag {
+		case TagInteger:
+			v.Value, err = decodeInt(value)
+		case TagOctetString, TagOpaque:
+			v.Value = append([]byte{}, value...)
+		case TagOID:
+			v.Value, err = decodeOID(value)
+		case TagIPAddress:
+			if len(value) != net.IPv4len {
+				err = fmt.Errorf("invalid length %d of the ip address", len(value))
+			} else {
+				v.Value = net.IP(append([]byte{}, value...))
+			}
+		case TagCounter32, TagGauge32, TagTimeTicks, TagCounter64:
+			v.Value, err = decodeUint(value)
+		case TagNull, TagNoSuchObject, TagNoSuchInstance, TagEndOfMibView:
+		default:
+			v.Value = append([]byte{}, value...)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %v", oid, err)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
@@ -0,0 +1,232 @@
+package snmp
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// tlv encodes the tag-length-value with the definite length
+func tlv(tag byte, values ...[]byte) []byte {
+	value := bytes.Join(values, nil)
+	n := len(value)
+	var header []byte
+	switch {
+	case n < 0x80:
+		header = []byte{tag, byte(n)}
+	case n < 0x100:
+		header = []byte{tag, 0x81, byte(n)}
+	default:
+		header = []byte{tag, 0x82, byte(n >> 8), byte(n)}
+	}
+	return append(header, value...)
+}
+
+// integer encodes the integer with the fewest bytes of the two's complement
+func integer(n int64) []byte {
+	value := []byte{byte(n)}
+	for n >= 0x80 || n < -0x80 {
+		n >>= 8
+		value = append([]byte{byte(n)}, value...)
+	}
+	return tlv(TagInteger, value)
+}
+
+func octets(s string) []byte {
+	return tlv(TagOctetString, []byte(s))
+}
+
+// oid encodes the object identifier, the sub-identifiers are in base 128
+func oid(s string) []byte {
+	arcs := []uint64{}
+	for _, part := range strings.Split(s, ".") {
+		n, err := strconv.ParseUint(part, 10, 64)
+		Expect(err).NotTo(HaveOccurred())
+		arcs = append(arcs, n)
+	}
+	value := []byte{}
+	for _, n := range append([]uint64{arcs[0]*40 + arcs[1]}, arcs[2:]...) {
+		encoded := []byte{byte(n & 0x7f)}
+		for n >>= 7; n > 0; n >>= 7 {
+			encoded = append([]byte{byte(n&0x7f) | 0x80}, encoded...)
+		}
+		value = append(value, encoded...)
+	}
+	return tlv(TagOID, value)
+}
+
+// varbind encodes the variable binding of the oid and the encoded value
+func varbind(name string, value []byte) []byte {
+	return tlv(TagSequence, oid(name), value)
+}
+
+var _ = Describe("BER", Label("unitest"), func() {
+	DescribeTable("reads the tag-length-value",
+		func(data []byte, tag byte, value, rest []byte) {
+			t, v, r, err := readTLV(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(tag))
+			Expect(v).To(Equal(value))
+			Expect(r).To(Equal(rest))
+		},
+		Entry("short length", []byte{0x04, 0x02, 'o', 'k', 0x05, 0x00}, byte(0x04), []byte("ok"), []byte{0x05, 0x00}),
+		Entry("empty value", []byte{0x05, 0x00}, byte(0x05), []byte{}, []byte{}),
+		Entry("long length of one byte", append([]byte{0x04, 0x81, 0x80}, make([]byte, 0x80)...), byte(0x04), make([]byte, 0x80), []byte{}),
+		Entry("long length of two bytes", append([]byte{0x30, 0x82, 0x01, 0x00}, make([]byte, 0x100)...), byte(0x30), make([]byte, 0x100), []byte{}),
+		Entry("long length with the leading zero", []byte{0x04, 0x82, 0x00, 0x01, 'a'}, byte(0x04), []byte("a"), []byte{}),
+	)
+
+	DescribeTable("refuses the malformed tag-length-value",
+		func(data []byte, expectedErr string) {
+			_, _, _, err := readTLV(data)
+			Expect(err).To(MatchError(expectedErr))
+		},
+		Entry("empty", []byte{}, errTruncated.Error()),
+		Entry("without the length", []byte{0x30}, errTruncated.Error()),
+		Entry("value shorter than the length", []byte{0x04, 0x03, 'a', 'b'}, errTruncated.Error()),
+		Entry("truncated long length", []byte{0x04, 0x82, 0x01}, "invalid ber length of tag 0x04"),
+		Entry("indefinite length", []byte{0x30, 0x80, 0x00, 0x00}, "invalid ber length of tag 0x30"),
+		Entry("long length of too many bytes", []byte{0x30, 0x85, 0, 0, 0, 0, 1, 0}, "invalid ber length of tag 0x30"),
+		Entry("long length beyond the data", []byte{0x30, 0x84, 0x7f, 0xff, 0xff, 0xff, 0}, errTruncated.Error()),
+		Entry("long length overflowing the int32", []byte{0x30, 0x84, 0xff, 0xff, 0xff, 0xff, 0}, errTruncated.Error()),
+	)
+
+	It("refuses the unexpected tag", func() {
+		_, _, err := expectTLV([]byte{0x04, 0x00}, TagSequence)
+		Expect(err).To(MatchError("unexpected ber tag 0x04, expected 0x30"))
+	})
+
+	DescribeTable("decodes the integer",
+		func(value []byte, expected int64) {
+			Expect(decodeInt(value)).To(Equal(expected))
+		},
+		Entry("zero", []byte{0x00}, int64(0)),
+		Entry("positive with the leading zero", []byte{0x00, 0x80}, int64(128)),
+		Entry("negative", []byte{0xff, 0x7f}, int64(-129)),
+		Entry("minus one", []byte{0xff}, int64(-1)),
+		Entry("max int64", []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, int64(1<<63-1)),
+	)
+
+	DescribeTable("decodes the unsigned integer",
+		func(value []byte, expected uint64) {
+			Expect(decodeUint(value)).To(Equal(expected))
+		},
+		Entry("TimeTicks", []byte{0x01, 0x00}, uint64(256)),
+		Entry("Counter32 with the high bit", []byte{0x00, 0xff, 0xff, 0xff, 0xff}, uint64(0xffffffff)),
+		Entry("Counter64 with the leading zero", []byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint64(1<<64-1)),
+	)
+
+	It("refuses the integer of the invalid length", func() {
+		_, err := decodeInt([]byte{})
+		Expect(err).To(HaveOccurred())
+		_, err = decodeInt(make([]byte, 9))
+		Expect(err).To(HaveOccurred())
+		_, err = decodeUint([]byte{})
+		Expect(err).To(HaveOccurred())
+		_, err = decodeUint(make([]byte, 10))
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("decodes the object identifier",
+		func(value []byte, expected string) {
+			Expect(decodeOID(value)).To(Equal(expected))
+		},
+		Entry("sysUpTime.0", oid(OIDSysUpTime)[2:], OIDSysUpTime),
+		Entry("the sub-identifiers of several bytes", []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0x94, 0x78, 0x01}, "1.3.6.1.4.1.2680.1"),
+		Entry("the first arc 0", []byte{0x00}, "0.0"),
+		Entry("the first arc 2 with the second arc beyond 39", []byte{0x88, 0x37, 0x03}, "2.999.3"),
+	)
+
+	DescribeTable("refuses the malformed object identifier",
+		func(value []byte) {
+			_, err := decodeOID(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", []byte{}),
+		Entry("truncated sub-identifier", []byte{0x2b, 0x06, 0x81}),
+	)
+
+	It("decodes the variable bindings of all the types", func() {
+		data := tlv(TagSequence,
+			varbind("1.3.6.1.2.1.2.2.1.1.3", integer(-3)),
+			varbind("1.3.6.1.2.1.1.5.0", octets("bmc-01")),
+			varbind("1.3.6.1.2.1.1.2.0", oid("1.3.6.1.4.1.674")),
+			varbind("1.3.6.1.2.1.4.20.1.1", tlv(TagIPAddress, []byte{10, 0, 0, 1})),
+			varbind("1.3.6.1.2.1.2.2.1.10.1", tlv(TagCounter32, []byte{0x00, 0x80, 0x00, 0x00, 0x00})),
+			varbind("1.3.6.1.2.1.31.1.1.1.6.1", tlv(TagCounter64, []byte{0x01, 0x00, 0x00, 0x00, 0x00})),
+			varbind("1.3.6.1.2.1.1.3.0", tlv(TagTimeTicks, []byte{0x01, 0x00})),
+			varbind("1.3.6.1.4.1.1.1", tlv(TagOpaque, []byte{0x9f, 0x78})),
+			varbind("1.3.6.1.4.1.1.2", tlv(TagNull)),
+			varbind("1.3.6.1.4.1.1.3", tlv(TagNoSuchObject)),
+			varbind("1.3.6.1.4.1.1.4", tlv(0x47, []byte{0x01})),
+		)
+		variables, err := decodeVariables(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(variables).To(Equal([]Variable{
+			{OID: "1.3.6.1.2.1.2.2.1.1.3", Type: TagInteger, Value: int64(-3)},
+			{OID: "1.3.6.1.2.1.1.5.0", Type: TagOctetString, Value: []byte("bmc-01")},
+			{OID: "1.3.6.1.2.1.1.2.0", Type: TagOID, Value: "1.3.6.1.4.1.674"},
+			{OID: "1.3.6.1.2.1.4.20.1.1", Type: TagIPAddress, Value: net.IP{10, 0, 0, 1}},
+			{OID: "1.3.6.1.2.1.2.2.1.10.1", Type: TagCounter32, Value: uint64(0x80000000)},
+			{OID: "1.3.6.1.2.1.31.1.1.1.6.1", Type: TagCounter64, Value: uint64(1 << 32)},
+			{OID: "1.3.6.1.2.1.1.3.0", Type: TagTimeTicks, Value: uint64(256)},
+			{OID: "1.3.6.1.4.1.1.1", Type: TagOpaque, Value: []byte{0x9f, 0x78}},
+			{OID: "1.3.6.1.4.1.1.2", Type: TagNull},
+			{OID: "1.3.6.1.4.1.1.3", Type: TagNoSuchObject},
+			{OID: "1.3.6.1.4.1.1.4", Type: 0x47, Value: []byte{0x01}},
+		}))
+		Expect(variables[3].String()).To(Equal("10.0.0.1"))
+		Expect(variables[1].String()).To(Equal("bmc-01"))
+		Expect(variables[7].String()).To(Equal("9f78"))
+	})
+
+	DescribeTable("formats the value of the variable",
+		func(v Variable, expected string) {
+			Expect(v.String()).To(Equal(expected))
+		},
+		Entry("null", Variable{Type: TagNull}, ""),
+		Entry("integer", Variable{Type: TagInteger, Value: int64(-1)}, "-1"),
+		Entry("text terminated with NUL", Variable{Type: TagOctetString, Value: []byte("fan failed\x00")}, "fan failed"),
+		Entry("text with the line breaks", Variable{Type: TagOctetString, Value: []byte("line1\r\nline2\t")}, "line1\r\nline2\t"),
+		Entry("binary with NUL in the middle", Variable{Type: TagOctetString, Value: []byte("a\x00b")}, "610062"),
+	)
+
+	DescribeTable("refuses the malformed variable bindings",
+		func(data []byte, expectedErr string) {
+			_, err := decodeVariables(data)
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+		},
+		Entry("not a sequence", octets("x"), "invalid variable bindings"),
+		Entry("the binding is not a sequence", tlv(TagSequence, octets("x")), "invalid variable binding"),
+		Entry("the name is not an oid", tlv(TagSequence, tlv(TagSequence, octets("x"), tlv(TagNull))), "invalid name of the variable binding"),
+		Entry("without the value", tlv(TagSequence, tlv(TagSequence, oid("1.3.6.1"))), "invalid value of 1.3.6.1"),
+		Entry("ip address of 16 bytes", tlv(TagSequence, varbind("1.3.6.1", tlv(TagIPAddress, make([]byte, 16)))), "invalid length 16 of the ip address"),
+		Entry("empty integer", tlv(TagSequence, varbind("1.3.6.1", tlv(TagInteger))), "invalid length 0 of the integer"),
+		Entry("malformed oid value", tlv(TagSequence, varbind("1.3.6.1", tlv(TagOID, []byte{0x81}))), "truncated object identifier"),
+	)
+
+	It("does not panic on the truncated or the changed variable bindings", func() {
+		data := tlv(TagSequence,
+			varbind("1.3.6.1.2.1.1.5.0", octets("bmc-01")),
+			varbind("1.3.6.1.2.1.4.20.1.1", tlv(TagIPAddress, []byte{10, 0, 0, 1})),
+			varbind("1.3.6.1.2.1.1.3.0", tlv(TagTimeTicks, []byte{0x01, 0x00})),
+		)
+		for n := 0; n < len(data); n++ {
+			Expect(func() {
+				_, err := decodeVariables(data[:n])
+				Expect(err).To(HaveOccurred())
+			}).NotTo(Panic())
+		}
+		for i := range data {
+			for _, b := range []byte{0x00, 0x7f, 0x80, 0x84, 0xff} {
+				changed := append([]byte{}, data...)
+				changed[i] = b
+				Expect(func() { _, _ = decodeVariables(changed) }).NotTo(Panic())
+			}
+		}
+	})
+})
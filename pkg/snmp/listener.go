@@ -0,0 +1,126 @@
+// Package snmp receives the SNMP traps sent by the bmc. It implements the decoding of the SNMPv1, SNMPv2c traps and
+// the SNMPv3 traps with the user-based security model, so no snmp library is required.
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultPort = 162
+	// the max size of the udp datagram
+	maxPacketSize = 65535
+)
+
+// ErrListenerClosed is returned by ListenAndServe after Close is called
+var ErrListenerClosed = errors.New("snmp trap listener closed")
+
+// Config holds the credentials to accept the traps, the v1 and v2c traps are accepted when Community is not empty,
+// and the v3 traps are accepted when User is not nil
+type Config struct {
+	Community string
+	User      *User
+}
+
+// Handler is called for each trap, one at a time
+type Handler func(trap *Trap)
+
+// Listener receives the traps on the udp address
+type Listener struct {
+	addr   string
+	config Config
+	log    *zap.SugaredLogger
+
+	mu     sync.Mutex
+	conn   net.PacketConn
+	closed bool
+
+	// keys caches the localized keys of the v3 user for each engine
+	keys map[string]*usmKeys
+}
+
+// NewListener creates the listener for the address such as ":162"
+func NewListener(addr string, config Config, log *zap.SugaredLogger) (*Listener, error) {
+	if len(config.Community) == 0 && config.User == nil {
+		return nil, fmt.Errorf("neither the community nor the v3 user is configured")
+	}
+	if config.User != nil {
+		if err := config.User.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &Listener{
+		addr:   addr,
+		config: config,
+		log:    log,
+		keys:   map[string]*usmKeys{},
+	}, nil
+}
+
+// Addr returns the listening address
+func (l *Listener) Addr() string {
+	return l.addr
+}
+
+// ListenAndServe receives the traps and calls the handler until the listener is closed
+func (l *Listener) ListenAndServe(handler Handler) error {
+	conn, err := net.ListenPacket("udp", l.addr)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		conn.Close()
+		return ErrListenerClosed
+	}
+	l.conn = conn
+	l.mu.Unlock()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			l.mu.Lock()
+			closed := l.closed
+			l.mu.Unlock()
+			if closed {
+				return ErrListenerClosed
+			}
+			return err
+		}
+		udpAddr, ok := addr.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		source := udpAddr.IP
+		if v4 := source.To4(); v4 != nil {
+			source = v4
+		}
+
+		trap, err := l.parseMessage(buf[:n], source)
+		if err != nil {
+			if err != errIgnored {
+				l.log.Debugf("drop the snmp message from %s: %v", addr, err)
+			}
+			continue
+		}
+		handler(trap)
+	}
+}
+
+// Close stops the listener
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	if l.conn != nil {
+		return l.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package snmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/infrastructure-io/topohub/pkg/ipmi"
+)
+
+// the severities follow the redfish events
+const (
+	SeverityOK       = "OK"
+	SeverityWarning  = "Warning"
+	SeverityCritical = "Critical"
+)
+
+// Alert is the trap decoded with the known MIB
+type Alert struct {
+	Severity string
+	// MIB is the name of the MIB which defines the trap, it is empty for the unknown trap
+	MIB     string
+	Message string
+}
+
+// the decoders of the common bmc traps, the trap oid is matched with the prefix
+var decoders = []struct {
+	prefix string
+	decode func(trap *Trap) Alert
+}{
+	{prefix: OIDSnmpTraps, decode: decodeGenericTrap},
+	{prefix: oidPetTraps, decode: decodePetTrap},
+	{prefix: oidIdracTraps, decode: decodeIdracTrap},
+	{prefix: oidHpeTraps, decode: decodeHpeTrap},
+}
+
+// Decode returns the severity and the message of the trap. The unknown trap is regarded as a warning,
+// since the bmc sends the traps for the alerts
+func Decode(trap *Trap) Alert {
+	for _, d := range decoders {
+		if trap.TrapOID == d.prefix || strings.HasPrefix(trap.TrapOID, d.prefix+".") {
+			return d.decode(trap)
+		}
+	}
+	return Alert{Severity: SeverityWarning, Message: fmt.Sprintf("%s%s", trap.TrapOID, variableText(trap))}
+}
+
+// variableText joins the text values of the variables, which usually describe the alert
+func variableText(trap *Trap) string {
+	texts := []string{}
+	for _, v := range trap.Variables {
+		if v.OID == OIDSnmpTrapEnterprise {
+			continue
+		}
+		if b, ok := v.Value.([]byte); ok && len(b) > 0 && isPrintable(b) {
+			texts = append(texts, v.String())
+		}
+	}
+	if len(texts) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(texts, ", ")
+}
+
+// the generic traps of SNMPv2-MIB
+var genericTraps = map[string]struct {
+	name     string
+	severity string
+}{
+	"1": {name: "coldStart", severity: SeverityOK},
+	"2": {name: "warmStart", severity: SeverityOK},
+	"3": {name: "linkDown", severity: SeverityWarning},
+	"4": {name: "linkUp", severity: SeverityOK},
+	"5": {name: "authenticationFailure", severity: SeverityWarning},
+}
+
+func decodeGenericTrap(trap *Trap) Alert {
+	id := strings.TrimPrefix(trap.TrapOID, OIDSnmpTraps+".")
+	t, ok := genericTraps[id]
+	if !ok {
+		return Alert{Severity: SeverityWarning, MIB: "SNMPv2-MIB", Message: trap.TrapOID}
+	}
+	msg := t.name
+	if id == "3" || id == "4" {
+		// ifIndex of IF-MIB
+		if v, ok := trap.Lookup("1.3.6.1.2.1.2.2.1.1"); ok {
+			msg = fmt.Sprintf("%s of the interface %s", t.name, v.String())
+		}
+	}
+	return Alert{Severity: t.severity, MIB: "SNMPv2-MIB", Message: msg}
+}
+
+const (
+	// the Platform Event Trap of IPMI, the specific trap is (sensor type << 16) | (event type << 8) | event offset
+	oidPetTraps = "1.3.6.1.4.1.3183.1.1.0"
+	// the variable which carries the event data of the Platform Event Trap
+	oidPetEvent = "1.3.6.1.4.1.3183.1.1.1"
+	// the positions in the variable of the Platform Event Trap
+	petSeverityIndex     = 24
+	petSensorNumberIndex = 27
+	petEventDataIndex    = 30
+	petDeassertion       = 0x80
+)
+
+// the event severities of the Platform Event Trap
+var petSeverities = map[byte]string{
+	0x01: SeverityOK,
+	0x02: SeverityOK,
+	0x04: SeverityOK,
+	0x08: SeverityWarning,
+	0x10: SeverityCritical,
+	0x20: SeverityCritical,
+}
+
+func decodePetTrap(trap *Trap) Alert {
+	specific, err := strconv.ParseUint(strings.TrimPrefix(trap.TrapOID, oidPetTraps+"."), 10, 32)
+	if err != nil {
+		return Alert{Severity: SeverityWarning, MIB: "PET", Message: trap.TrapOID}
+	}
+	record := ipmi.SELRecord{
+		// the Platform Event Trap is the same as the system event record
+		RecordType:  0x02,
+		SensorType:  byte(specific >> 16),
+		EventType:   byte(specific>>8) & 0x7f,
+		Deassertion: byte(specific)&petDeassertion != 0,
+	}
+	record.EventData[0] = byte(specific) & 0x0f
+	severity := ""
+	if v, ok := trap.Lookup(oidPetEvent); ok {
+		if data, ok := v.Value.([]byte); ok && len(data) >= petEventDataIndex+3 {
+			record.SensorNumber = data[petSensorNumberIndex]
+			copy(record.EventData[:], data[petEventDataIndex:petEventDataIndex+3])
+			severity = petSeverities[data[petSeverityIndex]]
+		}
+	}
+	if len(severity) == 0 {
+		severity = record.Severity()
+	}
+	return Alert{Severity: severity, MIB: "PET", Message: record.Message()}
+}
+
+const (
+	// the traps of IDRAC-MIB-SMIv2 of Dell iDRAC
+	oidIdracTraps = "1.3.6.1.4.1.674.10892.5.3.2"
+	// alertMessageID, alertMessage and alertCurrentStatus of alertVariables
+	oidIdracMessageID = "1.3.6.1.4.1.674.10892.5.3.1.1"
+	oidIdracMessage   = "1.3.6.1.4.1.674.10892.5.3.1.2"
+	oidIdracStatus    = "1.3.6.1.4.1.674.10892.5.3.1.3"
+)
+
+func decodeIdracTrap(trap *Trap) Alert {
+	alert := Alert{Severity: SeverityWarning, MIB: "IDRAC-MIB"}
+	if v, ok := trap.Lookup(oidIdracStatus); ok {
+		// ObjectStatusEnum: other(1), unknown(2), ok(3), nonCritical(4), critical(5), nonRecoverable(6)
+		if status, ok := v.Value.(int64); ok {
+			switch {
+			case status == 3:
+				alert.Severity = SeverityOK
+			case status >= 5:
+				alert.Severity = SeverityCritical
+			}
+		}
+	}
+	messageID := ""
+	if v, ok := trap.Lookup(oidIdracMessageID); ok {
+		messageID = v.String()
+	}
+	if v, ok := trap.Lookup(oidIdracMessage); ok {
+		alert.Message = strings.TrimSpace(fmt.Sprintf("%s %s", messageID, v.String()))
+	} else {
+		alert.Message = trap.TrapOID + variableText(trap)
+	}
+	return alert
+}
+
+const (
+	// the traps of the Compaq MIBs of HPE iLO
+	oidHpeTraps = "1.3.6.1.4.1.232"
+	// cpqHoTrapFlags of CPQHOST-MIB, the bits 2-4 are the condition of the trap
+	oidHpeTrapFlags = "1.3.6.1.4.1.232.11.2.11.1"
+)
+
+func decodeHpeTrap(trap *Trap) Alert {
+	alert := Alert{Severity: SeverityWarning, MIB: "CPQ-MIB", Message: trap.TrapOID + variableText(trap)}
+	if v, ok := trap.Lookup(oidHpeTrapFlags); ok {
+		if flags, ok := v.Value.(int64); ok {
+			// other(1), ok(2), degraded(3), failed(4)
+			switch (flags >> 2) & 0x07 {
+			case 2:
+				alert.Severity = SeverityOK
+			case 4:
+				alert.Severity = SeverityCritical
+			}
+		}
+	}
+	return alert
+}
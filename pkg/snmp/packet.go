@@ -0,0 +1,194 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	VersionV1  = "v1"
+	VersionV2c = "v2c"
+	VersionV3  = "v3"
+
+	snmpVersion1  = 0
+	snmpVersion2c = 1
+	snmpVersion3  = 3
+
+	OIDSysUpTime          = "1.3.6.1.2.1.1.3.0"
+	OIDSnmpTrapOID        = "1.3.6.1.6.3.1.1.4.1.0"
+	OIDSnmpTrapEnterprise = "1.3.6.1.6.3.1.1.4.3.0"
+	// the generic traps of SNMPv2-MIB, such as coldStart and linkDown
+	OIDSnmpTraps = "1.3.6.1.6.3.1.1.5"
+
+	genericTrapEnterpriseSpecific = 6
+)
+
+// errIgnored is returned for the valid message which is not a trap, such as the inform and the report
+var errIgnored = errors.New("not a trap")
+
+// Trap is the notification sent by the agent, the SNMPv1 trap is converted to the SNMPv2 form according to RFC 3584
+type Trap struct {
+	// Version is v1, v2c or v3
+	Version string
+	// Source is the address which sends the trap
+	Source net.IP
+	// Community is set for v1 and v2c, and User is set for v3
+	Community string
+	User      string
+	TrapOID   string
+	// Uptime is the sysUpTime of the agent in hundredths of a second
+	Uptime uint64
+	// Variables excludes sysUpTime.0 and snmpTrapOID.0
+	Variables []Variable
+}
+
+// Lookup returns the first variable which is the oid or an instance of the oid
+func (t *Trap) Lookup(oid string) (Variable, bool) {
+	for _, v := range t.Variables {
+		if v.OID == oid || strings.HasPrefix(v.OID, oid+".") {
+			return v, true
+		}
+	}
+	return Variable{}, false
+}
+
+// parseMessage decodes the message received from the source
+func (l *Listener) parseMessage(data []byte, source net.IP) (*Trap, error) {
+	msg, trailing, err := expectTLV(data, TagSequence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snmp message: %v", err)
+	}
+	version, rest, err := readInt(msg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snmp version: %v", err)
+	}
+
+	var trap *Trap
+	switch version {
+	case snmpVersion1, snmpVersion2c:
+		community, pdu, err := readOctetString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid community: %v", err)
+		}
+		if len(l.config.Community) == 0 || string(community) != l.config.Community {
+			return nil, fmt.Errorf("unknown community of the snmp %s message", versionName(version))
+		}
+		trap, err = parsePDU(pdu)
+		if err != nil {
+			return nil, err
+		}
+		trap.Community = string(community)
+	case snmpVersion3:
+		trap, err = l.parseV3(data[:len(data)-len(trailing)], rest)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported snmp version %d", version)
+	}
+	trap.Version = versionName(version)
+	trap.Source = source
+	return trap, nil
+}
+
+func versionName(version int64) string {
+	switch version {
+	case snmpVersion1:
+		return VersionV1
+	case snmpVersion2c:
+		return VersionV2c
+	default:
+		return VersionV3
+	}
+}
+
+// parsePDU decodes the SNMPv1 trap or the SNMPv2 trap
+func parsePDU(data []byte) (*Trap, error) {
+	tag, pdu, _, err := readTLV(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pdu: %v", err)
+	}
+	switch tag {
+	case pduTrapV1:
+		return parseTrapV1(pdu)
+	case pduTrapV2:
+		return parseTrapV2(pdu)
+	case pduInform, pduReport:
+		return nil, errIgnored
+	default:
+		return nil, fmt.Errorf("unexpected pdu type 0x%02x", tag)
+	}
+}
+
+func parseTrapV2(pdu []byte) (*Trap, error) {
+	// request-id, error-status and error-index
+	rest := pdu
+	for i := 0; i < 3; i++ {
+		var err error
+		if _, rest, err = readInt(rest); err != nil {
+			return nil, fmt.Errorf("invalid header of the trap: %v", err)
+		}
+	}
+	variables, err := decodeVariables(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	trap := &Trap{}
+	for _, v := range variables {
+		switch v.OID {
+		case OIDSysUpTime:
+			trap.Uptime, _ = v.Value.(uint64)
+		case OIDSnmpTrapOID:
+			trap.TrapOID, _ = v.Value.(string)
+		default:
+			trap.Variables = append(trap.Variables, v)
+		}
+	}
+	if len(trap.TrapOID) == 0 {
+		return nil, fmt.Errorf("the trap has no snmpTrapOID")
+	}
+	return trap, nil
+}
+
+func parseTrapV1(pdu []byte) (*Trap, error) {
+	enterprise, rest, err := readOID(pdu)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enterprise of the trap: %v", err)
+	}
+	// the agent address is not used, the source address of the packet is trusted
+	if _, rest, err = expectTLV(rest, TagIPAddress); err != nil {
+		return nil, fmt.Errorf("invalid agent address of the trap: %v", err)
+	}
+	generic, rest, err := readInt(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generic trap: %v", err)
+	}
+	specific, rest, err := readInt(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid specific trap: %v", err)
+	}
+	timestamp, rest, err := expectTLV(rest, TagTimeTicks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp of the trap: %v", err)
+	}
+	uptime, err := decodeUint(timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp of the trap: %v", err)
+	}
+	variables, err := decodeVariables(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	trap := &Trap{Uptime: uptime, Variables: variables}
+	if generic == genericTrapEnterpriseSpecific {
+		trap.TrapOID = fmt.Sprintf("%s.0.%d", enterprise, specific)
+	} else {
+		trap.TrapOID = fmt.Sprintf("%s.%d", OIDSnmpTraps, generic+1)
+	}
+	trap.Variables = append(trap.Variables, Variable{OID: OIDSnmpTrapEnterprise, Type: TagOID, Value: enterprise})
+	return trap, nil
+}
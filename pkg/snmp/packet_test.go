@@ -0,0 +1,221 @@
+package snmp
+
+import (
+	"math/rand"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+const (
+	// the trap of the failed fan in IDRAC-MIB-SMIv2
+	testTrapOID = "1.3.6.1.4.1.674.10892.5.3.2.5.0.2102"
+	testMessage = "1.3.6.1.4.1.674.10892.5.3.1.2.0"
+)
+
+var testSource = net.IP{192, 168, 0, 10}
+
+func newListener(config Config) *Listener {
+	l, err := NewListener(":0", config, zap.NewNop().Sugar())
+	Expect(err).NotTo(HaveOccurred())
+	return l
+}
+
+// trapV2 builds the SNMPv2 trap pdu with sysUpTime.0, snmpTrapOID.0 and the variables
+func trapV2(trapOID string, variables ...[]byte) []byte {
+	list := append([][]byte{
+		varbind(OIDSysUpTime, tlv(TagTimeTicks, []byte{0x01, 0x00})),
+		varbind(OIDSnmpTrapOID, oid(trapOID)),
+	}, variables...)
+	return tlv(pduTrapV2, integer(1), integer(0), integer(0), tlv(TagSequence, list...))
+}
+
+// message builds the SNMPv1 or SNMPv2c message of the pdu
+func message(version int64, community string, pdu []byte) []byte {
+	return tlv(TagSequence, integer(version), octets(community), pdu)
+}
+
+var _ = Describe("trap", Label("unitest"), func() {
+	Context("v1 and v2c", func() {
+		var l *Listener
+
+		BeforeEach(func() {
+			l = newListener(Config{Community: "public"})
+		})
+
+		It("decodes the v2c trap", func() {
+			data := message(snmpVersion2c, "public", trapV2(testTrapOID, varbind(testMessage, octets("The fan 1 failed."))))
+			trap, err := l.parseMessage(data, testSource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trap).To(Equal(&Trap{
+				Version:   VersionV2c,
+				Source:    testSource,
+				Community: "public",
+				TrapOID:   testTrapOID,
+				Uptime:    256,
+				Variables: []Variable{{OID: testMessage, Type: TagOctetString, Value: []byte("The fan 1 failed.")}},
+			}))
+			v, ok := trap.Lookup("1.3.6.1.4.1.674.10892.5.3.1.2")
+			Expect(ok).To(BeTrue())
+			Expect(v.String()).To(Equal("The fan 1 failed."))
+			_, ok = trap.Lookup("1.3.6.1.4.1.674.10892.5.3.1.2.0.1")
+			Expect(ok).To(BeFalse())
+		})
+
+		DescribeTable("converts the v1 trap to the v2 form",
+			func(generic, specific int64, trapOID string) {
+				pdu := tlv(pduTrapV1,
+					oid("1.3.6.1.4.1.674.10892.5"),
+					tlv(TagIPAddress, []byte{10, 0, 0, 1}),
+					integer(generic),
+					integer(specific),
+					tlv(TagTimeTicks, []byte{0x00, 0x80}),
+					tlv(TagSequence, varbind(testMessage, octets("linkDown"))),
+				)
+				trap, err := l.parseMessage(message(snmpVersion1, "public", pdu), testSource)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trap.Version).To(Equal(VersionV1))
+				Expect(trap.TrapOID).To(Equal(trapOID))
+				Expect(trap.Uptime).To(Equal(uint64(128)))
+				Expect(trap.Variables).To(HaveLen(2))
+				v, ok := trap.Lookup(OIDSnmpTrapEnterprise)
+				Expect(ok).To(BeTrue())
+				Expect(v.Value).To(Equal("1.3.6.1.4.1.674.10892.5"))
+			},
+			Entry("generic trap", int64(2), int64(0), "1.3.6.1.6.3.1.1.5.3"),
+			Entry("enterprise specific trap", int64(6), int64(2102), "1.3.6.1.4.1.674.10892.5.0.2102"),
+		)
+
+		DescribeTable("refuses the message",
+			func(data []byte, expectedErr interface{}) {
+				_, err := l.parseMessage(data, testSource)
+				Expect(err).To(MatchError(expectedErr))
+			},
+			Entry("of the wrong community", message(snmpVersion2c, "private", trapV2(testTrapOID)), "unknown community of the snmp v2c message"),
+			Entry("of the unknown version", message(2, "public", trapV2(testTrapOID)), "unsupported snmp version 2"),
+			Entry("of v3 without the user", tlv(TagSequence, integer(snmpVersion3), tlv(TagSequence)), ContainSubstring("invalid msgGlobalData")),
+			Entry("without snmpTrapOID", message(snmpVersion2c, "public",
+				tlv(pduTrapV2, integer(1), integer(0), integer(0), tlv(TagSequence, varbind(OIDSysUpTime, tlv(TagTimeTicks, []byte{1}))))),
+				"the trap has no snmpTrapOID"),
+			Entry("of the get request", message(snmpVersion2c, "public", tlv(0xa0, integer(1))), "unexpected pdu type 0xa0"),
+			Entry("of the inform", message(snmpVersion2c, "public", tlv(pduInform, integer(1))), errIgnored),
+			Entry("not a sequence", octets("public"), ContainSubstring("invalid snmp message")),
+		)
+	})
+
+	Context("v3", func() {
+		DescribeTable("authenticates and decrypts the trap",
+			func(user User, flags byte) {
+				l := newListener(Config{User: &user})
+				data := messageV3(&user, user.Name, flags, trapV2(testTrapOID, varbind(testMessage, octets("The fan 1 failed."))))
+				trap, err := l.parseMessage(data, testSource)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trap.Version).To(Equal(VersionV3))
+				Expect(trap.User).To(Equal(user.Name))
+				Expect(trap.Community).To(BeEmpty())
+				Expect(trap.TrapOID).To(Equal(testTrapOID))
+				Expect(trap.Variables).To(Equal([]Variable{{OID: testMessage, Type: TagOctetString, Value: []byte("The fan 1 failed.")}}))
+			},
+			Entry("authNoPriv with MD5", User{Name: "admin", AuthProtocol: AuthMD5, AuthPassword: "authpass"}, byte(flagAuth)),
+			Entry("authNoPriv with SHA", User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass"}, byte(flagAuth)),
+			Entry("authNoPriv with SHA256", User{Name: "admin", AuthProtocol: AuthSHA256, AuthPassword: "authpass"}, byte(flagAuth)),
+			Entry("authPriv with SHA and AES", User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "privpass"}, byte(flagAuth|flagPriv)),
+			Entry("authPriv with MD5 and DES", User{Name: "admin", AuthProtocol: AuthMD5, AuthPassword: "authpass", PrivProtocol: PrivDES, PrivPassword: "privpass"}, byte(flagAuth|flagPriv)),
+			// the reportable flag is ignored
+			Entry("authPriv with the reportable flag", User{Name: "admin", AuthProtocol: AuthSHA256, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "privpass"}, byte(flagAuth|flagPriv|0x04)),
+		)
+
+		user := User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "privpass"}
+		authFlags := byte(flagAuth | flagPriv)
+
+		It("authenticates the message followed by the trailing bytes of the datagram", func() {
+			l := newListener(Config{User: &user})
+			data := append(messageV3(&user, user.Name, authFlags, trapV2(testTrapOID)), 0x00, 0x00, 0x00)
+			trap, err := l.parseMessage(data, testSource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trap.TrapOID).To(Equal(testTrapOID))
+		})
+
+		It("ignores the v3 inform", func() {
+			l := newListener(Config{User: &user})
+			_, err := l.parseMessage(messageV3(&user, user.Name, authFlags, tlv(pduInform, integer(1))), testSource)
+			Expect(err).To(Equal(errIgnored))
+		})
+
+		It("refuses the changed message", func() {
+			l := newListener(Config{User: &user})
+			data := messageV3(&user, user.Name, authFlags, trapV2(testTrapOID))
+			// the last byte of the encrypted pdu
+			data[len(data)-1] ^= 0x01
+			_, err := l.parseMessage(data, testSource)
+			Expect(err).To(MatchError("wrong digest of the snmp v3 message from the user admin"))
+		})
+
+		DescribeTable("refuses the message of the other security",
+			func(data []byte, expectedErr interface{}) {
+				l := newListener(Config{User: &user})
+				_, err := l.parseMessage(data, testSource)
+				Expect(err).To(MatchError(expectedErr))
+			},
+			Entry("unknown user", messageV3(&user, "guest", authFlags, trapV2(testTrapOID)), `unknown snmp user "guest"`),
+			Entry("noAuthNoPriv", messageV3(&user, user.Name, 0, trapV2(testTrapOID)), "unexpected security level 0x00 of the snmp user admin"),
+			Entry("authNoPriv of the authPriv user", messageV3(&user, user.Name, flagAuth, trapV2(testTrapOID)), "unexpected security level 0x01 of the snmp user admin"),
+			Entry("wrong authentication password",
+				messageV3(&User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "wrongpass", PrivProtocol: PrivAES, PrivPassword: "privpass"}, "admin", authFlags, trapV2(testTrapOID)),
+				"wrong digest of the snmp v3 message from the user admin"),
+			Entry("wrong authentication protocol",
+				messageV3(&User{Name: "admin", AuthProtocol: AuthSHA256, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "privpass"}, "admin", authFlags, trapV2(testTrapOID)),
+				"invalid length 24 of msgAuthenticationParameters"),
+			Entry("wrong privacy password",
+				messageV3(&User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "wrongpass"}, "admin", authFlags, trapV2(testTrapOID)),
+				// the decrypted pdu is random
+				ContainSubstring("invalid scoped pdu")),
+		)
+
+		It("refuses the message of the other security model", func() {
+			l := newListener(Config{User: &user})
+			data := tlv(TagSequence,
+				integer(snmpVersion3),
+				tlv(TagSequence, integer(100), integer(65507), tlv(TagOctetString, []byte{flagAuth}), integer(2)),
+				octets(""),
+			)
+			_, err := l.parseMessage(data, testSource)
+			Expect(err).To(MatchError("unsupported security model of the snmp v3 message"))
+		})
+
+		It("does not panic on the truncated or the changed message", func() {
+			l := newListener(Config{Community: "public", User: &user})
+			pdu := trapV2(testTrapOID, varbind(testMessage, octets("The fan 1 failed.")))
+			for _, data := range [][]byte{
+				messageV3(&user, user.Name, authFlags, pdu),
+				message(snmpVersion2c, "public", pdu),
+			} {
+				for n := 0; n < len(data); n++ {
+					Expect(func() {
+						_, err := l.parseMessage(data[:n], testSource)
+						Expect(err).To(HaveOccurred())
+					}).NotTo(Panic())
+				}
+				for i := range data {
+					for _, b := range []byte{0x00, 0x7f, 0x80, 0x84, 0xff} {
+						changed := append([]byte{}, data...)
+						changed[i] = b
+						Expect(func() { _, _ = l.parseMessage(changed, testSource) }).NotTo(Panic())
+					}
+				}
+			}
+
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 5000; i++ {
+				data := make([]byte, r.Intn(128))
+				r.Read(data)
+				if len(data) > 0 && r.Intn(2) == 0 {
+					data[0] = TagSequence
+				}
+				Expect(func() { _, _ = l.parseMessage(data, testSource) }).NotTo(Panic())
+			}
+		})
+	})
+})
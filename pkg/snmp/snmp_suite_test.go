@@ -0,0 +1,13 @@
+package snmp
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSnmp(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Snmp Suite")
+}
@@ -0,0 +1,300 @@
+package snmp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// the protocols of the user-based security model
+const (
+	AuthMD5    = "MD5"
+	AuthSHA    = "SHA"
+	AuthSHA256 = "SHA256"
+	PrivDES    = "DES"
+	PrivAES    = "AES"
+
+	securityModelUSM = 3
+	flagAuth         = 0x01
+	flagPriv         = 0x02
+	// the size of the password expanded for the key, according to RFC 3414 A.2
+	passwordExpandSize = 1048576
+	// the localized keys of the engines are cached, the cache is cleared when it is too large
+	maxCachedEngines = 4096
+)
+
+// User is the SNMPv3 user which the agents use to send the traps. The trap is sent by the authoritative engine,
+// so the keys are localized with the engine id of each agent
+type User struct {
+	Name string
+	// AuthProtocol is MD5, SHA or SHA256
+	AuthProtocol string
+	AuthPassword string
+	// PrivProtocol is empty for authNoPriv, or DES or AES
+	PrivProtocol string
+	PrivPassword string
+}
+
+// Validate checks the protocols and the passwords of the user
+func (u *User) Validate() error {
+	if len(u.Name) == 0 {
+		return fmt.Errorf("the name of the snmp user is empty")
+	}
+	if u.hash() == nil {
+		return fmt.Errorf("unsupported snmp authentication protocol %q", u.AuthProtocol)
+	}
+	// RFC 3414 requires at least 8 characters
+	if len(u.AuthPassword) < 8 {
+		return fmt.Errorf("the authentication password of the snmp user must have at least 8 characters")
+	}
+	switch u.PrivProtocol {
+	case "":
+	case PrivDES, PrivAES:
+		if len(u.PrivPassword) < 8 {
+			return fmt.Errorf("the privacy password of the snmp user must have at least 8 characters")
+		}
+	default:
+		return fmt.Errorf("unsupported snmp privacy protocol %q", u.PrivProtocol)
+	}
+	return nil
+}
+
+func (u *User) hash() func() hash.Hash {
+	switch u.AuthProtocol {
+	case AuthMD5:
+		return md5.New
+	case AuthSHA:
+		return sha1.New
+	case AuthSHA256:
+		return sha256.New
+	}
+	return nil
+}
+
+// macLen is the length of the truncated hmac in msgAuthenticationParameters
+func (u *User) macLen() int {
+	if u.AuthProtocol == AuthSHA256 {
+		// usmHMAC192SHA256AuthProtocol of RFC 7860
+		return 24
+	}
+	return 12
+}
+
+type usmKeys struct {
+	auth []byte
+	priv []byte
+}
+
+// localizedKeys returns the keys of the user for the engine
+func (l *Listener) localizedKeys(engineID []byte) *usmKeys {
+	if keys, ok := l.keys[string(engineID)]; ok {
+		return keys
+	}
+	if len(l.keys) >= maxCachedEngines {
+		l.keys = map[string]*usmKeys{}
+	}
+	user := l.config.User
+	keys := &usmKeys{auth: localizeKey(user.hash(), user.AuthPassword, engineID)}
+	if len(user.PrivProtocol) > 0 {
+		keys.priv = localizeKey(user.hash(), user.PrivPassword, engineID)
+	}
+	l.keys[string(engineID)] = keys
+	return keys
+}
+
+// localizeKey converts the password to the key, and localizes it with the engine id, according to RFC 3414 A.2
+func localizeKey(h func() hash.Hash, password string, engineID []byte) []byte {
+	d := h()
+	buf := make([]byte, 64)
+	index := 0
+	for count := 0; count < passwordExpandSize; count += len(buf) {
+		for i := range buf {
+			buf[i] = password[index%len(password)]
+			index++
+		}
+		d.Write(buf)
+	}
+	ku := d.Sum(nil)
+
+	d.Reset()
+	d.Write(ku)
+	d.Write(engineID)
+	d.Write(ku)
+	return d.Sum(nil)
+}
+
+// usmParameters is UsmSecurityParameters of RFC 3414
+type usmParameters struct {
+	engineID   []byte
+	boots      int64
+	engineTime int64
+	userName   []byte
+	authParams []byte
+	privParams []byte
+	// authOffset is the offset of the value of msgAuthenticationParameters in msgSecurityParameters
+	authOffset int
+}
+
+// decodeUSM decodes msgSecurityParameters, and locates msgAuthenticationParameters which is zeroed to compute the mac
+func decodeUSM(securityParameters []byte) (*usmParameters, error) {
+	usm, trailing, err := expectTLV(securityParameters, TagSequence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid usm security parameters: %v", err)
+	}
+	// the offset of a value is counted back from the end of the sequence by the length of the value and what follows it
+	end := len(securityParameters) - len(trailing)
+	p := &usmParameters{}
+	if p.engineID, usm, err = readOctetString(usm); err != nil {
+		return nil, fmt.Errorf("invalid msgAuthoritativeEngineID: %v", err)
+	}
+	if p.boots, usm, err = readInt(usm); err != nil {
+		return nil, fmt.Errorf("invalid msgAuthoritativeEngineBoots: %v", err)
+	}
+	if p.engineTime, usm, err = readInt(usm); err != nil {
+		return nil, fmt.Errorf("invalid msgAuthoritativeEngineTime: %v", err)
+	}
+	if p.userName, usm, err = readOctetString(usm); err != nil {
+		return nil, fmt.Errorf("invalid msgUserName: %v", err)
+	}
+	if p.authParams, usm, err = readOctetString(usm); err != nil {
+		return nil, fmt.Errorf("invalid msgAuthenticationParameters: %v", err)
+	}
+	p.authOffset = end - len(usm) - len(p.authParams)
+	if p.privParams, _, err = readOctetString(usm); err != nil {
+		return nil, fmt.Errorf("invalid msgPrivacyParameters: %v", err)
+	}
+	return p, nil
+}
+
+// parseV3 authenticates and decrypts the SNMPv3 message. message is the whole message without the trailing bytes of
+// the datagram, and rest follows msgVersion
+func (l *Listener) parseV3(message []byte, rest []byte) (*Trap, error) {
+	globalData, rest, err := expectTLV(rest, TagSequence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid msgGlobalData: %v", err)
+	}
+	// msgID and msgMaxSize
+	for i := 0; i < 2; i++ {
+		if _, globalData, err = readInt(globalData); err != nil {
+			return nil, fmt.Errorf("invalid msgGlobalData: %v", err)
+		}
+	}
+	flags, globalData, err := readOctetString(globalData)
+	if err != nil || len(flags) != 1 {
+		return nil, fmt.Errorf("invalid msgFlags")
+	}
+	model, _, err := readInt(globalData)
+	if err != nil || model != securityModelUSM {
+		return nil, fmt.Errorf("unsupported security model of the snmp v3 message")
+	}
+
+	securityParameters, msgData, err := readOctetString(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid msgSecurityParameters: %v", err)
+	}
+	// msgData is the last field of the message
+	securityOffset := len(message) - len(msgData) - len(securityParameters)
+	usm, err := decodeUSM(securityParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	user := l.config.User
+	if user == nil || string(usm.userName) != user.Name {
+		return nil, fmt.Errorf("unknown snmp user %q", string(usm.userName))
+	}
+	// only accept the messages with the security level of the user
+	if flags[0]&flagAuth == 0 || (flags[0]&flagPriv != 0) != (len(user.PrivProtocol) > 0) {
+		return nil, fmt.Errorf("unexpected security level 0x%02x of the snmp user %s", flags[0], user.Name)
+	}
+	if len(usm.engineID) == 0 {
+		return nil, fmt.Errorf("empty msgAuthoritativeEngineID")
+	}
+
+	if len(usm.authParams) != user.macLen() {
+		return nil, fmt.Errorf("invalid length %d of msgAuthenticationParameters", len(usm.authParams))
+	}
+	keys := l.localizedKeys(usm.engineID)
+	// the mac is computed with the zeroed msgAuthenticationParameters
+	offset := securityOffset + usm.authOffset
+	whole := append([]byte{}, message...)
+	for i := offset; i < offset+len(usm.authParams); i++ {
+		whole[i] = 0
+	}
+	mac := hmac.New(user.hash(), keys.auth)
+	mac.Write(whole)
+	if !hmac.Equal(mac.Sum(nil)[:len(usm.authParams)], usm.authParams) {
+		return nil, fmt.Errorf("wrong digest of the snmp v3 message from the user %s", user.Name)
+	}
+
+	scoped := msgData
+	if flags[0]&flagPriv != 0 {
+		encrypted, _, err := readOctetString(msgData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryptedPDU: %v", err)
+		}
+		if scoped, err = decrypt(user.PrivProtocol, keys.priv, uint32(usm.boots), uint32(usm.engineTime), usm.privParams, encrypted); err != nil {
+			return nil, err
+		}
+	}
+	// contextEngineID, contextName and the pdu
+	pdu, _, err := expectTLV(scoped, TagSequence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoped pdu: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, pdu, err = readOctetString(pdu); err != nil {
+			return nil, fmt.Errorf("invalid scoped pdu: %v", err)
+		}
+	}
+	trap, err := parsePDU(pdu)
+	if err != nil {
+		return nil, err
+	}
+	trap.User = user.Name
+	return trap, nil
+}
+
+func decrypt(protocol string, key []byte, boots, engineTime uint32, salt []byte, encrypted []byte) ([]byte, error) {
+	if len(salt) != 8 {
+		return nil, fmt.Errorf("invalid length %d of msgPrivacyParameters", len(salt))
+	}
+	plain := make([]byte, len(encrypted))
+	switch protocol {
+	case PrivAES:
+		// RFC 3826
+		block, err := aes.NewCipher(key[:16])
+		if err != nil {
+			return nil, err
+		}
+		iv := make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint32(iv[0:4], boots)
+		binary.BigEndian.PutUint32(iv[4:8], engineTime)
+		copy(iv[8:], salt)
+		cipher.NewCFBDecrypter(block, iv).XORKeyStream(plain, encrypted)
+	case PrivDES:
+		// RFC 3414 8.1.1
+		if len(encrypted)%des.BlockSize != 0 {
+			return nil, fmt.Errorf("invalid length %d of the des encrypted pdu", len(encrypted))
+		}
+		block, err := des.NewCipher(key[:8])
+		if err != nil {
+			return nil, err
+		}
+		iv := make([]byte, des.BlockSize)
+		for i := range iv {
+			iv[i] = key[8+i] ^ salt[i]
+		}
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, encrypted)
+	default:
+		return nil, fmt.Errorf("unsupported snmp privacy protocol %q", protocol)
+	}
+	return plain, nil
+}
@@ -0,0 +1,179 @@
+package snmp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	testEngineID = []byte{0x80, 0x00, 0x02, 0xb8, 0x04, 'b', 'm', 'c'}
+	testSalt     = []byte{0, 0, 0, 0, 0x12, 0x34, 0x56, 0x78}
+)
+
+const (
+	testBoots      = 3
+	testEngineTime = 86400
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	Expect(err).NotTo(HaveOccurred())
+	return b
+}
+
+// encrypt encrypts the scoped pdu of the user as the agent does, according to RFC 3826 and RFC 3414 8.1.1
+func encrypt(user *User, scoped []byte) []byte {
+	key := localizeKey(user.hash(), user.PrivPassword, testEngineID)
+	if user.PrivProtocol == PrivAES {
+		block, err := aes.NewCipher(key[:16])
+		Expect(err).NotTo(HaveOccurred())
+		iv := make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint32(iv[0:4], testBoots)
+		binary.BigEndian.PutUint32(iv[4:8], testEngineTime)
+		copy(iv[8:], testSalt)
+		encrypted := make([]byte, len(scoped))
+		cipher.NewCFBEncrypter(block, iv).XORKeyStream(encrypted, scoped)
+		return encrypted
+	}
+	// the scoped pdu is padded to the block, the padding follows the sequence and is ignored
+	for len(scoped)%des.BlockSize != 0 {
+		scoped = append(scoped, 0)
+	}
+	block, err := des.NewCipher(key[:8])
+	Expect(err).NotTo(HaveOccurred())
+	iv := make([]byte, des.BlockSize)
+	for i := range iv {
+		iv[i] = key[8+i] ^ testSalt[i]
+	}
+	encrypted := make([]byte, len(scoped))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, scoped)
+	return encrypted
+}
+
+// messageV3 builds the SNMPv3 message of the pdu with the security level of the user, and authenticates it
+func messageV3(user *User, userName string, flags byte, pdu []byte) []byte {
+	scoped := tlv(TagSequence, tlv(TagOctetString, testEngineID), octets(""), pdu)
+	msgData := scoped
+	privParams := []byte{}
+	if flags&flagPriv != 0 {
+		msgData = tlv(TagOctetString, encrypt(user, scoped))
+		privParams = testSalt
+	}
+	build := func(authParams []byte) []byte {
+		return tlv(TagSequence,
+			integer(snmpVersion3),
+			tlv(TagSequence, integer(100), integer(65507), tlv(TagOctetString, []byte{flags}), integer(securityModelUSM)),
+			tlv(TagOctetString, tlv(TagSequence,
+				tlv(TagOctetString, testEngineID),
+				integer(testBoots),
+				integer(testEngineTime),
+				octets(userName),
+				tlv(TagOctetString, authParams),
+				tlv(TagOctetString, privParams),
+			)),
+			msgData,
+		)
+	}
+	// the mac is computed with the zeroed msgAuthenticationParameters, which has the same length as the mac
+	mac := hmac.New(user.hash(), localizeKey(user.hash(), user.AuthPassword, testEngineID))
+	mac.Write(build(make([]byte, user.macLen())))
+	return build(mac.Sum(nil)[:user.macLen()])
+}
+
+var _ = Describe("USM", Label("unitest"), func() {
+	DescribeTable("localizes the key of the password, according to RFC 3414 A.3",
+		func(h func() hash.Hash, expected string) {
+			engineID := unhex("000000000000000000000002")
+			Expect(hex.EncodeToString(localizeKey(h, "maplesyrup", engineID))).To(Equal(expected))
+		},
+		Entry("A.3.1 MD5", md5.New, "526f5eed9fcce26f8964c2930787d82b"),
+		Entry("A.3.2 SHA", sha1.New, "6695febc9288e36282235fc7151f128497b38f3f"),
+	)
+
+	It("caches the localized keys of each engine", func() {
+		l := newListener(Config{User: &User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "privpass"}})
+		keys := l.localizedKeys(testEngineID)
+		Expect(keys.auth).To(Equal(localizeKey(sha1.New, "authpass", testEngineID)))
+		Expect(keys.priv).To(Equal(localizeKey(sha1.New, "privpass", testEngineID)))
+		Expect(l.localizedKeys(testEngineID)).To(BeIdenticalTo(keys))
+		Expect(l.localizedKeys([]byte{0x80, 0x00, 0x00, 0x01})).NotTo(BeIdenticalTo(keys))
+		Expect(l.keys).To(HaveLen(2))
+	})
+
+	// the vectors of CFB128-AES128 of NIST SP 800-38A F.3.14, whose IV is taken as msgAuthoritativeEngineBoots
+	// 0x00010203, msgAuthoritativeEngineTime 0x04050607 and the salt 08090a0b0c0d0e0f of RFC 3826 3.1.2.1
+	It("decrypts the pdu with AES in the CFB mode", func() {
+		key := unhex("2b7e151628aed2a6abf7158809cf4f3c")
+		// only the first 16 bytes of the localized key of SHA are used
+		key = append(key, 0xff, 0xff, 0xff, 0xff)
+		plain, err := decrypt(PrivAES, key, 0x00010203, 0x04050607, unhex("08090a0b0c0d0e0f"),
+			unhex("3b3fd92eb72dad20333449f8e83cfb4ac8a64537a0b3a93fcde3cdad9f1ce58b26751f"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hex.EncodeToString(plain)).To(Equal("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c"))
+	})
+
+	It("decrypts the pdu with DES in the CBC mode", func() {
+		user := &User{Name: "admin", AuthProtocol: AuthMD5, AuthPassword: "authpass", PrivProtocol: PrivDES, PrivPassword: "privpass"}
+		scoped := tlv(TagSequence, octets("0123456789abcdef"))
+		encrypted := encrypt(user, scoped)
+		key := localizeKey(md5.New, "privpass", testEngineID)
+		plain, err := decrypt(PrivDES, key, testBoots, testEngineTime, testSalt, encrypted)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plain[:len(scoped)]).To(Equal(scoped))
+	})
+
+	DescribeTable("refuses the invalid encrypted pdu",
+		func(protocol string, salt, encrypted []byte, expectedErr string) {
+			_, err := decrypt(protocol, make([]byte, 16), 0, 0, salt, encrypted)
+			Expect(err).To(MatchError(expectedErr))
+		},
+		Entry("short salt", PrivAES, []byte{1, 2, 3}, make([]byte, 16), "invalid length 3 of msgPrivacyParameters"),
+		Entry("des pdu not in the blocks", PrivDES, testSalt, make([]byte, 12), "invalid length 12 of the des encrypted pdu"),
+		Entry("unknown protocol", "3DES", testSalt, make([]byte, 16), `unsupported snmp privacy protocol "3DES"`),
+	)
+
+	DescribeTable("validates the user",
+		func(user User, expectedErr string) {
+			err := user.Validate()
+			if len(expectedErr) == 0 {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			}
+		},
+		Entry("authNoPriv", User{Name: "admin", AuthProtocol: AuthSHA256, AuthPassword: "authpass"}, ""),
+		Entry("authPriv", User{Name: "admin", AuthProtocol: AuthMD5, AuthPassword: "authpass", PrivProtocol: PrivDES, PrivPassword: "privpass"}, ""),
+		Entry("without the name", User{AuthProtocol: AuthSHA, AuthPassword: "authpass"}, "the name of the snmp user is empty"),
+		Entry("unknown authentication protocol", User{Name: "admin", AuthProtocol: "SHA512", AuthPassword: "authpass"}, `unsupported snmp authentication protocol "SHA512"`),
+		Entry("short authentication password", User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "short"}, "at least 8 characters"),
+		Entry("short privacy password", User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass", PrivProtocol: PrivAES, PrivPassword: "short"}, "at least 8 characters"),
+		Entry("unknown privacy protocol", User{Name: "admin", AuthProtocol: AuthSHA, AuthPassword: "authpass", PrivProtocol: "AES256", PrivPassword: "privpass"}, `unsupported snmp privacy protocol "AES256"`),
+	)
+
+	It("locates msgAuthenticationParameters in the security parameters", func() {
+		authParams := []byte{0xaa, 0xbb, 0xcc}
+		securityParameters := tlv(TagSequence,
+			tlv(TagOctetString, testEngineID), integer(testBoots), integer(testEngineTime), octets("admin"),
+			tlv(TagOctetString, authParams), tlv(TagOctetString, testSalt))
+		// the bytes after the sequence are ignored
+		securityParameters = append(securityParameters, 0x00, 0x00)
+		usm, err := decodeUSM(securityParameters)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(usm.engineID).To(Equal(testEngineID))
+		Expect(usm.boots).To(Equal(int64(testBoots)))
+		Expect(usm.engineTime).To(Equal(int64(testEngineTime)))
+		Expect(usm.userName).To(Equal([]byte("admin")))
+		Expect(usm.privParams).To(Equal(testSalt))
+		Expect(securityParameters[usm.authOffset : usm.authOffset+len(authParams)]).To(Equal(authParams))
+	})
+})
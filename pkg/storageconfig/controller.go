@@ -0,0 +1,416 @@
+package storageconfig
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+)
+
+// the BMC may create or delete the volume after the reboot, so the action is not requested again until the timeout
+const volumeRequestTimeout = 30 * time.Minute
+
+// StorageConfigController reconciles the volumes of the hosts to the desired ones in the StorageConfig
+type StorageConfigController struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	agentConfig *config.AgentConfig
+	log         *zap.SugaredLogger
+}
+
+func NewStorageConfigController(mgr ctrl.Manager, agentConfig *config.AgentConfig) (*StorageConfigController, error) {
+	return &StorageConfigController{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		agentConfig: agentConfig,
+		log:         log.Logger.Named("StorageConfigController"),
+	}, nil
+}
+
+// 只有 leader 才会执行 Reconcile
+// Reconcile checks the volumes of every selected host, and it is requeued at the interval of updating the HostStatus
+func (r *StorageConfigController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.log.With("storageconfig", req.Name)
+	logger.Debugf("Starting reconcile for StorageConfig %s", req.Name)
+
+	storageConfig := &topohubv1beta1.StorageConfig{}
+	if err := r.Get(ctx, req.NamespacedName, storageConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	interval := time.Duration(r.agentConfig.RedfishHostStatusUpdateInterval) * time.Second
+
+	// 记录上一次的状态，已经下发的操作在超时前不再重复下发
+	previous := map[string]topohubv1beta1.StorageHostStatus{}
+	for _, item := range storageConfig.Status.Hosts {
+		previous[item.HostStatusName+"/"+item.SystemId] = item
+	}
+
+	hosts, missing, err := r.selectHosts(ctx, storageConfig)
+	if err != nil {
+		logger.Errorf("Failed to select hosts: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	status := topohubv1beta1.StorageConfigStatus{}
+	for _, name := range missing {
+		status.Hosts = append(status.Hosts, topohubv1beta1.StorageHostStatus{
+			HostStatusName: name,
+			State:          topohubv1beta1.StorageStateFailed,
+			Message:        fmt.Sprintf("hostStatus %s is not found", name),
+		})
+	}
+	for i := range hosts {
+		// the storage belongs to the ComputerSystem, so check every system of a multi-node host
+		systemIds := []string{""}
+		if len(hosts[i].Status.Systems) > 0 {
+			systemIds = []string{}
+			for _, s := range hosts[i].Status.Systems {
+				systemIds = append(systemIds, s.Id)
+			}
+		}
+		for _, systemId := range systemIds {
+			last := previous[hosts[i].Name+"/"+systemId]
+			item := r.syncHost(logger, storageConfig, &hosts[i], systemId, &last)
+			if len(item.LastApplyTime) == 0 {
+				item.LastApplyTime = last.LastApplyTime
+			}
+			status.Hosts = append(status.Hosts, item)
+		}
+	}
+
+	for _, item := range status.Hosts {
+		status.TotalHosts++
+		switch item.State {
+		case topohubv1beta1.StorageStateInSync:
+			status.InSyncHosts++
+		case topohubv1beta1.StorageStatePending:
+			status.PendingHosts++
+		case topohubv1beta1.StorageStateDrifted:
+			status.DriftedHosts++
+		default:
+			status.FailedHosts++
+		}
+	}
+
+	// ignore the update time when comparing
+	status.LastUpdateTime = storageConfig.Status.LastUpdateTime
+	if reflect.DeepEqual(status, storageConfig.Status) {
+		logger.Debugf("no need to update StorageConfig %s", storageConfig.Name)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+	status.LastUpdateTime = time.Now().UTC().Format(time.RFC3339)
+	storageConfig.Status = status
+	if err := r.Status().Update(ctx, storageConfig); err != nil {
+		logger.Errorf("Failed to update StorageConfig status: %v", err)
+		return ctrl.Result{}, err
+	}
+	logger.Infof("Successfully updated StorageConfig %s status, total %d, inSync %d, pending %d, drifted %d, failed %d",
+		storageConfig.Name, status.TotalHosts, status.InSyncHosts, status.PendingHosts, status.DriftedHosts, status.FailedHosts)
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// selectHosts returns the HostStatus selected by the names and the label selector, and the names which are not found
+func (r *StorageConfigController) selectHosts(ctx context.Context, storageConfig *topohubv1beta1.StorageConfig) ([]topohubv1beta1.HostStatus, []string, error) {
+	selected := map[string]topohubv1beta1.HostStatus{}
+	missing := []string{}
+
+	for _, name := range storageConfig.Spec.HostStatusNames {
+		hostStatus := topohubv1beta1.HostStatus{}
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &hostStatus); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return nil, nil, err
+		}
+		selected[name] = hostStatus
+	}
+
+	if storageConfig.Spec.HostSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(storageConfig.Spec.HostSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid hostSelector: %v", err)
+		}
+		hostStatusList := &topohubv1beta1.HostStatusList{}
+		if err := r.List(ctx, hostStatusList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range hostStatusList.Items {
+			selected[item.Name] = item
+		}
+	}
+
+	result := []topohubv1beta1.HostStatus{}
+	for _, item := range selected {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	sort.Strings(missing)
+	return result, missing, nil
+}
+
+// syncHost compares the volumes of the system with the desired ones, and creates or deletes the volumes in the Enforce mode.
+// The volumes are deleted before creating, so the drives of the deleted volumes could be used by the new ones
+func (r *StorageConfigController) syncHost(logger *zap.SugaredLogger, storageConfig *topohubv1beta1.StorageConfig, hostStatus *topohubv1beta1.HostStatus, systemId string, last *topohubv1beta1.StorageHostStatus) topohubv1beta1.StorageHostStatus {
+	result := topohubv1beta1.StorageHostStatus{
+		HostStatusName: hostStatus.Name,
+		SystemId:       systemId,
+		State:          topohubv1beta1.StorageStateFailed,
+	}
+
+	if !hostStatus.Status.Healthy {
+		result.Message = fmt.Sprintf("hostStatus %s is not healthy", hostStatus.Name)
+		return result
+	}
+	d := hoststatusData.HostCacheDatabase.Get(hostStatus.Name)
+	if d == nil {
+		result.Message = fmt.Sprintf("failed to get connect config %s from cache", hostStatus.Name)
+		return result
+	}
+	c, err := redfish.NewClient(*d, logger)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	storages, err := c.GetStorage(systemId)
+	if err != nil {
+		logger.Warnf("Failed to get storage of %s: %v", hostStatus.Name, err)
+		result.Message = err.Error()
+		return result
+	}
+
+	actions, err := diffVolumes(&storageConfig.Spec, storages)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	for _, a := range actions {
+		result.Drift = append(result.Drift, a.drift)
+	}
+	switch {
+	case len(actions) == 0:
+		result.State = topohubv1beta1.StorageStateInSync
+		return result
+	case storageConfig.Spec.Mode == topohubv1beta1.StorageConfigModeMonitor:
+		result.State = topohubv1beta1.StorageStateDrifted
+		return result
+	}
+
+	// the action which has been requested is not requested again until it times out
+	requested := map[string]string{}
+	for _, item := range last.Drift {
+		if t, err := time.Parse(time.RFC3339, item.RequestTime); err == nil && time.Since(t) < volumeRequestTimeout {
+			requested[item.Action+"/"+item.Name] = item.RequestTime
+		}
+	}
+
+	deleting := false
+	for _, a := range actions {
+		if a.drift.Action == topohubv1beta1.VolumeActionDelete {
+			deleting = true
+		}
+	}
+	state := topohubv1beta1.StorageStatePending
+	messages := []string{}
+	for i := range actions {
+		a := &actions[i]
+		if t, ok := requested[a.drift.Action+"/"+a.drift.Name]; ok {
+			a.drift.RequestTime = t
+			continue
+		}
+		if a.drift.Action == topohubv1beta1.VolumeActionDelete && !storageConfig.Spec.DeleteUndeclaredVolumes {
+			if state == topohubv1beta1.StorageStatePending {
+				state = topohubv1beta1.StorageStateDrifted
+			}
+			messages = append(messages, fmt.Sprintf("volume %s is not deleted without deleteUndeclaredVolumes", a.drift.Name))
+			continue
+		}
+		if a.drift.Action == topohubv1beta1.VolumeActionCreate && deleting {
+			// wait for the deletion to release the drives
+			continue
+		}
+
+		var err error
+		if a.drift.Action == topohubv1beta1.VolumeActionDelete {
+			_, err = c.DeleteVolume(a.uri, storageConfig.Spec.ApplyTime)
+		} else {
+			_, err = c.CreateVolume(a.uri, a.request, storageConfig.Spec.ApplyTime)
+		}
+		if err != nil {
+			logger.Errorf("Failed to %s volume %s of %s: %v", a.drift.Action, a.drift.Name, hostStatus.Name, err)
+			state = topohubv1beta1.StorageStateFailed
+			messages = append(messages, err.Error())
+			continue
+		}
+		a.drift.RequestTime = time.Now().UTC().Format(time.RFC3339)
+		result.LastApplyTime = a.drift.RequestTime
+		logger.Infof("Requested to %s volume %s of %s, system %s", a.drift.Action, a.drift.Name, hostStatus.Name, systemId)
+	}
+
+	result.Drift = nil
+	for _, a := range actions {
+		result.Drift = append(result.Drift, a.drift)
+	}
+	result.State = state
+	if len(messages) > 0 {
+		result.Message = fmt.Sprintf("%v", messages)
+	}
+	return result
+}
+
+// volumeAction is the action to make the volumes as declared
+type volumeAction struct {
+	drift topohubv1beta1.VolumeDrift
+	// uri is the @odata.id of the volume to delete, or the storage to create the volume
+	uri     string
+	request redfish.VolumeRequest
+}
+
+// diffVolumes returns the volumes to delete and to create. The volume is matched by the name on the storage which
+// has its drives, the undeclared volumes are only reported on the storage used by the declared volumes when they are going to be deleted
+func diffVolumes(spec *topohubv1beta1.StorageConfigSpec, storages []topohubv1beta1.StorageInventory) ([]volumeAction, error) {
+	deletes := []volumeAction{}
+	creates := []volumeAction{}
+	declared := map[string]bool{}
+	used := map[string]bool{}
+
+	for _, v := range spec.Volumes {
+		st, drives, err := resolveDrives(&v, storages)
+		if err != nil {
+			return nil, err
+		}
+		used[st.ODataId] = true
+
+		var existing *topohubv1beta1.VolumeInventory
+		for i := range st.Volumes {
+			if st.Volumes[i].Name == v.Name {
+				existing = &st.Volumes[i]
+				break
+			}
+		}
+		if existing == nil {
+			request := redfish.VolumeRequest{
+				Name:             v.Name,
+				RAIDType:         v.RAIDType,
+				Drives:           drives,
+				InitializeMethod: v.InitializeMethod,
+			}
+			if v.CapacityBytes != nil {
+				request.CapacityBytes = *v.CapacityBytes
+			}
+			creates = append(creates, volumeAction{
+				drift:   topohubv1beta1.VolumeDrift{Name: v.Name, Action: topohubv1beta1.VolumeActionCreate, Message: fmt.Sprintf("volume %s is not found on storage %s", v.Name, st.Id)},
+				uri:     st.ODataId,
+				request: request,
+			})
+			continue
+		}
+		declared[existing.ODataId] = true
+
+		// the volume of the different layout is deleted, and created again in the following reconciliation
+		message := ""
+		if len(existing.RAIDType) > 0 && existing.RAIDType != v.RAIDType {
+			message = fmt.Sprintf("the raid type is %s rather than %s", existing.RAIDType, v.RAIDType)
+		} else if len(existing.Drives) > 0 && !reflect.DeepEqual(existing.Drives, drives) {
+			message = fmt.Sprintf("the drives are %v rather than %v", existing.Drives, drives)
+		}
+		if len(message) > 0 {
+			deletes = append(deletes, volumeAction{
+				drift: topohubv1beta1.VolumeDrift{Name: v.Name, Action: topohubv1beta1.VolumeActionDelete, Message: message},
+				uri:   existing.ODataId,
+			})
+		}
+	}
+
+	if spec.DeleteUndeclaredVolumes {
+		for _, st := range storages {
+			if !used[st.ODataId] {
+				continue
+			}
+			for _, volume := range st.Volumes {
+				if declared[volume.ODataId] {
+					continue
+				}
+				name := volume.Name
+				if len(name) == 0 {
+					name = volume.Id
+				}
+				deletes = append(deletes, volumeAction{
+					drift: topohubv1beta1.VolumeDrift{Name: name, Action: topohubv1beta1.VolumeActionDelete, Message: "the volume is not declared"},
+					uri:   volume.ODataId,
+				})
+			}
+		}
+	}
+	return append(deletes, creates...), nil
+}
+
+// resolveDrives returns the storage of the volume and the @odata.id of its drives, sorted
+func resolveDrives(v *topohubv1beta1.VolumeSpec, storages []topohubv1beta1.StorageInventory) (*topohubv1beta1.StorageInventory, []string, error) {
+	var result *topohubv1beta1.StorageInventory
+	drives := []string{}
+	for _, name := range v.Drives {
+		found := false
+		for i := range storages {
+			st := &storages[i]
+			if len(v.StorageId) > 0 && st.Id != v.StorageId {
+				continue
+			}
+			for _, d := range st.Drives {
+				if d != name && path.Base(d) != name {
+					continue
+				}
+				if result != nil && result != st {
+					return nil, nil, fmt.Errorf("the drives of volume %s are on different storage %s and %s", v.Name, result.Id, st.Id)
+				}
+				result = st
+				drives = append(drives, d)
+				found = true
+				break
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			if len(v.StorageId) > 0 {
+				return nil, nil, fmt.Errorf("drive %s of volume %s is not found on storage %s", name, v.Name, v.StorageId)
+			}
+			return nil, nil, fmt.Errorf("drive %s of volume %s is not found", name, v.Name)
+		}
+	}
+	if result == nil {
+		return nil, nil, fmt.Errorf("no drive is specified for volume %s", v.Name)
+	}
+	sort.Strings(drives)
+	return result, drives, nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *StorageConfigController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topohubv1beta1.StorageConfig{}).
+		// the status is updated by itself, and the hosts are checked periodically
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
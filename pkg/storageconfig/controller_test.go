@@ -0,0 +1,146 @@
+package storageconfig
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/infrastructure-io/topohub/pkg/config"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+	"github.com/infrastructure-io/topohub/pkg/testhost"
+)
+
+const (
+	testStorageConfigName = "raid"
+	volumesPath           = "/redfish/v1/Systems/1/Storage/RAID.1/Volumes"
+)
+
+var _ = Describe("StorageConfigController", Label("unitest"), func() {
+	var bmc *testhost.Emulator
+	var r *StorageConfigController
+
+	// newController creates the controller with the HostStatus of the emulator and the StorageConfig
+	newController := func(spec topohubv1beta1.StorageConfigSpec) {
+		hostStatus := bmc.HostStatus()
+		hostStatus.Status.Systems = []topohubv1beta1.SystemInfo{{Id: "1"}}
+		spec.HostStatusNames = []string{testhost.HostStatusName, "missing"}
+		c := bmc.Build(hostStatus, &topohubv1beta1.StorageConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: testStorageConfigName},
+			Spec:       spec,
+		})
+		r = &StorageConfigController{
+			Client:      c,
+			Scheme:      c.Scheme(),
+			agentConfig: &config.AgentConfig{RedfishHostStatusUpdateInterval: 60},
+			log:         zap.NewNop().Sugar(),
+		}
+	}
+
+	// reconcile returns the status of the emulator host
+	reconcile := func() topohubv1beta1.StorageHostStatus {
+		testhost.Reconcile(r, testStorageConfigName)
+		storageConfig := testhost.Get(r, testStorageConfigName, &topohubv1beta1.StorageConfig{})
+		Expect(storageConfig.Status.TotalHosts).To(Equal(int32(2)))
+		Expect(storageConfig.Status.Hosts).To(HaveLen(2))
+		Expect(storageConfig.Status.Hosts[0].HostStatusName).To(Equal("missing"))
+		Expect(storageConfig.Status.Hosts[0].State).To(Equal(topohubv1beta1.StorageStateFailed))
+		return storageConfig.Status.Hosts[1]
+	}
+
+	osVolume := topohubv1beta1.VolumeSpec{Name: "os", RAIDType: "RAID1", Drives: []string{"Disk.0", "Disk.1"}}
+
+	BeforeEach(func() {
+		bmc = testhost.Start(false)
+		bmc.SetStorage("1", emulator.Storage{
+			Id:                 "RAID.1",
+			SupportedRAIDTypes: []string{"RAID0", "RAID1"},
+			Drives:             []emulator.Drive{{Id: "Disk.0"}, {Id: "Disk.1"}, {Id: "Disk.2"}},
+			Volumes:            []emulator.Volume{{Id: "old", Name: "old", RAIDType: "RAID0", Drives: []string{"Disk.0"}}},
+		})
+	})
+
+	It("deletes the undeclared volume before creating the declared one", func() {
+		newController(topohubv1beta1.StorageConfigSpec{
+			Volumes:                 []topohubv1beta1.VolumeSpec{osVolume},
+			DeleteUndeclaredVolumes: true,
+			Mode:                    topohubv1beta1.StorageConfigModeEnforce,
+		})
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStatePending))
+		Expect(status.Drift).To(HaveLen(2))
+		Expect(status.Drift[0].Action).To(Equal(topohubv1beta1.VolumeActionDelete))
+		Expect(status.Drift[0].RequestTime).NotTo(BeEmpty())
+		Expect(status.Drift[1].Action).To(Equal(topohubv1beta1.VolumeActionCreate))
+		Expect(status.Drift[1].RequestTime).To(BeEmpty())
+		Expect(bmc.Volumes("1", "RAID.1")).To(BeEmpty())
+
+		status = reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStatePending))
+		Expect(status.LastApplyTime).NotTo(BeEmpty())
+		volumes := bmc.Volumes("1", "RAID.1")
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Name).To(Equal("os"))
+		Expect(volumes[0].Drives).To(ConsistOf("Disk.0", "Disk.1"))
+
+		status = reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStateInSync))
+		Expect(status.Drift).To(BeEmpty())
+	})
+
+	It("does not request the action again before it times out", func() {
+		bmc.SetStorage("1", emulator.Storage{
+			Id:     "RAID.1",
+			Drives: []emulator.Drive{{Id: "Disk.0"}, {Id: "Disk.1"}},
+		})
+		newController(topohubv1beta1.StorageConfigSpec{Volumes: []topohubv1beta1.VolumeSpec{osVolume}})
+		// the bmc accepts the request, but creates the volume after the reboot
+		bmc.Fail(http.MethodPost, volumesPath, http.StatusNoContent, 1)
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStatePending))
+		status = reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStatePending))
+		Expect(bmc.CountRequests(http.MethodPost, volumesPath)).To(Equal(1))
+	})
+
+	It("reports the drift without changing the volumes", func() {
+		newController(topohubv1beta1.StorageConfigSpec{
+			Volumes:                 []topohubv1beta1.VolumeSpec{osVolume},
+			DeleteUndeclaredVolumes: true,
+			Mode:                    topohubv1beta1.StorageConfigModeMonitor,
+		})
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStateDrifted))
+		Expect(status.Drift).To(HaveLen(2))
+		Expect(bmc.CountRequests(http.MethodPost, volumesPath)).To(Equal(0))
+		Expect(bmc.CountRequests(http.MethodDelete, volumesPath)).To(Equal(0))
+	})
+
+	It("keeps the volume of the different layout without deleteUndeclaredVolumes", func() {
+		newController(topohubv1beta1.StorageConfigSpec{
+			Volumes: []topohubv1beta1.VolumeSpec{{Name: "old", RAIDType: "RAID1", Drives: []string{"Disk.0", "Disk.2"}}},
+		})
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStateDrifted))
+		Expect(status.Drift).To(HaveLen(1))
+		Expect(status.Drift[0].Message).To(ContainSubstring("RAID0"))
+		Expect(bmc.Volumes("1", "RAID.1")).To(HaveLen(1))
+	})
+
+	It("fails when the drive is not found", func() {
+		newController(topohubv1beta1.StorageConfigSpec{
+			Volumes: []topohubv1beta1.VolumeSpec{{Name: "data", RAIDType: "RAID0", Drives: []string{"Disk.9"}}},
+		})
+
+		status := reconcile()
+		Expect(status.State).To(Equal(topohubv1beta1.StorageStateFailed))
+		Expect(status.Message).To(ContainSubstring("Disk.9"))
+	})
+})
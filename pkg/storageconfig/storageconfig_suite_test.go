@@ -0,0 +1,13 @@
+package storageconfig
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestStorageConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "StorageConfig Suite")
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
 	"github.com/infrastructure-io/topohub/pkg/tools"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -164,6 +165,12 @@ func (s *dhcpServer) updateSubnetWithRetry() error {
 			updated.Status.DhcpStatus.DhcpIpAutoBindAmount = uint64(len(s.currentAutoBindingClients))
 			updated.Status.DhcpStatus.DhcpIpBindAmount = updated.Status.DhcpStatus.DhcpIpManualBindAmount + updated.Status.DhcpStatus.DhcpIpAutoBindAmount
 
+			// export the usage of the ip pool
+			metrics.SubnetIpTotal.WithLabelValues(s.subnet.Name).Set(float64(updated.Status.DhcpStatus.DhcpIpTotalAmount))
+			metrics.SubnetIpAvailable.WithLabelValues(s.subnet.Name).Set(float64(updated.Status.DhcpStatus.DhcpIpAvailableAmount))
+			metrics.SubnetIpActive.WithLabelValues(s.subnet.Name).Set(float64(updated.Status.DhcpStatus.DhcpIpActiveAmount))
+			metrics.SubnetIpBound.WithLabelValues(s.subnet.Name).Set(float64(updated.Status.DhcpStatus.DhcpIpBindAmount))
+
 			if updated.Status.HostNode == nil || *updated.Status.HostNode != s.config.NodeName {
 				s.log.Infof("update host node %s to subnet %s", s.config.NodeName, s.subnet.Name)
 				updated.Status.HostNode = &s.config.NodeName
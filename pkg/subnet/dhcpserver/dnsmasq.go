@@ -11,6 +11,7 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
 )
 
 // startDnsmasq starts the dnsmasq process
@@ -265,6 +266,7 @@ func (s *dhcpServer) monitor() {
 
 		} else if needRestart {
 			s.log.Infof("restarting dhcp server")
+			metrics.DhcpServerRestartsTotal.WithLabelValues(subnetName).Inc()
 			// in the startDnsmasq, it finish 's.statusUpdateCh <- struct{}{}'
 			if err := s.startDnsmasq(); err != nil {
 				s.log.Errorf("Failed to restart dnsmasq: %v", err)
@@ -13,6 +13,7 @@ import (
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
 	"github.com/infrastructure-io/topohub/pkg/lock"
 	"github.com/infrastructure-io/topohub/pkg/log"
+	"github.com/infrastructure-io/topohub/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -149,6 +150,10 @@ func (s *dhcpServer) Stop() error {
 		s.log.Errorf("Failed to cleanup network interface: %v", err)
 	}
 
+	s.lockData.RLock()
+	metrics.DeleteSubnet(s.subnet.Name)
+	s.lockData.RUnlock()
+
 	return nil
 }
 
@@ -0,0 +1,122 @@
+// Package testhost sets up the redfish emulator as the HostStatus of the unit tests of the controllers, with the fake
+// client holding the HostStatus and the custom resources of the controller
+package testhost
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hoststatusData "github.com/infrastructure-io/topohub/pkg/hoststatus/data"
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+	"github.com/infrastructure-io/topohub/pkg/redfish"
+	"github.com/infrastructure-io/topohub/pkg/redfish/emulator"
+)
+
+const (
+	// HostStatusName is the name of the HostStatus of the emulator
+	HostStatusName = "emulator"
+	Username       = "admin"
+	Password       = "password"
+)
+
+// Emulator is the redfish emulator which is registered as the HostStatus
+type Emulator struct {
+	*emulator.Server
+
+	// Basic is the basic info of the HostStatus, it could be changed before Build
+	Basic topohubv1beta1.BasicInfo
+	// TLS is the trust of the certificate in the connect config, the certificate is not verified when it is nil
+	TLS *hoststatusData.TLSTrust
+}
+
+// Start starts the emulator with the credential, it serves https when https is true. It is called in the BeforeEach,
+// and the emulator is closed and its cached clients are removed after the spec
+func Start(https bool) *Emulator {
+	var bmc *emulator.Server
+	if https {
+		bmc = emulator.New()
+	} else {
+		bmc = emulator.NewHTTP()
+	}
+	bmc.SetCredential(Username, Password)
+	e := &Emulator{
+		Server: bmc,
+		Basic: topohubv1beta1.BasicInfo{
+			Type:   topohubv1beta1.HostTypeEndpoint,
+			IpAddr: bmc.Host(),
+			Port:   bmc.Port(),
+			Https:  https,
+		},
+	}
+	DeferCleanup(func() {
+		redfish.CacheClient.Delete(bmc.Host())
+		hoststatusData.HostCacheDatabase.Delete(HostStatusName)
+		bmc.Close()
+	})
+	return e
+}
+
+// HostStatus returns the healthy HostStatus of the emulator
+func (e *Emulator) HostStatus() *topohubv1beta1.HostStatus {
+	return &topohubv1beta1.HostStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: HostStatusName},
+		Status:     topohubv1beta1.HostStatusStatus{Healthy: true, Basic: e.Basic},
+	}
+}
+
+// Build adds the connect config of the emulator to the cache, and returns the fake client holding the objects. The
+// objects should include the HostStatus when the controller reads it
+func (e *Emulator) Build(objects ...client.Object) client.Client {
+	basic := e.Basic
+	hoststatusData.HostCacheDatabase.Add(HostStatusName, hoststatusData.HostConnectCon{
+		Info:     &basic,
+		Username: Username,
+		Password: Password,
+		TLS:      e.TLS,
+	})
+	return NewClient(objects...)
+}
+
+// NewClient returns the fake client holding the objects, the status subresource is enabled for all the custom resources
+func NewClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(topohubv1beta1.AddToScheme(scheme)).To(Succeed())
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(
+			&topohubv1beta1.HostStatus{},
+			&topohubv1beta1.HostInventory{},
+			&topohubv1beta1.HostOperation{},
+			&topohubv1beta1.BiosConfig{},
+			&topohubv1beta1.BmcAccount{},
+			&topohubv1beta1.StorageConfig{},
+			&topohubv1beta1.PowerCapConfig{},
+			&topohubv1beta1.BmcCertificate{},
+			&topohubv1beta1.BmcProfile{},
+		).
+		Build()
+}
+
+// Reconcile reconciles the cluster-scoped object with the name, and expects no error
+func Reconcile(r reconcile.Reconciler, name string) ctrl.Result {
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	Expect(err).NotTo(HaveOccurred())
+	return result
+}
+
+// Get reads the cluster-scoped object with the name, and expects it exists
+func Get[T client.Object](c client.Reader, name string, obj T) T {
+	Expect(c.Get(context.Background(), types.NamespacedName{Name: name}, obj)).To(Succeed())
+	return obj
+}
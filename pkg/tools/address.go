@@ -0,0 +1,28 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
+)
+
+// GetAgentAddress returns the address of topohub which the bmc of the host could access.
+// It is the configured address, or the self ip of the dhcp server in the subnet of the dhcp host
+func GetAgentAddress(ctx context.Context, c client.Client, configuredAddress string, hostStatus *topohubv1beta1.HostStatus) (string, error) {
+	if len(configuredAddress) > 0 {
+		return configuredAddress, nil
+	}
+
+	if hostStatus.Status.Basic.Type != topohubv1beta1.HostTypeDHCP || hostStatus.Status.Basic.SubnetName == nil {
+		return "", fmt.Errorf("the address of topohub is not configured, which is required for the host %s", hostStatus.Name)
+	}
+	subnet := &topohubv1beta1.Subnet{}
+	if err := c.Get(ctx, client.ObjectKey{Name: *hostStatus.Status.Basic.SubnetName}, subnet); err != nil {
+		return "", fmt.Errorf("failed to get subnet %s: %v", *hostStatus.Status.Basic.SubnetName, err)
+	}
+	return strings.Split(subnet.Spec.Interface.IPv4, "/")[0], nil
+}
@@ -52,14 +52,25 @@ func (w *HostEndpointWebhook) Default(ctx context.Context, obj runtime.Object) e
 		w.log.Infof("Setting default HTTPS to true for HostEndpoint %s", hostEndpoint.Name)
 	}
 
+	if hostEndpoint.Spec.Protocol == nil || *hostEndpoint.Spec.Protocol == "" {
+		defaultProtocol := topohubv1beta1.ProtocolRedfish
+		hostEndpoint.Spec.Protocol = &defaultProtocol
+		w.log.Infof("Setting default Protocol to redfish for HostEndpoint %s", hostEndpoint.Name)
+	}
+	ipmi := *hostEndpoint.Spec.Protocol == topohubv1beta1.ProtocolIPMI
+
 	if hostEndpoint.Spec.Port == nil {
 		defaultPort := int32(443)
+		if ipmi {
+			defaultPort = 623
+		}
 		hostEndpoint.Spec.Port = &defaultPort
-		w.log.Infof("Setting default Port to 443 for HostEndpoint %s", hostEndpoint.Name)
+		w.log.Infof("Setting default Port to %d for HostEndpoint %s", defaultPort, hostEndpoint.Name)
 	}
 
 	if (hostEndpoint.Spec.SecretName == nil || *hostEndpoint.Spec.SecretName == "") && (hostEndpoint.Spec.SecretNamespace == nil || *hostEndpoint.Spec.SecretNamespace == "") {
-		if hostEndpoint.Spec.HTTPS != nil && *hostEndpoint.Spec.HTTPS {
+		// ipmi always authenticates with the credential
+		if (hostEndpoint.Spec.HTTPS != nil && *hostEndpoint.Spec.HTTPS) || ipmi {
 			hostEndpoint.Spec.SecretName = &w.config.RedfishSecretName
 			hostEndpoint.Spec.SecretNamespace = &w.config.RedfishSecretNamespace
 		}
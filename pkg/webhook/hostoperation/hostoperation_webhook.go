@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"go.uber.org/zap"
+	"os"
+	"path"
+	"path/filepath"
 
 	//"time"
 
@@ -11,6 +14,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/infrastructure-io/topohub/pkg/config"
 	topohubv1beta1 "github.com/infrastructure-io/topohub/pkg/k8s/apis/topohub.infrastructure.io/v1beta1"
 	"github.com/infrastructure-io/topohub/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,11 +22,13 @@ import (
 
 type HostOperationWebhook struct {
 	Client client.Client
+	config *config.AgentConfig
 	log    *zap.SugaredLogger
 }
 
-func (h *HostOperationWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (h *HostOperationWebhook) SetupWebhookWithManager(mgr ctrl.Manager, config config.AgentConfig) error {
 	h.Client = mgr.GetClient()
+	h.config = &config
 	h.log = log.Logger.Named("hostoperationWebhook")
 	log.Logger.Info("Setting up HostOperation webhook")
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -68,12 +74,60 @@ func (h *HostOperationWebhook) ValidateCreate(ctx context.Context, obj runtime.O
 		return nil, err
 	}
 
-	if !hostStatus.Status.Healthy {
+	// the bmc which does not respond properly is allowed to be reset
+	if !hostStatus.Status.Healthy && hostOp.Spec.Action != topohubv1beta1.ActionResetBmc {
 		err := fmt.Errorf("hostStatus %s is not healthy, so it is not allowed to create hostOperation %s", hostOp.Spec.HostStatusName, hostOp.Name)
 		h.log.Error(err.Error())
 		return nil, err
 	}
 
+	// 多 system 的主机，必须指定操作哪一个 system
+	if err := validateSystemId(hostOp, &hostStatus); err != nil {
+		h.log.Error(err.Error())
+		return nil, err
+	}
+
+	if hostOp.Spec.Action == topohubv1beta1.ActionFirmwareUpdate {
+		if err := h.validateFirmware(hostOp); err != nil {
+			h.log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	if hostOp.Spec.Action == topohubv1beta1.ActionVirtualMediaBoot {
+		if err := h.validateVirtualMedia(hostOp); err != nil {
+			h.log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	if hostOp.Spec.Action == topohubv1beta1.ActionSetBoot {
+		if err := validateBoot(hostOp); err != nil {
+			h.log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	if hostOp.Spec.Action == topohubv1beta1.ActionDecommission {
+		if err := validateDecommission(hostOp); err != nil {
+			h.log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	if hostOp.Spec.Action == topohubv1beta1.ActionSetIndicatorLED && hostOp.Spec.IndicatorLED == nil {
+		err := fmt.Errorf("spec.indicatorLED is required for the action %s", hostOp.Spec.Action)
+		h.log.Error(err.Error())
+		return nil, err
+	}
+
+	if hostOp.Spec.Action == topohubv1beta1.ActionResetBmcToDefaults {
+		if err := validateBmcResetToDefaults(hostOp); err != nil {
+			h.log.Error(err.Error())
+			return nil, err
+		}
+	}
+
 	h.log.Debugf("Successfully validated HostOperation %s creation", hostOp.Name)
 	return nil, nil
 }
@@ -100,3 +154,110 @@ func (h *HostOperationWebhook) ValidateDelete(ctx context.Context, obj runtime.O
 	h.log.Debugf("Processing ValidateDelete webhook for HostOperation %s", hostOp.Name)
 	return nil, nil
 }
+
+// validateSystemId checks the spec.systemId against the systems recorded in the HostStatus
+func validateSystemId(hostOp *topohubv1beta1.HostOperation, hostStatus *topohubv1beta1.HostStatus) error {
+	systems := hostStatus.Status.Systems
+	if len(hostOp.Spec.SystemId) == 0 {
+		if len(systems) > 1 {
+			ids := []string{}
+			for _, s := range systems {
+				ids = append(ids, s.Id)
+			}
+			return fmt.Errorf("hostStatus %s exposes %d systems, spec.systemId must be one of %v", hostStatus.Name, len(systems), ids)
+		}
+		return nil
+	}
+
+	// the systems may not be collected yet, leave it to the controller
+	if len(systems) == 0 {
+		return nil
+	}
+	for _, s := range systems {
+		if s.Id == hostOp.Spec.SystemId {
+			return nil
+		}
+	}
+	return fmt.Errorf("system %s is not found in hostStatus %s", hostOp.Spec.SystemId, hostStatus.Name)
+}
+
+// validateFirmware checks the firmware image exists under the root directory of the http server
+func (h *HostOperationWebhook) validateFirmware(hostOp *topohubv1beta1.HostOperation) error {
+	if hostOp.Spec.Firmware == nil || len(hostOp.Spec.Firmware.ImagePath) == 0 {
+		return fmt.Errorf("spec.firmware.imagePath is required for the action %s", hostOp.Spec.Action)
+	}
+	if hostOp.Spec.Firmware.TransferMethod != topohubv1beta1.FirmwareTransferMultipartPush && !h.config.HttpEnabled {
+		return fmt.Errorf("the http server of topohub is disabled, the firmware could only be updated by %s", topohubv1beta1.FirmwareTransferMultipartPush)
+	}
+	imageFile := filepath.Join(h.config.StoragePathHttp, path.Clean("/"+hostOp.Spec.Firmware.ImagePath))
+	if info, err := os.Stat(imageFile); err != nil {
+		return fmt.Errorf("firmware image %s is not found: %v", hostOp.Spec.Firmware.ImagePath, err)
+	} else if info.IsDir() {
+		return fmt.Errorf("firmware image %s is a directory", hostOp.Spec.Firmware.ImagePath)
+	}
+	return nil
+}
+
+// validateVirtualMedia checks the iso exists under the iso directory of the http server
+func (h *HostOperationWebhook) validateVirtualMedia(hostOp *topohubv1beta1.HostOperation) error {
+	if hostOp.Spec.VirtualMedia == nil || len(hostOp.Spec.VirtualMedia.IsoFile) == 0 {
+		return fmt.Errorf("spec.virtualMedia.isoFile is required for the action %s", hostOp.Spec.Action)
+	}
+	if !h.config.HttpEnabled {
+		return fmt.Errorf("the http server of topohub is disabled, so the host could not boot from the iso")
+	}
+	isoFile := filepath.Join(h.config.StoragePathHttpIso, path.Clean("/"+hostOp.Spec.VirtualMedia.IsoFile))
+	if info, err := os.Stat(isoFile); err != nil {
+		return fmt.Errorf("iso %s is not found: %v", hostOp.Spec.VirtualMedia.IsoFile, err)
+	} else if info.IsDir() {
+		return fmt.Errorf("iso %s is a directory", hostOp.Spec.VirtualMedia.IsoFile)
+	}
+	return nil
+}
+
+// validateBoot checks the boot configuration of the SetBoot action
+func validateBoot(hostOp *topohubv1beta1.HostOperation) error {
+	boot := hostOp.Spec.Boot
+	if boot == nil {
+		return fmt.Errorf("spec.boot is required for the action %s", hostOp.Spec.Action)
+	}
+	if len(boot.Target) == 0 && len(boot.OverrideEnabled) == 0 && len(boot.BootMode) == 0 && len(boot.BootOrder) == 0 {
+		return fmt.Errorf("at least one of spec.boot.target, spec.boot.overrideEnabled, spec.boot.bootMode and spec.boot.bootOrder is required")
+	}
+	if boot.Target == "UefiTarget" && len(boot.UefiTarget) == 0 {
+		return fmt.Errorf("spec.boot.uefiTarget is required for the target UefiTarget")
+	}
+	if len(boot.UefiTarget) > 0 && boot.Target != "UefiTarget" {
+		return fmt.Errorf("spec.boot.uefiTarget only works for the target UefiTarget")
+	}
+	if len(boot.HttpBootUri) > 0 && boot.Target != "UefiHttp" {
+		return fmt.Errorf("spec.boot.httpBootUri only works for the target UefiHttp")
+	}
+	seen := map[string]bool{}
+	for _, item := range boot.BootOrder {
+		if seen[item] {
+			return fmt.Errorf("duplicate item %s in spec.boot.bootOrder", item)
+		}
+		seen[item] = true
+	}
+	return nil
+}
+
+// validateDecommission requires the name of the host to be confirmed, since the Decommission action erases the drives
+func validateDecommission(hostOp *topohubv1beta1.HostOperation) error {
+	if hostOp.Spec.Decommission == nil {
+		return fmt.Errorf("spec.decommission is required for the action %s", hostOp.Spec.Action)
+	}
+	if hostOp.Spec.Decommission.ConfirmHostStatusName != hostOp.Spec.HostStatusName {
+		return fmt.Errorf("spec.decommission.confirmHostStatusName must be the same as spec.hostStatusName %s", hostOp.Spec.HostStatusName)
+	}
+	return nil
+}
+
+// validateBmcResetToDefaults requires the name of the host to be confirmed, since the settings of the bmc are lost
+func validateBmcResetToDefaults(hostOp *topohubv1beta1.HostOperation) error {
+	if hostOp.Spec.BmcReset == nil || hostOp.Spec.BmcReset.ConfirmHostStatusName != hostOp.Spec.HostStatusName {
+		return fmt.Errorf("spec.bmcReset.confirmHostStatusName must be the same as spec.hostStatusName %s for the action %s", hostOp.Spec.HostStatusName, hostOp.Spec.Action)
+	}
+	return nil
+}